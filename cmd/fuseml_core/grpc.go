@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
 	"net"
 	"net/url"
 	"sync"
 
 	applicationpb "github.com/fuseml/fuseml-core/gen/grpc/application/pb"
 	applicationsvr "github.com/fuseml/fuseml-core/gen/grpc/application/server"
+	auditpb "github.com/fuseml/fuseml-core/gen/grpc/audit/pb"
+	auditsvr "github.com/fuseml/fuseml-core/gen/grpc/audit/server"
+	authpb "github.com/fuseml/fuseml-core/gen/grpc/auth/pb"
+	authsvr "github.com/fuseml/fuseml-core/gen/grpc/auth/server"
 	codesetpb "github.com/fuseml/fuseml-core/gen/grpc/codeset/pb"
 	codesetsvr "github.com/fuseml/fuseml-core/gen/grpc/codeset/server"
 	extensionpb "github.com/fuseml/fuseml-core/gen/grpc/extension/pb"
@@ -21,21 +25,28 @@ import (
 	workflowsvr "github.com/fuseml/fuseml-core/gen/grpc/workflow/server"
 
 	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"go.uber.org/zap"
 	grpcmdlwr "goa.design/goa/v3/grpc/middleware"
 	"goa.design/goa/v3/middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
+
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 )
 
 // handleGRPCServer starts configures and starts a gRPC server on the given
-// URL. It shuts down the server if any error is received in the error channel.
-func handleGRPCServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
+// URL. The server uses TLS when a TLS configuration is provided. It shuts down
+// the server if any error is received in the error channel.
+func handleGRPCServer(ctx context.Context, u *url.URL, endpoints *endpoints, tlsConfig *tls.Config, m *metrics.Metrics, wg *sync.WaitGroup, errc chan error, logger *zap.SugaredLogger, debug bool) {
 	// Setup goa log adapter.
 	var (
 		adapter middleware.Logger
 	)
 	{
-		adapter = middleware.NewLogger(logger)
+		adapter = logging.NewGoaLogger(logger)
 	}
 
 	// Wrap the endpoints with the transport specific layers. The generated
@@ -43,41 +54,53 @@ func handleGRPCServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 	// the service input and output data structures to gRPC requests and
 	// responses.
 	var (
+		authServer        *authsvr.Server
 		applicationServer *applicationsvr.Server
 		runnableServer    *runnablesvr.Server
 		codesetServer     *codesetsvr.Server
 		projectServer     *projectsvr.Server
 		workflowServer    *workflowsvr.Server
 		extensionServer   *extensionsvr.Server
+		auditServer       *auditsvr.Server
 	)
 	{
+		authServer = authsvr.New(endpoints.auth, nil)
 		applicationServer = applicationsvr.New(endpoints.application, nil)
 		runnableServer = runnablesvr.New(endpoints.runnable, nil)
 		codesetServer = codesetsvr.New(endpoints.codeset, nil)
 		projectServer = projectsvr.New(endpoints.project, nil)
 		workflowServer = workflowsvr.New(endpoints.workflow, nil)
 		extensionServer = extensionsvr.New(endpoints.extension, nil)
+		auditServer = auditsvr.New(endpoints.audit, nil)
 	}
 
 	// Initialize gRPC server with the middleware.
-	srv := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpcmiddleware.WithUnaryServerChain(
+			tracing.UnaryServerInterceptor(),
 			grpcmdlwr.UnaryRequestID(),
 			grpcmdlwr.UnaryServerLog(adapter),
+			m.UnaryServerInterceptor(),
 		),
-	)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	srv := grpc.NewServer(opts...)
 
 	// Register the servers.
+	authpb.RegisterAuthServer(srv, authServer)
 	applicationpb.RegisterApplicationServer(srv, applicationServer)
 	runnablepb.RegisterRunnableServer(srv, runnableServer)
 	codesetpb.RegisterCodesetServer(srv, codesetServer)
 	projectpb.RegisterProjectServer(srv, projectServer)
 	workflowpb.RegisterWorkflowServer(srv, workflowServer)
 	extensionpb.RegisterExtensionServer(srv, extensionServer)
+	auditpb.RegisterAuditServer(srv, auditServer)
 
 	for svc, info := range srv.GetServiceInfo() {
 		for _, m := range info.Methods {
-			logger.Printf("serving gRPC method %s", svc+"/"+m.Name)
+			logger.Infof("serving gRPC method %s", svc+"/"+m.Name)
 		}
 	}
 
@@ -95,12 +118,12 @@ func handleGRPCServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 			if err != nil {
 				errc <- err
 			}
-			logger.Printf("gRPC server listening on %q", u.Host)
+			logger.Infof("gRPC server listening on %q", u.Host)
 			errc <- srv.Serve(lis)
 		}()
 
 		<-ctx.Done()
-		logger.Printf("shutting down gRPC server at %q", u.Host)
+		logger.Infof("shutting down gRPC server at %q", u.Host)
 		srv.Stop()
 	}()
 }
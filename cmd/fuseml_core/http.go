@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
 	"mime"
 	"net/http"
 	"net/url"
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	applicationsvr "github.com/fuseml/fuseml-core/gen/http/application/server"
+	auditsvr "github.com/fuseml/fuseml-core/gen/http/audit/server"
+	authsvr "github.com/fuseml/fuseml-core/gen/http/auth/server"
 	codesetsvr "github.com/fuseml/fuseml-core/gen/http/codeset/server"
 	extensionsvr "github.com/fuseml/fuseml-core/gen/http/extension/server"
 	openapisvr "github.com/fuseml/fuseml-core/gen/http/openapi/server"
@@ -21,20 +23,33 @@ import (
 	workflowsvr "github.com/fuseml/fuseml-core/gen/http/workflow/server"
 
 	"github.com/goccy/go-yaml"
+	"go.uber.org/zap"
 	goahttp "goa.design/goa/v3/http"
 	httpmdlwr "goa.design/goa/v3/http/middleware"
 	"goa.design/goa/v3/middleware"
+
+	"github.com/fuseml/fuseml-core/pkg/health"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/tlsconfig"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 )
 
 // handleHTTPServer starts configures and starts a HTTP server on the given
-// URL. It shuts down the server if any error is received in the error channel.
-func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg *sync.WaitGroup, errc chan error, logger *log.Logger, debug bool) {
+// URL. The server uses TLS when a TLS reloader is provided, exposes
+// the metrics on /metrics and the liveness and readiness of the server on
+// /healthz and /readyz. When mTLS is configured, the client certificates are
+// required for all the requests except those for the metrics and the health
+// checks. It shuts down the server if any error is received in the error
+// channel.
+func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, tlsReloader *tlsconfig.Reloader, m *metrics.Metrics,
+	readiness *health.Checker, wg *sync.WaitGroup, errc chan error, logger *zap.SugaredLogger, debug bool) {
 	// Setup goa log adapter.
 	var (
 		adapter middleware.Logger
 	)
 	{
-		adapter = middleware.NewLogger(logger)
+		adapter = logging.NewGoaLogger(logger)
 	}
 
 	// Provide the transport specific request decoder and response encoder.
@@ -59,6 +74,7 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 	// responses.
 	var (
 		versionServer     *versionsvr.Server
+		authServer        *authsvr.Server
 		applicationServer *applicationsvr.Server
 		runnableServer    *runnablesvr.Server
 		codesetServer     *codesetsvr.Server
@@ -66,20 +82,24 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 		openapiServer     *openapisvr.Server
 		workflowServer    *workflowsvr.Server
 		extensionServer   *extensionsvr.Server
+		auditServer       *auditsvr.Server
 	)
 	{
 		eh := errorHandler(logger)
 		versionServer = versionsvr.New(endpoints.version, mux, dec, enc, eh, nil)
+		authServer = authsvr.New(endpoints.auth, mux, dec, enc, eh, nil)
 		applicationServer = applicationsvr.New(endpoints.application, mux, dec, enc, eh, nil)
 		runnableServer = runnablesvr.New(endpoints.runnable, mux, dec, enc, eh, nil)
 		codesetServer = codesetsvr.New(endpoints.codeset, mux, dec, enc, eh, nil)
 		projectServer = projectsvr.New(endpoints.project, mux, dec, enc, eh, nil)
 		workflowServer = workflowsvr.New(endpoints.workflow, mux, dec, enc, eh, nil)
 		extensionServer = extensionsvr.New(endpoints.extension, mux, dec, enc, eh, nil)
+		auditServer = auditsvr.New(endpoints.audit, mux, dec, enc, eh, nil)
 		openapiServer = openapisvr.New(nil, mux, dec, enc, eh, nil, nil, nil, nil, nil)
 		if debug {
 			servers := goahttp.Servers{
 				versionServer,
+				authServer,
 				applicationServer,
 				runnableServer,
 				codesetServer,
@@ -87,12 +107,14 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 				openapiServer,
 				workflowServer,
 				extensionServer,
+				auditServer,
 			}
 			servers.Use(httpmdlwr.Debug(mux, os.Stdout))
 		}
 	}
 	// Configure the mux.
 	versionsvr.Mount(mux, versionServer)
+	authsvr.Mount(mux, authServer)
 	applicationsvr.Mount(mux, applicationServer)
 	runnablesvr.Mount(mux, runnableServer)
 	codesetsvr.Mount(mux, codesetServer)
@@ -100,42 +122,62 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 	openapisvr.Mount(mux, openapiServer)
 	workflowsvr.Mount(mux, workflowServer)
 	extensionsvr.Mount(mux, extensionServer)
+	auditsvr.Mount(mux, auditServer)
+	mux.Handle(http.MethodGet, "/metrics", m.Handler().ServeHTTP)
+	mux.Handle(http.MethodGet, "/healthz", health.HealthzHandler)
+	mux.Handle(http.MethodGet, "/readyz", readiness.ReadyzHandler)
 
 	// Wrap the multiplexer with additional middlewares. Middlewares mounted
 	// here apply to all the service endpoints.
 	var handler http.Handler = mux
 	{
+		handler = m.HTTPMiddleware(handler)
 		handler = httpmdlwr.Log(adapter)(handler)
 		handler = httpmdlwr.RequestID()(handler)
+		handler = tracing.HTTPMiddleware(handler)
+	}
+
+	var tlsConfig *tls.Config
+	if tlsReloader != nil {
+		tlsConfig = tlsReloader.HTTPConfig()
+		handler = tlsReloader.RequireClientCert(handler, "/metrics", "/healthz", "/readyz")
 	}
 
 	// Start HTTP server using default configuration, change the code to
 	// configure the server as required by your service.
-	srv := &http.Server{Addr: u.Host, Handler: handler}
+	srv := &http.Server{Addr: u.Host, Handler: handler, TLSConfig: tlsConfig}
 	for _, m := range versionServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+	}
+	for _, m := range authServer.Mounts {
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range applicationServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range runnableServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range codesetServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range projectServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range openapiServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range workflowServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
 	for _, m := range extensionServer.Mounts {
-		logger.Printf("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
+	}
+	for _, m := range auditServer.Mounts {
+		logger.Infof("HTTP %q mounted on %s %s", m.Method, m.Verb, m.Pattern)
 	}
+	logger.Info("HTTP metrics mounted on GET /metrics")
+	logger.Info("HTTP health checks mounted on GET /healthz and GET /readyz")
 
 	(*wg).Add(1)
 	go func() {
@@ -143,12 +185,17 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 
 		// Start HTTP server in a separate goroutine.
 		go func() {
-			logger.Printf("HTTP server listening on %q", u.Host)
+			logger.Infof("HTTP server listening on %q", u.Host)
+			if tlsConfig != nil {
+				// the certificates are provided by the TLS configuration
+				errc <- srv.ListenAndServeTLS("", "")
+				return
+			}
 			errc <- srv.ListenAndServe()
 		}()
 
 		<-ctx.Done()
-		logger.Printf("shutting down HTTP server at %q", u.Host)
+		logger.Infof("shutting down HTTP server at %q", u.Host)
 
 		// Shutdown gracefully with a 30s timeout.
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -161,11 +208,11 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 // errorHandler returns a function that writes and logs the given error.
 // The function also writes and logs the error unique ID so that it's possible
 // to correlate.
-func errorHandler(logger *log.Logger) func(context.Context, http.ResponseWriter, error) {
+func errorHandler(logger *zap.SugaredLogger) func(context.Context, http.ResponseWriter, error) {
 	return func(ctx context.Context, w http.ResponseWriter, err error) {
 		id := ctx.Value(middleware.RequestIDKey).(string)
 		_, _ = w.Write([]byte("[" + id + "] encoding: " + err.Error()))
-		logger.Printf("[%s] ERROR: %s", id, err.Error())
+		logger.Errorw("failed to encode response", logging.RequestIDKey, id, logging.ErrorKey, err)
 	}
 }
 
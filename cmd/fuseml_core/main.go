@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"net"
 	"net/url"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/timshannon/badgerhold/v3"
+	"go.uber.org/zap"
+	goa "goa.design/goa/v3/pkg"
 
 	"github.com/fuseml/fuseml-core/gen/application"
+	"github.com/fuseml/fuseml-core/gen/audit"
+	"github.com/fuseml/fuseml-core/gen/auth"
 	"github.com/fuseml/fuseml-core/gen/codeset"
 	"github.com/fuseml/fuseml-core/gen/extension"
 	"github.com/fuseml/fuseml-core/gen/project"
@@ -22,15 +28,36 @@ import (
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/core/gitea"
+	"github.com/fuseml/fuseml-core/pkg/core/manager"
+	"github.com/fuseml/fuseml-core/pkg/core/tekton"
+	"github.com/fuseml/fuseml-core/pkg/health"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/svc"
+	"github.com/fuseml/fuseml-core/pkg/tlsconfig"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 	ver "github.com/fuseml/fuseml-core/pkg/version"
 )
 
+// tracingShutdownTimeout is the time allowed to export the pending spans when the server stops
+const tracingShutdownTimeout = 5 * time.Second
+
 type coreInit struct {
-	endpoints *endpoints
-	store     *badgerhold.Store
+	endpoints             *endpoints
+	store                 *badgerhold.Store
+	applicationReconciler *manager.ApplicationReconciler
+	codesetPoller         *manager.CodesetPoller
+	assignmentReconciler  *manager.AssignmentReconciler
+	runAdmitter           *manager.RunAdmitter
+	auditor               *svc.Auditor
+	metrics               *metrics.Metrics
+	domainCollector       *metrics.DomainCollector
+	readiness             *health.Checker
 }
 
 type endpoints struct {
+	auth        *auth.Endpoints
 	application *application.Endpoints
 	codeset     *codeset.Endpoints
 	project     *project.Endpoints
@@ -38,41 +65,117 @@ type endpoints struct {
 	version     *version.Endpoints
 	workflow    *workflow.Endpoints
 	extension   *extension.Endpoints
+	audit       *audit.Endpoints
+}
+
+// use applies a middleware to the endpoints of the services managing the FuseML resources.
+func (e *endpoints) use(m func(goa.Endpoint) goa.Endpoint) {
+	e.application.Use(m)
+	e.codeset.Use(m)
+	e.project.Use(m)
+	e.runnable.Use(m)
+	e.workflow.Use(m)
+	e.extension.Use(m)
+}
+
+// useAll applies a middleware to the endpoints of all the services.
+func (e *endpoints) useAll(m func(goa.Endpoint) goa.Endpoint) {
+	e.use(m)
+	e.auth.Use(m)
+	e.version.Use(m)
+	e.audit.Use(m)
+}
+
+// newStoreOptions returns the options used to open the store
+func newStoreOptions(cfg config.StoreConfig) badgerhold.Options {
+	options := badgerhold.DefaultOptions
+	options.Dir = cfg.Dir
+	options.ValueDir = cfg.Dir
+	return options
+}
+
+// newReadinessChecker returns the checker of the dependencies that must be available for the
+// server to handle requests
+func newReadinessChecker(store *badgerhold.Store, giteaClient *gitea.AdminClient, backend *tekton.WorkflowBackend) *health.Checker {
+	checker := health.NewChecker(health.DefaultCheckTimeout)
+	checker.Add("store", func(context.Context) error {
+		if store.Badger().IsClosed() {
+			return errors.New("badger store is closed")
+		}
+		return nil
+	})
+	checker.Add("gitea", giteaClient.CheckReadiness)
+	checker.Add("tekton", backend.CheckReadiness)
+	return checker
 }
 
 func main() {
-	// Define command line flags, add any other flag required to configure the
-	// service.
-	var (
-		hostF     = flag.String("host", "dev", "Server host (valid values: dev, prod)")
-		domainF   = flag.String("domain", "", "Host domain name (overrides host domain specified in service design)")
-		httpPortF = flag.String("http-port", "", "HTTP port (overrides host HTTP port specified in service design)")
-		grpcPortF = flag.String("grpc-port", "", "gRPC port (overrides host gRPC port specified in service design)")
-		secureF   = flag.Bool("secure", false, "Use secure scheme (https or grpcs)")
-		dbgF      = flag.Bool("debug", false, "Log request and response bodies")
-	)
-	flag.Parse()
+	// Load the configuration from the configuration file, the environment and the command line.
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, "Failed to load the fuseml-core configuration:", err.Error())
+		os.Exit(2)
+	}
 
-	// Setup logger. Replace logger with your own log package of choice.
+	// Setup the structured logger.
 	var (
-		logger *log.Logger
+		logger *zap.SugaredLogger
 	)
 	{
-		logger = log.New(os.Stderr, "[fuseml] ", log.Ltime)
+		logger, err = logging.New(os.Stderr, cfg.Server.LogFormat, cfg.Server.LogLevel)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to initialize the logger:", err.Error())
+			os.Exit(2)
+		}
+		defer func() { _ = logger.Sync() }()
 	}
 
-	logger.Printf("version: %s", ver.GetInfoStr())
+	logger.Infof("version: %s", ver.GetInfoStr())
+
+	// Setup the export of the traces.
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, ver.GetInfo().Version, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to initialize the tracing: ", err.Error())
+		os.Exit(1)
+	}
 
-	storeOptions := badgerhold.DefaultOptions
-	storeOptions.Dir = "./data"
-	storeOptions.ValueDir = storeOptions.Dir
+	// Load the TLS certificates. The servers use the secure schemes when TLS is configured.
+	var (
+		tlsReloader *tlsconfig.Reloader
+		tlsConfig   *tls.Config
+	)
+	if cfg.Server.Secure || cfg.Server.TLS.Enabled() {
+		tlsReloader, err = tlsconfig.NewReloader(logger, cfg.Server.TLS)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load the TLS certificates: ", err.Error())
+			os.Exit(1)
+		}
+		tlsConfig = tlsReloader.Config()
+		cfg.Server.Secure = true
+	}
 
-	coreInit, err := InitializeCore(logger, storeOptions, config.FuseMLNamespace)
+	coreInit, err := InitializeCore(logger, cfg)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to initialize fuseml-core: ", err.Error())
 		os.Exit(1)
 	}
 
+	// Record the operations changing the FuseML resources in the audit log.
+	coreInit.endpoints.use(coreInit.auditor.Middleware)
+
+	// Expose the API and domain metrics.
+	coreInit.endpoints.useAll(coreInit.metrics.EndpointMiddleware)
+	if err := coreInit.metrics.Register(coreInit.domainCollector); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to register the domain metrics: ", err.Error())
+		os.Exit(1)
+	}
+
+	// Name the spans of the API requests after the service methods.
+	coreInit.endpoints.useAll(tracing.EndpointMiddleware)
+
 	// Create channel used by both the signal handler and server goroutines
 	// to notify the main goroutine when to stop the server.
 	errc := make(chan error)
@@ -88,8 +191,54 @@ func main() {
 	var wg sync.WaitGroup
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// Start checking the status of the registered applications in the background.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		coreInit.applicationReconciler.Run(ctx)
+	}()
+
+	// Start polling the external repositories tracked by codesets for new commits in the background.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		coreInit.codesetPoller.Run(ctx)
+	}()
+
+	// Restore the codeset subscriptions of the workflow assignments and remove the assignments to deleted
+	// codesets in the background.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		coreInit.assignmentReconciler.Run(ctx)
+	}()
+
+	// Start the workflow runs triggered by the codeset webhooks, within the quotas of the projects, in the
+	// background.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		coreInit.runAdmitter.Run(ctx)
+	}()
+
+	// Remove the audit events older than the retention period in the background.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		coreInit.auditor.Run(ctx)
+	}()
+
+	// Reload the TLS certificates when they are renewed.
+	if tlsReloader != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tlsReloader.Run(ctx, tlsconfig.DefaultReloadInterval)
+		}()
+	}
+
 	// Start the servers and send errors (if any) to the error channel.
-	switch *hostF {
+	switch cfg.Server.Host {
 	case "dev":
 		{
 			addr := "http://localhost:8000"
@@ -98,23 +247,23 @@ func main() {
 				fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", addr, err)
 				os.Exit(1)
 			}
-			if *secureF {
+			if cfg.Server.Secure {
 				u.Scheme = "https"
 			}
-			if *domainF != "" {
-				u.Host = *domainF
+			if cfg.Server.Domain != "" {
+				u.Host = cfg.Server.Domain
 			}
-			if *httpPortF != "" {
+			if cfg.Server.HTTPPort != "" {
 				h, _, err := net.SplitHostPort(u.Host)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", u.Host, err)
 					os.Exit(1)
 				}
-				u.Host = net.JoinHostPort(h, *httpPortF)
+				u.Host = net.JoinHostPort(h, cfg.Server.HTTPPort)
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "80")
 			}
-			handleHTTPServer(ctx, u, coreInit.endpoints, &wg, errc, logger, *dbgF)
+			handleHTTPServer(ctx, u, coreInit.endpoints, tlsReloader, coreInit.metrics, coreInit.readiness, &wg, errc, logger, cfg.Server.Debug)
 		}
 
 		{
@@ -124,23 +273,23 @@ func main() {
 				fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", addr, err)
 				os.Exit(1)
 			}
-			if *secureF {
+			if cfg.Server.Secure {
 				u.Scheme = "grpcs"
 			}
-			if *domainF != "" {
-				u.Host = *domainF
+			if cfg.Server.Domain != "" {
+				u.Host = cfg.Server.Domain
 			}
-			if *grpcPortF != "" {
+			if cfg.Server.GRPCPort != "" {
 				h, _, err := net.SplitHostPort(u.Host)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", u.Host, err)
 					os.Exit(1)
 				}
-				u.Host = net.JoinHostPort(h, *grpcPortF)
+				u.Host = net.JoinHostPort(h, cfg.Server.GRPCPort)
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "8080")
 			}
-			handleGRPCServer(ctx, u, coreInit.endpoints, &wg, errc, logger, *dbgF)
+			handleGRPCServer(ctx, u, coreInit.endpoints, tlsConfig, coreInit.metrics, &wg, errc, logger, cfg.Server.Debug)
 		}
 
 	case "prod":
@@ -151,23 +300,23 @@ func main() {
 				fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", addr, err)
 				os.Exit(1)
 			}
-			if *secureF {
+			if cfg.Server.Secure {
 				u.Scheme = "https"
 			}
-			if *domainF != "" {
-				u.Host = *domainF
+			if cfg.Server.Domain != "" {
+				u.Host = cfg.Server.Domain
 			}
-			if *httpPortF != "" {
+			if cfg.Server.HTTPPort != "" {
 				h, _, err := net.SplitHostPort(u.Host)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", u.Host, err)
 					os.Exit(1)
 				}
-				u.Host = net.JoinHostPort(h, *httpPortF)
+				u.Host = net.JoinHostPort(h, cfg.Server.HTTPPort)
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "80")
 			}
-			handleHTTPServer(ctx, u, coreInit.endpoints, &wg, errc, logger, *dbgF)
+			handleHTTPServer(ctx, u, coreInit.endpoints, tlsReloader, coreInit.metrics, coreInit.readiness, &wg, errc, logger, cfg.Server.Debug)
 		}
 
 		{
@@ -177,38 +326,45 @@ func main() {
 				fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", addr, err)
 				os.Exit(1)
 			}
-			if *secureF {
+			if cfg.Server.Secure {
 				u.Scheme = "grpcs"
 			}
-			if *domainF != "" {
-				u.Host = *domainF
+			if cfg.Server.Domain != "" {
+				u.Host = cfg.Server.Domain
 			}
-			if *grpcPortF != "" {
+			if cfg.Server.GRPCPort != "" {
 				h, _, err := net.SplitHostPort(u.Host)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "invalid URL %#v: %s\n", u.Host, err)
 					os.Exit(1)
 				}
-				u.Host = net.JoinHostPort(h, *grpcPortF)
+				u.Host = net.JoinHostPort(h, cfg.Server.GRPCPort)
 			} else if u.Port() == "" {
 				u.Host = net.JoinHostPort(u.Host, "8080")
 			}
-			handleGRPCServer(ctx, u, coreInit.endpoints, &wg, errc, logger, *dbgF)
+			handleGRPCServer(ctx, u, coreInit.endpoints, tlsConfig, coreInit.metrics, &wg, errc, logger, cfg.Server.Debug)
 		}
 
 	default:
-		fmt.Fprintf(os.Stderr, "invalid host argument: %q (valid hosts: dev|prod)\n", *hostF)
+		fmt.Fprintf(os.Stderr, "invalid host argument: %q (valid hosts: dev|prod)\n", cfg.Server.Host)
 	}
 
 	// Wait for signal.
-	logger.Printf("exiting (%v)", <-errc)
+	logger.Infof("exiting (%v)", <-errc)
 
 	// Send cancellation signal to the goroutines.
 	cancel()
 
-	// Closes the stores.
+	wg.Wait()
+
+	// Close the stores, once the background reconcilers writing to them have stopped.
 	coreInit.store.Close()
 
-	wg.Wait()
-	logger.Println("exited")
+	// Flush the pending spans.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer shutdownCancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Errorw("Failed to flush the traces", logging.ErrorKey, err)
+	}
+	logger.Info("exited")
 }
@@ -4,12 +4,13 @@
 package main
 
 import (
-	"log"
-
 	"github.com/google/wire"
 	"github.com/timshannon/badgerhold/v3"
+	"go.uber.org/zap"
 
 	"github.com/fuseml/fuseml-core/gen/application"
+	"github.com/fuseml/fuseml-core/gen/audit"
+	"github.com/fuseml/fuseml-core/gen/auth"
 	"github.com/fuseml/fuseml-core/gen/codeset"
 	"github.com/fuseml/fuseml-core/gen/extension"
 	"github.com/fuseml/fuseml-core/gen/project"
@@ -17,20 +18,35 @@ import (
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/core"
+	coreauth "github.com/fuseml/fuseml-core/pkg/core/auth"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/core/gitea"
+	"github.com/fuseml/fuseml-core/pkg/core/gitremote"
 	"github.com/fuseml/fuseml-core/pkg/core/manager"
 	"github.com/fuseml/fuseml-core/pkg/core/store/badger"
 	"github.com/fuseml/fuseml-core/pkg/core/tekton"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
 	"github.com/fuseml/fuseml-core/pkg/svc"
 )
 
+var configSet = wire.NewSet(
+	wire.FieldsOf(new(*config.Config), "Store", "Auth", "Gitea", "Tekton"),
+	newStoreOptions,
+)
+
 var storeSet = wire.NewSet(
 	badgerhold.Open,
 	badger.NewApplicationStore,
 	wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)),
 	gitea.NewAdminClient,
 	wire.Bind(new(domain.GitAdminClient), new(*gitea.AdminClient)),
+	wire.Bind(new(domain.UserVerifier), new(*gitea.AdminClient)),
+	badger.NewExternalCodesetStore,
+	wire.Bind(new(domain.ExternalCodesetStore), new(*badger.ExternalCodesetStore)),
+	gitremote.NewProvider,
+	wire.Bind(new(domain.CodesetProvider), new(*gitremote.Provider)),
 	core.NewGitCodesetStore,
 	wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)),
 	core.NewGitProjectStore,
@@ -39,6 +55,12 @@ var storeSet = wire.NewSet(
 	wire.Bind(new(domain.RunnableStore), new(*core.RunnableStore)),
 	badger.NewWorkflowStore,
 	wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)),
+	badger.NewProjectMemberStore,
+	wire.Bind(new(domain.ProjectMemberStore), new(*badger.ProjectMemberStore)),
+	badger.NewProjectQuotaStore,
+	wire.Bind(new(domain.ProjectQuotaStore), new(*badger.ProjectQuotaStore)),
+	badger.NewAuditStore,
+	wire.Bind(new(domain.AuditStore), new(*badger.AuditStore)),
 	core.NewExtensionStore,
 	wire.Bind(new(domain.ExtensionStore), new(*core.ExtensionStore)),
 )
@@ -48,14 +70,43 @@ var managerSet = wire.NewSet(
 	wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)),
 	manager.NewExtensionRegistry,
 	wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)),
+	manager.NewRunnableManager,
+	wire.Bind(new(domain.RunnableManager), new(*manager.RunnableManager)),
+	manager.NewApplicationReconciler,
+	manager.NewCodesetPoller,
+	manager.NewAssignmentReconciler,
+	manager.NewQuotaManager,
+	manager.NewRunAdmitter,
+	wire.Bind(new(domain.ProjectQuotaManager), new(*manager.QuotaManager)),
+	manager.NewProjectManager,
+	wire.Bind(new(domain.ProjectManager), new(*manager.ProjectManager)),
 )
 
 var backendSet = wire.NewSet(
 	tekton.NewWorkflowBackend,
 	wire.Bind(new(domain.WorkflowBackend), new(*tekton.WorkflowBackend)),
+	wire.Bind(new(domain.RunnableBuilder), new(*tekton.WorkflowBackend)),
+	wire.Bind(new(domain.CodesetCredentialsStore), new(*tekton.WorkflowBackend)),
+	kubernetes.NewCluster,
+	wire.Bind(new(domain.KubernetesResourceInspector), new(*kubernetes.Cluster)),
+	wire.Bind(new(domain.KubernetesResourceRemover), new(*kubernetes.Cluster)),
+)
+
+var authSet = wire.NewSet(
+	coreauth.NewAuthenticator,
+	wire.Bind(new(domain.Authenticator), new(*coreauth.Authenticator)),
+	wire.Bind(new(domain.WorkflowKeyIssuer), new(*coreauth.Authenticator)),
+	svc.NewAuditor,
+)
+
+var metricsSet = wire.NewSet(
+	metrics.New,
+	metrics.NewDomainCollector,
 )
 
 var endpointsSet = wire.NewSet(
+	svc.NewAuthService,
+	auth.NewEndpoints,
 	svc.NewApplicationService,
 	application.NewEndpoints,
 	svc.NewCodesetService,
@@ -70,14 +121,20 @@ var endpointsSet = wire.NewSet(
 	workflow.NewEndpoints,
 	svc.NewExtensionRegistryService,
 	extension.NewEndpoints,
+	svc.NewAuditService,
+	audit.NewEndpoints,
 )
 
-func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLNamespace string) (*coreInit, error) {
+func InitializeCore(logger *zap.SugaredLogger, cfg *config.Config) (*coreInit, error) {
 	wire.Build(
+		configSet,
 		storeSet,
 		managerSet,
 		backendSet,
+		authSet,
+		metricsSet,
 		endpointsSet,
+		newReadinessChecker,
 		wire.Struct(new(endpoints), "*"),
 		wire.Struct(new(coreInit), "*"),
 	)
@@ -7,6 +7,8 @@ package main
 
 import (
 	"github.com/fuseml/fuseml-core/gen/application"
+	"github.com/fuseml/fuseml-core/gen/audit"
+	"github.com/fuseml/fuseml-core/gen/auth"
 	"github.com/fuseml/fuseml-core/gen/codeset"
 	"github.com/fuseml/fuseml-core/gen/extension"
 	"github.com/fuseml/fuseml-core/gen/project"
@@ -14,55 +16,87 @@ import (
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/core"
+	auth2 "github.com/fuseml/fuseml-core/pkg/core/auth"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/core/gitea"
+	"github.com/fuseml/fuseml-core/pkg/core/gitremote"
 	"github.com/fuseml/fuseml-core/pkg/core/manager"
 	"github.com/fuseml/fuseml-core/pkg/core/store/badger"
 	"github.com/fuseml/fuseml-core/pkg/core/tekton"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
 	"github.com/fuseml/fuseml-core/pkg/svc"
 	"github.com/google/wire"
 	"github.com/timshannon/badgerhold/v3"
-	"log"
+	"go.uber.org/zap"
 )
 
 // Injectors from wire.go:
 
-func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLNamespace string) (*coreInit, error) {
-	store, err := badgerhold.Open(storeOptions)
+func InitializeCore(logger *zap.SugaredLogger, cfg *config.Config) (*coreInit, error) {
+	storeConfig := cfg.Store
+	options := newStoreOptions(storeConfig)
+	store, err := badgerhold.Open(options)
 	if err != nil {
 		return nil, err
 	}
+	giteaConfig := cfg.Gitea
+	metricsMetrics := metrics.New()
+	adminClient, err := gitea.NewAdminClient(logger, giteaConfig, metricsMetrics)
+	if err != nil {
+		return nil, err
+	}
+	workflowStore := badger.NewWorkflowStore(store)
+	authConfig := cfg.Auth
+	authenticator, err := auth2.NewAuthenticator(logger, adminClient, workflowStore, authConfig)
+	if err != nil {
+		return nil, err
+	}
+	service := svc.NewAuthService(logger, authenticator)
+	authEndpoints := auth.NewEndpoints(service)
 	applicationStore := badger.NewApplicationStore(store)
-	service := svc.NewApplicationService(logger, applicationStore)
-	applicationEndpoints := application.NewEndpoints(service)
-	adminClient, err := gitea.NewAdminClient(logger)
+	projectMemberStore := badger.NewProjectMemberStore(store)
+	tektonConfig := cfg.Tekton
+	applicationService := svc.NewApplicationService(logger, applicationStore, tektonConfig, authenticator, projectMemberStore)
+	applicationEndpoints := application.NewEndpoints(applicationService)
+	externalCodesetStore := badger.NewExternalCodesetStore(store)
+	workflowBackend, err := tekton.NewWorkflowBackend(logger, tektonConfig, metricsMetrics, authenticator)
 	if err != nil {
 		return nil, err
 	}
-	gitCodesetStore := core.NewGitCodesetStore(adminClient)
-	codesetService := svc.NewCodesetService(logger, gitCodesetStore)
-	codesetEndpoints := codeset.NewEndpoints(codesetService)
+	provider := gitremote.NewProvider(logger)
+	gitCodesetStore := core.NewGitCodesetStore(adminClient, externalCodesetStore, workflowBackend, provider)
+	projectQuotaStore := badger.NewProjectQuotaStore(store)
+	quotaManager := manager.NewQuotaManager(projectQuotaStore, gitCodesetStore, workflowStore, workflowBackend, tektonConfig)
 	gitProjectStore := core.NewGitProjectStore(adminClient)
-	projectService := svc.NewProjectService(logger, gitProjectStore)
+	codesetService := svc.NewCodesetService(logger, gitCodesetStore, authenticator, projectMemberStore, quotaManager, gitProjectStore)
+	codesetEndpoints := codeset.NewEndpoints(codesetService)
+	extensionStore := core.NewExtensionStore()
+	extensionRegistry := manager.NewExtensionRegistry(extensionStore)
+	workflowManager := manager.NewWorkflowManager(logger, workflowBackend, workflowStore, gitCodesetStore, extensionRegistry, quotaManager)
+	cluster, err := kubernetes.NewCluster(logger)
+	if err != nil {
+		return nil, err
+	}
+	projectManager := manager.NewProjectManager(logger, gitProjectStore, gitCodesetStore, workflowManager, workflowBackend, applicationStore, cluster, projectMemberStore, quotaManager, tektonConfig)
+	projectService := svc.NewProjectService(logger, gitProjectStore, authenticator, projectMemberStore, quotaManager, projectManager)
 	projectEndpoints := project.NewEndpoints(projectService)
 	runnableStore := core.NewRunnableStore()
-	runnableService := svc.NewRunnableService(logger, runnableStore)
+	runnableManager := manager.NewRunnableManager(logger, workflowBackend, runnableStore, gitCodesetStore)
+	runnableService := svc.NewRunnableService(logger, runnableStore, runnableManager, authenticator, projectMemberStore)
 	runnableEndpoints := runnable.NewEndpoints(runnableService)
 	versionService := svc.NewVersionService(logger)
 	versionEndpoints := version.NewEndpoints(versionService)
-	workflowBackend, err := tekton.NewWorkflowBackend(logger, fuseMLNamespace)
-	if err != nil {
-		return nil, err
-	}
-	workflowStore := badger.NewWorkflowStore(store)
-	extensionStore := core.NewExtensionStore()
-	extensionRegistry := manager.NewExtensionRegistry(extensionStore)
-	workflowManager := manager.NewWorkflowManager(workflowBackend, workflowStore, gitCodesetStore, extensionRegistry)
-	workflowService := svc.NewWorkflowService(logger, workflowManager)
+	workflowService := svc.NewWorkflowService(logger, workflowManager, authenticator, projectMemberStore)
 	workflowEndpoints := workflow.NewEndpoints(workflowService)
-	extensionService := svc.NewExtensionRegistryService(logger, extensionRegistry)
+	extensionService := svc.NewExtensionRegistryService(logger, extensionRegistry, authenticator, projectMemberStore)
 	extensionEndpoints := extension.NewEndpoints(extensionService)
+	auditStore := badger.NewAuditStore(store)
+	auditService := svc.NewAuditService(logger, auditStore, authenticator, projectMemberStore)
+	auditEndpoints := audit.NewEndpoints(auditService)
 	mainEndpoints := &endpoints{
+		auth:        authEndpoints,
 		application: applicationEndpoints,
 		codeset:     codesetEndpoints,
 		project:     projectEndpoints,
@@ -70,20 +104,42 @@ func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLN
 		version:     versionEndpoints,
 		workflow:    workflowEndpoints,
 		extension:   extensionEndpoints,
+		audit:       auditEndpoints,
 	}
+	applicationReconciler := manager.NewApplicationReconciler(logger, applicationStore, cluster)
+	codesetPoller := manager.NewCodesetPoller(logger, externalCodesetStore, workflowBackend, provider, workflowStore, workflowBackend, quotaManager)
+	assignmentReconciler := manager.NewAssignmentReconciler(logger, workflowManager)
+	runAdmitter := manager.NewRunAdmitter(logger, quotaManager, workflowStore, workflowBackend)
+	auditor := svc.NewAuditor(logger, auditStore, storeConfig)
+	domainCollector := metrics.NewDomainCollector(logger, gitCodesetStore, workflowManager, extensionRegistry, store)
+	checker := newReadinessChecker(store, adminClient, workflowBackend)
 	mainCoreInit := &coreInit{
-		endpoints: mainEndpoints,
-		store:     store,
+		endpoints:             mainEndpoints,
+		store:                 store,
+		applicationReconciler: applicationReconciler,
+		codesetPoller:         codesetPoller,
+		assignmentReconciler:  assignmentReconciler,
+		runAdmitter:           runAdmitter,
+		auditor:               auditor,
+		metrics:               metricsMetrics,
+		domainCollector:       domainCollector,
+		readiness:             checker,
 	}
 	return mainCoreInit, nil
 }
 
 // wire.go:
 
-var storeSet = wire.NewSet(badgerhold.Open, badger.NewApplicationStore, wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)), gitea.NewAdminClient, wire.Bind(new(domain.GitAdminClient), new(*gitea.AdminClient)), core.NewGitCodesetStore, wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)), core.NewGitProjectStore, wire.Bind(new(domain.ProjectStore), new(*core.GitProjectStore)), core.NewRunnableStore, wire.Bind(new(domain.RunnableStore), new(*core.RunnableStore)), badger.NewWorkflowStore, wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)), core.NewExtensionStore, wire.Bind(new(domain.ExtensionStore), new(*core.ExtensionStore)))
+var configSet = wire.NewSet(wire.FieldsOf(new(*config.Config), "Store", "Auth", "Gitea", "Tekton"), newStoreOptions)
+
+var storeSet = wire.NewSet(badgerhold.Open, badger.NewApplicationStore, wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)), gitea.NewAdminClient, wire.Bind(new(domain.GitAdminClient), new(*gitea.AdminClient)), wire.Bind(new(domain.UserVerifier), new(*gitea.AdminClient)), badger.NewExternalCodesetStore, wire.Bind(new(domain.ExternalCodesetStore), new(*badger.ExternalCodesetStore)), gitremote.NewProvider, wire.Bind(new(domain.CodesetProvider), new(*gitremote.Provider)), core.NewGitCodesetStore, wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)), core.NewGitProjectStore, wire.Bind(new(domain.ProjectStore), new(*core.GitProjectStore)), core.NewRunnableStore, wire.Bind(new(domain.RunnableStore), new(*core.RunnableStore)), badger.NewWorkflowStore, wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)), badger.NewProjectMemberStore, wire.Bind(new(domain.ProjectMemberStore), new(*badger.ProjectMemberStore)), badger.NewProjectQuotaStore, wire.Bind(new(domain.ProjectQuotaStore), new(*badger.ProjectQuotaStore)), badger.NewAuditStore, wire.Bind(new(domain.AuditStore), new(*badger.AuditStore)), core.NewExtensionStore, wire.Bind(new(domain.ExtensionStore), new(*core.ExtensionStore)))
+
+var managerSet = wire.NewSet(manager.NewWorkflowManager, wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)), manager.NewExtensionRegistry, wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)), manager.NewRunnableManager, wire.Bind(new(domain.RunnableManager), new(*manager.RunnableManager)), manager.NewApplicationReconciler, manager.NewCodesetPoller, manager.NewAssignmentReconciler, manager.NewQuotaManager, manager.NewRunAdmitter, wire.Bind(new(domain.ProjectQuotaManager), new(*manager.QuotaManager)), manager.NewProjectManager, wire.Bind(new(domain.ProjectManager), new(*manager.ProjectManager)))
+
+var backendSet = wire.NewSet(tekton.NewWorkflowBackend, wire.Bind(new(domain.WorkflowBackend), new(*tekton.WorkflowBackend)), wire.Bind(new(domain.RunnableBuilder), new(*tekton.WorkflowBackend)), wire.Bind(new(domain.CodesetCredentialsStore), new(*tekton.WorkflowBackend)), kubernetes.NewCluster, wire.Bind(new(domain.KubernetesResourceInspector), new(*kubernetes.Cluster)), wire.Bind(new(domain.KubernetesResourceRemover), new(*kubernetes.Cluster)))
 
-var managerSet = wire.NewSet(manager.NewWorkflowManager, wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)), manager.NewExtensionRegistry, wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)))
+var authSet = wire.NewSet(auth2.NewAuthenticator, wire.Bind(new(domain.Authenticator), new(*auth2.Authenticator)), wire.Bind(new(domain.WorkflowKeyIssuer), new(*auth2.Authenticator)), svc.NewAuditor)
 
-var backendSet = wire.NewSet(tekton.NewWorkflowBackend, wire.Bind(new(domain.WorkflowBackend), new(*tekton.WorkflowBackend)))
+var metricsSet = wire.NewSet(metrics.New, metrics.NewDomainCollector)
 
-var endpointsSet = wire.NewSet(svc.NewApplicationService, application.NewEndpoints, svc.NewCodesetService, codeset.NewEndpoints, svc.NewProjectService, project.NewEndpoints, svc.NewRunnableService, runnable.NewEndpoints, svc.NewVersionService, version.NewEndpoints, svc.NewWorkflowService, workflow.NewEndpoints, svc.NewExtensionRegistryService, extension.NewEndpoints)
+var endpointsSet = wire.NewSet(svc.NewAuthService, auth.NewEndpoints, svc.NewApplicationService, application.NewEndpoints, svc.NewCodesetService, codeset.NewEndpoints, svc.NewProjectService, project.NewEndpoints, svc.NewRunnableService, runnable.NewEndpoints, svc.NewVersionService, version.NewEndpoints, svc.NewWorkflowService, workflow.NewEndpoints, svc.NewExtensionRegistryService, extension.NewEndpoints, svc.NewAuditService, audit.NewEndpoints)
@@ -15,7 +15,7 @@ var _ = API("fuseml", func() {
 		Description("fuseml-core hosts the core services")
 
 		// List the services hosted by this server.
-		Services("application", "runnable", "codeset", "project", "workflow", "openapi")
+		Services("application", "runnable", "codeset", "project", "workflow", "auth", "audit", "openapi")
 
 		// List the Hosts and their transport URLs.
 		Host("dev", func() {
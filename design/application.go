@@ -7,18 +7,32 @@ import (
 var _ = Service("application", func() {
 	Description("The aplication service performs operations on Applications.")
 
+	secured()
+
 	Method("list", func() {
 		Description("Retrieve information about Applications registered in FuseML.")
 		Payload(func() {
+			credentials()
 			Field(1, "type", String, "List only Applications of given type", func() {
 				Example("predictor")
 			})
 			Field(2, "workflow", String, "List only Applications generated by given workflow", func() {
 				Example("mlflow-sklearn-e2e")
 			})
+			Field(3, "codesetProject", String, "List only Applications generated from codesets belonging to given project", func() {
+				Example("mlflow-project-01")
+			})
+			Field(4, "codesetName", String, "List only Applications generated from codesets with given name", func() {
+				Example("mlflow-app-01")
+			})
+			listFields(5, "name", "type", "workflow")
 		})
 
-		Result(ArrayOf(Application), "Return all registered Applications matching the query.")
+		Result(ApplicationPage, "Return the registered Applications matching the query.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		Error("NotFound", func() {
 			Description("If the Application is not found, should return 404 Not Found.")
@@ -26,16 +40,23 @@ var _ = Service("application", func() {
 
 		HTTP(func() {
 			GET("/applications")
+			credentialsHTTP()
 			Param("type")
 			Param("workflow")
-			Response(StatusOK)
+			Param("codesetProject")
+			Param("codesetName")
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			// Responses use a "OK" gRPC code.
 			// The result is encoded in the response message (default).
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
 		})
 
@@ -44,22 +65,75 @@ var _ = Service("application", func() {
 	Method("register", func() {
 		Description("Register an Application with the FuseML application store.")
 
-		Payload(Application)
+		Payload(func() {
+			credentials()
+			Field(1, "application", Application, "The Application to register")
+			Field(2, "replace", Boolean, "Replace the Application if one with the same name is already registered", func() {
+				Default(false)
+			})
+			Required("application")
+		})
 
 		Error("BadRequest", func() {
-			Description("If the Application does not have the required fields, should return 400 Bad Request.")
+			Description("If the Application does not have the required fields, or its resources are not located in the FuseML workloads namespace, should return 400 Bad Request.")
+		})
+		Error("Conflict", func() {
+			Description("If an Application with the same name already exists and replace is not set, should return 409 Conflict.")
 		})
 
 		Result(Application)
 
 		HTTP(func() {
 			POST("/applications")
+			credentialsHTTP()
+			Body("application")
+			Param("replace")
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
+			Response("Conflict", StatusConflict)
+		})
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("Conflict", CodeAlreadyExists)
+		})
+	})
+
+	Method("update", func() {
+		Description("Update an Application registered by FuseML. The version of the Application must match the version of the registered Application.")
+
+		Payload(func() {
+			Extend(Application)
+			credentials()
+		})
+
+		Error("BadRequest", func() {
+			Description("If the Application does not have the required fields, or its resources are not located in the FuseML workloads namespace, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no application with the given name, should return 404 Not Found.")
+		})
+		Error("Conflict", func() {
+			Description("If the Application was modified since it was retrieved, should return 409 Conflict.")
+		})
+
+		Result(Application)
+
+		HTTP(func() {
+			PUT("/applications/{name}")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+			Response("Conflict", StatusConflict)
 		})
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+			Response("Conflict", CodeAborted)
 		})
 	})
 
@@ -67,6 +141,7 @@ var _ = Service("application", func() {
 		Description("Retrieve an Application registered by FuseML.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Application name", func() {
 				Example("mlflow-seldon-predictor-01")
 			})
@@ -84,12 +159,14 @@ var _ = Service("application", func() {
 
 		HTTP(func() {
 			GET("/applications/{name}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -100,6 +177,7 @@ var _ = Service("application", func() {
 		Description("Delete an Application registered by FuseML application store.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Application name", func() {
 				Example("mlflow-seldon-predictor-01")
 			})
@@ -112,10 +190,12 @@ var _ = Service("application", func() {
 
 		HTTP(func() {
 			DELETE("/applications/{name}/")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("BadRequest", StatusBadRequest)
 		})
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 		})
@@ -142,9 +222,25 @@ var Application = Type("Application", func() {
 		Example("mlflow-seldon-e2e")
 	})
 	Field(6, "k8s_resources", ArrayOf(KubernetesResource), "Kubernetes resources describing the Application")
-	Field(7, "k8s_namespace", String, "Kubernetes namespace where the resources are located", func() {
+	Field(7, "k8s_namespace", String, "Kubernetes namespace where the resources are located, which must be the FuseML workloads namespace", func() {
 		Example("fuseml-workloads")
 	})
+	Field(8, "status", ApplicationStatus, "The status of the Application, as last checked by FuseML")
+	Field(9, "workflowRun", String, "Name of the Workflow run that created the Application", func() {
+		Example("fuseml-mlflow-project-01-mlflow-app-01-mlflow-seldon-e2e-z6fxn")
+	})
+	Field(10, "codeset", ApplicationCodeset, "The codeset used by the Workflow run that created the Application")
+	Field(11, "codesetVersion", String, "The codeset version used by the Workflow run that created the Application", func() {
+		Example("8a1d2b6c1f7e3bd2a5e1f7b0a2c6e1d9c9b8a7f6")
+	})
+	Field(12, "createdAt", String, "The time when the Application was registered", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Field(13, "version", UInt64, "The version of the Application, incremented with every update. Must match the registered version when updating the Application", func() {
+		Example(1)
+	})
+	Field(14, "replaced", ApplicationReplacement, "Information about the last time the Application was replaced by a new registration")
 
 	Required("name", "type", "url", "workflow", "k8s_namespace")
 })
@@ -159,3 +255,57 @@ var KubernetesResource = Type("KubernetesResource", func() {
 	})
 	Required("name", "kind")
 })
+
+// ApplicationCodeset references the codeset used to create the Application
+var ApplicationCodeset = Type("ApplicationCodeset", func() {
+	Field(1, "project", String, "The project the codeset belongs to", func() {
+		Example("mlflow-project-01")
+	})
+	Field(2, "name", String, "The name of the codeset", func() {
+		Example("mlflow-app-01")
+	})
+	Required("project", "name")
+})
+
+// ApplicationReplacement describes who replaced a registered Application and what was replaced
+var ApplicationReplacement = Type("ApplicationReplacement", func() {
+	Field(1, "replacedAt", String, "The time when the Application was replaced", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Field(2, "replacedBy", String, "The Workflow run (or Workflow) that replaced the Application", func() {
+		Example("fuseml-mlflow-project-01-mlflow-app-01-mlflow-seldon-e2e-z6fxn")
+	})
+	Field(3, "previousVersion", UInt64, "The version of the replaced Application", func() {
+		Example(1)
+	})
+	Field(4, "previousWorkflow", String, "Name of the Workflow used to create the replaced Application", func() {
+		Example("mlflow-seldon-e2e")
+	})
+	Field(5, "previousWorkflowRun", String, "Name of the Workflow run that created the replaced Application", func() {
+		Example("fuseml-mlflow-project-01-mlflow-app-01-mlflow-seldon-e2e-8kf2d")
+	})
+	Field(6, "previousCodesetVersion", String, "The codeset version used to create the replaced Application", func() {
+		Example("5c7fbd6a3c3e1f1d8a1c2b9e0f4a6d3e2b1c0a9f")
+	})
+	Required("replacedAt", "replacedBy", "previousVersion", "previousWorkflow")
+})
+
+// ApplicationStatus describes the health of the Application
+var ApplicationStatus = Type("ApplicationStatus", func() {
+	Field(1, "state", String, "The state of the Application", func() {
+		Enum("ready", "degraded", "missing")
+		Example("ready")
+	})
+	Field(2, "lastChecked", String, "The time when the Application status was last checked", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Field(3, "message", String, "Message describing why the Application is not ready", func() {
+		Example("Deployment mlflow-seldon-predictor-01 is not ready: Deployment does not have minimum availability.")
+	})
+	Required("state", "lastChecked")
+})
+
+// ApplicationPage is a page of the applications returned by the list method
+var ApplicationPage = pageOf("ApplicationPage", Application)
@@ -0,0 +1,102 @@
+package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var _ = Service("audit", func() {
+	Description("The audit service lists the operations that changed the resources managed by FuseML.")
+
+	secured()
+
+	Method("list", func() {
+		Description("List the audit events recorded for the create, update and delete operations.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "since", String, "List only events recorded at or after this time", func() {
+				Format(FormatDateTime)
+				Example("2021-04-09T06:17:25Z")
+			})
+			Field(2, "until", String, "List only events recorded before this time", func() {
+				Format(FormatDateTime)
+				Example("2021-04-09T18:17:25Z")
+			})
+			Field(3, "service", String, "List only events recorded for operations performed through this service", func() {
+				Example("codeset")
+			})
+			Field(4, "resource", String, "List only events recorded for operations targeting this resource", func() {
+				Example("mlflow-app-01")
+			})
+			Field(5, "project", String, "List only events recorded for operations targeting the resources of this project", func() {
+				Example("mlflow-project-01")
+			})
+			listFields(6, "timestamp")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the filters or the continue token are not valid, should return 400 Bad Request.")
+		})
+
+		Result(AuditEventPage, "Return the audit events, ordered by time.")
+
+		HTTP(func() {
+			GET("/audit")
+			credentialsHTTP()
+			Param("since")
+			Param("until")
+			Param("service")
+			Param("resource")
+			Param("project")
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+		})
+	})
+})
+
+// AuditEvent describes an operation that changed the resources managed by FuseML
+var AuditEvent = Type("AuditEvent", func() {
+	Field(1, "id", String, "The unique ID of the event", func() {
+		Example("1617992245000000000-5f0c2a1b")
+	})
+	Field(2, "timestamp", String, "The time when the operation was performed", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T18:17:25Z")
+	})
+	Field(3, "principal", String, "The user or API key that performed the operation", func() {
+		Example("fuseml-user")
+	})
+	Field(4, "service", String, "The service called to perform the operation", func() {
+		Example("codeset")
+	})
+	Field(5, "method", String, "The method called to perform the operation", func() {
+		Example("delete")
+	})
+	Field(6, "project", String, "The project owning the resource targeted by the operation", func() {
+		Example("mlflow-project-01")
+	})
+	Field(7, "resource", String, "The name or ID of the resource targeted by the operation", func() {
+		Example("mlflow-app-01")
+	})
+	Field(8, "payload", String, "Summary of the operation payload, with the secrets redacted", func() {
+		Example(`{"Name":"mlflow-app-01","Project":"mlflow-project-01"}`)
+	})
+	Field(9, "result", String, "The result of the operation", func() {
+		Enum("success", "failure")
+		Example("success")
+	})
+	Field(10, "error", String, "The error returned when the operation failed", func() {
+		Example("could not find a codeset with the specified name")
+	})
+	Required("id", "timestamp", "principal", "service", "method", "result")
+})
+
+// AuditEventPage is a page of the audit events returned by the list method
+var AuditEventPage = pageOf("AuditEventPage", AuditEvent)
@@ -0,0 +1,57 @@
+package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+var _ = Service("auth", func() {
+	Description("The auth service issues the tokens used to authenticate requests to the FuseML API.")
+
+	Method("login", func() {
+		Description("Authenticate a FuseML user and issue a JWT token for it.")
+
+		Security(BasicAuth)
+
+		Payload(func() {
+			Username("username", String, "User name", func() {
+				Example("fuseml-mlflow-project-01")
+			})
+			Password("password", String, "User password", func() {
+				Example("changeme")
+			})
+			Required("username", "password")
+		})
+
+		Error("Unauthorized", func() {
+			Description("If the user credentials are invalid, should return 401 Unauthorized.")
+		})
+
+		Result(AuthToken)
+
+		HTTP(func() {
+			POST("/auth/login")
+			Response(StatusOK)
+			Response("Unauthorized", StatusUnauthorized)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("Unauthorized", CodeUnauthenticated)
+		})
+	})
+})
+
+// AuthToken describes a token issued by the auth service
+var AuthToken = Type("AuthToken", func() {
+	Field(1, "token", String, "The JWT token", func() {
+		Example("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiJmdXNlbWwtYWRtaW4ifQ.8nYyCkxc")
+	})
+	Field(2, "user", String, "The name of the authenticated user", func() {
+		Example("fuseml-mlflow-project-01")
+	})
+	Field(3, "expiresAt", String, "The time when the token expires", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T18:17:25Z")
+	})
+	Required("token", "user", "expiresAt")
+})
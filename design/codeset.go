@@ -7,12 +7,15 @@ import (
 var _ = Service("codeset", func() {
 	Description("The codeset service performs operations on Codesets.")
 
+	secured()
+
 	// Method describes a service method (endpoint)
 	Method("list", func() {
 		Description("Retrieve information about Codesets registered in FuseML.")
 		// Payload describes the method payload.
 		// Here the payload is an object that consists of two fields.
 		Payload(func() {
+			credentials()
 			// Field describes an object field given a field index, a field
 			// name, a type and a description.
 			Field(1, "project", String, "List only Codesets that belong to given project", func() {
@@ -21,11 +24,19 @@ var _ = Service("codeset", func() {
 			Field(2, "label", String, "List only Codesets with matching label", func() {
 				Example("mlflow")
 			})
+			Field(3, "archived", Boolean, "List also the archived Codesets", func() {
+				Default(false)
+			})
+			listFields(4, "name", "project")
 		})
 
 		// Result describes the method result.
-		// Here the result is a collection of codeset value.
-		Result(ArrayOf(Codeset), "Return all registered Codesets matching the query.")
+		// Here the result is a page of codesets.
+		Result(CodesetPage, "Return the registered Codesets matching the query.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		Error("NotFound", func() {
 			Description("If the Codeset is not found, should return 404 Not Found.")
@@ -36,23 +47,29 @@ var _ = Service("codeset", func() {
 			// Requests to the service consist of HTTP GET requests.
 			// The payload fields are encoded as path parameters.
 			GET("/codesets")
+			credentialsHTTP()
 			Param("project", String, "List only Codesets that belong to given project", func() {
 				Example("mlflow-project-01")
 			})
 			Param("label", String, "List only Codesets with matching label", func() {
 				Example("mlflow")
 			})
+			Param("archived")
+			listParams()
 			// Responses use a "200 OK" HTTP status.
-			// The result is encoded in the response body (default).
-			Response(StatusOK)
+			// The codesets are encoded in the response body.
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		// GRPC describes the gRPC transport mapping.
 		GRPC(func() {
+			credentialsGRPC()
 			// Responses use a "OK" gRPC code.
 			// The result is encoded in the response message (default).
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
 		})
 
@@ -62,6 +79,7 @@ var _ = Service("codeset", func() {
 		Description("Register a Codeset with the FuseML codeset store.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "The name of the Codeset", func() {
 				Example("mlflow-app-01")
 				Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
@@ -80,11 +98,12 @@ var _ = Service("codeset", func() {
 				})
 				Example([]string{"mlflow", "playground"})
 			})
+			Field(5, "source", CodesetSourceRegistration, "The external git repository the Codeset is registered from, if any")
 			Required("name", "project")
 		})
 
 		Error("BadRequest", func() {
-			Description("If the Codeset does not have the required fields, should return 400 Bad Request.")
+			Description("If the Codeset does not have the required fields, already exists, its external repository cannot be accessed or the project codeset quota is exceeded, should return 400 Bad Request.")
 		})
 
 		Result(func() {
@@ -95,6 +114,7 @@ var _ = Service("codeset", func() {
 
 		HTTP(func() {
 			POST("/codesets")
+			credentialsHTTP()
 			Param("name")
 			Param("project")
 			Param("description")
@@ -103,6 +123,7 @@ var _ = Service("codeset", func() {
 			Response("BadRequest", StatusBadRequest)
 		})
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 		})
@@ -112,6 +133,7 @@ var _ = Service("codeset", func() {
 		Description("Retrieve a Codeset from FuseML.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "project", String, "Project name", func() {
 				Example("mlflow-project-01")
 			})
@@ -132,12 +154,328 @@ var _ = Service("codeset", func() {
 
 		HTTP(func() {
 			GET("/codesets/{project}/{name}")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("update", func() {
+		Description("Update the description and labels of a Codeset. The labels, when given, replace the existing ones.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "description", String, "Codeset description", func() {
+				Example("My first MLFlow application with FuseML")
+			})
+			Field(4, "labels", ArrayOf(String), "Codeset labels replacing the existing ones", func() {
+				Elem(func() {
+					Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
+				})
+				Example([]string{"mlflow", "playground"})
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the Codeset is archived, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(Codeset)
+
+		HTTP(func() {
+			PUT("/codesets/{project}/{name}")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("archive", func() {
+		Description("Archive a Codeset: it is kept read-only and hidden from the Codeset list by default.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Required("project", "name")
+		})
+
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(Codeset)
+
+		HTTP(func() {
+			POST("/codesets/{project}/{name}/archive")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("unarchive", func() {
+		Description("Unarchive an archived Codeset.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Required("project", "name")
+		})
+
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(Codeset)
+
+		HTTP(func() {
+			POST("/codesets/{project}/{name}/unarchive")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("listVersions", func() {
+		Description("List the versions of a Codeset: its branches, tags and most recent commits.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "ref", String, "Branch, tag or commit to list the most recent commits from (the default branch if not set)", func() {
+				Example("main")
+			})
+			Field(4, "commits", Int, "Maximum number of commits to list", func() {
+				Minimum(0)
+				Maximum(50)
+				Default(10)
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If neither name or project is not given, or the ref does not exist, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(CodesetVersions)
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/versions")
+			credentialsHTTP()
+			Param("ref")
+			Param("commits")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("listFiles", func() {
+		Description("List the files of a Codeset directory, at a given Codeset version.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "path", String, "Path of the directory, relative to the root of the Codeset (the root if not set)", func() {
+				Example(".fuseml")
+				Pattern(relativePathPattern)
+				Default("")
+			})
+			Field(4, "ref", String, "Branch, tag or commit to list the files from (the default branch if not set)", func() {
+				Example("main")
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If neither name or project is not given, the ref does not exist or the path refers to a parent directory, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, or the path does not exist, should return 404 Not Found.")
+		})
+
+		Result(ArrayOf(CodesetFile))
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/files")
+			credentialsHTTP()
+			Param("path")
+			Param("ref")
 			Response(StatusOK)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("getFile", func() {
+		Description("Retrieve the contents of a Codeset file, at a given Codeset version.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "path", String, "Path of the file, relative to the root of the Codeset", func() {
+				Example(".fuseml/Dockerfile")
+				Pattern(relativePathPattern)
+			})
+			Field(4, "ref", String, "Branch, tag or commit to read the file from (the default branch if not set)", func() {
+				Example("main")
+			})
+			Required("project", "name", "path")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the ref does not exist, or the path is not a file, refers to a parent directory or is larger than 10MiB, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, or the file does not exist, should return 404 Not Found.")
+		})
+
+		Result(CodesetFileContents)
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/files/{*path}")
+			credentialsHTTP()
+			Param("ref")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("tag", func() {
+		Description("Tag a Codeset commit as a named Codeset version.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "tag", String, "Name of the tag", func() {
+				Example("v1.0")
+				Pattern(`^[A-Za-z0-9_][A-Za-z0-9._/-]*$`)
+			})
+			Field(4, "commit", String, "Commit, branch or tag to be tagged (the head of the default branch if not set)", func() {
+				Example("3b3ebe1b")
+			})
+			Field(5, "message", String, "Message describing the Codeset version", func() {
+				Example("First release")
+				Default("")
+			})
+			Required("project", "name", "tag")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the tag already exists, the commit does not exist or the Codeset is archived, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(CodesetRef)
+
+		HTTP(func() {
+			POST("/codesets/{project}/{name}/tags")
+			credentialsHTTP()
+			Param("tag")
+			Param("commit")
+			Param("message")
+			Response(StatusCreated)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -148,6 +486,7 @@ var _ = Service("codeset", func() {
 		Description("Delete a Codeset registered by FuseML.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "project", String, "Project name", func() {
 				Example("mlflow-project-01")
 			})
@@ -166,10 +505,12 @@ var _ = Service("codeset", func() {
 
 		HTTP(func() {
 			DELETE("/codesets/{project}/{name}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("BadRequest", StatusBadRequest)
 		})
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 		})
@@ -196,5 +537,119 @@ var Codeset = Type("Codeset", func() {
 	Field(5, "url", String, "Full URL to the Codeset", func() {
 		Example("http://my-gitea.server/project/repository.git")
 	})
+	Field(6, "source", CodesetSource, "The external git repository the Codeset is registered from, if any")
+	Field(7, "archived", Boolean, "Whether the Codeset is archived", func() {
+		Default(false)
+	})
 	Required("name", "project")
 })
+
+// CodesetSource describes the external git repository a Codeset is registered from
+var CodesetSource = Type("CodesetSource", func() {
+	Field(1, "url", String, "The URL of the git repository", func() {
+		Example("https://github.com/fuseml/fuseml-examples.git")
+	})
+	Field(2, "mode", String, "Whether the repository is mirrored into the FuseML git server or tracked directly", func() {
+		Enum("mirror", "track")
+	})
+	Field(3, "interval", String, "The interval at which the repository is mirrored or polled for new commits", func() {
+		Example("10m0s")
+	})
+	Field(4, "defaultBranch", String, "The default branch of the repository", func() {
+		Example("main")
+	})
+	Field(5, "polled", String, "The last time a tracked repository was polled", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Required("url", "mode")
+})
+
+// CodesetSourceRegistration describes the external git repository a Codeset is registered from, and the
+// credentials used to access it
+var CodesetSourceRegistration = Type("CodesetSourceRegistration", func() {
+	Field(1, "url", String, "The HTTP(S) URL of the git repository", func() {
+		Example("https://github.com/fuseml/fuseml-examples.git")
+	})
+	Field(2, "mode", String, "Whether the repository is mirrored into the FuseML git server or tracked directly", func() {
+		Enum("mirror", "track")
+		Default("mirror")
+	})
+	Field(3, "interval", String, "The interval at which the repository is mirrored or polled for new commits (at least one minute)", func() {
+		Example("10m")
+		Default("10m")
+	})
+	Field(4, "username", String, "The username used to access the repository", func() {
+		Example("fuseml")
+	})
+	Field(5, "password", String, "The password or access token used to access the repository", func() {
+		Example("ghp_0123456789abcdef")
+	})
+	Required("url")
+})
+
+// CodesetVersions describes the versions of a Codeset
+var CodesetVersions = Type("CodesetVersions", func() {
+	Field(1, "branches", ArrayOf(CodesetRef), "The branches of the Codeset")
+	Field(2, "tags", ArrayOf(CodesetRef), "The tags of the Codeset")
+	Field(3, "commits", ArrayOf(CodesetCommit), "The most recent commits of the Codeset, newest first")
+	Required("branches", "tags", "commits")
+})
+
+// CodesetRef describes a named Codeset version: a branch or a tag
+var CodesetRef = Type("CodesetRef", func() {
+	Field(1, "name", String, "The name of the branch or tag", func() {
+		Example("main")
+	})
+	Field(2, "commit", String, "The ID of the commit the branch or tag points to", func() {
+		Example("3b3ebe1b8f0cbc4e4ec4e26b18e8d6bb4cd1e2b5")
+	})
+	Required("name", "commit")
+})
+
+// CodesetCommit describes a Codeset commit
+var CodesetCommit = Type("CodesetCommit", func() {
+	Field(1, "id", String, "The commit ID", func() {
+		Example("3b3ebe1b8f0cbc4e4ec4e26b18e8d6bb4cd1e2b5")
+	})
+	Field(2, "author", String, "The name of the commit author", func() {
+		Example("John Doe")
+	})
+	Field(3, "message", String, "The commit message", func() {
+		Example("Tune the model hyperparameters")
+	})
+	Field(4, "date", String, "The time the commit was authored", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Required("id", "author", "message")
+})
+
+// CodesetFile describes an entry of a Codeset directory
+var CodesetFile = Type("CodesetFile", func() {
+	Field(1, "name", String, "The name of the file", func() {
+		Example("Dockerfile")
+	})
+	Field(2, "path", String, "The path of the file, relative to the root of the Codeset", func() {
+		Example(".fuseml/Dockerfile")
+	})
+	Field(3, "type", String, "The type of the file", func() {
+		Enum("file", "dir", "symlink", "submodule")
+	})
+	Field(4, "size", Int64, "The size of the file, in bytes", func() {
+		Example(512)
+	})
+	Required("name", "path", "type")
+})
+
+// CodesetFileContents describes the contents of a Codeset file
+var CodesetFileContents = Type("CodesetFileContents", func() {
+	Field(1, "path", String, "The path of the file, relative to the root of the Codeset", func() {
+		Example(".fuseml/Dockerfile")
+	})
+	Field(2, "content", Bytes, "The contents of the file")
+	Required("path", "content")
+})
+
+// CodesetPage is a page of the codesets returned by the list method
+var CodesetPage = pageOf("CodesetPage", Codeset)
@@ -1,6 +1,61 @@
 package design
 
+import (
+	. "goa.design/goa/v3/dsl"
+	"goa.design/goa/v3/expr"
+)
+
 const (
 	identifierPattern         = `^[A-Za-z0-9_][A-Za-z0-9-_]*$`
 	optionalIdentifierPattern = `^([A-Za-z0-9_][A-Za-z0-9-_]*)*$`
+	// relative paths whose segments are not ".."
+	relativePathPattern = `^(([^./][^/]*|\.[^./][^/]*|\.\.[^/]+|\.?)/)*([^./][^/]*|\.[^./][^/]*|\.\.[^/]+|\.?)$`
 )
+
+// listFields adds the fields used to page through and sort the items returned by a list method to
+// its payload, starting with the given field index. The items can be sorted by any of sortFields,
+// in ascending order, or in descending order when the field is prefixed with "-".
+func listFields(index int, sortFields ...string) {
+	sortValues := make([]interface{}, 0, 2*len(sortFields))
+	for _, f := range sortFields {
+		sortValues = append(sortValues, f, "-"+f)
+	}
+	Field(index, "limit", Int, "Maximum number of items to return, all the items are returned when not set", func() {
+		Minimum(1)
+		Example(50)
+	})
+	Field(index+1, "continue", String, "Token returned with the previous page of items, used to retrieve the next page", func() {
+		Example("eyJvZmZzZXQiOjUwfQ")
+	})
+	Field(index+2, "sort", String, "Field used to sort the items, prefixed with - to sort them in descending order", func() {
+		Enum(sortValues...)
+		Example(sortFields[0])
+	})
+}
+
+// listParams maps the pagination and sorting fields of a list method to HTTP query parameters.
+func listParams() {
+	Param("limit")
+	Param("continue")
+	Param("sort")
+}
+
+// pageOf returns the result type of a list method returning a page of items of type t.
+func pageOf(name string, t expr.DataType) expr.UserType {
+	return Type(name, func() {
+		Field(1, "items", ArrayOf(t), "The items of the page")
+		Field(2, "continue", String, "Token used to retrieve the next page of items, not set for the last page", func() {
+			Example("eyJvZmZzZXQiOjUwfQ")
+		})
+		Required("items")
+	})
+}
+
+// pageResponse maps a page of items to the HTTP response of a list method. The response body is
+// the list of items, while the token used to retrieve the next page is sent in a header.
+func pageResponse() {
+	Response(StatusOK, func() {
+		Header("continue:X-Continue-Token")
+		Body("items")
+	})
+}
@@ -9,10 +9,16 @@ import (
 var _ = Service("extension", func() {
 	Description("The extension registry service interfaces with the FuseML Extension Registry.")
 
+	secured()
+
 	Method("registerExtension", func() {
 		Description("Register an external utility as a FuseML extension with the FuseML extension registry.")
 
-		Payload(Extension, "Extension registration request")
+		Payload(func() {
+			Description("Extension registration request")
+			Extend(Extension)
+			credentials()
+		})
 
 		Error("BadRequest", func() {
 			Description("If the extension does not have the required fields, should return 400 Bad Request.")
@@ -25,12 +31,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			POST("/extensions")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
 			Response("Conflict", StatusConflict)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("Conflict", CodeAlreadyExists)
@@ -41,6 +49,7 @@ var _ = Service("extension", func() {
 		Description("Retrieve information about an extension.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -57,11 +66,13 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 		})
@@ -70,24 +81,42 @@ var _ = Service("extension", func() {
 	Method("listExtensions", func() {
 		Description("List extensions registered in FuseML")
 
-		Payload(ExtensionQuery, "Extension query parameters")
+		Payload(func() {
+			Description("Extension query parameters")
+			Extend(ExtensionQuery)
+			credentials()
+			listFields(8, "id", "product", "registered")
+		})
 
-		Result(ArrayOf(Extension), "Return all registered extensions.")
+		Result(ExtensionPage, "Return the registered extensions matching the query.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		HTTP(func() {
 			GET("/extensions")
-			Response(StatusOK)
+			credentialsHTTP()
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 		})
 	})
 
 	Method("updateExtension", func() {
 		Description("Update an extension registered in FuseML")
 
-		Payload(Extension, "Extension update request")
+		Payload(func() {
+			Description("Extension update request")
+			Extend(Extension)
+			credentials()
+		})
 
 		Result(Extension, "Return the updated extension.")
 
@@ -101,12 +130,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			PUT("/extensions/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -116,6 +147,7 @@ var _ = Service("extension", func() {
 	Method("deleteExtension", func() {
 		Description("Delete an extension and its subtree of services, endpoints and credentials")
 		Payload(func() {
+			credentials()
 			Field(1, "id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -134,12 +166,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			DELETE("/extensions/{id}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -149,7 +183,11 @@ var _ = Service("extension", func() {
 	Method("addService", func() {
 		Description("Add a service to an existing extension registered with the FuseML extension registry.")
 
-		Payload(ExtensionService, "Extension service add request")
+		Payload(func() {
+			Description("Extension service add request")
+			Extend(ExtensionService)
+			credentials()
+		})
 
 		Error("NotFound", func() {
 			Description("If the extension is not found, should return 404 Not Found.")
@@ -165,6 +203,7 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			POST("/extensions/{extension_id}/services")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
@@ -172,6 +211,7 @@ var _ = Service("extension", func() {
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -183,6 +223,7 @@ var _ = Service("extension", func() {
 		Description("Retrieve information about a service belonging to an extension.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -204,11 +245,13 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 		})
@@ -218,6 +261,7 @@ var _ = Service("extension", func() {
 		Description("List all services associated with an extension registered in FuseML")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -230,10 +274,12 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services")
+			credentialsHTTP()
 			Response(StatusOK)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 		})
 	})
@@ -241,7 +287,11 @@ var _ = Service("extension", func() {
 	Method("updateService", func() {
 		Description("Update a service belonging to an extension registered in FuseML")
 
-		Payload(ExtensionService, "Extension service update request")
+		Payload(func() {
+			Description("Extension service update request")
+			Extend(ExtensionService)
+			credentials()
+		})
 
 		Result(ExtensionService, "Return the updated extension service.")
 
@@ -255,12 +305,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			PUT("/extensions/{extension_id}/services/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -271,6 +323,7 @@ var _ = Service("extension", func() {
 		Description("Delete an extension service and its subtree of endpoints and credentials")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -294,12 +347,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			DELETE("/extensions/{extension_id}/services/{id}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -309,7 +364,11 @@ var _ = Service("extension", func() {
 	Method("addEndpoint", func() {
 		Description("Add an endpoint to an existing extension service registered with the FuseML extension registry.")
 
-		Payload(ExtensionEndpoint, "Extension endpoint add request")
+		Payload(func() {
+			Description("Extension endpoint add request")
+			Extend(ExtensionEndpoint)
+			credentials()
+		})
 
 		Error("NotFound", func() {
 			Description("If the extension or service are not found, should return 404 Not Found.")
@@ -325,6 +384,7 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			POST("/extensions/{extension_id}/services/{service_id}/endpoints")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
@@ -332,6 +392,7 @@ var _ = Service("extension", func() {
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -343,6 +404,7 @@ var _ = Service("extension", func() {
 		Description("Retrieve information about an endpoint belonging to an extension.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -368,11 +430,13 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services/{service_id}/endpoints/{url}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 		})
@@ -382,6 +446,7 @@ var _ = Service("extension", func() {
 		Description("List all endpoints associated with an extension service registered in FuseML")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -399,10 +464,12 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services/{service_id}/endpoints")
+			credentialsHTTP()
 			Response(StatusOK)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 		})
 	})
@@ -410,7 +477,11 @@ var _ = Service("extension", func() {
 	Method("updateEndpoint", func() {
 		Description("Update an endpoint belonging to an extension service registered in FuseML")
 
-		Payload(ExtensionEndpoint, "Extension endpoint update request")
+		Payload(func() {
+			Description("Extension endpoint update request")
+			Extend(ExtensionEndpoint)
+			credentials()
+		})
 
 		Result(ExtensionEndpoint, "Return the updated endpoint.")
 
@@ -424,12 +495,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			PUT("/extensions/{extension_id}/services/{service_id}/endpoints/{url}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -440,6 +513,7 @@ var _ = Service("extension", func() {
 		Description("Delete an extension endpoint")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -468,12 +542,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			DELETE("/extensions/{extension_id}/services/{service_id}/endpoints/{url}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -483,7 +559,11 @@ var _ = Service("extension", func() {
 	Method("addCredentials", func() {
 		Description("Add a set of credentials to an existing extension service registered with the FuseML extension registry.")
 
-		Payload(ExtensionCredentials, "Extension credentials add request")
+		Payload(func() {
+			Description("Extension credentials add request")
+			Extend(ExtensionCredentials)
+			credentials()
+		})
 
 		Error("NotFound", func() {
 			Description("If the extension or service are not found, should return 404 Not Found.")
@@ -499,6 +579,7 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			POST("/extensions/{extension_id}/services/{service_id}/credentials")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
@@ -506,6 +587,7 @@ var _ = Service("extension", func() {
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -517,6 +599,7 @@ var _ = Service("extension", func() {
 		Description("Retrieve information about a set of credentials belonging to an extension.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -543,11 +626,13 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services/{service_id}/credentials/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 		})
@@ -557,6 +642,7 @@ var _ = Service("extension", func() {
 		Description("List all credentials associated with an extension service registered in FuseML")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -574,10 +660,12 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			GET("/extensions/{extension_id}/services/{service_id}/credentials")
+			credentialsHTTP()
 			Response(StatusOK)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 		})
 	})
@@ -585,7 +673,11 @@ var _ = Service("extension", func() {
 	Method("updateCredentials", func() {
 		Description("Update a set of credentials belonging to an extension service registered in FuseML")
 
-		Payload(ExtensionCredentials, "Extension credentials update request")
+		Payload(func() {
+			Description("Extension credentials update request")
+			Extend(ExtensionCredentials)
+			credentials()
+		})
 
 		Result(ExtensionCredentials, "Return the updated set of credentials.")
 
@@ -599,12 +691,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			PUT("/extensions/{extension_id}/services/{service_id}/credentials/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -615,6 +709,7 @@ var _ = Service("extension", func() {
 		Description("Delete a set of extension credentials")
 
 		Payload(func() {
+			credentials()
 			Field(1, "extension_id", String, "Extension identifier", func() {
 				Pattern(identifierPattern)
 				MaxLength(100)
@@ -643,12 +738,14 @@ var _ = Service("extension", func() {
 
 		HTTP(func() {
 			DELETE("/extensions/{extension_id}/services/{service_id}/credentials/{id}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("NotFound", StatusNotFound)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 			Response("BadRequest", CodeInvalidArgument)
@@ -997,3 +1094,6 @@ categories of AI/ML services (e.g. model store, feature store, distributed train
 		})
 	tag++
 })
+
+// ExtensionPage is a page of the extensions returned by the listExtensions method
+var ExtensionPage = pageOf("ExtensionPage", Extension)
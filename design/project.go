@@ -7,18 +7,34 @@ import (
 var _ = Service("project", func() {
 	Description("The project service performs operations on Projects.")
 
+	secured()
+
 	Method("list", func() {
 		Description("Retrieve information about FuseML Projects.")
 
-		Result(ArrayOf(Project), "Return all Projects.")
+		Payload(func() {
+			credentials()
+			listFields(1, "name")
+		})
+
+		Result(ProjectPage, "Return the Projects.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		HTTP(func() {
 			GET("/projects")
-			Response(StatusOK)
+			credentialsHTTP()
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 		})
 
 	})
@@ -27,6 +43,7 @@ var _ = Service("project", func() {
 		Description("Retrieve a Project from FuseML.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Project name", func() {
 				Example("mlflow-project-01")
 			})
@@ -44,12 +61,14 @@ var _ = Service("project", func() {
 
 		HTTP(func() {
 			GET("/projects/{name}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -60,6 +79,7 @@ var _ = Service("project", func() {
 		Description("Create a new Project.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "The name of the Project", func() {
 				Example("mlflow-project-01")
 				Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
@@ -81,6 +101,7 @@ var _ = Service("project", func() {
 
 		HTTP(func() {
 			POST("/projects")
+			credentialsHTTP()
 			Param("name")
 			Param("description")
 			Response(StatusCreated)
@@ -89,34 +110,215 @@ var _ = Service("project", func() {
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("Conflict", CodeAlreadyExists)
 		})
 	})
 
+	Method("addMember", func() {
+		Description("Add a user to a Project, or change the role of a Project member.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "user", String, "User name", func() {
+				Example("fuseml-user")
+			})
+			Field(3, "role", String, "Role granted to the user in the Project", func() {
+				Enum("viewer", "editor", "admin")
+				Default("viewer")
+			})
+			Required("name", "user")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the user or the role are not valid, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no project with the given name, should return 404 Not Found.")
+		})
+
+		Result(Project)
+
+		HTTP(func() {
+			PUT("/projects/{name}/members/{user}")
+			credentialsHTTP()
+			Param("role")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("removeMember", func() {
+		Description("Remove a user from a Project.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "user", String, "User name", func() {
+				Example("fuseml-user")
+			})
+			Required("name", "user")
+		})
+
+		Error("NotFound", func() {
+			Description("If the user is not a member of the project, should return 404 Not Found.")
+		})
+
+		HTTP(func() {
+			DELETE("/projects/{name}/members/{user}")
+			credentialsHTTP()
+			Response(StatusNoContent)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("setQuota", func() {
+		Description("Set the limits on the resources consumed by a Project.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "maxCodesets", Int, "Maximum number of Codesets registered in the Project (0 for no limit)", func() {
+				Minimum(0)
+				Default(0)
+				Example(10)
+			})
+			Field(3, "maxConcurrentRuns", Int, "Maximum number of Workflow runs in progress for the Codesets of the Project (0 for no limit)", func() {
+				Minimum(0)
+				Default(0)
+				Example(2)
+			})
+			Field(4, "maxWorkspaceSize", String, "Maximum total size of the workspaces used by the Workflow runs in progress, as a Kubernetes quantity (empty for no limit)", func() {
+				Default("")
+				Example("10Gi")
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If a limit is not valid, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no project with the given name, should return 404 Not Found.")
+		})
+
+		Result(Project)
+
+		HTTP(func() {
+			PUT("/projects/{name}/quota")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("update", func() {
+		Description("Update the description and members of a Project.")
+
+		Payload(func() {
+			credentials()
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "description", String, "Project description (unchanged if not set)", func() {
+				Example("Set of MLFlow applications")
+			})
+			Field(3, "members", ArrayOf(ProjectMember), "Roles granted to the users in the Project, replacing the existing members (unchanged if not set)")
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If a member role is not valid, or the members include no admin, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no project with the given name, should return 404 Not Found.")
+		})
+
+		Result(Project)
+
+		HTTP(func() {
+			PUT("/projects/{name}")
+			credentialsHTTP()
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
 	Method("delete", func() {
-		Description("Delete a FuseML Project.")
+		Description("Delete a FuseML Project, along with its codesets, the workflow assignments to them, the workflow runs and applications created for them and the git users of the project.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Project name", func() {
 				Example("mlflow-project-01")
 			})
+			Field(2, "dryRun", Boolean, "Only list the resources that would be deleted", func() {
+				Default(false)
+			})
 			Required("name")
 		})
 
 		Error("BadRequest", func() {
 			Description("If name is not given, should return 400 Bad Request.")
 		})
+		Error("NotFound", func() {
+			Description("If there is no project with the given name, should return 404 Not Found.")
+		})
+
+		Result(ProjectResources)
 
 		HTTP(func() {
 			DELETE("/projects/{name}")
-			Response(StatusNoContent)
+			credentialsHTTP()
+			Param("dryRun")
+			Response(StatusOK)
 			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
 		})
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
 		})
 	})
 })
@@ -132,9 +334,53 @@ var Project = Type("Project", func() {
 		Example("Set of MLFlow applications")
 		Default("")
 	})
+	Field(4, "members", ArrayOf(ProjectMember), "Roles granted to the users in the Project")
+	Field(5, "quota", ProjectQuota, "Limits on the resources consumed by the Project")
+	Field(6, "usage", ProjectUsage, "Resources currently consumed by the Project")
 	Required("name")
 })
 
+// ProjectQuota describes the limits on the resources consumed by a project. A zero or empty limit means that
+// the resource is not limited.
+var ProjectQuota = Type("ProjectQuota", func() {
+	Field(1, "maxCodesets", Int, "Maximum number of Codesets registered in the Project", func() {
+		Example(10)
+	})
+	Field(2, "maxConcurrentRuns", Int, "Maximum number of Workflow runs in progress for the Codesets of the Project", func() {
+		Example(2)
+	})
+	Field(3, "maxWorkspaceSize", String, "Maximum total size of the workspaces used by the Workflow runs in progress", func() {
+		Example("10Gi")
+	})
+	Required("maxCodesets", "maxConcurrentRuns", "maxWorkspaceSize")
+})
+
+// ProjectUsage describes the resources consumed by a project
+var ProjectUsage = Type("ProjectUsage", func() {
+	Field(1, "codesets", Int, "Number of Codesets registered in the Project", func() {
+		Example(4)
+	})
+	Field(2, "concurrentRuns", Int, "Number of Workflow runs in progress for the Codesets of the Project", func() {
+		Example(1)
+	})
+	Field(3, "workspaceSize", String, "Total size of the workspaces used by the Workflow runs in progress", func() {
+		Example("2Gi")
+	})
+	Required("codesets", "concurrentRuns", "workspaceSize")
+})
+
+// ProjectMember describes the role granted to a user in a project
+var ProjectMember = Type("ProjectMember", func() {
+	Field(1, "user", String, "User name", func() {
+		Example("fuseml-user")
+	})
+	Field(2, "role", String, "Role of the user in the Project", func() {
+		Enum("viewer", "editor", "admin")
+		Example("editor")
+	})
+	Required("user", "role")
+})
+
 // User describes the user assigned to the project
 var User = Type("User", func() {
 	Field(1, "name", String, "User name", func() {
@@ -145,3 +391,28 @@ var User = Type("User", func() {
 	})
 	Required("name", "email")
 })
+
+// ProjectResources describes the resources deleted along with a project
+var ProjectResources = Type("ProjectResources", func() {
+	Field(1, "codesets", ArrayOf(String), "Codesets registered in the Project")
+	Field(2, "assignments", ArrayOf(ProjectAssignment), "Assignments of Workflows to the Codesets of the Project")
+	Field(3, "workflowRuns", ArrayOf(String), "Workflow runs created for the Codesets of the Project")
+	Field(4, "applications", ArrayOf(String), "Applications created from the Codesets of the Project")
+	Field(5, "members", ArrayOf(String), "Users that were granted a role in the Project")
+	Field(6, "users", ArrayOf(String), "Git users of the Project, deleted unless they belong to other Projects")
+	Required("codesets", "assignments", "workflowRuns", "applications", "members", "users")
+})
+
+// ProjectAssignment describes the assignment of a workflow to a codeset of a project
+var ProjectAssignment = Type("ProjectAssignment", func() {
+	Field(1, "workflow", String, "Workflow name", func() {
+		Example("mlflow-sklearn-e2e")
+	})
+	Field(2, "codeset", String, "Codeset name", func() {
+		Example("mlflow-app-01")
+	})
+	Required("workflow", "codeset")
+})
+
+// ProjectPage is a page of the projects returned by the list method
+var ProjectPage = pageOf("ProjectPage", Project)
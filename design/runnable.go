@@ -7,10 +7,13 @@ import (
 var _ = Service("runnable", func() {
 	Description("The runable service performs operations on runnables.")
 
+	secured()
+
 	// List service endpoint
 	Method("list", func() {
 		Description("Retrieve information about runnables registered in FuseML. Only runnables matching all supplied criteria are returned.")
 		Payload(func() {
+			credentials()
 			Field(1, "id", String,
 				"Value or regular expression used to filter runnables by their ID",
 				func() {
@@ -32,11 +35,16 @@ var _ = Service("runnable", func() {
 						"function": "predict|train",
 					})
 				})
+			listFields(4, "id", "kind", "created")
 			Required()
 		})
 
-		// Result is a collection of runnables
-		Result(ArrayOf(Runnable), "Return all registered runnables matching the query.")
+		// Result is a page of runnables
+		Result(RunnablePage, "Return the registered runnables matching the query.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		Error("NotFound", func() {
 			Description("If the runnable is not found, should return 404 Not Found.")
@@ -47,31 +55,40 @@ var _ = Service("runnable", func() {
 			// Requests to the service consist of HTTP GET requests.
 			// The payload fields are encoded as path parameters.
 			GET("/runnables")
+			credentialsHTTP()
 			Param("id")
 			Param("kind")
 			Param("labels")
+			listParams()
 			// Responses use a "200 OK" HTTP status.
-			// The result is encoded in the response body (default).
-			Response(StatusOK)
+			// The runnables are encoded in the response body.
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		// GRPC describes the gRPC transport mapping.
 		GRPC(func() {
+			credentialsGRPC()
 			// Responses use a "OK" gRPC code.
 			// The result is encoded in the response message (default).
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
 		})
 
 	})
 
 	Method("register", func() {
-		Description("Register a runnable with the FuseML runnable store.")
+		Description("Register a runnable with the FuseML runnable store. Restricted to admins.")
 
 		// Payload also accepts a Type object where you can list its attribute
 		// as well as its required fields
-		Payload(Runnable, "Runnable descriptor")
+		Payload(func() {
+			Description("Runnable descriptor")
+			Extend(Runnable)
+			credentials()
+		})
 
 		Error("BadRequest", func() {
 			Description("If the runnable does not have the required fields, should return 400 Bad Request.")
@@ -81,11 +98,13 @@ var _ = Service("runnable", func() {
 
 		HTTP(func() {
 			POST("/runnables")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 		})
@@ -95,6 +114,7 @@ var _ = Service("runnable", func() {
 		Description("Retrieve a Runnable from FuseML.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "id", String, "Unique runnable identifier", func() {
 				Pattern(identifierPattern)
 				Example("model-trainer-1234")
@@ -110,15 +130,166 @@ var _ = Service("runnable", func() {
 
 		HTTP(func() {
 			GET("/runnables/{id}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("NotFound", CodeNotFound)
 		})
 	})
+
+	Method("build", func() {
+		Description(`Build the container image for a runnable from source. The sources are retrieved from a
+codeset or a git repository and the image is built using the supplied Dockerfile. When the build succeeds,
+the runnable is registered, or updated if it already exists, to use the image stored in the FuseML built-in registry.
+Building from a codeset requires the editor role in its project, while building from a git repository or updating
+an existing runnable is restricted to admins.`)
+
+		Payload(func() {
+			credentials()
+			Field(1, "id", String, "Unique runnable identifier", func() {
+				Pattern(identifierPattern)
+				Example("model-trainer-1234")
+			})
+			Field(2, "codesetProject", String, "Project of the codeset providing the sources", func() {
+				Pattern(identifierPattern)
+				Example("mlflow-project-01")
+			})
+			Field(3, "codesetName", String, "Name of the codeset providing the sources", func() {
+				Pattern(identifierPattern)
+				Example("mlflow-app-01")
+			})
+			Field(4, "gitURL", String, "URL of a git repository providing the sources, when a codeset is not specified", func() {
+				Example("https://github.com/fuseml/examples.git")
+			})
+			Field(5, "revision", String, "Git revision (branch, tag or commit) of the sources", func() {
+				Default("main")
+				Example("v1.0")
+			})
+			Field(6, "dockerfile", String, "Path to the Dockerfile, relative to the root of the sources", func() {
+				Default("Dockerfile")
+				Example("docker/trainer/Dockerfile")
+			})
+			Field(7, "tag", String, "Tag for the resulting container image", func() {
+				Default("latest")
+				Example("v1.0")
+			})
+			Field(8, "runnable", Runnable,
+				"Runnable descriptor used to register the runnable, if it is not already registered. When supplied for an existing runnable, it replaces the registered descriptor.")
+			Required("id")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the build source or the runnable descriptor are not valid, or the codeset does not contain the Dockerfile, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If the codeset or the runnable are not found, should return 404 Not Found.")
+		})
+
+		Result(RunnableBuild)
+
+		HTTP(func() {
+			POST("/runnables/{id}/build")
+			credentialsHTTP()
+			Response(StatusAccepted)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	// The method is not named "import" as that is a reserved protocol buffer keyword, which the
+	// gRPC code generation does not handle consistently.
+	Method("importRunnables", func() {
+		Description(`Import a set of runnables into FuseML. Each runnable descriptor is validated and registered
+individually and the result of importing each runnable is reported separately. Restricted to admins.`)
+
+		Payload(func() {
+			credentials()
+			Field(1, "runnables", ArrayOf(Runnable), "Runnable descriptors", func() {
+				MinLength(1)
+			})
+			Field(2, "replace", Boolean, "Replace the descriptors of runnables that are already registered", func() {
+				Default(false)
+			})
+			Required("runnables")
+		})
+
+		Result(ArrayOf(RunnableImportResult), "Return the result of importing each runnable.")
+
+		HTTP(func() {
+			POST("/runnables/import")
+			credentialsHTTP()
+			Response(StatusOK)
+		})
+
+		GRPC(func() {
+			credentialsGRPC()
+			Response(CodeOK)
+		})
+	})
+})
+
+// RunnableImportResult describes the result of importing a runnable
+var RunnableImportResult = Type("RunnableImportResult", func() {
+	Field(1, "id", String, "The runnable identifier", func() {
+		Example("model-trainer-1234")
+	})
+	Field(2, "status", String, "The result of importing the runnable", func() {
+		Enum("registered", "updated", "skipped", "failed")
+		Example("registered")
+	})
+	Field(3, "error", String, "The reason why the runnable was not imported", func() {
+		Example("input parameter [learning-rate]: default value missing for optional input")
+	})
+	Required("id", "status")
+})
+
+// RunnableBuild describes the build of a runnable container image
+var RunnableBuild = Type("RunnableBuild", func() {
+	Field(1, "name", String, "Name of the build", func() {
+		Example("fuseml-build-model-trainer-1234-x8k2n")
+	})
+	Field(2, "runnable", String, "ID of the runnable being built", func() {
+		Example("model-trainer-1234")
+	})
+	Field(3, "source", String, "URL of the git repository providing the sources", func() {
+		Example("http://gitea.10.160.5.140.nip.io/mlflow-project-01/mlflow-app-01.git")
+	})
+	Field(4, "revision", String, "Git revision of the sources", func() {
+		Example("main")
+	})
+	Field(5, "dockerfile", String, "Path to the Dockerfile, relative to the root of the sources", func() {
+		Example("Dockerfile")
+	})
+	Field(6, "image", String, "Container image resulting from the build", func() {
+		Example("fuseml.local/runnables/model-trainer-1234:latest")
+	})
+	Field(7, "status", String, "Build status", func() {
+		Example("Running")
+	})
+	Field(8, "startTime", String, "The time when the build started", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Field(9, "completionTime", String, "The time when the build completed", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:20:35Z")
+	})
+	Field(10, "url", String, "URL to the build details", func() {
+		Example("http://tekton.10.160.5.140.nip.io/#/namespaces/fuseml-workloads/pipelineruns/fuseml-build-model-trainer-1234-x8k2n")
+	})
+	Required("name", "runnable", "image", "status")
 })
 
 // Runnable description
@@ -658,3 +829,6 @@ var RunnableArgumentDesc = Type("RunnableArgumentDesc", func() {
 		Example("trainer")
 	})
 })
+
+// RunnablePage is a page of the runnables returned by the list method
+var RunnablePage = pageOf("RunnablePage", Runnable)
@@ -0,0 +1,68 @@
+package design
+
+import (
+	. "goa.design/goa/v3/dsl"
+)
+
+// JWTAuth defines a security scheme that uses tokens issued by the auth service.
+var JWTAuth = JWTSecurity("jwt", func() {
+	Description("Secures the endpoints by requiring a valid JWT token issued by the FuseML auth service.")
+})
+
+// APIKeyAuth defines a security scheme that uses static API keys configured for the FuseML server.
+var APIKeyAuth = APIKeySecurity("api_key", func() {
+	Description("Secures the endpoints by requiring an API key configured for the FuseML server.")
+})
+
+// BasicAuth defines a security scheme that uses the credentials of the FuseML (Gitea) users.
+var BasicAuth = BasicAuthSecurity("basic", func() {
+	Description("Authenticates FuseML users with their user name and password.")
+})
+
+// secured requires all the methods of a service to be authenticated either with a JWT token
+// or with an API key.
+func secured() {
+	Security(JWTAuth)
+	Security(APIKeyAuth)
+
+	Error("Unauthorized", func() {
+		Description("If the request credentials are missing or invalid, should return 401 Unauthorized.")
+	})
+	Error("Forbidden", func() {
+		Description("If the caller does not have the project role required by the operation, should return 403 Forbidden.")
+	})
+
+	HTTP(func() {
+		Response("Unauthorized", StatusUnauthorized)
+		Response("Forbidden", StatusForbidden)
+	})
+
+	GRPC(func() {
+		Response("Unauthorized", CodeUnauthenticated)
+		Response("Forbidden", CodePermissionDenied)
+	})
+}
+
+// credentials defines the payload attributes holding the credentials of a secured method.
+func credentials() {
+	Token("token", String, "JWT token used to authenticate the request", func() {
+		Example("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiJmdXNlbWwtYWRtaW4ifQ.8nYyCkxc")
+	})
+	APIKey("api_key", "key", String, "API key used to authenticate the request", func() {
+		Example("d6f8a4e5c1b94f0ab2c3")
+	})
+}
+
+// credentialsHTTP maps the credentials of a secured method to HTTP headers.
+func credentialsHTTP() {
+	Header("token:Authorization")
+	Header("key:X-API-Key")
+}
+
+// credentialsGRPC maps the credentials of a secured method to gRPC metadata.
+func credentialsGRPC() {
+	Metadata(func() {
+		Attribute("token:authorization")
+		Attribute("key:x-api-key")
+	})
+}
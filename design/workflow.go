@@ -7,33 +7,50 @@ import (
 var _ = Service("workflow", func() {
 	Description("The workflow service performs operations on workflows.")
 
+	secured()
+
 	Method("list", func() {
 		Description("List Workflows.")
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "List workflows with the specified name", func() {
 				Example("workflowA")
 			})
+			listFields(2, "name", "created")
 		})
 
-		Result(ArrayOf(Workflow), "Return all workflows matching the query.")
+		Result(WorkflowPage, "Return the workflows matching the query.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		HTTP(func() {
 			GET("/workflows")
+			credentialsHTTP()
 			Param("name", String, "List workflows with the specified name", func() {
 				Example("workflowA")
 			})
-			Response(StatusOK)
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 		})
 
 	})
 
 	Method("create", func() {
 		Description("Create a new Workflow.")
-		Payload(Workflow, "Workflow descriptor")
+		Payload(func() {
+			Description("Workflow descriptor")
+			Extend(Workflow)
+			credentials()
+		})
 		Error("BadRequest", func() {
 			Description("If the workflow does not have the required fields, should return 400 Bad Request.")
 		})
@@ -44,12 +61,14 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			POST("/workflows")
+			credentialsHTTP()
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
 			Response("Conflict", StatusConflict)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("Conflict", CodeAlreadyExists)
@@ -60,6 +79,7 @@ var _ = Service("workflow", func() {
 		Description("Get a Workflow.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Workflow name", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -77,12 +97,14 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			GET("/workflows/{name}")
+			credentialsHTTP()
 			Response(StatusOK)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -93,6 +115,7 @@ var _ = Service("workflow", func() {
 		Description("Delete a Workflow and its assignments.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Workflow name", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -105,11 +128,13 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			DELETE("/workflows/{name}")
+			credentialsHTTP()
 			Response(StatusNoContent)
 			Response("BadRequest", StatusBadRequest)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 		})
@@ -119,6 +144,7 @@ var _ = Service("workflow", func() {
 		Description("Assign a Workflow to a Codeset.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Name of the Workflow to be associated with the codeset", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -128,11 +154,18 @@ var _ = Service("workflow", func() {
 			Field(3, "codesetName", String, "Codeset to assign the workflow to", func() {
 				Example("mlflow-project-001")
 			})
+			Field(4, "branches", ArrayOf(String), `Glob patterns selecting the codeset branches whose pushes trigger the workflow
+('*' matches any sequence of characters, '?' any single character)`, func() {
+				Example([]string{"main", "release/*"})
+			})
+			Field(5, "tags", ArrayOf(String), "Glob patterns selecting the codeset tags whose pushes trigger the workflow", func() {
+				Example([]string{"v*"})
+			})
 			Required("name", "codesetProject", "codesetName")
 		})
 
 		Error("BadRequest", func() {
-			Description("If no workflowName or codeset is given, should return 400 Bad Request.")
+			Description("If no workflowName or codeset is given, a branch or tag pattern is invalid, the codeset does not contain the Dockerfile of a workflow step, or the run quota of the codeset project is exceeded, should return 400 Bad Request.")
 		})
 		Error("NotFound", func() {
 			Description("If there is no workflow with the given name or codeset, should return 404 Not Found.")
@@ -140,15 +173,19 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			POST("/workflows/assignments")
+			credentialsHTTP()
 			Param("name")
 			Param("codesetProject")
 			Param("codesetName")
+			Param("branches")
+			Param("tags")
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -159,6 +196,7 @@ var _ = Service("workflow", func() {
 		Description("Unassign a Workflow from a Codeset.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Name of the Workflow to be unassigned", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -180,6 +218,7 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			DELETE("/workflows/assignments/{name}")
+			credentialsHTTP()
 			Param("name")
 			Param("codesetProject")
 			Param("codesetName")
@@ -189,6 +228,7 @@ var _ = Service("workflow", func() {
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
@@ -199,6 +239,7 @@ var _ = Service("workflow", func() {
 		Description("List Workflow assignments.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Name of the workflow to list assignments", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -208,11 +249,13 @@ var _ = Service("workflow", func() {
 
 		HTTP(func() {
 			GET("/workflows/assignments")
+			credentialsHTTP()
 			Param("name")
 			Response(StatusOK)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
 		})
 	})
@@ -221,6 +264,7 @@ var _ = Service("workflow", func() {
 		Description("List Workflow runs.")
 
 		Payload(func() {
+			credentials()
 			Field(1, "name", String, "Name of the Workflow to list runs from", func() {
 				Example("mlflow-sklearn-e2e")
 			})
@@ -234,26 +278,36 @@ var _ = Service("workflow", func() {
 				Example("Succeeded")
 
 			})
+			listFields(5, "name", "startTime")
 		})
 
 		Error("NotFound", func() {
 			Description("If there is no workflow with the given name, should return 404 Not Found.")
 		})
 
-		Result(ArrayOf(WorkflowRun), "Return all runs of a workflow.")
+		Result(WorkflowRunPage, "Return the runs of a workflow.")
+
+		Error("BadRequest", func() {
+			Description("If the continue token is not valid, should return 400 Bad Request.")
+		})
 
 		HTTP(func() {
 			GET("/workflows/runs")
+			credentialsHTTP()
 			Param("name")
 			Param("codesetProject")
 			Param("codesetName")
 			Param("status")
-			Response(StatusOK)
+			listParams()
+			pageResponse()
+			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
+			credentialsGRPC()
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
 		})
 
@@ -519,6 +573,9 @@ var WorkflowRun = Type("WorkflowRun", func() {
 		Example("Succeeded")
 	})
 	Field(8, "URL", String, "Dashboard URL to the workflow run")
+	Field(9, "ref", String, "The codeset git ref (branch or tag) whose push triggered the workflow run", func() {
+		Example("refs/heads/main")
+	})
 
 	Required("name", "workflowRef", "startTime", "completionTime", "status")
 })
@@ -544,10 +601,21 @@ var WorkflowAssignment = Type("WorkflowAssignment", func() {
 	Field(1, "workflow", String, "Workflow assigned to the codeset")
 	Field(2, "codesets", ArrayOf(Codeset), "Codesets assigned to the workflow")
 	Field(3, "status", WorkflowAssignmentStatus, "The status of the assignment")
+	Field(4, "refFilters", ArrayOf(CodesetRefFilter), "The branch and tag filters of the assigned codesets that have them")
 
 	Required("workflow", "codesets")
 })
 
+// CodesetRefFilter describes the branches and tags of an assigned codeset whose pushes trigger the workflow
+var CodesetRefFilter = Type("CodesetRefFilter", func() {
+	Field(1, "codesetProject", String, "Project that hosts the codeset")
+	Field(2, "codesetName", String, "Name of the codeset")
+	Field(3, "branches", ArrayOf(String), "Glob patterns selecting the branches")
+	Field(4, "tags", ArrayOf(String), "Glob patterns selecting the tags")
+
+	Required("codesetProject", "codesetName")
+})
+
 // WorkflowAssignmentStatus describes the status of the resource responsible for the
 // assignment between a workflow and codesets
 var WorkflowAssignmentStatus = Type("WorkflowAssignmentStatus", func() {
@@ -556,3 +624,9 @@ var WorkflowAssignmentStatus = Type("WorkflowAssignmentStatus", func() {
 
 	Required("available")
 })
+
+// WorkflowPage is a page of the workflows returned by the list method
+var WorkflowPage = pageOf("WorkflowPage", Workflow)
+
+// WorkflowRunPage is a page of the workflow runs returned by the listRuns method
+var WorkflowRunPage = pageOf("WorkflowRunPage", WorkflowRun)
@@ -31,7 +31,7 @@ func newSubCmdApplicationDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Delete an application.",
 		Long:  `Delete an application registered by FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -48,7 +48,7 @@ func (o *deleteOptions) validate() error {
 }
 
 func (o *deleteOptions) run() error {
-	request, err := applicationc.BuildDeletePayload(o.Name)
+	request, err := applicationc.BuildDeletePayload(o.Name, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
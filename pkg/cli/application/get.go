@@ -34,7 +34,7 @@ func newSubCmdApplicationGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get an application.",
 		Long:  `Show details about a FuseML application`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -52,7 +52,7 @@ func (o *getOptions) validate() error {
 }
 
 func (o *getOptions) run() error {
-	request, err := applicationc.BuildGetPayload(o.Name)
+	request, err := applicationc.BuildGetPayload(o.Name, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
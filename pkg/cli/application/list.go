@@ -4,9 +4,10 @@ import (
 	"context"
 	"os"
 
-	applicationc "github.com/fuseml/fuseml-core/gen/http/application/client"
+	"github.com/fuseml/fuseml-core/gen/application"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 )
@@ -14,16 +15,18 @@ import (
 // listOptions holds the options for 'application list' sub command
 type listOptions struct {
 	client.Clients
-	global   *common.GlobalOptions
-	format   *common.FormattingOptions
-	Type     string
-	Workflow string
+	global         *common.GlobalOptions
+	format         *common.FormattingOptions
+	Type           string
+	Workflow       string
+	CodesetProject string
+	CodesetName    string
 }
 
 func newListOptions(o *common.GlobalOptions) (res *listOptions) {
 	res = &listOptions{global: o}
 	res.format = common.NewFormattingOptions(
-		[]string{"Name", "Type", "Description", "URL", "Workflow"},
+		[]string{"Name", "Type", "Description", "URL", "Workflow", "Codeset:codeset.name", "Status:status.state"},
 		[]table.SortBy{{Name: "Name", Mode: table.Asc}, {Name: "Type", Mode: table.Asc}},
 		common.OutputFormatters{},
 	)
@@ -37,11 +40,11 @@ func newSubCmdApplicationList(gOpt *common.GlobalOptions) *cobra.Command {
 	o := newListOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   "list [-t|--type TYPE] [-w|--workflow WORKFLOW]",
+		Use:   "list [-t|--type TYPE] [-w|--workflow WORKFLOW] [-p|--codeset-project PROJECT] [-c|--codeset-name CODESET]",
 		Short: "List applications.",
 		Long:  `Retrieve information about applications registered in FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -50,6 +53,8 @@ func newSubCmdApplicationList(gOpt *common.GlobalOptions) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.Type, "type", "t", "", "list only applications of given type")
 	cmd.Flags().StringVarP(&o.Workflow, "workflow", "w", "", "list only applications generated by given workflow")
+	cmd.Flags().StringVarP(&o.CodesetProject, "codeset-project", "p", "", "list only applications generated from codesets belonging to given project")
+	cmd.Flags().StringVarP(&o.CodesetName, "codeset-name", "c", "", "list only applications generated from codesets with given name")
 	o.format.AddMultiValueFormattingFlags(cmd)
 
 	return cmd
@@ -60,17 +65,30 @@ func (o *listOptions) validate() error {
 }
 
 func (o *listOptions) run() error {
-	request, err := applicationc.BuildListPayload(o.Type, o.Workflow)
+	applications := []*application.Application{}
+	err := client.ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := o.ApplicationClient.List()(context.Background(), &application.ListPayload{
+			Type:           util.RefString(o.Type),
+			Workflow:       util.RefString(o.Workflow),
+			CodesetProject: util.RefString(o.CodesetProject),
+			CodesetName:    util.RefString(o.CodesetName),
+			Limit:          &limit,
+			Continue:       cont,
+			Token:          o.TokenRef(),
+			Key:            o.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*application.ApplicationPage)
+		applications = append(applications, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	response, err := o.ApplicationClient.List()(context.Background(), request)
-	if err != nil {
-		return err
-	}
-
-	o.format.FormatValue(os.Stdout, response)
+	o.format.FormatValue(os.Stdout, applications)
 
 	return nil
 }
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdAudit creates and returns the cobra command that acts as a root for all other audit CLI sub-commands
+func NewCmdAudit(c *common.GlobalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "audit log",
+		Long:  `Inspect the operations that changed the resources managed by FuseML`,
+	}
+
+	cmd.AddCommand(newSubCmdAuditList(c))
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"os"
+
+	"github.com/fuseml/fuseml-core/gen/audit"
+	auditc "github.com/fuseml/fuseml-core/gen/http/audit/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// listOptions holds the options for 'audit list' sub command
+type listOptions struct {
+	client.Clients
+	global   *common.GlobalOptions
+	format   *common.FormattingOptions
+	Since    string
+	Until    string
+	Service  string
+	Resource string
+	Project  string
+}
+
+func newListOptions(o *common.GlobalOptions) (res *listOptions) {
+	res = &listOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"Timestamp", "Principal", "Service", "Method", "Project", "Resource", "Result", "Error"},
+		[]table.SortBy{{Name: "Timestamp", Mode: table.Asc}},
+		common.OutputFormatters{},
+	)
+
+	return
+}
+
+// newSubCmdAuditList creates and returns the cobra command for the `audit list` CLI command
+func newSubCmdAuditList(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := newListOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "list [--since TIME] [--until TIME] [--service SERVICE] [--resource RESOURCE] [-p|--project PROJECT]",
+		Short: "List audit events.",
+		Long:  `Retrieve the audit events recorded for the create, update and delete operations performed through FuseML`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVar(&o.Since, "since", "", "list only events recorded at or after given time (RFC3339 format, e.g. 2021-04-09T06:17:25Z)")
+	cmd.Flags().StringVar(&o.Until, "until", "", "list only events recorded before given time (RFC3339 format, e.g. 2021-04-09T18:17:25Z)")
+	cmd.Flags().StringVar(&o.Service, "service", "", "list only events recorded for operations performed through given service (e.g. codeset)")
+	cmd.Flags().StringVar(&o.Resource, "resource", "", "list only events recorded for operations targeting given resource")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "list only events recorded for operations targeting the resources of given project")
+	o.format.AddMultiValueFormattingFlags(cmd)
+
+	return cmd
+}
+
+func (o *listOptions) validate() error {
+	return nil
+}
+
+func (o *listOptions) run() error {
+	events := []*audit.AuditEvent{}
+	err := client.ListAll(func(limit int, cont *string) (*string, error) {
+		request, err := auditc.BuildListPayload(o.Since, o.Until, o.Service, o.Resource, o.Project,
+			limit, util.DerefString(cont), "", o.Token, o.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		response, err := o.AuditClient.List()(context.Background(), request)
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*audit.AuditEventPage)
+		events = append(events, page.Items...)
+		return page.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	o.format.FormatValue(os.Stdout, events)
+
+	return nil
+}
@@ -9,14 +9,58 @@ import (
 	"time"
 
 	applicationc "github.com/fuseml/fuseml-core/gen/http/application/client"
+	auditc "github.com/fuseml/fuseml-core/gen/http/audit/client"
+	authc "github.com/fuseml/fuseml-core/gen/http/auth/client"
 	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
 	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/tlsconfig"
+	"github.com/fuseml/fuseml-core/pkg/util"
 	yaml "github.com/goccy/go-yaml"
 	goahttp "goa.design/goa/v3/http"
 )
 
+// Credentials holds the credentials used to authenticate the requests sent to the FuseML API
+type Credentials struct {
+	// Token issued by the FuseML server when logging in
+	Token string
+	// APIKey configured for the FuseML server
+	APIKey string
+}
+
+// TokenRef returns a reference to the token, or nil if a token is not set
+func (c Credentials) TokenRef() *string {
+	return util.RefString(c.Token)
+}
+
+// APIKeyRef returns a reference to the API key, or nil if an API key is not set
+func (c Credentials) APIKeyRef() *string {
+	return util.RefString(c.APIKey)
+}
+
+// ListPageSize is the number of items requested at a time when listing all the items of a kind
+const ListPageSize = 100
+
+// ListAll calls list with the continue token of the previous page, nil for the first page, until
+// list returns the last page, identified by an empty continue token
+func ListAll(list func(limit int, cont *string) (next *string, err error)) error {
+	var cont *string
+	for {
+		next, err := list(ListPageSize, cont)
+		if err != nil {
+			return err
+		}
+		if util.DerefString(next) == "" {
+			return nil
+		}
+		cont = next
+	}
+}
+
 // Clients holds a list of clients for all FuseML endpoints
 type Clients struct {
+	Credentials
+	AuthClient        *authc.Client
 	CodesetClient     *codesetc.Client
 	ApplicationClient *applicationc.Client
 	WorkflowClient    *WorkflowClient
@@ -24,41 +68,58 @@ type Clients struct {
 	RunnableClient    *runnablec.Client
 	VersionClient     *VersionClient
 	ExtensionClient   *ExtensionClient
+	AuditClient       *auditc.Client
 }
 
 // InitializeClients initializes a list of fuseml clients based on global configuration parameters
-func (c *Clients) InitializeClients(URL string, timeout int, verbose bool) error {
+func (c *Clients) InitializeClients(o *common.GlobalOptions) error {
 	var (
-		doer    goahttp.Doer                         = &http.Client{Timeout: time.Duration(timeout) * time.Second}
+		doer    goahttp.Doer
 		encoder func(*http.Request) goahttp.Encoder  = goahttp.RequestEncoder
 		decoder func(*http.Response) goahttp.Decoder = responseDecoder
 		scheme  string
 		host    string
 	)
 
-	u, err := url.Parse(URL)
+	u, err := url.Parse(o.URL)
 	if err != nil || u.Host == "" {
 		// assume the scheme part is missing and default to https
-		u, err = url.ParseRequestURI("https://" + URL)
+		u, err = url.ParseRequestURI("https://" + o.URL)
 		if err != nil || u.Host == "" {
-			return fmt.Errorf("invalid URL %#v: %s", URL, err)
+			return fmt.Errorf("invalid URL %#v: %s", o.URL, err)
 		}
 	}
 
 	scheme = u.Scheme
 	host = u.Host
 
+	var transport http.RoundTripper = http.DefaultTransport
+	if o.TLSCACert != "" || o.TLSCert != "" || o.TLSKey != "" {
+		tlsConfig, err := tlsconfig.ClientConfig(o.TLSCACert, o.TLSCert, o.TLSKey)
+		if err != nil {
+			return fmt.Errorf("invalid TLS configuration: %s", err)
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		transport = t
+	}
+	doer = &http.Client{Timeout: time.Duration(o.Timeout) * time.Second, Transport: newTracingTransport(transport)}
+
+	verbose := o.Verbose
 	if verbose {
 		doer = goahttp.NewDebugDoer(doer)
 	}
 
+	c.Credentials = Credentials{Token: o.Token, APIKey: o.APIKey}
+	c.AuthClient = authc.NewClient(scheme, host, doer, encoder, decoder, verbose)
 	c.ApplicationClient = applicationc.NewClient(scheme, host, doer, encoder, decoder, verbose)
 	c.CodesetClient = codesetc.NewClient(scheme, host, doer, encoder, decoder, verbose)
-	c.ProjectClient = NewProjectClient(scheme, host, doer, encoder, decoder, verbose)
+	c.ProjectClient = NewProjectClient(scheme, host, doer, encoder, decoder, verbose, c.Credentials)
 	c.RunnableClient = runnablec.NewClient(scheme, host, doer, encoder, decoder, verbose)
 	c.VersionClient = NewVersionClient(scheme, host, doer, encoder, decoder, verbose)
-	c.WorkflowClient = NewWorkflowClient(scheme, host, doer, encoder, decoder, verbose)
-	c.ExtensionClient = NewExtensionClient(scheme, host, doer, encoder, decoder, verbose)
+	c.WorkflowClient = NewWorkflowClient(scheme, host, doer, encoder, decoder, verbose, c.Credentials)
+	c.ExtensionClient = NewExtensionClient(scheme, host, doer, encoder, decoder, verbose, c.Credentials)
+	c.AuditClient = auditc.NewClient(scheme, host, doer, encoder, decoder, verbose)
 
 	return nil
 }
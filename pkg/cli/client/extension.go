@@ -14,13 +14,14 @@ import (
 
 // ExtensionClient holds a client for the extension HTTP REST service
 type ExtensionClient struct {
-	c *extensionc.Client
+	c     *extensionc.Client
+	creds Credentials
 }
 
 // NewExtensionClient initializes a ExtensionClient
 func NewExtensionClient(scheme string, host string, doer goahttp.Doer, encoder func(*http.Request) goahttp.Encoder,
-	decoder func(*http.Response) goahttp.Decoder, verbose bool) *ExtensionClient {
-	ec := &ExtensionClient{extensionc.NewClient(scheme, host, doer, encoder, decoder, verbose)}
+	decoder func(*http.Response) goahttp.Decoder, verbose bool, creds Credentials) *ExtensionClient {
+	ec := &ExtensionClient{extensionc.NewClient(scheme, host, doer, encoder, decoder, verbose), creds}
 	return ec
 }
 
@@ -33,18 +34,37 @@ func (ec *ExtensionClient) ReadExtensionFromFile(filepath string) (res *extensio
 		return nil, err
 	}
 
-	request, err := extensionc.BuildRegisterExtensionPayload(extDescriptor)
+	request, err := extensionc.BuildRegisterExtensionPayload(extDescriptor, "", "")
 	if err != nil {
 		return nil, err
 	}
 
-	return request, err
+	return &extension.Extension{
+		ID:            request.ID,
+		Product:       request.Product,
+		Version:       request.Version,
+		Description:   request.Description,
+		Zone:          request.Zone,
+		Configuration: request.Configuration,
+		Services:      request.Services,
+	}, nil
 }
 
 // RegisterExtension - register an extension.
 func (ec *ExtensionClient) RegisterExtension(ext *extension.Extension) (res *extension.Extension, err error) {
 
-	response, err := ec.c.RegisterExtension()(context.Background(), ext)
+	request := &extension.RegisterExtensionPayload{
+		ID:            ext.ID,
+		Product:       ext.Product,
+		Version:       ext.Version,
+		Description:   ext.Description,
+		Zone:          ext.Zone,
+		Configuration: ext.Configuration,
+		Services:      ext.Services,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.RegisterExtension()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +74,7 @@ func (ec *ExtensionClient) RegisterExtension(ext *extension.Extension) (res *ext
 
 // GetExtension - get an extension.
 func (ec *ExtensionClient) GetExtension(extensionID string) (*extension.Extension, error) {
-	request, err := extensionc.BuildGetExtensionPayload(extensionID)
+	request, err := extensionc.BuildGetExtensionPayload(extensionID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -68,19 +88,32 @@ func (ec *ExtensionClient) GetExtension(extensionID string) (*extension.Extensio
 }
 
 // ListExtension - list Extensions.
-func (ec *ExtensionClient) ListExtension(query *extension.ExtensionQuery) ([]*extension.Extension, error) {
-
-	wfs, err := ec.c.ListExtensions()(context.Background(), query)
+func (ec *ExtensionClient) ListExtension(query *extension.ListExtensionsPayload) ([]*extension.Extension, error) {
+	exts := []*extension.Extension{}
+	err := ListAll(func(limit int, cont *string) (*string, error) {
+		request := *query
+		request.Token = ec.creds.TokenRef()
+		request.Key = ec.creds.APIKeyRef()
+		request.Limit = &limit
+		request.Continue = cont
+		response, err := ec.c.ListExtensions()(context.Background(), &request)
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*extension.ExtensionPage)
+		exts = append(exts, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return wfs.([]*extension.Extension), nil
+	return exts, nil
 }
 
 // DeleteExtension - delete an Extension.
 func (ec *ExtensionClient) DeleteExtension(extensionID string) error {
-	request, err := extensionc.BuildDeleteExtensionPayload(extensionID)
+	request, err := extensionc.BuildDeleteExtensionPayload(extensionID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return err
 	}
@@ -96,7 +129,18 @@ func (ec *ExtensionClient) DeleteExtension(extensionID string) error {
 // UpdateExtension - update the attributes of an extension.
 func (ec *ExtensionClient) UpdateExtension(ext *extension.Extension) (res *extension.Extension, err error) {
 
-	response, err := ec.c.UpdateExtension()(context.Background(), ext)
+	request := &extension.UpdateExtensionPayload{
+		ID:            ext.ID,
+		Product:       ext.Product,
+		Version:       ext.Version,
+		Description:   ext.Description,
+		Zone:          ext.Zone,
+		Configuration: ext.Configuration,
+		Services:      ext.Services,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.UpdateExtension()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +151,20 @@ func (ec *ExtensionClient) UpdateExtension(ext *extension.Extension) (res *exten
 // AddService - add a service to an extension.
 func (ec *ExtensionClient) AddService(svc *extension.ExtensionService) (res *extension.ExtensionService, err error) {
 
-	response, err := ec.c.AddService()(context.Background(), svc)
+	request := &extension.AddServicePayload{
+		ID:            svc.ID,
+		ExtensionID:   svc.ExtensionID,
+		Resource:      svc.Resource,
+		Category:      svc.Category,
+		AuthRequired:  svc.AuthRequired,
+		Description:   svc.Description,
+		Configuration: svc.Configuration,
+		Endpoints:     svc.Endpoints,
+		Credentials:   svc.Credentials,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.AddService()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +174,7 @@ func (ec *ExtensionClient) AddService(svc *extension.ExtensionService) (res *ext
 
 // DeleteService - delete a service from an extension.
 func (ec *ExtensionClient) DeleteService(extensionID, serviceID string) error {
-	request, err := extensionc.BuildDeleteServicePayload(extensionID, serviceID)
+	request, err := extensionc.BuildDeleteServicePayload(extensionID, serviceID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return err
 	}
@@ -133,7 +190,20 @@ func (ec *ExtensionClient) DeleteService(extensionID, serviceID string) error {
 // UpdateService - update the attributes of a service from an extension.
 func (ec *ExtensionClient) UpdateService(service *extension.ExtensionService) (res *extension.ExtensionService, err error) {
 
-	response, err := ec.c.UpdateService()(context.Background(), service)
+	request := &extension.UpdateServicePayload{
+		ID:            service.ID,
+		ExtensionID:   service.ExtensionID,
+		Resource:      service.Resource,
+		Category:      service.Category,
+		AuthRequired:  service.AuthRequired,
+		Description:   service.Description,
+		Configuration: service.Configuration,
+		Endpoints:     service.Endpoints,
+		Credentials:   service.Credentials,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.UpdateService()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +213,7 @@ func (ec *ExtensionClient) UpdateService(service *extension.ExtensionService) (r
 
 // ListServices - list all services from an extension.
 func (ec *ExtensionClient) ListServices(extensionID string) (res []*extension.ExtensionService, err error) {
-	request, err := extensionc.BuildListServicesPayload(extensionID)
+	request, err := extensionc.BuildListServicesPayload(extensionID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -159,7 +229,16 @@ func (ec *ExtensionClient) ListServices(extensionID string) (res []*extension.Ex
 // AddEndpoint - add an endpoint to an extension service.
 func (ec *ExtensionClient) AddEndpoint(ep *extension.ExtensionEndpoint) (res *extension.ExtensionEndpoint, err error) {
 
-	response, err := ec.c.AddEndpoint()(context.Background(), ep)
+	request := &extension.AddEndpointPayload{
+		URL:           ep.URL,
+		ExtensionID:   ep.ExtensionID,
+		ServiceID:     ep.ServiceID,
+		Type:          ep.Type,
+		Configuration: ep.Configuration,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.AddEndpoint()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +250,7 @@ func (ec *ExtensionClient) AddEndpoint(ep *extension.ExtensionEndpoint) (res *ex
 func (ec *ExtensionClient) DeleteEndpoint(extensionID, serviceID, URL string) error {
 
 	url := url.QueryEscape(URL)
-	request, err := extensionc.BuildDeleteEndpointPayload(extensionID, serviceID, url)
+	request, err := extensionc.BuildDeleteEndpointPayload(extensionID, serviceID, url, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return err
 	}
@@ -188,8 +267,16 @@ func (ec *ExtensionClient) DeleteEndpoint(extensionID, serviceID, URL string) er
 func (ec *ExtensionClient) UpdateEndpoint(endpoint *extension.ExtensionEndpoint) (res *extension.ExtensionEndpoint, err error) {
 
 	url := url.QueryEscape(*endpoint.URL)
-	endpoint.URL = &url
-	response, err := ec.c.UpdateEndpoint()(context.Background(), endpoint)
+	request := &extension.UpdateEndpointPayload{
+		URL:           &url,
+		ExtensionID:   endpoint.ExtensionID,
+		ServiceID:     endpoint.ServiceID,
+		Type:          endpoint.Type,
+		Configuration: endpoint.Configuration,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.UpdateEndpoint()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -199,7 +286,7 @@ func (ec *ExtensionClient) UpdateEndpoint(endpoint *extension.ExtensionEndpoint)
 
 // ListEndpoints - list all endpoints from an extension service.
 func (ec *ExtensionClient) ListEndpoints(extensionID, serviceID string) (res []*extension.ExtensionEndpoint, err error) {
-	request, err := extensionc.BuildListEndpointsPayload(extensionID, serviceID)
+	request, err := extensionc.BuildListEndpointsPayload(extensionID, serviceID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +302,19 @@ func (ec *ExtensionClient) ListEndpoints(extensionID, serviceID string) (res []*
 // AddCredentials - add a set of credentials to an extension service.
 func (ec *ExtensionClient) AddCredentials(ep *extension.ExtensionCredentials) (res *extension.ExtensionCredentials, err error) {
 
-	response, err := ec.c.AddCredentials()(context.Background(), ep)
+	request := &extension.AddCredentialsPayload{
+		ID:            ep.ID,
+		ExtensionID:   ep.ExtensionID,
+		ServiceID:     ep.ServiceID,
+		Default:       ep.Default,
+		Scope:         ep.Scope,
+		Projects:      ep.Projects,
+		Users:         ep.Users,
+		Configuration: ep.Configuration,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.AddCredentials()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -225,7 +324,7 @@ func (ec *ExtensionClient) AddCredentials(ep *extension.ExtensionCredentials) (r
 
 // DeleteCredentials - delete a set of credentials from an extension service.
 func (ec *ExtensionClient) DeleteCredentials(extensionID, serviceID, credentialsID string) error {
-	request, err := extensionc.BuildDeleteCredentialsPayload(extensionID, serviceID, credentialsID)
+	request, err := extensionc.BuildDeleteCredentialsPayload(extensionID, serviceID, credentialsID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return err
 	}
@@ -241,7 +340,19 @@ func (ec *ExtensionClient) DeleteCredentials(extensionID, serviceID, credentials
 // UpdateCredentials - update the attributes of a set of credentials from an extension service.
 func (ec *ExtensionClient) UpdateCredentials(credentials *extension.ExtensionCredentials) (res *extension.ExtensionCredentials, err error) {
 
-	response, err := ec.c.UpdateCredentials()(context.Background(), credentials)
+	request := &extension.UpdateCredentialsPayload{
+		ID:            credentials.ID,
+		ExtensionID:   credentials.ExtensionID,
+		ServiceID:     credentials.ServiceID,
+		Default:       credentials.Default,
+		Scope:         credentials.Scope,
+		Projects:      credentials.Projects,
+		Users:         credentials.Users,
+		Configuration: credentials.Configuration,
+		Token:         ec.creds.TokenRef(),
+		Key:           ec.creds.APIKeyRef(),
+	}
+	response, err := ec.c.UpdateCredentials()(context.Background(), request)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +362,7 @@ func (ec *ExtensionClient) UpdateCredentials(credentials *extension.ExtensionCre
 
 // ListCredentials - list all credentials from an extension service.
 func (ec *ExtensionClient) ListCredentials(extensionID, serviceID string) (res []*extension.ExtensionCredentials, err error) {
-	request, err := extensionc.BuildListCredentialsPayload(extensionID, serviceID)
+	request, err := extensionc.BuildListCredentialsPayload(extensionID, serviceID, ec.creds.Token, ec.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
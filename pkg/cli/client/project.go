@@ -12,19 +12,20 @@ import (
 
 // ProjectClient holds a client for Project
 type ProjectClient struct {
-	c *projectc.Client
+	c     *projectc.Client
+	creds Credentials
 }
 
 // NewProjectClient initializes a ProjectClient
 func NewProjectClient(scheme string, host string, doer goahttp.Doer, encoder func(*http.Request) goahttp.Encoder,
-	decoder func(*http.Response) goahttp.Decoder, verbose bool) *ProjectClient {
-	pc := &ProjectClient{projectc.NewClient(scheme, host, doer, encoder, decoder, verbose)}
+	decoder func(*http.Response) goahttp.Decoder, verbose bool, creds Credentials) *ProjectClient {
+	pc := &ProjectClient{projectc.NewClient(scheme, host, doer, encoder, decoder, verbose), creds}
 	return pc
 }
 
 // Create a new Project.
 func (pc *ProjectClient) Create(name, desc string) (*project.Project, error) {
-	request, err := projectc.BuildCreatePayload(name, desc)
+	request, err := projectc.BuildCreatePayload(name, desc, pc.creds.Token, pc.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -37,20 +38,66 @@ func (pc *ProjectClient) Create(name, desc string) (*project.Project, error) {
 	return response.(*project.Project), nil
 }
 
-// Delete a Project and its assignments.
-func (pc *ProjectClient) Delete(name string) (err error) {
-	request, err := projectc.BuildDeletePayload(name)
+// Update the description and members of a Project. The description and members are left unchanged if nil.
+func (pc *ProjectClient) Update(name string, desc *string, members []*project.ProjectMember) (*project.Project, error) {
+	response, err := pc.c.Update()(context.Background(), &project.UpdatePayload{
+		Name:        name,
+		Description: desc,
+		Members:     members,
+		Token:       pc.creds.TokenRef(),
+		Key:         pc.creds.APIKeyRef(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.Project), nil
+}
+
+// Delete a Project along with all its resources, or only list them if dryRun is set.
+func (pc *ProjectClient) Delete(name string, dryRun bool) (*project.ProjectResources, error) {
+	response, err := pc.c.Delete()(context.Background(), &project.DeletePayload{
+		Name:   name,
+		DryRun: dryRun,
+		Token:  pc.creds.TokenRef(),
+		Key:    pc.creds.APIKeyRef(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.ProjectResources), nil
+}
+
+// AddMember adds a user to a Project, or changes the role of a Project member.
+func (pc *ProjectClient) AddMember(name, user, role string) (*project.Project, error) {
+	request, err := projectc.BuildAddMemberPayload(name, user, role, pc.creds.Token, pc.creds.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := pc.c.AddMember()(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.Project), nil
+}
+
+// RemoveMember removes a user from a Project.
+func (pc *ProjectClient) RemoveMember(name, user string) (err error) {
+	request, err := projectc.BuildRemoveMemberPayload(name, user, pc.creds.Token, pc.creds.APIKey)
 	if err != nil {
 		return
 	}
 
-	_, err = pc.c.Delete()(context.Background(), request)
+	_, err = pc.c.RemoveMember()(context.Background(), request)
 	return
 }
 
 // Get a Project.
 func (pc *ProjectClient) Get(name string) (*project.Project, error) {
-	request, err := projectc.BuildGetPayload(name)
+	request, err := projectc.BuildGetPayload(name, pc.creds.Token, pc.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -65,9 +112,40 @@ func (pc *ProjectClient) Get(name string) (*project.Project, error) {
 
 // List Projects.
 func (pc *ProjectClient) List() ([]*project.Project, error) {
-	response, err := pc.c.List()(context.Background(), nil)
+	projects := []*project.Project{}
+	err := ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := pc.c.List()(context.Background(), &project.ListPayload{
+			Limit:    &limit,
+			Continue: cont,
+			Token:    pc.creds.TokenRef(),
+			Key:      pc.creds.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*project.ProjectPage)
+		projects = append(projects, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return response.([]*project.Project), nil
+	return projects, nil
+}
+
+// SetQuota sets the limits on the resources consumed by a Project.
+func (pc *ProjectClient) SetQuota(name string, maxCodesets, maxConcurrentRuns int, maxWorkspaceSize string) (*project.Project, error) {
+	response, err := pc.c.SetQuota()(context.Background(), &project.SetQuotaPayload{
+		Name:              name,
+		MaxCodesets:       maxCodesets,
+		MaxConcurrentRuns: maxConcurrentRuns,
+		MaxWorkspaceSize:  maxWorkspaceSize,
+		Token:             pc.creds.TokenRef(),
+		Key:               pc.creds.APIKeyRef(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.Project), nil
 }
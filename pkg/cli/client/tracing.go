@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceParentEnv is the environment variable holding the W3C trace context that the requests
+// continue, e.g. when the CLI is invoked from a traced CI pipeline
+const traceParentEnv = "TRACEPARENT"
+
+// tracingTransport propagates the W3C trace context to the FuseML server with every request, so that
+// the requests sent by a CLI command belong to the same trace.
+type tracingTransport struct {
+	parent trace.SpanContext
+	next   http.RoundTripper
+}
+
+// newTracingTransport returns a round tripper propagating the trace context with the requests sent
+// through rt. The trace is continued from the TRACEPARENT environment variable, when set, or a new
+// trace is started. The CLI does not export its own spans, they only identify the requests in the
+// traces exported by the server.
+func newTracingTransport(rt http.RoundTripper) http.RoundTripper {
+	propagator := propagation.TraceContext{}
+	provider := sdktrace.NewTracerProvider()
+
+	carrier := propagation.HeaderCarrier{}
+	carrier.Set("traceparent", os.Getenv(traceParentEnv))
+	ctx := propagator.Extract(context.Background(), carrier)
+	ctx, _ = provider.Tracer("github.com/fuseml/fuseml-core/pkg/cli").Start(ctx, "fuseml")
+	return &tracingTransport{
+		parent: trace.SpanContextFromContext(ctx),
+		next:   otelhttp.NewTransport(rt, otelhttp.WithTracerProvider(provider), otelhttp.WithPropagators(propagator)),
+	}
+}
+
+// RoundTrip sends the request as part of the trace of the CLI command, unless the request is
+// already traced.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		req = req.WithContext(trace.ContextWithSpanContext(req.Context(), t.parent))
+	}
+	return t.next.RoundTrip(req)
+}
@@ -2,23 +2,30 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 
 	goahttp "goa.design/goa/v3/http"
 
 	versionc "github.com/fuseml/fuseml-core/gen/http/version/client"
 	"github.com/fuseml/fuseml-core/gen/version"
+	"github.com/fuseml/fuseml-core/pkg/health"
 )
 
 // VersionClient holds a client for Version
 type VersionClient struct {
-	c *versionc.Client
+	c         *versionc.Client
+	readyzURL string
+	doer      goahttp.Doer
 }
 
 // NewVersionClient initializes a VersionClient
 func NewVersionClient(scheme string, host string, doer goahttp.Doer, encoder func(*http.Request) goahttp.Encoder,
 	decoder func(*http.Response) goahttp.Decoder, verbose bool) *VersionClient {
-	vc := &VersionClient{versionc.NewClient(scheme, host, doer, encoder, decoder, verbose)}
+	readyzURL := &url.URL{Scheme: scheme, Host: host, Path: "/readyz"}
+	vc := &VersionClient{versionc.NewClient(scheme, host, doer, encoder, decoder, verbose), readyzURL.String(), doer}
 	return vc
 }
 
@@ -31,3 +38,26 @@ func (vc *VersionClient) Get() (*version.VersionInfo, error) {
 
 	return response.(*version.VersionInfo), nil
 }
+
+// Readiness returns the readiness of the server and of its dependencies.
+func (vc *VersionClient) Readiness() (*health.Status, error) {
+	req, err := http.NewRequest(http.MethodGet, vc.readyzURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := vc.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the status of the dependencies is also reported when the server is not ready
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("unexpected response from %s: %s", vc.readyzURL, resp.Status)
+	}
+	status := &health.Status{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %w", vc.readyzURL, err)
+	}
+	return status, nil
+}
@@ -10,25 +10,32 @@ import (
 
 	workflowc "github.com/fuseml/fuseml-core/gen/http/workflow/client"
 	"github.com/fuseml/fuseml-core/gen/workflow"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // WorkflowClient holds a client for Workflow
 type WorkflowClient struct {
-	c *workflowc.Client
+	c     *workflowc.Client
+	creds Credentials
 }
 
 // NewWorkflowClient initializes a WorkflowClient
 func NewWorkflowClient(scheme string, host string, doer goahttp.Doer, encoder func(*http.Request) goahttp.Encoder,
-	decoder func(*http.Response) goahttp.Decoder, verbose bool) *WorkflowClient {
-	wc := &WorkflowClient{workflowc.NewClient(scheme, host, doer, encoder, decoder, verbose)}
+	decoder func(*http.Response) goahttp.Decoder, verbose bool, creds Credentials) *WorkflowClient {
+	wc := &WorkflowClient{workflowc.NewClient(scheme, host, doer, encoder, decoder, verbose), creds}
 	return wc
 }
 
 // Assign a Workflow to a Codeset.
-func (wc *WorkflowClient) Assign(name, codesetProject, codesetName string) (err error) {
-	request, err := workflowc.BuildAssignPayload(name, codesetProject, codesetName)
-	if err != nil {
-		return
+func (wc *WorkflowClient) Assign(name, codesetProject, codesetName string, branches, tags []string) (err error) {
+	request := &workflow.AssignPayload{
+		Name:           name,
+		CodesetProject: codesetProject,
+		CodesetName:    codesetName,
+		Branches:       branches,
+		Tags:           tags,
+		Token:          wc.creds.TokenRef(),
+		Key:            wc.creds.APIKeyRef(),
 	}
 
 	_, err = wc.c.Assign()(context.Background(), request)
@@ -37,7 +44,7 @@ func (wc *WorkflowClient) Assign(name, codesetProject, codesetName string) (err
 
 // Create a new Workflow.
 func (wc *WorkflowClient) Create(workflowDef string) (*workflow.Workflow, error) {
-	request, err := workflowc.BuildCreatePayload(workflowDef)
+	request, err := workflowc.BuildCreatePayload(workflowDef, wc.creds.Token, wc.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +59,7 @@ func (wc *WorkflowClient) Create(workflowDef string) (*workflow.Workflow, error)
 
 // Delete a Workflow and its assignments.
 func (wc *WorkflowClient) Delete(name string) (err error) {
-	request, err := workflowc.BuildDeletePayload(name)
+	request, err := workflowc.BuildDeletePayload(name, wc.creds.Token, wc.creds.APIKey)
 	if err != nil {
 		return
 	}
@@ -63,7 +70,7 @@ func (wc *WorkflowClient) Delete(name string) (err error) {
 
 // Get a Workflow.
 func (wc *WorkflowClient) Get(name string) (*workflow.Workflow, error) {
-	request, err := workflowc.BuildGetPayload(name)
+	request, err := workflowc.BuildGetPayload(name, wc.creds.Token, wc.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -78,22 +85,32 @@ func (wc *WorkflowClient) Get(name string) (*workflow.Workflow, error) {
 
 // List Workflows.
 func (wc *WorkflowClient) List(name string) ([]*workflow.Workflow, error) {
-	request, err := workflowc.BuildListPayload(name)
-	if err != nil {
-		return nil, err
-	}
-
-	wfs, err := wc.c.List()(context.Background(), request)
+	wfs := []*workflow.Workflow{}
+	err := ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := wc.c.List()(context.Background(), &workflow.ListPayload{
+			Name:     util.RefString(name),
+			Limit:    &limit,
+			Continue: cont,
+			Token:    wc.creds.TokenRef(),
+			Key:      wc.creds.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*workflow.WorkflowPage)
+		wfs = append(wfs, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return wfs.([]*workflow.Workflow), nil
+	return wfs, nil
 }
 
 // ListAssignments lists Workflow assignments.
 func (wc *WorkflowClient) ListAssignments(name string) ([]*workflow.WorkflowAssignment, error) {
-	request, err := workflowc.BuildListAssignmentsPayload(name)
+	request, err := workflowc.BuildListAssignmentsPayload(name, wc.creds.Token, wc.creds.APIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -108,17 +125,28 @@ func (wc *WorkflowClient) ListAssignments(name string) ([]*workflow.WorkflowAssi
 
 // ListRuns lists Workflow runs.
 func (wc *WorkflowClient) ListRuns(name, codesetProject, codesetName, status string) ([]*workflow.WorkflowRun, error) {
-	request, err := workflowc.BuildListRunsPayload(name, codesetProject, codesetName, status)
+	wrs := []*workflow.WorkflowRun{}
+	err := ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := wc.c.ListRuns()(context.Background(), &workflow.ListRunsPayload{
+			Name:           util.RefString(name),
+			CodesetProject: util.RefString(codesetProject),
+			CodesetName:    util.RefString(codesetName),
+			Status:         util.RefString(status),
+			Limit:          &limit,
+			Continue:       cont,
+			Token:          wc.creds.TokenRef(),
+			Key:            wc.creds.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*workflow.WorkflowRunPage)
+		wrs = append(wrs, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	res, err := wc.c.ListRuns()(context.Background(), request)
-	if err != nil {
-		return nil, err
-	}
-
-	wrs := res.([]*workflow.WorkflowRun)
 	sortWorkflowRunsByStartTime(wrs)
 
 	return wrs, nil
@@ -126,7 +154,7 @@ func (wc *WorkflowClient) ListRuns(name, codesetProject, codesetName, status str
 
 // Unassign removes an assignment between a workflow and a codeset.
 func (wc *WorkflowClient) Unassign(name, codesetProject, codesetName string) (err error) {
-	request, err := workflowc.BuildUnassignPayload(name, codesetProject, codesetName)
+	request, err := workflowc.BuildUnassignPayload(name, codesetProject, codesetName, wc.creds.Token, wc.creds.APIKey)
 	if err != nil {
 		return
 	}
@@ -6,9 +6,11 @@ import (
 	"path/filepath"
 
 	"github.com/fuseml/fuseml-core/pkg/cli/application"
+	"github.com/fuseml/fuseml-core/pkg/cli/audit"
 	"github.com/fuseml/fuseml-core/pkg/cli/codeset"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
 	"github.com/fuseml/fuseml-core/pkg/cli/extension"
+	"github.com/fuseml/fuseml-core/pkg/cli/login"
 	"github.com/fuseml/fuseml-core/pkg/cli/project"
 	"github.com/fuseml/fuseml-core/pkg/cli/runnable"
 	"github.com/fuseml/fuseml-core/pkg/cli/version"
@@ -45,13 +47,30 @@ func NewCmdRoot() *cobra.Command {
 	pf.BoolVarP(&o.Verbose, "verbose", "v", false, "(FUSEML_VERBOSE) print verbose information, such as HTTP request and response details")
 	viper.BindEnv("verbose", "FUSEML_VERBOSE")
 
+	pf.StringVar(&o.Token, "token", "", "(FUSEML_TOKEN) token used to authenticate to the FuseML service, as issued by 'login'")
+	viper.BindEnv("token", "FUSEML_TOKEN")
+
+	pf.StringVar(&o.APIKey, "api-key", "", "(FUSEML_API_KEY) API key used to authenticate to the FuseML service")
+	viper.BindEnv("api-key", "FUSEML_API_KEY")
+
+	pf.StringVar(&o.TLSCACert, "tls-ca-cert", "", "(FUSEML_TLS_CA_CERT) file with the PEM encoded CA certificates used to verify the FuseML service certificate")
+	viper.BindEnv("tls-ca-cert", "FUSEML_TLS_CA_CERT")
+
+	pf.StringVar(&o.TLSCert, "tls-cert", "", "(FUSEML_TLS_CERT) file with the PEM encoded client certificate presented to the FuseML service")
+	viper.BindEnv("tls-cert", "FUSEML_TLS_CERT")
+
+	pf.StringVar(&o.TLSKey, "tls-key", "", "(FUSEML_TLS_KEY) file with the PEM encoded private key of the client certificate")
+	viper.BindEnv("tls-key", "FUSEML_TLS_KEY")
+
 	cmd.AddCommand(version.NewCmdVersion(o))
+	cmd.AddCommand(login.NewCmdLogin(o))
 	cmd.AddCommand(codeset.NewCmdCodeset(o))
 	cmd.AddCommand(project.NewCmdProject(o))
 	cmd.AddCommand(runnable.NewCmdRunnable(o))
 	cmd.AddCommand(workflow.NewCmdWorkflow(o))
 	cmd.AddCommand(application.NewCmdApplication(o))
 	cmd.AddCommand(extension.NewCmdExtension(o))
+	cmd.AddCommand(audit.NewCmdAudit(o))
 
 	return cmd
 }
@@ -0,0 +1,85 @@
+package codeset
+
+import (
+	"context"
+	"fmt"
+
+	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// ArchiveOptions holds the options for 'codeset archive' and 'codeset unarchive' sub commands
+type ArchiveOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	Name    string
+	Project string
+	Archive bool
+}
+
+// NewArchiveOptions creates a ArchiveOptions struct
+func NewArchiveOptions(o *common.GlobalOptions, archive bool) *ArchiveOptions {
+	return &ArchiveOptions{global: o, Archive: archive}
+}
+
+// NewSubCmdCodesetArchive creates and returns the cobra command for the `codeset archive` CLI command
+func NewSubCmdCodesetArchive(gOpt *common.GlobalOptions) *cobra.Command {
+	return newArchiveCmd(NewArchiveOptions(gOpt, true), "archive", "Archive codesets.",
+		`Archive a FuseML codeset: it is kept read-only and hidden from the codeset list by default`)
+}
+
+// NewSubCmdCodesetUnarchive creates and returns the cobra command for the `codeset unarchive` CLI command
+func NewSubCmdCodesetUnarchive(gOpt *common.GlobalOptions) *cobra.Command {
+	return newArchiveCmd(NewArchiveOptions(gOpt, false), "unarchive", "Unarchive codesets.",
+		`Unarchive an archived FuseML codeset`)
+}
+
+func newArchiveCmd(o *ArchiveOptions, use, short, long string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + ` {-n|--name NAME} {-p|--project PROJECT}`,
+		Short: short,
+		Long:  long,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(o.global))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *ArchiveOptions) validate() error {
+	return nil
+}
+
+func (o *ArchiveOptions) run() error {
+	if !o.Archive {
+		request, err := codesetc.BuildUnarchivePayload(o.Project, o.Name, o.Token, o.APIKey)
+		if err != nil {
+			return err
+		}
+		if _, err = o.CodesetClient.Unarchive()(context.Background(), request); err != nil {
+			return err
+		}
+		fmt.Printf("Codeset %s successfully unarchived\n", o.Name)
+		return nil
+	}
+
+	request, err := codesetc.BuildArchivePayload(o.Project, o.Name, o.Token, o.APIKey)
+	if err != nil {
+		return err
+	}
+	if _, err = o.CodesetClient.Archive()(context.Background(), request); err != nil {
+		return err
+	}
+	fmt.Printf("Codeset %s successfully archived\n", o.Name)
+	return nil
+}
@@ -0,0 +1,74 @@
+package codeset
+
+import (
+	"context"
+	"os"
+
+	codeset "github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// CatOptions holds the options for 'codeset cat' sub command
+type CatOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	Name    string
+	Project string
+	Ref     string
+	Path    string
+}
+
+// NewCatOptions initializes a CatOptions struct
+func NewCatOptions(o *common.GlobalOptions) *CatOptions {
+	return &CatOptions{global: o}
+}
+
+// NewSubCmdCodesetCat creates and returns the cobra command for the `codeset cat` CLI command
+func NewSubCmdCodesetCat(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewCatOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "cat {-n|--name NAME} {-p|--project PROJECT} [--ref REF] PATH",
+		Short: "Print a codeset file.",
+		Long:  `Print the contents of a file from a FuseML codeset`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Path = args[0]
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit to read the file from (defaults to the default branch)")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *CatOptions) validate() error {
+	return nil
+}
+
+func (o *CatOptions) run() error {
+	response, err := o.CodesetClient.GetFile()(context.Background(), &codeset.GetFilePayload{
+		Project: o.Project,
+		Name:    o.Name,
+		Path:    o.Path,
+		Ref:     util.RefString(o.Ref),
+		Token:   o.TokenRef(),
+		Key:     o.APIKeyRef(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(response.(*codeset.CodesetFileContents).Content)
+	return err
+}
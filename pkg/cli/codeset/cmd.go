@@ -16,8 +16,14 @@ func NewCmdCodeset(c *common.GlobalOptions) *cobra.Command {
 	cmd.AddCommand(NewSubCmdCodesetRegister(c))
 	cmd.AddCommand(NewSubCmdCodesetGet(c))
 	cmd.AddCommand(NewSubCmdCodesetList(c))
+	cmd.AddCommand(NewSubCmdCodesetUpdate(c))
+	cmd.AddCommand(NewSubCmdCodesetArchive(c))
+	cmd.AddCommand(NewSubCmdCodesetUnarchive(c))
 	cmd.AddCommand(NewSubCmdCodesetDelete(c))
 	cmd.AddCommand(NewSubCmdCodesetSet(c))
+	cmd.AddCommand(NewSubCmdCodesetVersions(c))
+	cmd.AddCommand(NewSubCmdCodesetLs(c))
+	cmd.AddCommand(NewSubCmdCodesetCat(c))
 
 	return cmd
 }
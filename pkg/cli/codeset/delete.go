@@ -33,7 +33,7 @@ func NewSubCmdCodesetDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Delete codesets.",
 		Long:  `Delete a codeset from FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -52,7 +52,7 @@ func (o *DeleteOptions) validate() error {
 }
 
 func (o *DeleteOptions) run() error {
-	request, err := codesetc.BuildDeletePayload(o.Project, o.Name)
+	request, err := codesetc.BuildDeletePayload(o.Project, o.Name, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
@@ -36,7 +36,7 @@ func NewSubCmdCodesetGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get codesets.",
 		Long:  `Show details about a FuseML codeset`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -56,7 +56,7 @@ func (o *GetOptions) validate() error {
 }
 
 func (o *GetOptions) run() error {
-	request, err := codesetc.BuildGetPayload(o.Project, o.Name)
+	request, err := codesetc.BuildGetPayload(o.Project, o.Name, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
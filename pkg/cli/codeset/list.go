@@ -7,9 +7,9 @@ import (
 	"strings"
 
 	codeset "github.com/fuseml/fuseml-core/gen/codeset"
-	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 )
@@ -17,10 +17,11 @@ import (
 // ListOptions holds the options for 'codeset list' sub command
 type ListOptions struct {
 	client.Clients
-	global  *common.GlobalOptions
-	format  *common.FormattingOptions
-	Project string
-	Label   string
+	global   *common.GlobalOptions
+	format   *common.FormattingOptions
+	Project  string
+	Label    string
+	Archived bool
 }
 
 // custom formatting handler used to format codeset labels
@@ -31,13 +32,21 @@ func formatLabels(object interface{}, column string, field interface{}) string {
 	return ""
 }
 
+// custom formatting handler used to format the external repository of a codeset as the mode and the URL
+func formatSource(object interface{}, column string, field interface{}) string {
+	if codeset, ok := object.(*codeset.Codeset); ok && codeset.Source != nil {
+		return fmt.Sprintf("%s: %s", codeset.Source.Mode, codeset.Source.URL)
+	}
+	return ""
+}
+
 // NewListOptions initializes a ListOptions struct
 func NewListOptions(o *common.GlobalOptions) (res *ListOptions) {
 	res = &ListOptions{global: o}
 	res.format = common.NewFormattingOptions(
-		[]string{"Name", "Project", "Description", "Labels", "URL"},
+		[]string{"Name", "Project", "Description", "Labels", "URL", "Source", "Archived"},
 		[]table.SortBy{{Name: "Name", Mode: table.Asc}, {Name: "Project", Mode: table.Asc}},
-		common.OutputFormatters{"Labels": formatLabels},
+		common.OutputFormatters{"Labels": formatLabels, "Source": formatSource},
 	)
 
 	return
@@ -49,11 +58,11 @@ func NewSubCmdCodesetList(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewListOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   "list [-p|--project PROJECT] [-l|--label LABEL]",
+		Use:   "list [-p|--project PROJECT] [-l|--label LABEL] [--archived]",
 		Short: "List codesets.",
 		Long:  `Retrieve information about Codesets registered in FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -62,6 +71,7 @@ func NewSubCmdCodesetList(gOpt *common.GlobalOptions) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "filter codesets by project")
 	cmd.Flags().StringVarP(&o.Label, "label", "l", "", "filter codesets by label")
+	cmd.Flags().BoolVar(&o.Archived, "archived", false, "list also the archived codesets")
 	o.format.AddMultiValueFormattingFlags(cmd)
 
 	return cmd
@@ -72,12 +82,24 @@ func (o *ListOptions) validate() error {
 }
 
 func (o *ListOptions) run() error {
-	request, err := codesetc.BuildListPayload(o.Project, o.Label)
-	if err != nil {
-		return err
-	}
-
-	response, err := o.CodesetClient.List()(context.Background(), request)
+	codesets := []*codeset.Codeset{}
+	err := client.ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := o.CodesetClient.List()(context.Background(), &codeset.ListPayload{
+			Project:  util.RefString(o.Project),
+			Label:    util.RefString(o.Label),
+			Archived: o.Archived,
+			Limit:    &limit,
+			Continue: cont,
+			Token:    o.TokenRef(),
+			Key:      o.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*codeset.CodesetPage)
+		codesets = append(codesets, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return err
 	}
@@ -91,7 +113,7 @@ func (o *ListOptions) run() error {
 	} else {
 		fmt.Printf("Listing Codesets for project %s and with label %s:\n", o.Project, o.Label)
 	}
-	o.format.FormatValue(os.Stdout, response)
+	o.format.FormatValue(os.Stdout, codesets)
 
 	return nil
 }
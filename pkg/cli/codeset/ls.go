@@ -0,0 +1,87 @@
+package codeset
+
+import (
+	"context"
+	"os"
+
+	codeset "github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// LsOptions holds the options for 'codeset ls' sub command
+type LsOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	format  *common.FormattingOptions
+	Name    string
+	Project string
+	Ref     string
+	Path    string
+}
+
+// NewLsOptions initializes a LsOptions struct
+func NewLsOptions(o *common.GlobalOptions) (res *LsOptions) {
+	res = &LsOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"Name", "Type", "Size"},
+		[]table.SortBy{{Name: "Type", Mode: table.Asc}, {Name: "Name", Mode: table.Asc}},
+		nil,
+	)
+
+	return
+}
+
+// NewSubCmdCodesetLs creates and returns the cobra command for the `codeset ls` CLI command
+func NewSubCmdCodesetLs(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewLsOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "ls {-n|--name NAME} {-p|--project PROJECT} [--ref REF] [PATH]",
+		Short: "List codeset files.",
+		Long:  `List the files of a FuseML codeset directory (the codeset root if PATH is not given)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				o.Path = args[0]
+			}
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit to list the files from (defaults to the default branch)")
+	o.format.AddMultiValueFormattingFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *LsOptions) validate() error {
+	return nil
+}
+
+func (o *LsOptions) run() error {
+	response, err := o.CodesetClient.ListFiles()(context.Background(), &codeset.ListFilesPayload{
+		Project: o.Project,
+		Name:    o.Name,
+		Path:    o.Path,
+		Ref:     util.RefString(o.Ref),
+		Token:   o.TokenRef(),
+		Key:     o.APIKeyRef(),
+	})
+	if err != nil {
+		return err
+	}
+
+	o.format.FormatValue(os.Stdout, response)
+
+	return nil
+}
@@ -9,10 +9,10 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/fuseml/fuseml-core/gen/codeset"
-	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
 	gitc "github.com/fuseml/fuseml-core/pkg/cli/git"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // RegisterOptions holds the options for 'codeset register' sub command
@@ -26,6 +26,15 @@ type RegisterOptions struct {
 	Location    string
 	Password    string
 	User        string
+	// registration from an external git repository
+	Mirror         bool
+	Track          bool
+	SourceUser     string
+	SourcePassword string
+	Interval       string
+	// options used when pushing the code
+	Message string
+	History bool
 }
 
 // NewRegisterOptions creates a CodesetRegisterOptions struct
@@ -41,12 +50,16 @@ func NewSubCmdCodesetRegister(gOpt *common.GlobalOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use: `register {-n|--name NAME} {-p|--project PROJECT} {-d|--desc DESCRIPTION} [--label LABEL] LOCATION [flags]
 
-LOCATION can be path to local directory or URL of a git repository`,
+LOCATION can be path to local directory or URL of a git repository. The files matched by the .gitignore and
+.fusemlignore files found in LOCATION are not pushed. With --history, LOCATION must be a local git checkout, whose
+current branch history is pushed as the codeset history. With --mirror or --track, LOCATION is the
+HTTP(S) URL of an external git repository that is registered as a codeset without pushing its code: it is either
+mirrored into FuseML at the given interval, or tracked directly, polling it for new commits at the given interval.`,
 		Short: "Register codesets.",
 		Long:  `Register a codeset with FuseML.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Location = cmd.Flags().Arg(0)
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -65,19 +78,62 @@ LOCATION can be path to local directory or URL of a git repository`,
 	cmd.Flags().StringVarP(&o.User, "user", "", "", "(FUSEML_PROJECT_USER) Username of the user accessing a project")
 	viper.BindEnv("user", "FUSEML_PROJECT_USER")
 
+	cmd.Flags().BoolVar(&o.Mirror, "mirror", false, "register LOCATION as an external git repository mirrored into FuseML")
+	cmd.Flags().BoolVar(&o.Track, "track", false, "register LOCATION as an external git repository tracked directly")
+
+	cmd.Flags().StringVarP(&o.SourceUser, "source-user", "", "", "(FUSEML_SOURCE_USER) Username used to access the external git repository")
+	viper.BindEnv("source-user", "FUSEML_SOURCE_USER")
+
+	cmd.Flags().StringVarP(&o.SourcePassword, "source-password", "", "", "(FUSEML_SOURCE_PASSWORD) Password or access token used to access the external git repository")
+	viper.BindEnv("source-password", "FUSEML_SOURCE_PASSWORD")
+
+	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "message of the commit created with the pushed code")
+	cmd.Flags().BoolVar(&o.History, "history", false, "push the history of the current branch of LOCATION, a local git checkout, instead of a new commit")
+
+	cmd.Flags().StringVar(&o.Interval, "interval", "10m", "interval at which the external git repository is mirrored or polled for new commits")
+
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("project")
 	return cmd
 }
 
 func (o *RegisterOptions) validate() error {
+	if o.Mirror && o.Track {
+		return fmt.Errorf("only one of --mirror and --track can be set")
+	}
+	if !o.Mirror && !o.Track && (o.SourceUser != "" || o.SourcePassword != "") {
+		return fmt.Errorf("the external git repository credentials can only be set with --mirror or --track")
+	}
+	if (o.Mirror || o.Track) && (o.Message != "" || o.History) {
+		return fmt.Errorf("--message and --history cannot be used with --mirror or --track, as no code is pushed")
+	}
+	if o.Message != "" && o.History {
+		return fmt.Errorf("only one of --message and --history can be set")
+	}
 	return nil
 }
 
 func (o *RegisterOptions) run() error {
-	request, err := codesetc.BuildRegisterPayload(o.Name, o.Project, o.Description, o.Labels)
-	if err != nil {
-		return err
+	request := &codeset.RegisterPayload{
+		Name:        o.Name,
+		Project:     o.Project,
+		Description: o.Description,
+		Labels:      o.Labels,
+		Token:       o.TokenRef(),
+		Key:         o.APIKeyRef(),
+	}
+	if o.Mirror || o.Track {
+		mode := "mirror"
+		if o.Track {
+			mode = "track"
+		}
+		request.Source = &codeset.CodesetSourceRegistration{
+			URL:      o.Location,
+			Mode:     mode,
+			Interval: o.Interval,
+			Username: util.RefString(o.SourceUser),
+			Password: util.RefString(o.SourcePassword),
+		}
 	}
 
 	response, err := o.CodesetClient.Register()(context.Background(), request)
@@ -88,19 +144,26 @@ func (o *RegisterOptions) run() error {
 	result := response.(*codeset.RegisterResult)
 	codeset := result.Codeset
 
-	// priority have username/password from the registering (when the new user was created)
-	password := result.Password
-	username := result.Username
-	if username == nil && o.User != "" {
-		username = &o.User
-	}
-	if password == nil && o.Password != "" {
-		password = &o.Password
-	}
+	// the code of the codesets registered from an external repository is not pushed
+	if request.Source == nil {
+		// priority have username/password from the registering (when the new user was created)
+		password := result.Password
+		username := result.Username
+		if username == nil && o.User != "" {
+			username = &o.User
+		}
+		if password == nil && o.Password != "" {
+			password = &o.Password
+		}
 
-	err = gitc.Push(o.Project, o.Name, o.Location, *codeset.URL, username, password, o.global.Verbose)
-	if err != nil {
-		return err
+		err = gitc.Push(o.Project, o.Name, o.Location, *codeset.URL, username, password, gitc.PushOptions{
+			Message: o.Message,
+			History: o.History,
+			Debug:   o.global.Verbose,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	fmt.Printf("Codeset %s successfully registered\n", *codeset.URL)
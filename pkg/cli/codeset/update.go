@@ -0,0 +1,83 @@
+package codeset
+
+import (
+	"context"
+	"fmt"
+
+	codeset "github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// UpdateOptions holds the options for 'codeset update' sub command
+type UpdateOptions struct {
+	client.Clients
+	global      *common.GlobalOptions
+	Name        string
+	Project     string
+	Description string
+	Labels      []string
+}
+
+// NewUpdateOptions creates a UpdateOptions struct
+func NewUpdateOptions(o *common.GlobalOptions) *UpdateOptions {
+	return &UpdateOptions{global: o}
+}
+
+// NewSubCmdCodesetUpdate creates and returns the cobra command for the `codeset update` CLI command
+func NewSubCmdCodesetUpdate(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewUpdateOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `update {-n|--name NAME} {-p|--project PROJECT} [-d|--desc DESCRIPTION] [--label LABEL]...`,
+		Short: "Update codesets.",
+		Long:  `Update the description and labels of a FuseML codeset. The labels, when given, replace the existing ones`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate(cmd.Flags()))
+			common.CheckErr(o.run(cmd.Flags()))
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVarP(&o.Description, "desc", "d", "", "codeset description")
+	cmd.Flags().StringSliceVar(&o.Labels, "label", []string{}, "one or more codeset labels replacing the existing ones (an empty value removes all of them)")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *UpdateOptions) validate(flags *pflag.FlagSet) error {
+	if !flags.Changed("desc") && !flags.Changed("label") {
+		return fmt.Errorf("nothing to update, the description or the labels are required")
+	}
+	return nil
+}
+
+func (o *UpdateOptions) run(flags *pflag.FlagSet) error {
+	payload := &codeset.UpdatePayload{
+		Project: o.Project,
+		Name:    o.Name,
+		Token:   o.TokenRef(),
+		Key:     o.APIKeyRef(),
+	}
+	if flags.Changed("desc") {
+		payload.Description = &o.Description
+	}
+	if flags.Changed("label") {
+		payload.Labels = o.Labels
+	}
+	_, err := o.CodesetClient.Update()(context.Background(), payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Codeset %s successfully updated\n", o.Name)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package codeset
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	codeset "github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// number of characters shown for the commit IDs in table output
+const shortCommitIDLength = 8
+
+// VersionsOptions holds the options for 'codeset versions' sub command
+type VersionsOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	format  *common.FormattingOptions
+	Name    string
+	Project string
+	Ref     string
+	Commits int
+}
+
+// custom formatting handler used to shorten the commit IDs
+func formatCommitID(object interface{}, column string, field interface{}) string {
+	id, _ := field.(string)
+	if len(id) > shortCommitIDLength {
+		return id[:shortCommitIDLength]
+	}
+	return id
+}
+
+// custom formatting handler used to show only the first line of the commit messages
+func formatCommitMessage(object interface{}, column string, field interface{}) string {
+	message, _ := field.(string)
+	return strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+}
+
+// NewVersionsOptions initializes a VersionsOptions struct
+func NewVersionsOptions(o *common.GlobalOptions) (res *VersionsOptions) {
+	res = &VersionsOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"ID", "Author", "Date", "Message"},
+		nil,
+		common.OutputFormatters{"ID": formatCommitID, "Message": formatCommitMessage},
+	)
+
+	return
+}
+
+// NewSubCmdCodesetVersions creates and returns the cobra command for the `codeset versions` CLI command
+func NewSubCmdCodesetVersions(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewVersionsOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "versions {-n|--name NAME} {-p|--project PROJECT} [--ref REF] [--commits COMMITS]",
+		Short: "List codeset versions.",
+		Long:  `List the branches, tags and most recent commits of a FuseML codeset`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit to list the most recent commits from (defaults to the default branch)")
+	cmd.Flags().IntVar(&o.Commits, "commits", 10, "maximum number of commits to list")
+	o.format.AddMultiValueFormattingFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *VersionsOptions) validate() error {
+	if o.Commits < 0 {
+		return fmt.Errorf("the number of commits cannot be negative")
+	}
+	return nil
+}
+
+func (o *VersionsOptions) run() error {
+	response, err := o.CodesetClient.ListVersions()(context.Background(), &codeset.ListVersionsPayload{
+		Project: o.Project,
+		Name:    o.Name,
+		Ref:     util.RefString(o.Ref),
+		Commits: o.Commits,
+		Token:   o.TokenRef(),
+		Key:     o.APIKeyRef(),
+	})
+	if err != nil {
+		return err
+	}
+	versions := response.(*codeset.CodesetVersions)
+
+	if o.format.Format != common.FormatTable && o.format.Format != common.FormatCSV {
+		o.format.FormatValue(os.Stdout, versions)
+		return nil
+	}
+
+	refFormat := common.NewFormattingOptions(
+		[]string{"Name", "Commit"},
+		[]table.SortBy{{Name: "Name", Mode: table.Asc}},
+		common.OutputFormatters{"Commit": formatCommitID},
+	)
+	refFormat.Format = o.format.Format
+
+	fmt.Println("Branches:")
+	refFormat.FormatValue(os.Stdout, versions.Branches)
+	fmt.Println("Tags:")
+	refFormat.FormatValue(os.Stdout, versions.Tags)
+	fmt.Println("Commits:")
+	o.format.FormatValue(os.Stdout, versions.Commits)
+
+	return nil
+}
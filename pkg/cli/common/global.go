@@ -23,6 +23,17 @@ type GlobalOptions struct {
 	Timeout int
 	// Verbose mode prints out additional information
 	Verbose bool
+	// Token used to authenticate the requests, issued by the FuseML server on login
+	Token string
+	// APIKey used to authenticate the requests, as an alternative to the token
+	APIKey string
+	// TLSCACert is the file holding the CA certificates trusted when connecting to the FuseML server,
+	// in addition to the system ones
+	TLSCACert string
+	// TLSCert is the file holding the client certificate presented to the FuseML server
+	TLSCert string
+	// TLSKey is the file holding the private key of the client certificate
+	TLSKey string
 	// CurrentProject says which project to use if "project" flag is not passed
 	CurrentProject string
 	// CurrentCodeset sets which codeset to use if the name is not provided
@@ -105,6 +105,17 @@ func WriteConfigFile() error {
 		fmt.Printf("FuseML configuration file created at %s\n", cf)
 	}
 
+	// the configuration holds the token issued at login, so it must only be accessible by the user.
+	// The file is created beforehand, as viper creates it with the default permissions.
+	f, err := os.OpenFile(cf, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	if err := os.Chmod(cf, 0600); err != nil {
+		return err
+	}
+
 	if err := viper.WriteConfigAs(cf); err != nil {
 		return err
 	}
@@ -38,7 +38,7 @@ func newSubCmdCredentialsList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists credentials",
 		Long:  `Display information about the credentials configured for an extension service.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1)))
 		},
@@ -32,7 +32,7 @@ func newSubCmdCredentialsUpdate(gOpt *common.GlobalOptions) *cobra.Command {
 		Long:  `Update the attributes of a set of FuseML extension service credentials already registered with the extension registry`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.config.Unpack()
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1), cmd.Flags().Arg(2), cmd.Flags()))
 		},
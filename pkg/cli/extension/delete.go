@@ -25,7 +25,7 @@ func newSubCmdExtensionDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Deletes an extension",
 		Long:  `Delete an extension from the FuseML extension registry, along with all services, endpoints and credentials.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0)))
 		},
@@ -29,7 +29,7 @@ func newSubCmdEndpointAdd(gOpt *common.GlobalOptions) *cobra.Command {
 		Long:  `Add an endpoint to a FuseML extension service already registered with the extension registry`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.config.Unpack()
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1), cmd.Flags().Arg(2)))
 		},
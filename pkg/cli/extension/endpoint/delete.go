@@ -25,7 +25,7 @@ func newSubCmdEndpointDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Deletes an endpoint from an extension service",
 		Long:  `Delete an endpoint from an extension service registered with the FuseML extension registry.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1), cmd.Flags().Arg(2)))
 		},
@@ -34,7 +34,7 @@ func newSubCmdEndpointList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists endpoints",
 		Long:  `Display information about the endpoints configured for an extension service.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1)))
 		},
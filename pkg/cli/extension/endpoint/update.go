@@ -30,7 +30,7 @@ func newSubCmdEndpointUpdate(gOpt *common.GlobalOptions) *cobra.Command {
 		Long:  `Update the attributes of a FuseML extension endpoint already registered with the extension registry`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.config.Unpack()
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1), cmd.Flags().Arg(2), cmd.Flags()))
 		},
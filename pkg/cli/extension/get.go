@@ -110,7 +110,7 @@ func newSubCmdExtensionGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get an extension",
 		Long:  `Show detailed information about an extension`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0)))
 		},
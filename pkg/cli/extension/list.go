@@ -18,7 +18,7 @@ type extensionListOptions struct {
 	client.Clients
 	global *common.GlobalOptions
 	format *common.FormattingOptions
-	query  extension.ExtensionQuery
+	query  extension.ListExtensionsPayload
 }
 
 func newExtensionListOptions(o *common.GlobalOptions) (res *extensionListOptions) {
@@ -95,7 +95,7 @@ func newSubCmdExtensionList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists one or more extensions",
 		Long:  `Display information about registered extensions matching supplied criteria.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -58,7 +58,7 @@ ID and zone attribues with the values supplied as command line arguments:
 
 `,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate(cmd.Flags()))
 			common.CheckErr(o.run(cmd.Flags()))
 		},
@@ -32,7 +32,7 @@ func newSubCmdServiceAdd(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Add a new service to an existing FuseML extension",
 		Long:  `Add a service to a FuseML extension already registered with the extension registry`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0)))
 		},
@@ -25,7 +25,7 @@ func newSubCmdServiceDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Deletes a service from an extension",
 		Long:  `Delete a service from an extension registered with the FuseML extension registry.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1)))
 		},
@@ -34,7 +34,7 @@ func newSubCmdServiceList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists services",
 		Long:  `Display information about the services configured for an extension.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0)))
 		},
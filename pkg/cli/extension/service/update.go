@@ -32,7 +32,7 @@ func newSubCmdServiceUpdate(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Update the attributes of an existing FuseML extension service",
 		Long:  `Update the attributes of FuseML extension service already registered with the extension registry`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run(cmd.Flags().Arg(0), cmd.Flags().Arg(1), cmd.Flags()))
 		},
@@ -12,15 +12,38 @@ import (
 	"time"
 
 	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	gitobject "github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/pkg/errors"
 
-	config "github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	dircopy "github.com/otiai10/copy"
 )
 
-// check if the location is indeed pointing to local directory and if so, copy it to target dir
-func prepareLocalDirectory(location, target string) error {
+// sizeWarningThreshold is the size of the codeset content above which a warning is shown before pushing it
+const sizeWarningThreshold = 100 * 1024 * 1024
+
+// codesetBranch is the default branch of the codeset repositories
+const codesetBranch = "main"
+
+// anonymousRemote is the name of the remote used to push the history of a local git checkout, which is not
+// added to its configuration
+const anonymousRemote = "anonymous"
+
+// PushOptions holds the options used when pushing code to a codeset
+type PushOptions struct {
+	// Message is the message of the commit created with the new code (a default message is used if empty)
+	Message string
+	// History pushes the history of the current branch of a local git checkout, instead of creating a
+	// new commit with its content. It replaces the content and history of the codeset.
+	History bool
+	// Debug shows the progress of the push operation
+	Debug bool
+}
+
+// check if the location is indeed pointing to local directory
+func checkLocalDirectory(location string) error {
 	info, err := os.Stat(location)
 	if os.IsNotExist(err) {
 		return err
@@ -28,10 +51,23 @@ func prepareLocalDirectory(location, target string) error {
 	if !info.IsDir() {
 		return errors.New(fmt.Sprintf("input path (%s) is not a directory", location))
 	}
+	return nil
+}
 
-	err = dircopy.Copy(location, target)
+// copy the files to be pushed from the source directory to the target dir
+func copySourceFiles(source, target string) error {
+	files, size, err := sourceFiles(source)
 	if err != nil {
-		return errors.Wrap(err, "can't copy source directory "+location+" to "+target)
+		return errors.Wrap(err, "can't list the files of the source directory "+source)
+	}
+	if size > sizeWarningThreshold {
+		log.Printf("Warning: the code to push has %d MiB, consider excluding large files, such as datasets or "+
+			"virtual environments, by listing them in a .fusemlignore file", size/(1024*1024))
+	}
+	for _, f := range files {
+		if err := dircopy.Copy(filepath.Join(source, f), filepath.Join(target, f)); err != nil {
+			return errors.Wrap(err, "can't copy source file "+f+" to "+target)
+		}
 	}
 	return nil
 }
@@ -47,39 +83,62 @@ func fetchRemoteRepository(path, target string) error {
 	return nil
 }
 
-// Push the code from local dir to remote repo
-// If username or password is not provided, use default values
-func Push(org, name, location, gitURL string, uname, pass *string, debug bool) error {
-	log.Printf("Pushing the code to the git repository...")
+// push the history of the current branch of a local git checkout to the codeset default branch
+func pushHistory(location string, u *url.URL, debug bool) error {
+	r, err := git.PlainOpen(location)
+	if err != nil {
+		return errors.Wrap(err, "failed opening the local git checkout "+location)
+	}
+	head, err := r.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed resolving the current branch of "+location)
+	}
+	if !head.Name().IsBranch() {
+		return errors.New(fmt.Sprintf("the local git checkout (%s) is not on a branch", location))
+	}
 
-	tmpDir, err := ioutil.TempDir("", "codeset-source")
+	remote, err := r.CreateRemoteAnonymous(&gitconfig.RemoteConfig{
+		Name: anonymousRemote,
+		URLs: []string{u.String()},
+	})
 	if err != nil {
-		return errors.Wrap(err, "can't create temp directory "+tmpDir)
+		return errors.Wrap(err, "failed configuring the codeset remote")
 	}
-	defer os.Remove(tmpDir)
 
-	// if location is URL pointing to git repo, clone the content localy
-	loc, err := url.Parse(location)
-	if err == nil && loc.IsAbs() && loc.Scheme != "" && loc.Host != "" {
-		if err := fetchRemoteRepository(location, tmpDir); err != nil {
-			return err
-		}
-	} else {
-		if err := prepareLocalDirectory(location, tmpDir); err != nil {
-			return err
-		}
+	// the codeset repository is initialized when registered, so its history is replaced
+	pushOpts := &git.PushOptions{
+		RemoteName: anonymousRemote,
+		RefSpecs: []gitconfig.RefSpec{
+			gitconfig.RefSpec(fmt.Sprintf("+%s:%s", head.Name(), plumbing.NewBranchReferenceName(codesetBranch))),
+		},
+	}
+	if debug {
+		pushOpts.Progress = os.Stdout
 	}
+	err = remote.Push(pushOpts)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return errors.Wrap(err, "failed pushing commits")
+	}
+	return nil
+}
 
-	// ignore the fact that the source dir might also be a git repository
-	os.RemoveAll(filepath.Join(tmpDir, ".git"))
+// Push the code from local dir to remote repo
+// If username or password is not provided, use default values
+// Unless the history of a local git checkout is pushed, the code is added as a new commit on top of the codeset
+// history, which only changes the files that differ from the codeset content. The files matched by the
+// .gitignore and .fusemlignore files found in the source directory are not pushed.
+func Push(org, name, location, gitURL string, uname, pass *string, opts PushOptions) error {
+	log.Printf("Pushing the code to the git repository...")
 
 	// prepare the full URL for the remote (target) repository
 	u, err := url.Parse(gitURL)
 	if err != nil {
 		return errors.Wrap(err, "Failed to parse git url")
 	}
-	username := config.DefaultUserName(org)
-	password := config.DefaultUserPassword
+	// default to the credentials of the user created by the server for the project
+	defaults := config.Default().Gitea
+	username := defaults.UserName(org)
+	password := defaults.UserPassword
 
 	if uname != nil {
 		username = *uname
@@ -90,12 +149,37 @@ func Push(org, name, location, gitURL string, uname, pass *string, debug bool) e
 
 	u.User = url.UserPassword(username, password)
 
+	// if location is URL pointing to git repo, clone the content localy
+	source := location
+	loc, err := url.Parse(location)
+	remote := err == nil && loc.IsAbs() && loc.Scheme != "" && loc.Host != ""
+	if opts.History {
+		if remote {
+			return errors.New("only the history of a local git checkout can be pushed")
+		}
+		return pushHistory(location, u, opts.Debug)
+	}
+	if remote {
+		tmpDir, err := ioutil.TempDir("", "codeset-source")
+		if err != nil {
+			return errors.Wrap(err, "can't create temp directory "+tmpDir)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if err := fetchRemoteRepository(location, tmpDir); err != nil {
+			return err
+		}
+		source = tmpDir
+	} else if err := checkLocalDirectory(location); err != nil {
+		return err
+	}
+
 	// Clone new repository so we can push new content
 	cloneDir, err := ioutil.TempDir("", "codeset-clone")
 	if err != nil {
 		return errors.Wrap(err, "can't create temp directory "+cloneDir)
 	}
-	defer os.Remove(cloneDir)
+	defer os.RemoveAll(cloneDir)
 
 	r, err := git.PlainClone(cloneDir, false, &git.CloneOptions{
 		URL: u.String(),
@@ -115,18 +199,31 @@ func Push(org, name, location, gitURL string, uname, pass *string, debug bool) e
 		return errors.Wrap(err, "failed marking existing files for removal")
 	}
 
-	// tmpDir has .git removed, so we can move just data to the real repository clone
-	err = dircopy.Copy(tmpDir, cloneDir)
+	// only the files that are not ignored are copied, without the git metadata of the source
+	err = copySourceFiles(source, cloneDir)
 	if err != nil {
-		return errors.Wrap(err, "failed copying directory content")
+		return err
 	}
 
 	err = w.AddWithOptions(&git.AddOptions{All: true})
 	if err != nil {
 		return errors.Wrap(err, "failed adding new directory content")
 	}
+	status, err := w.Status()
+	if err != nil {
+		return errors.Wrap(err, "failed checking the changes")
+	}
+	if status.IsClean() {
+		log.Printf("The codeset is already up to date.")
+		return nil
+	}
+
+	message := opts.Message
+	if message == "" {
+		message = fmt.Sprintf("New codeset update from %s", location)
+	}
 	_, err = w.Commit(
-		fmt.Sprintf("New codeset update from %s", location),
+		message,
 		&git.CommitOptions{
 			Author: &gitobject.Signature{
 				Name:  "FuseML core user",
@@ -139,7 +236,7 @@ func Push(org, name, location, gitURL string, uname, pass *string, debug bool) e
 	}
 
 	pushOpts := &git.PushOptions{}
-	if debug {
+	if opts.Debug {
 		pushOpts.Progress = os.Stdout
 	}
 
@@ -0,0 +1,117 @@
+package git
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// newTestCheckout creates a local git checkout with a commit for each message on the given branch and returns
+// its directory and the ID of the last commit
+func newTestCheckout(t *testing.T, branch string, messages ...string) (string, plumbing.Hash) {
+	t.Helper()
+
+	dir := newTestDirectory(t, map[string]string{})
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(branch)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hash plumbing.Hash
+	for _, m := range messages {
+		hash, err = wt.Commit(m, &git.CommitOptions{Author: &gitobject.Signature{
+			Name:  "author",
+			Email: "author@example.io",
+			When:  time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, hash
+}
+
+// newTestCodesetRepository creates a bare repository, standing for the repository of a codeset, with a
+// commit on its default branch
+func newTestCodesetRepository(t *testing.T) (*git.Repository, *url.URL) {
+	t.Helper()
+
+	source, _ := newTestCheckout(t, codesetBranch, "initial commit")
+	dir := newTestDirectory(t, map[string]string{})
+	repo, err := git.PlainClone(dir, true, &git.CloneOptions{URL: source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, u
+}
+
+func TestPushHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		detached bool
+		messages []string
+		err      bool
+	}{
+		{name: "default branch", branch: "main", messages: []string{"first", "second"}},
+		{name: "other branch", branch: "feature", messages: []string{"first"}},
+		{name: "detached head", branch: "main", detached: true, messages: []string{"first"}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeset, u := newTestCodesetRepository(t)
+			before, err := codeset.Reference(plumbing.NewBranchReferenceName(codesetBranch), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir, head := newTestCheckout(t, tt.branch, tt.messages...)
+			if tt.detached {
+				repo, err := git.PlainOpen(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, head)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = pushHistory(dir, u, false)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+
+			// the history of the codeset is replaced, unless the push fails
+			want := head
+			if tt.err {
+				want = before.Hash()
+			}
+			got, err := codeset.Reference(plumbing.NewBranchReferenceName(codesetBranch), true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Hash() != want {
+				t.Errorf("got codeset head %s, want %s", got.Hash(), want)
+			}
+		})
+	}
+
+	if err := pushHistory(newTestDirectory(t, map[string]string{}), &url.URL{Path: "/unknown"}, false); err == nil {
+		t.Error("expected an error when pushing from a directory that is not a git checkout")
+	}
+}
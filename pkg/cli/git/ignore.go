@@ -0,0 +1,82 @@
+package git
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// files holding the patterns of the paths that are not pushed to the codeset, in the order in which they
+// are applied: the .fusemlignore patterns can override the .gitignore ones
+var ignoreFiles = []string{".gitignore", ".fusemlignore"}
+
+// readIgnorePatterns reads the ignore patterns from the ignore files found in the given directory
+func readIgnorePatterns(root string, domain []string) ([]gitignore.Pattern, error) {
+	var ps []gitignore.Pattern
+	for _, name := range ignoreFiles {
+		f, err := os.Open(filepath.Join(append([]string{root}, append(domain, name)...)...))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "#") || len(strings.TrimSpace(line)) == 0 {
+				continue
+			}
+			ps = append(ps, gitignore.ParsePattern(line, domain))
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ps, nil
+}
+
+// sourceFiles walks the given directory and returns the paths (relative to the directory) of the files to be
+// pushed to the codeset, skipping the git metadata and the paths matched by the ignore files, as well as
+// their total size
+func sourceFiles(root string) (files []string, size int64, err error) {
+	var patterns []gitignore.Pattern
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		var parts []string
+		if rel != "." {
+			parts = strings.Split(filepath.ToSlash(rel), "/")
+			if info.Name() == ".git" || gitignore.NewMatcher(patterns).Match(parts, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if info.IsDir() {
+			// the patterns of a directory only match paths inside that directory, so they can be
+			// accumulated while walking the tree
+			ps, err := readIgnorePatterns(root, parts)
+			if err != nil {
+				return err
+			}
+			patterns = append(patterns, ps...)
+			return nil
+		}
+		files = append(files, rel)
+		size += info.Size()
+		return nil
+	})
+	return
+}
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// newTestDirectory creates a directory holding the given files, indexed by their slash separated path
+func newTestDirectory(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "fuseml-push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadIgnorePatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		domain  []string
+		ignored []string
+		kept    []string
+	}{
+		{
+			name:  "no ignore files",
+			files: map[string]string{"train.py": ""},
+			kept:  []string{"train.py"},
+		},
+		{
+			name:    "comments and blank lines",
+			files:   map[string]string{".gitignore": "#*.py\n\n*.log\n"},
+			ignored: []string{"run.log", "logs/run.log"},
+			kept:    []string{"train.py"},
+		},
+		{
+			name: "fusemlignore applied after gitignore",
+			files: map[string]string{
+				".gitignore":    "*.csv\n",
+				".fusemlignore": "!labels.csv\nvenv/\n",
+			},
+			ignored: []string{"data.csv", "venv/bin/conda"},
+			kept:    []string{"labels.csv"},
+		},
+		{
+			name: "nested directory",
+			files: map[string]string{
+				"src/.gitignore":    "*.bin\n",
+				"src/.fusemlignore": "!model.bin\n",
+			},
+			domain:  []string{"src"},
+			ignored: []string{"src/weights.bin"},
+			kept:    []string{"weights.bin", "other/weights.bin", "src/model.bin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestDirectory(t, tt.files)
+			patterns, err := readIgnorePatterns(dir, tt.domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matcher := gitignore.NewMatcher(patterns)
+			for _, path := range tt.ignored {
+				if !matcher.Match(strings.Split(path, "/"), false) {
+					t.Errorf("path %q is not ignored", path)
+				}
+			}
+			for _, path := range tt.kept {
+				if matcher.Match(strings.Split(path, "/"), false) {
+					t.Errorf("path %q is ignored", path)
+				}
+			}
+		})
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+		size  int64
+	}{
+		{
+			name:  "no ignore files",
+			files: map[string]string{"train.py": "train", "conf/app.yaml": "app"},
+			want:  []string{"conf/app.yaml", "train.py"},
+			size:  8,
+		},
+		{
+			name:  "git metadata",
+			files: map[string]string{".git/HEAD": "ref: refs/heads/main", "train.py": "train"},
+			want:  []string{"train.py"},
+			size:  5,
+		},
+		{
+			name: "ignored directory",
+			files: map[string]string{
+				".fusemlignore":  "venv/\n",
+				"train.py":       "train",
+				"venv/bin/conda": "conda",
+			},
+			want: []string{".fusemlignore", "train.py"},
+			size: 11,
+		},
+		{
+			name: "fusemlignore negates gitignore",
+			files: map[string]string{
+				".gitignore":    "*.csv\n",
+				".fusemlignore": "!labels.csv\n",
+				"data.csv":      "data",
+				"labels.csv":    "labels",
+			},
+			want: []string{".fusemlignore", ".gitignore", "labels.csv"},
+			size: 24,
+		},
+		{
+			name: "nested ignore files",
+			files: map[string]string{
+				".gitignore":        "*.bin\n",
+				"model.bin":         "model",
+				"src/.fusemlignore": "!model.bin\n*.py\n",
+				"src/model.bin":     "model",
+				"src/weights.bin":   "weights",
+				"src/train.py":      "train",
+				"train.py":          "train",
+			},
+			want: []string{".gitignore", "src/.fusemlignore", "src/model.bin", "train.py"},
+			size: 32,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newTestDirectory(t, tt.files)
+			files, size, err := sourceFiles(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, f := range files {
+				got = append(got, filepath.ToSlash(f))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got files %v, want %v", got, tt.want)
+			}
+			if size != tt.size {
+				t.Errorf("got size %d, want %d", size, tt.size)
+			}
+		})
+	}
+}
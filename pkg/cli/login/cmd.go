@@ -0,0 +1,81 @@
+package login
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/fuseml/fuseml-core/gen/auth"
+	authc "github.com/fuseml/fuseml-core/gen/http/auth/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// loginOptions holds the options for the 'login' sub command
+type loginOptions struct {
+	client.Clients
+	global   *common.GlobalOptions
+	User     string
+	Password string
+}
+
+// newLoginOptions initializes a loginOptions struct
+func newLoginOptions(gOpt *common.GlobalOptions) *loginOptions {
+	return &loginOptions{global: gOpt}
+}
+
+// NewCmdLogin creates and returns the cobra command for the `login` CLI command
+func NewCmdLogin(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := newLoginOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "login [--user USER] [--password PASSWORD]",
+		Short: "Log in to the FuseML service",
+		Long:  `Authenticate with the FuseML service and save the issued token into the CLI configuration file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVar(&o.User, "user", "", "name of the FuseML user")
+	cmd.Flags().StringVar(&o.Password, "password", "", "password of the FuseML user")
+
+	return cmd
+}
+
+func (o *loginOptions) validate() error {
+	if o.User == "" || o.Password == "" {
+		return errors.New("the user name and password must be provided")
+	}
+	return nil
+}
+
+func (o *loginOptions) run() error {
+	request, err := authc.BuildLoginPayload(o.User, o.Password)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.AuthClient.Login()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	token := response.(*auth.AuthToken)
+
+	viper.Set("token", token.Token)
+	if err := common.WriteConfigFile(); err != nil {
+		return errors.Wrap(err, "Error writing config file")
+	}
+
+	fmt.Printf("Logged in as %s, the token expires at %s\n", token.User, token.ExpiresAt)
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// AddMemberOptions holds the options for 'project add-member' sub command
+type AddMemberOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	Name   string
+	User   string
+	Role   string
+}
+
+// NewAddMemberOptions creates a AddMemberOptions struct
+func NewAddMemberOptions(o *common.GlobalOptions) *AddMemberOptions {
+	return &AddMemberOptions{global: o}
+}
+
+// NewSubCmdProjectAddMember creates and returns the cobra command for the `project add-member` CLI command
+func NewSubCmdProjectAddMember(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewAddMemberOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `add-member {-n|--name NAME} {--user USER} [--role viewer|editor|admin]`,
+		Short: "Add project members.",
+		Long:  `Add a user to a project, or change the role of a project member`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().StringVar(&o.User, "user", "", "name of the user")
+	cmd.Flags().StringVar(&o.Role, "role", "viewer", "role granted to the user in the project (viewer, editor or admin)")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+func (o *AddMemberOptions) validate() error {
+	return common.ValidateEnumArgument("role", o.Role, []string{"viewer", "editor", "admin"})
+}
+
+func (o *AddMemberOptions) run() error {
+	_, err := o.ProjectClient.AddMember(o.Name, o.User, o.Role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s added to project %s with the %s role\n", o.User, o.Name, o.Role)
+
+	return nil
+}
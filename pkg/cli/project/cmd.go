@@ -18,6 +18,10 @@ func NewCmdProject(c *common.GlobalOptions) *cobra.Command {
 	cmd.AddCommand(NewSubCmdProjectGet(c))
 	cmd.AddCommand(NewSubCmdProjectList(c))
 	cmd.AddCommand(NewSubCmdProjectSet(c))
+	cmd.AddCommand(NewSubCmdProjectUpdate(c))
+	cmd.AddCommand(NewSubCmdProjectAddMember(c))
+	cmd.AddCommand(NewSubCmdProjectRemoveMember(c))
+	cmd.AddCommand(NewSubCmdProjectSetQuota(c))
 
 	return cmd
 }
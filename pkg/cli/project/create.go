@@ -34,7 +34,7 @@ func NewSubCmdProjectCreate(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Create projects.",
 		Long:  `Create new FuseML project`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -3,6 +3,7 @@ package project
 import (
 	"fmt"
 
+	"github.com/fuseml/fuseml-core/gen/project"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
 	"github.com/spf13/cobra"
@@ -13,6 +14,7 @@ type DeleteOptions struct {
 	client.Clients
 	global *common.GlobalOptions
 	Name   string
+	DryRun bool
 }
 
 // NewDeleteOptions creates a ProjectDeleteOptions struct
@@ -26,11 +28,12 @@ func NewSubCmdProjectDelete(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewDeleteOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   `delete {-n|--name NAME}`,
+		Use:   `delete {-n|--name NAME} [--dry-run]`,
 		Short: "Delete projects.",
-		Long:  `Delete a project from FuseML`,
+		Long: `Delete a project from FuseML, along with its codesets, the workflow assignments to them, the workflow
+runs and applications created for them and the git users of the project`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -38,6 +41,7 @@ func NewSubCmdProjectDelete(gOpt *common.GlobalOptions) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "only list the resources that would be deleted")
 	cmd.MarkFlagRequired("name")
 	return cmd
 }
@@ -47,12 +51,45 @@ func (o *DeleteOptions) validate() error {
 }
 
 func (o *DeleteOptions) run() error {
-	err := o.ProjectClient.Delete(o.Name)
+	res, err := o.ProjectClient.Delete(o.Name, o.DryRun)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Project %s successfully deleted\n", o.Name)
+	if o.DryRun {
+		fmt.Printf("Deleting project %s would remove:\n", o.Name)
+	} else {
+		fmt.Printf("Project %s successfully deleted, along with:\n", o.Name)
+	}
+	printResources(res)
 
 	return nil
 }
+
+// printResources prints the resources of a project, grouped by kind
+func printResources(res *project.ProjectResources) {
+	assignments := make([]string, 0, len(res.Assignments))
+	for _, a := range res.Assignments {
+		assignments = append(assignments, fmt.Sprintf("%s -> %s", a.Workflow, a.Codeset))
+	}
+	for _, r := range []struct {
+		kind  string
+		names []string
+	}{
+		{"codesets", res.Codesets},
+		{"workflow assignments", assignments},
+		{"workflow runs", res.WorkflowRuns},
+		{"applications", res.Applications},
+		{"members", res.Members},
+		{"git users", res.Users},
+	} {
+		if len(r.names) == 0 {
+			fmt.Printf("  %s: none\n", r.kind)
+			continue
+		}
+		fmt.Printf("  %s:\n", r.kind)
+		for _, name := range r.names {
+			fmt.Printf("    - %s\n", name)
+		}
+	}
+}
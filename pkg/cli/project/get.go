@@ -33,7 +33,7 @@ func NewSubCmdProjectGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get projects.",
 		Long:  `Show details about a FuseML project`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -54,7 +54,7 @@ func NewSubCmdProjectList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "List all projects.",
 		Long:  `Retrieve information about Projects registered in FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
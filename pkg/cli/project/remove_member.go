@@ -0,0 +1,61 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// RemoveMemberOptions holds the options for 'project remove-member' sub command
+type RemoveMemberOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	Name   string
+	User   string
+}
+
+// NewRemoveMemberOptions creates a RemoveMemberOptions struct
+func NewRemoveMemberOptions(o *common.GlobalOptions) *RemoveMemberOptions {
+	return &RemoveMemberOptions{global: o}
+}
+
+// NewSubCmdProjectRemoveMember creates and returns the cobra command for the `project remove-member` CLI command
+func NewSubCmdProjectRemoveMember(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewRemoveMemberOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `remove-member {-n|--name NAME} {--user USER}`,
+		Short: "Remove project members.",
+		Long:  `Remove a user from a project`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().StringVar(&o.User, "user", "", "name of the user")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("user")
+	return cmd
+}
+
+func (o *RemoveMemberOptions) validate() error {
+	return nil
+}
+
+func (o *RemoveMemberOptions) run() error {
+	err := o.ProjectClient.RemoveMember(o.Name, o.User)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s removed from project %s\n", o.User, o.Name)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// SetQuotaOptions holds the options for 'project set-quota' sub command
+type SetQuotaOptions struct {
+	client.Clients
+	global            *common.GlobalOptions
+	Name              string
+	MaxCodesets       int
+	MaxConcurrentRuns int
+	MaxWorkspaceSize  string
+}
+
+// NewSetQuotaOptions creates a SetQuotaOptions struct
+func NewSetQuotaOptions(o *common.GlobalOptions) *SetQuotaOptions {
+	return &SetQuotaOptions{global: o}
+}
+
+// NewSubCmdProjectSetQuota creates and returns the cobra command for the `project set-quota` CLI command
+func NewSubCmdProjectSetQuota(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewSetQuotaOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `set-quota {-n|--name NAME} [--max-codesets N] [--max-concurrent-runs N] [--max-workspace-size SIZE]`,
+		Short: "Set project quotas.",
+		Long: `Set the limits on the resources consumed by a project. The limits that are not given are removed.
+Only FuseML admins can change the project quotas.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().IntVar(&o.MaxCodesets, "max-codesets", 0, "maximum number of codesets registered in the project (0 for no limit)")
+	cmd.Flags().IntVar(&o.MaxConcurrentRuns, "max-concurrent-runs", 0, "maximum number of workflow runs in progress for the project codesets (0 for no limit)")
+	cmd.Flags().StringVar(&o.MaxWorkspaceSize, "max-workspace-size", "", "maximum total size of the workspaces used by the workflow runs in progress, e.g. 10Gi (empty for no limit)")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *SetQuotaOptions) validate() error {
+	if o.MaxCodesets < 0 || o.MaxConcurrentRuns < 0 {
+		return fmt.Errorf("the limits cannot be negative")
+	}
+	return nil
+}
+
+func (o *SetQuotaOptions) run() error {
+	_, err := o.ProjectClient.SetQuota(o.Name, o.MaxCodesets, o.MaxConcurrentRuns, o.MaxWorkspaceSize)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Quota of project %s updated\n", o.Name)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/project"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// UpdateOptions holds the options for 'project update' sub command
+type UpdateOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	Name    string
+	Desc    string
+	members common.KeyValueArgs
+}
+
+// NewUpdateOptions creates a UpdateOptions struct
+func NewUpdateOptions(o *common.GlobalOptions) *UpdateOptions {
+	return &UpdateOptions{global: o}
+}
+
+// NewSubCmdProjectUpdate creates and returns the cobra command for the `project update` CLI command
+func NewSubCmdProjectUpdate(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewUpdateOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `update {-n|--name NAME} [--desc DESCRIPTION] [-m|--member USER:ROLE]...`,
+		Short: "Update projects.",
+		Long: `Update the description and members of a project. When members are given, they replace
+the existing project members.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.members.Unpack()
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run(cmd))
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().StringVar(&o.Desc, "desc", "", "project description")
+	cmd.Flags().StringSliceVarP(&o.members.Packed, "member", "m", []string{},
+		"project member and its role (viewer, editor or admin), e.g. alice:editor. One or more may be supplied")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *UpdateOptions) validate() error {
+	for user, role := range o.members.Unpacked {
+		if err := common.ValidateEnumArgument("role of member "+user, role, []string{"viewer", "editor", "admin"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *UpdateOptions) run(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	var desc *string
+	if flags.Changed("desc") {
+		desc = &o.Desc
+	}
+	var members []*project.ProjectMember
+	if flags.Changed("member") {
+		members = make([]*project.ProjectMember, 0, len(o.members.Unpacked))
+		for user, role := range o.members.Unpacked {
+			members = append(members, &project.ProjectMember{User: user, Role: role})
+		}
+	}
+	if desc == nil && members == nil {
+		return fmt.Errorf("nothing to update, set the description or the members of the project")
+	}
+
+	_, err := o.ProjectClient.Update(o.Name, desc, members)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Project %s successfully updated\n", o.Name)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package runnable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fuseml/fuseml-core/gen/runnable"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// BuildOptions holds the options for 'runnable build' sub command
+type BuildOptions struct {
+	client.Clients
+	global         *common.GlobalOptions
+	ID             string
+	CodesetProject string
+	CodesetName    string
+	GitURL         string
+	Revision       string
+	Dockerfile     string
+	Tag            string
+	RunnableFile   string
+}
+
+// NewBuildOptions initializes a BuildOptions struct
+func NewBuildOptions(o *common.GlobalOptions) *BuildOptions {
+	return &BuildOptions{global: o}
+}
+
+// NewSubCmdRunnableBuild creates and returns the cobra command for the `runnable build` CLI command
+func NewSubCmdRunnableBuild(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewBuildOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use: `build {-n|--id ID} {{-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} | --git-url URL} ` +
+			`[-r|--revision REVISION] [-f|--dockerfile DOCKERFILE] [-t|--tag TAG] [--runnable RUNNABLE_FILE]`,
+		Short: "Build runnables.",
+		Long: `Build the container image for a runnable from the sources in a codeset or git repository.
+When the build succeeds, the runnable is registered, or updated if it already exists, to use the image
+stored in the FuseML built-in registry. A runnable descriptor must be supplied if the runnable is not
+already registered.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.ID, "id", "n", "", "runnable ID")
+	cmd.Flags().StringVarP(&o.CodesetProject, "codeset-project", "p", "", "project of the codeset providing the sources")
+	cmd.Flags().StringVarP(&o.CodesetName, "codeset-name", "c", "", "name of the codeset providing the sources")
+	cmd.Flags().StringVar(&o.GitURL, "git-url", "", "URL of the git repository providing the sources")
+	cmd.Flags().StringVarP(&o.Revision, "revision", "r", "main", "git revision (branch, tag or commit) of the sources")
+	cmd.Flags().StringVarP(&o.Dockerfile, "dockerfile", "f", "Dockerfile", "path to the Dockerfile, relative to the root of the sources")
+	cmd.Flags().StringVarP(&o.Tag, "tag", "t", "latest", "tag for the resulting container image")
+	cmd.Flags().StringVar(&o.RunnableFile, "runnable", "", "runnable descriptor file, used to register the runnable")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (o *BuildOptions) validate() error {
+	if o.GitURL == "" && (o.CodesetProject == "" || o.CodesetName == "") {
+		return errors.New("either a codeset (--codeset-project and --codeset-name) or --git-url must be specified")
+	}
+	if o.GitURL != "" && (o.CodesetProject != "" || o.CodesetName != "") {
+		return errors.New("a codeset and --git-url cannot be specified at the same time")
+	}
+	return nil
+}
+
+func (o *BuildOptions) run() error {
+	request := &runnable.BuildPayload{
+		ID:         o.ID,
+		Revision:   o.Revision,
+		Dockerfile: o.Dockerfile,
+		Tag:        o.Tag,
+		Token:      o.TokenRef(),
+		Key:        o.APIKeyRef(),
+	}
+	if o.GitURL != "" {
+		request.GitURL = &o.GitURL
+	} else {
+		request.CodesetProject = &o.CodesetProject
+		request.CodesetName = &o.CodesetName
+	}
+
+	if o.RunnableFile != "" {
+		var runnableDesc string
+		err := common.LoadFileIntoVar(o.RunnableFile, &runnableDesc)
+		if err != nil {
+			return err
+		}
+		request.Runnable, err = runnableFromDescriptor(runnableDesc)
+		if err != nil {
+			return err
+		}
+	}
+
+	response, err := o.RunnableClient.Build()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	build := response.(*runnable.RunnableBuild)
+
+	fmt.Printf("Building image %s for runnable %s: %s\n", build.Image, build.Runnable, build.Status)
+	if build.URL != nil {
+		fmt.Printf("Build details: %s\n", *build.URL)
+	}
+
+	return nil
+}
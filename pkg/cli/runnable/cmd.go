@@ -16,6 +16,9 @@ func NewCmdRunnable(c *common.GlobalOptions) *cobra.Command {
 	cmd.AddCommand(NewSubCmdRunnableRegister(c))
 	cmd.AddCommand(NewSubCmdRunnableGet(c))
 	cmd.AddCommand(NewSubCmdRunnableList(c))
+	cmd.AddCommand(NewSubCmdRunnableBuild(c))
+	cmd.AddCommand(NewSubCmdRunnableExport(c))
+	cmd.AddCommand(NewSubCmdRunnableImport(c))
 
 	return cmd
 }
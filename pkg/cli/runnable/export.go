@@ -0,0 +1,91 @@
+package runnable
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+
+	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/gen/runnable"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// ExportOptions holds the options for 'runnable export' sub command
+type ExportOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	ID     string
+}
+
+// NewExportOptions initializes a ExportOptions struct
+func NewExportOptions(o *common.GlobalOptions) *ExportOptions {
+	return &ExportOptions{global: o}
+}
+
+// NewSubCmdRunnableExport creates and returns the cobra command for the `runnable export` CLI command
+func NewSubCmdRunnableExport(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewExportOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `export ID`,
+		Short: "Export runnables.",
+		Long: `Export the descriptor of a runnable registered with FuseML as YAML. The exported descriptor
+can be registered or imported into another FuseML installation.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.ID = cmd.Flags().Arg(0)
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func (o *ExportOptions) validate() error {
+	return nil
+}
+
+func (o *ExportOptions) run() error {
+	request, err := runnablec.BuildGetPayload(o.ID, o.Token, o.APIKey)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.RunnableClient.Get()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	r := response.(*runnable.Runnable)
+
+	// encode the runnable using the same format accepted by the register and import commands. The
+	// creation time is left out, as it is set by FuseML when the runnable is registered.
+	desc, err := yaml.Marshal(runnablec.NewRegisterRequestBody(&runnable.RegisterPayload{
+		ID:                r.ID,
+		Description:       r.Description,
+		Author:            r.Author,
+		Source:            r.Source,
+		Kind:              r.Kind,
+		Container:         r.Container,
+		Input:             r.Input,
+		Output:            r.Output,
+		DefaultInputPath:  r.DefaultInputPath,
+		DefaultOutputPath: r.DefaultOutputPath,
+		Labels:            r.Labels,
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to encode runnable %s: %w", o.ID, err)
+	}
+
+	fmt.Fprintln(os.Stdout, "---")
+	fmt.Fprint(os.Stdout, string(desc))
+
+	return nil
+}
@@ -35,7 +35,7 @@ func NewSubCmdRunnableGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get runnables.",
 		Long:  `Show details about a FuseML runnable`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -53,7 +53,7 @@ func (o *GetOptions) validate() error {
 }
 
 func (o *GetOptions) run() error {
-	request, err := runnablec.BuildGetPayload(o.ID)
+	request, err := runnablec.BuildGetPayload(o.ID, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
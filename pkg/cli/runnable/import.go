@@ -0,0 +1,157 @@
+package runnable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/runnable"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// ImportOptions holds the options for 'runnable import' sub command
+type ImportOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	format  *common.FormattingOptions
+	Path    string
+	Replace bool
+}
+
+// NewImportOptions initializes a ImportOptions struct
+func NewImportOptions(o *common.GlobalOptions) (res *ImportOptions) {
+	res = &ImportOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"ID", "Status", "Error"},
+		nil,
+		nil,
+	)
+
+	return
+}
+
+// NewSubCmdRunnableImport creates and returns the cobra command for the `runnable import` CLI command
+func NewSubCmdRunnableImport(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewImportOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `import {FILE|DIR} [--replace]`,
+		Short: "Import runnables.",
+		Long: `Import one or more runnables into FuseML. The runnable descriptors are read from a YAML or JSON file,
+which may contain several YAML documents, or from all the YAML and JSON files in a directory.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Path = cmd.Flags().Arg(0)
+			common.CheckErr(o.InitializeClients(gOpt))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().BoolVar(&o.Replace, "replace", false, "replace the descriptors of runnables that are already registered")
+	o.format.AddMultiValueFormattingFlags(cmd)
+
+	return cmd
+}
+
+func (o *ImportOptions) validate() error {
+	return nil
+}
+
+func (o *ImportOptions) run() error {
+	files, err := descriptorFiles(o.Path)
+	if err != nil {
+		return err
+	}
+
+	request := &runnable.ImportRunnablesPayload{Replace: o.Replace, Token: o.TokenRef(), Key: o.APIKeyRef()}
+	for _, f := range files {
+		runnables, err := readRunnables(f)
+		if err != nil {
+			return err
+		}
+		request.Runnables = append(request.Runnables, runnables...)
+	}
+	if len(request.Runnables) == 0 {
+		return fmt.Errorf("no runnable descriptors found in %s", o.Path)
+	}
+
+	response, err := o.RunnableClient.ImportRunnables()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	o.format.FormatValue(os.Stdout, response)
+
+	return nil
+}
+
+// descriptorFiles returns the list of files with runnable descriptors found at the supplied path
+func descriptorFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// readRunnables decodes all the runnable descriptors from a file
+func readRunnables(path string) ([]*runnable.Runnable, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %s: %w", path, err)
+	}
+
+	runnables := make([]*runnable.Runnable, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode file %s: %w", path, err)
+		}
+		if doc == nil {
+			continue
+		}
+		desc, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode file %s: %w", path, err)
+		}
+		r, err := runnableFromDescriptor(string(desc))
+		if err != nil {
+			return nil, fmt.Errorf("invalid runnable descriptor in %s: %w", path, err)
+		}
+		runnables = append(runnables, r)
+	}
+	return runnables, nil
+}
@@ -6,9 +6,10 @@ import (
 	"os"
 	"strings"
 
-	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/gen/runnable"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 )
@@ -57,7 +58,7 @@ func NewSubCmdRunnableList(gOpt *common.GlobalOptions) *cobra.Command {
 		Long:  `Retrieve information about Runnables registered in FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Labels.Unpack()
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -77,17 +78,29 @@ func (o *ListOptions) validate() error {
 }
 
 func (o *ListOptions) run() error {
-	request, err := runnablec.BuildListPayload(o.ID, o.Kind, o.Labels.Unpacked)
-	if err != nil {
-		return err
-	}
-
-	response, err := o.RunnableClient.List()(context.Background(), request)
+	runnables := []*runnable.Runnable{}
+	err := client.ListAll(func(limit int, cont *string) (*string, error) {
+		response, err := o.RunnableClient.List()(context.Background(), &runnable.ListPayload{
+			ID:       util.RefString(o.ID),
+			Kind:     util.RefString(o.Kind),
+			Labels:   o.Labels.Unpacked,
+			Limit:    &limit,
+			Continue: cont,
+			Token:    o.TokenRef(),
+			Key:      o.APIKeyRef(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		page := response.(*runnable.RunnablePage)
+		runnables = append(runnables, page.Items...)
+		return page.Continue, nil
+	})
 	if err != nil {
 		return err
 	}
 
-	o.format.FormatValue(os.Stdout, response)
+	o.format.FormatValue(os.Stdout, runnables)
 
 	return nil
 }
@@ -33,7 +33,7 @@ func NewSubCmdRunnableRegister(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Register runnables.",
 		Long:  `Register a runnable with FuseML`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(common.LoadFileIntoVar(cmd.Flags().Arg(0), &o.RunnableDesc))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
@@ -49,7 +49,7 @@ func (o *RegisterOptions) validate() error {
 }
 
 func (o *RegisterOptions) run() error {
-	request, err := runnablec.BuildRegisterPayload(o.RunnableDesc)
+	request, err := runnablec.BuildRegisterPayload(o.RunnableDesc, o.Token, o.APIKey)
 	if err != nil {
 		return err
 	}
@@ -65,3 +65,25 @@ func (o *RegisterOptions) run() error {
 
 	return nil
 }
+
+// runnableFromDescriptor decodes and validates a runnable descriptor
+func runnableFromDescriptor(desc string) (*runnable.Runnable, error) {
+	p, err := runnablec.BuildRegisterPayload(desc, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return &runnable.Runnable{
+		ID:                p.ID,
+		Created:           p.Created,
+		Description:       p.Description,
+		Author:            p.Author,
+		Source:            p.Source,
+		Kind:              p.Kind,
+		Container:         p.Container,
+		Input:             p.Input,
+		Output:            p.Output,
+		DefaultInputPath:  p.DefaultInputPath,
+		DefaultOutputPath: p.DefaultOutputPath,
+		Labels:            p.Labels,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 	versionc "github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/health"
 	"github.com/fuseml/fuseml-core/pkg/version"
 
 	"github.com/spf13/cobra"
@@ -34,7 +35,7 @@ func NewCmdVersion(gOpt *common.GlobalOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "display version information",
-		Long:  `Display version information about the CLI and server`,
+		Long:  `Display version information about the CLI and server, along with the server readiness`,
 		Run: func(cmd *cobra.Command, args []string) {
 			common.CheckErr(o.run())
 		},
@@ -47,22 +48,31 @@ func NewCmdVersion(gOpt *common.GlobalOptions) *cobra.Command {
 
 func (o *versionOptions) run() error {
 	var data = struct {
-		Client *version.Info
-		Server *versionc.VersionInfo
+		Client    *version.Info
+		Server    *versionc.VersionInfo
+		Readiness *health.Status
 	}{}
 
 	data.Client = version.GetInfo()
-	err := o.InitializeClients(o.global.URL, o.global.Timeout, o.global.Verbose)
+	err := o.InitializeClients(o.global)
 
 	if err == nil {
 		data.Server, err = o.VersionClient.Get()
 	}
 
+	var readinessErr error
+	if err == nil {
+		data.Readiness, readinessErr = o.VersionClient.Readiness()
+	}
+
 	o.format.FormatValue(os.Stdout, data)
 
 	if err != nil {
 		return fmt.Errorf("could not retrieve server version information: %s", err.Error())
 	}
+	if readinessErr != nil {
+		return fmt.Errorf("could not retrieve server readiness: %s", readinessErr.Error())
+	}
 
 	return nil
 }
@@ -15,6 +15,8 @@ type assignOptions struct {
 	name           string
 	codesetName    string
 	codesetProject string
+	branches       []string
+	tags           []string
 }
 
 func newAssignOptions(o *common.GlobalOptions) *assignOptions {
@@ -24,12 +26,16 @@ func newAssignOptions(o *common.GlobalOptions) *assignOptions {
 func newSubCmdAssign(gOpt *common.GlobalOptions) *cobra.Command {
 	o := newAssignOptions(gOpt)
 	cmd := &cobra.Command{
-		Use:   "assign {-n|--name NAME} {-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} ",
+		Use:   "assign {-n|--name NAME} {-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} [--branch PATTERN]... [--tag PATTERN]...",
 		Short: "Assigns a workflow to a codeset",
 		Long: `Assigning a workflow to a codeset makes any change pushed to the codeset trigger the workflow(s) assigned to it.
-Upon successfully assignment a workflow run is created using the workflow's default inputs and the assigned codeset.`,
+Upon successfully assignment a workflow run is created using the workflow's default inputs and the assigned codeset.
+
+The pushes that trigger the workflow can be restricted to the branches and tags matching the glob patterns given with
+--branch and --tag, where '*' matches any sequence of characters and '?' any single character. Assigning the workflow
+to a codeset it is already assigned to replaces its branch and tag filters.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -39,6 +45,8 @@ Upon successfully assignment a workflow run is created using the workflow's defa
 	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow to be assigned")
 	cmd.Flags().StringVarP(&o.codesetProject, "codeset-project", "p", "", "name of the project to which the codeset belongs")
 	cmd.Flags().StringVarP(&o.codesetName, "codeset-name", "c", "", "name of the codeset to assign the workflow to")
+	cmd.Flags().StringSliceVar(&o.branches, "branch", []string{}, "glob pattern selecting the branches whose pushes trigger the workflow. One or more may be supplied")
+	cmd.Flags().StringSliceVar(&o.tags, "tag", []string{}, "glob pattern selecting the tags whose pushes trigger the workflow. One or more may be supplied")
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("codeset-name")
 	cmd.MarkFlagRequired("codeset-project")
@@ -51,7 +59,7 @@ func (o *assignOptions) validate() error {
 }
 
 func (o *assignOptions) run() error {
-	err := o.WorkflowClient.Assign(o.name, o.codesetProject, o.codesetName)
+	err := o.WorkflowClient.Assign(o.name, o.codesetProject, o.codesetName, o.branches, o.tags)
 	if err != nil {
 		return err
 	}
@@ -26,7 +26,7 @@ func newSubCmdCreate(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Creates a workflow",
 		Long:  `Creates a workflow from a file`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(common.LoadFileIntoVar(cmd.Flags().Arg(0), &o.workflow))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
@@ -26,7 +26,7 @@ func newSubCmdDelete(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Deletes a workflow",
 		Long:  `Delete a workflow and all existing assignments to it.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
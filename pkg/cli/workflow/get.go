@@ -86,7 +86,7 @@ func newSubCmdGet(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Get a workflow",
 		Long:  `Show detailed information from a workflow`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -64,7 +64,7 @@ func newSubCmdList(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists one or more workflows",
 		Long:  `Prints a table of the most important information about workflows. You can filter the list by the workflow name.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -3,6 +3,7 @@ package workflow
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -34,6 +35,16 @@ func formatAssignedCodesets(object interface{}, column string, field interface{}
 	if wa, ok := object.(*workflow.WorkflowAssignment); ok {
 		for i, c := range wa.Codesets {
 			formated += fmt.Sprintf("- name: %s\n  project: %s", c.Name, c.Project)
+			for _, f := range wa.RefFilters {
+				if f.CodesetProject == c.Project && f.CodesetName == c.Name {
+					if len(f.Branches) > 0 {
+						formated += fmt.Sprintf("\n  branches: %s", strings.Join(f.Branches, ", "))
+					}
+					if len(f.Tags) > 0 {
+						formated += fmt.Sprintf("\n  tags: %s", strings.Join(f.Tags, ", "))
+					}
+				}
+			}
 			if i != len(wa.Codesets)-1 {
 				formated += "\n"
 			}
@@ -60,7 +71,7 @@ func newSubCmdListAssignments(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists one or more workflow assignments",
 		Long:  `Prints a table of the most important information about workflow assignments. You can filter the list by the workflow name.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
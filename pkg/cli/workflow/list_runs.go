@@ -54,6 +54,13 @@ func formatRunWorkflowRef(object interface{}, column string, field interface{})
 	return ""
 }
 
+func formatRunRef(object interface{}, column string, field interface{}) string {
+	if wr, ok := object.(*workflow.WorkflowRun); ok && wr.Ref != nil {
+		return strings.TrimPrefix(strings.TrimPrefix(*wr.Ref, "refs/heads/"), "refs/tags/")
+	}
+	return ""
+}
+
 func formatRunStartTime(object interface{}, column string, field interface{}) string {
 	if wr, ok := object.(*workflow.WorkflowRun); ok {
 		startTime, _ := time.Parse(time.RFC3339, wr.StartTime)
@@ -65,10 +72,10 @@ func formatRunStartTime(object interface{}, column string, field interface{}) st
 func newListRunsOptions(o *common.GlobalOptions) (res *listRunsOptions) {
 	res = &listRunsOptions{global: o}
 	res.format = common.NewFormattingOptions(
-		[]string{"Name", "Workflow", "Started", "Duration", "Status"},
+		[]string{"Name", "Workflow", "Ref", "Started", "Duration", "Status"},
 		[]table.SortBy{},
 		common.OutputFormatters{"Duration": formatRunDuration, "Status": formatRunStatus,
-			"Workflow": formatRunWorkflowRef, "Started": formatRunStartTime},
+			"Workflow": formatRunWorkflowRef, "Ref": formatRunRef, "Started": formatRunStartTime},
 	)
 
 	return
@@ -81,7 +88,7 @@ func newSubCmdListRuns(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Lists one or more workflow runs",
 		Long:  `Prints a table of the most important information about workflow runs. You can filter the list by the workflow name, codeset name, codeset project or status.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -28,7 +28,7 @@ func newSubCmdUnassign(gOpt *common.GlobalOptions) *cobra.Command {
 		Short: "Unassign a workflow from a codeset",
 		Long:  `Removes the assignment between a workflow and a codeset.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.InitializeClients(gOpt))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
@@ -23,29 +23,53 @@ func (as *ApplicationStore) Find(ctx context.Context, name string) *domain.Appli
 	return as.items[name]
 }
 
-// GetAll returns all applications of a given type.
-// If type is not specified, return all applications.
-func (as *ApplicationStore) GetAll(ctx context.Context, applicationType *string, applicationWorkflow *string) ([]*domain.Application, error) {
+// GetAll returns the page of applications matching the filter selected by the list options, and the
+// token used to retrieve the next page.
+// If filter is not specified, return all applications.
+func (as *ApplicationStore) GetAll(ctx context.Context, filter *domain.ApplicationFilter, opts *domain.ListOptions) ([]*domain.Application, string, error) {
 	result := make([]*domain.Application, 0, len(as.items))
 	for _, app := range as.items {
-		if applicationWorkflow != nil && app.Workflow != *applicationWorkflow {
-			continue
-		}
-		if applicationType != nil && app.Type != *applicationType {
+		if !filter.Matches(app) {
 			continue
 		}
 		result = append(result, app)
 	}
-	return result, nil
+	if err := domain.SortApplications(result, opts); err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(result))
+	if err != nil {
+		return nil, "", err
+	}
+	return result[start:end], next, nil
 }
 
 // Add adds a new application, based on the Application structure provided as argument
 func (as *ApplicationStore) Add(ctx context.Context, a *domain.Application) (*domain.Application, error) {
-	// TODO What if such application already exist? Should we thrown an error, or silently replace it?
+	if _, exists := as.items[a.Name]; exists {
+		return nil, domain.ErrApplicationExists
+	}
+	a.Version = 1
 	as.items[a.Name] = a
 	return a, nil
 }
 
+// Update replaces an application registered by FuseML, as long as the version of the application
+// provided as argument matches the version of the stored application
+func (as *ApplicationStore) Update(ctx context.Context, a *domain.Application) (*domain.Application, error) {
+	app, exists := as.items[a.Name]
+	if !exists {
+		return nil, domain.ErrApplicationNotFound
+	}
+	if app.Version != a.Version {
+		return nil, domain.ErrApplicationVersionConflict
+	}
+	updated := *a
+	updated.Version++
+	as.items[a.Name] = &updated
+	return &updated, nil
+}
+
 // Delete deletes the application registered by FuseML
 func (as *ApplicationStore) Delete(ctx context.Context, name string) error {
 	if _, exists := as.items[name]; exists {
@@ -53,3 +77,11 @@ func (as *ApplicationStore) Delete(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+// UpdateStatus updates the status of an application registered by FuseML
+func (as *ApplicationStore) UpdateStatus(ctx context.Context, name string, status *domain.ApplicationStatus) error {
+	if app, exists := as.items[name]; exists {
+		app.Status = status
+	}
+	return nil
+}
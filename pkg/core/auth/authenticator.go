@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/subtle"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// tokenIssuer is the issuer of the JWT tokens
+	tokenIssuer = "fuseml-core"
+	// defaultTokenTTL is the time after which the issued tokens expire
+	defaultTokenTTL = 12 * time.Hour
+	// generatedSecretLength is the length of the token signing secret generated when one is not configured
+	generatedSecretLength = 32
+	// workflowKeyPrefix is the prefix of the API keys issued for the workflow steps
+	workflowKeyPrefix = "fuseml-workflow."
+)
+
+// Authenticator issues JWT tokens for the FuseML users and validates the tokens and the static API keys
+// used to authenticate requests.
+// Implements domain.Authenticator interface.
+type Authenticator struct {
+	logger    *zap.SugaredLogger
+	users     domain.UserVerifier
+	workflows domain.WorkflowStore
+	secret    []byte
+	apiKeys   map[string]string
+	tokenTTL  time.Duration
+	clock     clockwork.Clock
+}
+
+// NewAuthenticator creates a new Authenticator with the token signing secret and the static API keys from
+// the configuration. When a secret is not configured a random one is generated, which means that the
+// issued tokens are no longer valid after fuseml-core is restarted.
+func NewAuthenticator(logger *zap.SugaredLogger, users domain.UserVerifier, workflows domain.WorkflowStore,
+	cfg config.AuthConfig) (*Authenticator, error) {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		logger.Warn("the token signing secret was not provided, generating a random one")
+		secret = make([]byte, generatedSecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, errors.Wrap(err, "failed to generate the token signing secret")
+		}
+	}
+
+	apiKeys, err := parseAPIKeys(cfg.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		logger:    logger,
+		users:     users,
+		workflows: workflows,
+		secret:    secret,
+		apiKeys:   apiKeys,
+		tokenTTL:  defaultTokenTTL,
+		clock:     clockwork.NewRealClock(),
+	}, nil
+}
+
+// parseAPIKeys parses a comma separated list of NAME=KEY entries into a map of keys to names
+func parseAPIKeys(value string) (map[string]string, error) {
+	apiKeys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		s := strings.SplitN(entry, "=", 2)
+		if len(s) != 2 || s[0] == "" || s[1] == "" {
+			return nil, errors.New("invalid API key entry, expected NAME=KEY")
+		}
+		apiKeys[s[1]] = s[0]
+	}
+	return apiKeys, nil
+}
+
+// Login verifies the user credentials and issues a token for the user.
+func (a *Authenticator) Login(ctx context.Context, username, password string) (*domain.AuthToken, error) {
+	principal, err := a.users.VerifyUser(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.clock.Now()
+	expiresAt := now.Add(a.tokenTTL)
+	token, err := signToken(&tokenClaims{
+		Issuer:    tokenIssuer,
+		Subject:   principal.Name,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		Admin:     principal.Admin,
+	}, a.secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to issue token")
+	}
+
+	logging.FromContext(ctx, a.logger).Infow("issued token", "user", principal.Name)
+	return &domain.AuthToken{
+		Token:     token,
+		Principal: principal,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ValidateToken validates a token issued by Login and returns the principal it was issued for.
+func (a *Authenticator) ValidateToken(ctx context.Context, token string) (*domain.Principal, error) {
+	claims, err := parseToken(strings.TrimPrefix(token, "Bearer "), a.secret, a.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Principal{
+		Name:  claims.Subject,
+		Admin: claims.Admin,
+	}, nil
+}
+
+// ValidateAPIKey validates a static API key, or a key issued for the steps of a workflow, and returns the
+// principal corresponding to it. The static API keys are meant to be used by automation, so they are granted
+// admin rights. The keys of the workflows can be read by any step of the workflow, so they are only granted
+// the editor role in the projects of the codesets the workflow is assigned to.
+func (a *Authenticator) ValidateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	for k, name := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return &domain.Principal{
+				Name:  name,
+				Admin: true,
+			}, nil
+		}
+	}
+	if strings.HasPrefix(key, workflowKeyPrefix) {
+		s := strings.SplitN(strings.TrimPrefix(key, workflowKeyPrefix), ".", 2)
+		if len(s) == 2 && hmac.Equal([]byte(s[1]), []byte(workflowKeySignature(s[0], a.secret))) {
+			return a.workflowPrincipal(ctx, s[0]), nil
+		}
+	}
+	return nil, domain.ErrInvalidCredentials
+}
+
+// workflowPrincipal returns the principal of the steps of a workflow, restricted to the projects of the codesets
+// the workflow is assigned to
+func (a *Authenticator) workflowPrincipal(ctx context.Context, workflow string) *domain.Principal {
+	principal := &domain.Principal{Name: "workflow:" + workflow, Workflow: workflow, Projects: []string{}}
+	for _, assignment := range a.workflows.GetCodesetAssignments(ctx, workflow) {
+		if !util.StringInSlice(assignment.Codeset.Project, principal.Projects) {
+			principal.Projects = append(principal.Projects, assignment.Codeset.Project)
+		}
+	}
+	sort.Strings(principal.Projects)
+	return principal
+}
+
+// WorkflowAPIKey returns the API key used by the steps of a workflow to call the FuseML API, e.g. to register
+// the applications they deploy. The key is derived from the token signing secret, so it does not need to be
+// stored and it is no longer valid when the secret changes.
+func (a *Authenticator) WorkflowAPIKey(workflow string) string {
+	return workflowKeyPrefix + workflow + "." + workflowKeySignature(workflow, a.secret)
+}
+
+// workflowKeySignature returns the signature of the API key issued for a workflow
+func workflowKeySignature(workflow string, secret []byte) string {
+	return signature("workflow:"+workflow, secret)
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jonboulle/clockwork"
+	"github.com/tektoncd/pipeline/test/diff"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+type fakeUserVerifier struct {
+	users map[string]string
+}
+
+func (v *fakeUserVerifier) VerifyUser(ctx context.Context, username, password string) (*domain.Principal, error) {
+	if p, ok := v.users[username]; ok && p == password {
+		return &domain.Principal{Name: username}, nil
+	}
+	return nil, domain.ErrInvalidCredentials
+}
+
+func newTestAuthenticator(t *testing.T, clock clockwork.Clock) *Authenticator {
+	t.Helper()
+
+	apiKeys, err := parseAPIKeys("ci=ci-key, automation=automation-key")
+	assertNoError(t, err)
+
+	// the mlflow-e2e workflow is assigned to two codesets of the same project
+	workflows := core.NewWorkflowStore()
+	_, err = workflows.AddWorkflow(context.TODO(), &domain.Workflow{Name: "mlflow-e2e"})
+	assertNoError(t, err)
+	for _, name := range []string{"cs1", "cs2"} {
+		_, err = workflows.AddCodesetAssignment(context.TODO(), "mlflow-e2e", &domain.Codeset{Project: "prj", Name: name}, nil, nil)
+		assertNoError(t, err)
+	}
+
+	return &Authenticator{
+		logger:    zap.NewNop().Sugar(),
+		users:     &fakeUserVerifier{users: map[string]string{"user": "password"}},
+		workflows: workflows,
+		secret:    []byte("secret"),
+		apiKeys:   apiKeys,
+		tokenTTL:  time.Hour,
+		clock:     clock,
+	}
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("valid credentials", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		a := newTestAuthenticator(t, clock)
+
+		token, err := a.Login(context.TODO(), "user", "password")
+		assertNoError(t, err)
+
+		if !token.ExpiresAt.Equal(clock.Now().Add(time.Hour)) {
+			t.Errorf("Unexpected token expiration time: %v", token.ExpiresAt)
+		}
+
+		got, err := a.ValidateToken(context.TODO(), token.Token)
+		assertNoError(t, err)
+
+		want := &domain.Principal{Name: "user"}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Principal: %s", diff.PrintWantGot(d))
+		}
+
+		got, err = a.ValidateToken(context.TODO(), "Bearer "+token.Token)
+		assertNoError(t, err)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Principal: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		a := newTestAuthenticator(t, clockwork.NewFakeClock())
+
+		_, err := a.Login(context.TODO(), "user", "wrong")
+		assertError(t, err, domain.ErrInvalidCredentials)
+	})
+}
+
+func TestValidateToken(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		clock := clockwork.NewFakeClock()
+		a := newTestAuthenticator(t, clock)
+
+		token, err := a.Login(context.TODO(), "user", "password")
+		assertNoError(t, err)
+
+		clock.Advance(2 * time.Hour)
+		_, err = a.ValidateToken(context.TODO(), token.Token)
+		assertError(t, err, domain.ErrTokenExpired)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		a := newTestAuthenticator(t, clockwork.NewFakeClock())
+
+		token, err := a.Login(context.TODO(), "user", "password")
+		assertNoError(t, err)
+
+		other := newTestAuthenticator(t, clockwork.NewFakeClock())
+		other.secret = []byte("other-secret")
+		_, err = other.ValidateToken(context.TODO(), token.Token)
+		assertError(t, err, domain.ErrInvalidCredentials)
+
+		_, err = a.ValidateToken(context.TODO(), "not-a-token")
+		assertError(t, err, domain.ErrInvalidCredentials)
+	})
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	a := newTestAuthenticator(t, clockwork.NewFakeClock())
+
+	got, err := a.ValidateAPIKey(context.TODO(), "ci-key")
+	assertNoError(t, err)
+
+	want := &domain.Principal{Name: "ci", Admin: true}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected Principal: %s", diff.PrintWantGot(d))
+	}
+
+	_, err = a.ValidateAPIKey(context.TODO(), "unknown-key")
+	assertError(t, err, domain.ErrInvalidCredentials)
+}
+
+func TestWorkflowAPIKey(t *testing.T) {
+	a := newTestAuthenticator(t, clockwork.NewFakeClock())
+
+	got, err := a.ValidateAPIKey(context.TODO(), a.WorkflowAPIKey("mlflow-e2e"))
+	assertNoError(t, err)
+
+	// the workflow keys are not granted admin rights, only access to the projects of the assigned codesets
+	want := &domain.Principal{Name: "workflow:mlflow-e2e", Workflow: "mlflow-e2e", Projects: []string{"prj"}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected Principal: %s", diff.PrintWantGot(d))
+	}
+
+	got, err = a.ValidateAPIKey(context.TODO(), a.WorkflowAPIKey("unassigned"))
+	assertNoError(t, err)
+
+	want = &domain.Principal{Name: "workflow:unassigned", Workflow: "unassigned", Projects: []string{}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected Principal: %s", diff.PrintWantGot(d))
+	}
+
+	// a key issued for a workflow cannot be used for another workflow
+	forged := strings.Replace(a.WorkflowAPIKey("mlflow-e2e"), "mlflow-e2e", "other", 1)
+	_, err = a.ValidateAPIKey(context.TODO(), forged)
+	assertError(t, err, domain.ErrInvalidCredentials)
+
+	// the keys are not valid once the signing secret changes
+	other := newTestAuthenticator(t, clockwork.NewFakeClock())
+	other.secret = []byte("another-secret")
+	_, err = other.ValidateAPIKey(context.TODO(), a.WorkflowAPIKey("mlflow-e2e"))
+	assertError(t, err, domain.ErrInvalidCredentials)
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	_, err := parseAPIKeys("ci")
+	if err == nil {
+		t.Errorf("Expected error for invalid API key entry")
+	}
+
+	got, err := parseAPIKeys("")
+	assertNoError(t, err)
+	if len(got) != 0 {
+		t.Errorf("Expected no API keys, got %v", got)
+	}
+}
+
+func assertError(t testing.TB, got, want error) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("got error %q want %q", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, got error) {
+	t.Helper()
+
+	if got != nil {
+		t.Errorf("got error %q wants no error", got)
+	}
+}
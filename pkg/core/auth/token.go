@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// tokenHeader is the (constant) header of the JWT tokens issued by FuseML
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// tokenClaims holds the claims of the JWT tokens issued by FuseML
+type tokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	Admin     bool   `json:"admin,omitempty"`
+}
+
+// signToken returns a JWT token holding the claims, signed with HMAC-SHA256 using the secret
+func signToken(claims *tokenClaims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signature(unsigned, secret), nil
+}
+
+// parseToken verifies the signature and the expiration time of a JWT token and returns its claims
+func parseToken(token string, secret []byte, now time.Time) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, domain.ErrInvalidCredentials
+	}
+	expected := signature(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	claims := tokenClaims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if claims.Issuer != tokenIssuer || claims.Subject == "" {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, domain.ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+func signature(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -2,10 +2,13 @@ package core
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 type codesetID struct {
@@ -13,21 +16,38 @@ type codesetID struct {
 	project string
 }
 
-// GitCodesetStore describes a structure that accesses codeset store implemented in git
+// GitCodesetStore describes a structure that accesses codeset store implemented in git. The codesets
+// are hosted by the FuseML git server, except for the codesets tracking an external repository, which
+// are kept in the external store and accessed through the codeset provider.
+// The subscribers are only kept in memory: they are expected to subscribe again when the store is created
+// (e.g. from the persisted workflow assignments).
 type GitCodesetStore struct {
 	gitAdmin    domain.GitAdminClient
+	external    domain.ExternalCodesetStore
+	credentials domain.CodesetCredentialsStore
+	provider    domain.CodesetProvider
+	// mu guards the subscribers, which are updated by concurrent requests
+	mu          sync.RWMutex
 	subscribers map[codesetID][]domain.CodesetSubscriber
 }
 
 // NewGitCodesetStore returns codeset store instance
-func NewGitCodesetStore(gitAdmin domain.GitAdminClient) *GitCodesetStore {
+func NewGitCodesetStore(gitAdmin domain.GitAdminClient, external domain.ExternalCodesetStore,
+	credentials domain.CodesetCredentialsStore, provider domain.CodesetProvider) *GitCodesetStore {
 	subscribers := make(map[codesetID][]domain.CodesetSubscriber)
-	return &GitCodesetStore{gitAdmin, subscribers}
+	return &GitCodesetStore{gitAdmin: gitAdmin, external: external, credentials: credentials, provider: provider, subscribers: subscribers}
 }
 
 // Find returns a codeset identified by project and name
 func (cs *GitCodesetStore) Find(ctx context.Context, project, name string) (*domain.Codeset, error) {
-	result, err := cs.gitAdmin.GetRepository(project, name)
+	result, err := cs.external.GetCodeset(ctx, project, name)
+	if err == nil {
+		return result, nil
+	}
+	if err != domain.ErrCodesetNotFound {
+		return nil, errors.Wrap(err, "Fetching Codeset failed")
+	}
+	result, err = cs.gitAdmin.GetRepository(ctx, project, name)
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Codeset failed")
 	}
@@ -40,11 +60,15 @@ func (cs *GitCodesetStore) Delete(ctx context.Context, project, name string) err
 	if err != nil {
 		return nil
 	}
-	// notify codeset subscribers about a codeset being deleted
-	for _, subscriber := range cs.subscribers[codesetID{name, project}] {
+	// notify codeset subscribers about a codeset being deleted, outside of the lock as they may unsubscribe
+	for _, subscriber := range cs.getSubscribers(codeset) {
 		subscriber.OnDeletingCodeset(ctx, codeset)
 	}
-	err = cs.gitAdmin.DeleteRepository(project, name)
+	if codeset.IsTracked() {
+		err = cs.deleteTracked(ctx, codeset)
+	} else {
+		err = cs.gitAdmin.DeleteRepository(ctx, project, name)
+	}
 	// TODO should we delete the project+user too? If it does not contain any repos?
 	if err != nil {
 		return errors.Wrap(err, "Deleting Codeset failed")
@@ -54,18 +78,53 @@ func (cs *GitCodesetStore) Delete(ctx context.Context, project, name string) err
 	return nil
 }
 
-// GetAll returns all codesets matching given project and label
-func (cs *GitCodesetStore) GetAll(ctx context.Context, project, label *string) ([]*domain.Codeset, error) {
-	result, err := cs.gitAdmin.GetRepositories(project, label)
+// GetAll returns the page of the codesets of the visible projects matching given project and label,
+// selected by the list options, and the token used to retrieve the next page. The archived codesets
+// are only returned when requested.
+func (cs *GitCodesetStore) GetAll(ctx context.Context, project, label *string, archived bool, visible domain.ProjectVisibility,
+	opts *domain.ListOptions) ([]*domain.Codeset, string, error) {
+	repos, err := cs.gitAdmin.GetRepositories(ctx, project, label)
 	if err != nil {
-		return nil, errors.Wrap(err, "Fetching Codesets failed")
+		return nil, "", errors.Wrap(err, "Fetching Codesets failed")
 	}
-	return result, nil
+	tracked, err := cs.external.GetCodesets(ctx, project)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Fetching Codesets failed")
+	}
+	result := []*domain.Codeset{}
+	for _, c := range repos {
+		if (archived || !c.Archived) && visible.Includes(c.Project) {
+			result = append(result, c)
+		}
+	}
+	for _, c := range tracked {
+		if (label == nil || util.StringInSlice(*label, c.Labels)) && (archived || !c.Archived) && visible.Includes(c.Project) {
+			result = append(result, c)
+		}
+	}
+	err = opts.SortItems(result, "project", domain.SortKeys{
+		"name":    func(i int) string { return result[i].Name + "/" + result[i].Project },
+		"project": func(i int) string { return result[i].Project + "/" + result[i].Name },
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(result))
+	if err != nil {
+		return nil, "", err
+	}
+	return result[start:end], next, nil
 }
 
-// CreateWebhook adds a new webhook to a codeset
-func (cs *GitCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset, listenerURL string) (*int64, error) {
-	hookID, err := cs.gitAdmin.CreateRepoWebhook(c.Project, c.Name, &listenerURL)
+// CreateWebhook adds a new webhook to a codeset, signing the events it sends with the secret. Only the
+// pushes to the branches selected by the refs filter are sent, as well as all the tag pushes. No webhook
+// is created for the codesets tracking an external repository, whose new commits are found by polling.
+func (cs *GitCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset, listenerURL, secret string,
+	refs *domain.CodesetRefFilter) (*int64, error) {
+	if c.IsTracked() {
+		return nil, nil
+	}
+	hookID, err := cs.gitAdmin.CreateRepoWebhook(ctx, c.Project, c.Name, &listenerURL, secret, refs)
 	if err != nil {
 		return nil, errors.Wrap(err, "Creating webhook failed")
 	}
@@ -74,16 +133,24 @@ func (cs *GitCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset,
 
 // DeleteWebhook deletes a webhook from a codeset
 func (cs *GitCodesetStore) DeleteWebhook(ctx context.Context, c *domain.Codeset, hookID *int64) error {
-	err := cs.gitAdmin.DeleteRepoWebhook(c.Project, c.Name, hookID)
+	err := cs.gitAdmin.DeleteRepoWebhook(ctx, c.Project, c.Name, hookID)
 	if err != nil {
 		return errors.Wrap(err, "Deleting webhook failed")
 	}
 	return nil
 }
 
-// Add creates new codeset
+// Add creates new codeset. The codesets registered from an external repository are either mirrored
+// into the FuseML git server, or tracked directly. No git credentials are returned for them, as their
+// code cannot be pushed.
 func (cs *GitCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.Codeset, *string, *string, error) {
-	username, password, err := cs.gitAdmin.PrepareRepository(c, nil)
+	if c.Source != nil {
+		return cs.addExternal(ctx, c)
+	}
+	if _, err := cs.external.GetCodeset(ctx, c.Project, c.Name); err == nil {
+		return nil, nil, nil, domain.ErrCodesetExists
+	}
+	username, password, err := cs.gitAdmin.PrepareRepository(ctx, c, nil)
 	if err != nil {
 		return nil, nil, nil, errors.Wrap(err, "Preparing Repository failed")
 	}
@@ -91,22 +158,244 @@ func (cs *GitCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.
 	return c, username, password, nil
 }
 
-// Subscribe adds a subscriber interested on operations performed on a specific codeset
+// Update sets the description, labels and archived flag of a codeset
+func (cs *GitCodesetStore) Update(ctx context.Context, c *domain.Codeset) (*domain.Codeset, error) {
+	tracked, err := cs.external.GetCodeset(ctx, c.Project, c.Name)
+	if err == nil {
+		tracked.Description, tracked.Labels, tracked.Archived = c.Description, c.Labels, c.Archived
+		if err := cs.external.UpdateCodeset(ctx, tracked); err != nil {
+			return nil, errors.Wrap(err, "Updating Codeset failed")
+		}
+		return tracked, nil
+	}
+	if err != domain.ErrCodesetNotFound {
+		return nil, errors.Wrap(err, "Fetching Codeset failed")
+	}
+	if err := cs.gitAdmin.UpdateRepository(ctx, c); err != nil {
+		return nil, errors.Wrap(err, "Updating Codeset failed")
+	}
+	return cs.Find(ctx, c.Project, c.Name)
+}
+
+// addExternal registers a codeset from an external repository
+func (cs *GitCodesetStore) addExternal(ctx context.Context, c *domain.Codeset) (*domain.Codeset, *string, *string, error) {
+	if err := c.Source.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := cs.external.GetCodeset(ctx, c.Project, c.Name); err == nil {
+		return nil, nil, nil, domain.ErrCodesetExists
+	}
+	var err error
+	if c.Source.Mode == domain.CodesetSourceMirror {
+		// the mirror credentials are kept by the FuseML git server
+		err = cs.gitAdmin.MirrorRepository(ctx, c)
+		if err != nil && err != domain.ErrCodesetExists {
+			err = errors.Wrap(err, "Mirroring Repository failed")
+		}
+	} else {
+		err = cs.addTracked(ctx, c)
+	}
+	c.Source.Username, c.Source.Password = "", ""
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return c, nil, nil, nil
+}
+
+// addTracked registers a codeset tracking an external repository directly, after checking that the
+// repository can be accessed with the given credentials. The commits the repository branches and tags
+// point to are recorded, so that only the newer ones trigger the assigned workflows.
+func (cs *GitCodesetStore) addTracked(ctx context.Context, c *domain.Codeset) error {
+	if _, err := cs.gitAdmin.GetRepository(ctx, c.Project, c.Name); err == nil {
+		return domain.ErrCodesetExists
+	}
+	branches, tags, err := cs.provider.GetRefs(ctx, c.Source)
+	if err != nil {
+		return errors.Wrapf(domain.ErrInvalidCodesetSource, "Accessing Repository failed: %v", err)
+	}
+	c.Source.DefaultBranch, err = cs.provider.GetDefaultBranch(ctx, c.Source)
+	if err != nil {
+		return errors.Wrap(err, "Accessing Repository failed")
+	}
+	c.Source.Heads = domain.RefHeads(branches, tags)
+	c.Source.Polled = time.Now()
+	c.URL = c.Source.URL
+
+	// the codesets are grouped in projects hosted by the FuseML git server
+	if _, err := cs.gitAdmin.CreateProject(ctx, c.Project, "", true); err != nil {
+		return errors.Wrap(err, "Creating Project failed")
+	}
+	if c.Source.Username != "" || c.Source.Password != "" {
+		if err := cs.credentials.SetCodesetCredentials(ctx, c, c.Source.Username, c.Source.Password); err != nil {
+			return errors.Wrap(err, "Storing Repository credentials failed")
+		}
+	}
+	stored := *c
+	source := *c.Source
+	source.Username, source.Password = "", ""
+	stored.Source = &source
+	if err := cs.external.AddCodeset(ctx, &stored); err != nil {
+		cs.credentials.DeleteCodesetCredentials(ctx, c)
+		return err
+	}
+	return nil
+}
+
+// deleteTracked removes a codeset tracking an external repository, as well as its credentials
+func (cs *GitCodesetStore) deleteTracked(ctx context.Context, c *domain.Codeset) error {
+	if err := cs.credentials.DeleteCodesetCredentials(ctx, c); err != nil {
+		return err
+	}
+	return cs.external.DeleteCodeset(ctx, c.Project, c.Name)
+}
+
+// trackedSource returns the source of a codeset tracking an external repository, with the credentials
+// used to access the repository
+func (cs *GitCodesetStore) trackedSource(ctx context.Context, c *domain.Codeset) (*domain.CodesetSource, error) {
+	source := *c.Source
+	var err error
+	source.Username, source.Password, err = cs.credentials.GetCodesetCredentials(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Repository credentials failed")
+	}
+	return &source, nil
+}
+
+// GetVersions returns the branches and tags of a codeset, as well as its most recent commits reachable
+// from the given ref (the default branch when empty)
+func (cs *GitCodesetStore) GetVersions(ctx context.Context, project, name, ref string, commits int) (*domain.CodesetVersions, error) {
+	if c, err := cs.external.GetCodeset(ctx, project, name); err == nil {
+		return cs.getTrackedVersions(ctx, c, ref, commits)
+	}
+	branches, err := cs.gitAdmin.GetRepoBranches(ctx, project, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset branches failed")
+	}
+	tags, err := cs.gitAdmin.GetRepoTags(ctx, project, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset tags failed")
+	}
+	versions := &domain.CodesetVersions{Branches: branches, Tags: tags, Commits: []*domain.CodesetCommit{}}
+	if commits > 0 {
+		versions.Commits, err = cs.gitAdmin.GetRepoCommits(ctx, project, name, ref, commits)
+		if err != nil {
+			return nil, errors.Wrap(err, "Fetching Codeset commits failed")
+		}
+	}
+	return versions, nil
+}
+
+// getTrackedVersions returns the versions of a codeset tracking an external repository, read from the repository
+func (cs *GitCodesetStore) getTrackedVersions(ctx context.Context, c *domain.Codeset, ref string, commits int) (*domain.CodesetVersions, error) {
+	source, err := cs.trackedSource(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	branches, tags, err := cs.provider.GetRefs(ctx, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset refs failed")
+	}
+	versions := &domain.CodesetVersions{Branches: branches, Tags: tags, Commits: []*domain.CodesetCommit{}}
+	if commits > 0 {
+		versions.Commits, err = cs.provider.GetCommits(ctx, source, ref, commits)
+		if err != nil {
+			return nil, errors.Wrap(err, "Fetching Codeset commits failed")
+		}
+	}
+	return versions, nil
+}
+
+// AddTag tags a codeset commit, branch or tag as a named codeset version. The codesets registered from an
+// external repository, as well as the archived ones, cannot be tagged.
+func (cs *GitCodesetStore) AddTag(ctx context.Context, project, name, tag, commit, message string) (*domain.CodesetRef, error) {
+	c, err := cs.Find(ctx, project, name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Source != nil {
+		return nil, domain.ErrCodesetReadOnly
+	}
+	if c.Archived {
+		return nil, domain.ErrCodesetArchived
+	}
+	ref, err := cs.gitAdmin.CreateRepoTag(ctx, project, name, tag, commit, message)
+	if err != nil {
+		return nil, errors.Wrap(err, "Tagging Codeset failed")
+	}
+	return ref, nil
+}
+
+// GetFiles returns the entries of a codeset directory (or the file itself) at the given path and branch, tag
+// or commit (the default branch when empty)
+func (cs *GitCodesetStore) GetFiles(ctx context.Context, project, name, ref, path string) ([]*domain.CodesetFile, error) {
+	path, err := domain.CleanCodesetPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if c, err := cs.external.GetCodeset(ctx, project, name); err == nil {
+		source, err := cs.trackedSource(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		return cs.provider.GetFiles(ctx, source, ref, path)
+	}
+	return cs.gitAdmin.GetRepoFiles(ctx, project, name, ref, path)
+}
+
+// GetFile returns the contents of a codeset file at the given path and branch, tag or commit (the default
+// branch when empty)
+func (cs *GitCodesetStore) GetFile(ctx context.Context, project, name, ref, path string) ([]byte, error) {
+	path, err := domain.CleanCodesetPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if c, err := cs.external.GetCodeset(ctx, project, name); err == nil {
+		source, err := cs.trackedSource(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		return cs.provider.GetFile(ctx, source, ref, path)
+	}
+	return cs.gitAdmin.GetRepoFile(ctx, project, name, ref, path)
+}
+
+// Subscribe adds a subscriber interested on operations performed on a specific codeset. Subscribing
+// again has no effect.
 func (cs *GitCodesetStore) Subscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
 	if _, err := cs.Find(ctx, codeset.Project, codeset.Name); err != nil {
 		return err
 	}
-	cs.subscribers[codesetID{codeset.Name, codeset.Project}] = append(cs.subscribers[codesetID{codeset.Name, codeset.Project}], subscriber)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	id := codesetID{codeset.Name, codeset.Project}
+	for _, s := range cs.subscribers[id] {
+		if s == subscriber {
+			return nil
+		}
+	}
+	cs.subscribers[id] = append(cs.subscribers[id], subscriber)
 	return nil
 }
 
 // Unsubscribe deletes a specific codeset subscriber
 func (cs *GitCodesetStore) Unsubscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
-	cs.subscribers[codesetID{codeset.Name, codeset.Project}] = removeSubscriber(cs.subscribers[codesetID{codeset.Name, codeset.Project}], subscriber)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	id := codesetID{codeset.Name, codeset.Project}
+	cs.subscribers[id] = removeSubscriber(cs.subscribers[id], subscriber)
 	return nil
 }
 
+// getSubscribers returns a copy of the subscribers of a codeset
+func (cs *GitCodesetStore) getSubscribers(codeset *domain.Codeset) []domain.CodesetSubscriber {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return append([]domain.CodesetSubscriber{}, cs.subscribers[codesetID{codeset.Name, codeset.Project}]...)
+}
+
 func (cs *GitCodesetStore) deleteSubscribers(codeset *domain.Codeset) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
 	delete(cs.subscribers, codesetID{codeset.Name, codeset.Project})
 }
 
@@ -1,28 +1,356 @@
 package config
 
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tlsconfig"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
 const (
-	// FuseMLNamespace is the kubernetes namespace where FuseML workfloads are created
-	FuseMLNamespace = "fuseml-workloads"
+	// configFileFlag is the command line flag pointing to the configuration file
+	configFileFlag = "config"
+	// configFileEnvVar is the environment variable pointing to the configuration file
+	configFileEnvVar = "FUSEML_CONFIG"
 )
 
-var (
-	// DefaultUserNamePrefix is the default prefix for user names created for each project
-	DefaultUserNamePrefix = "fuseml"
-	// DefaultUserPassword is the default password used when creating new per-project users
-	DefaultUserPassword = "changeme"
-	// DefaultUserEmailDomain is the default domain for user email
-	DefaultUserEmailDomain = "@fuseml.org"
+// Config is the configuration of the FuseML core server. It is loaded from a YAML file, environment
+// variables and command line flags, in increasing order of precedence.
+type Config struct {
+	Server  ServerConfig    `json:"server"`
+	Store   StoreConfig     `json:"store"`
+	Auth    AuthConfig      `json:"auth"`
+	Gitea   GiteaConfig     `json:"gitea"`
+	Tekton  TektonConfig    `json:"tekton"`
+	Tracing tracing.Options `json:"tracing"`
+}
+
+// ServerConfig configures the HTTP and gRPC servers
+type ServerConfig struct {
+	// Host selects the addresses the servers listen on (valid values: dev, prod)
+	Host string `json:"host"`
+	// Domain overrides the host domain specified in the service design
+	Domain string `json:"domain"`
+	// HTTPPort overrides the HTTP port specified in the service design
+	HTTPPort string `json:"httpPort"`
+	// GRPCPort overrides the gRPC port specified in the service design
+	GRPCPort string `json:"grpcPort"`
+	// Secure enables the secure schemes (https and grpcs), requires TLS to be configured
+	Secure bool `json:"secure"`
+	// Debug enables logging of the request and response bodies
+	Debug bool `json:"debug"`
+	// LogFormat is the format of the log lines (valid values: json, console)
+	LogFormat string `json:"logFormat"`
+	// LogLevel is the minimum level of the logged messages (valid values: debug, info, warn, error)
+	LogLevel string `json:"logLevel"`
+	// TLS holds the TLS certificate files
+	TLS tlsconfig.ServerOptions `json:"tls"`
+}
 
-	// HookSecret is the default secret used when creating repository hooks
-	HookSecret = "generatedsecret"
-)
+// StoreConfig configures the persistent store
+type StoreConfig struct {
+	// Dir is the directory holding the store data
+	Dir string `json:"dir"`
+	// AuditRetention is how long the audit events are kept (e.g. 720h), they are kept forever when not set
+	AuditRetention string `json:"auditRetention"`
+}
+
+// AuditRetentionPeriod returns how long the audit events are kept, 0 when they are kept forever.
+func (c StoreConfig) AuditRetentionPeriod() time.Duration {
+	d, _ := time.ParseDuration(c.AuditRetention)
+	return d
+}
+
+// AuthConfig configures the authentication of the API requests
+type AuthConfig struct {
+	// Secret is used to sign the issued tokens. When not set, a random one is generated.
+	Secret string `json:"secret"`
+	// APIKeys holds the static API keys, as a comma separated list of NAME=KEY entries
+	APIKeys string `json:"apiKeys"`
+}
+
+// GiteaConfig configures the connection to Gitea and the resources created in it
+type GiteaConfig struct {
+	// URL of the Gitea server
+	URL string `json:"url"`
+	// AdminUsername is the name of the Gitea admin user
+	AdminUsername string `json:"adminUsername"`
+	// AdminPassword is the password of the Gitea admin user
+	AdminPassword string `json:"adminPassword"`
+	// UserNamePrefix is the prefix for user names created for each project
+	UserNamePrefix string `json:"userNamePrefix"`
+	// UserEmailDomain is the domain for the email of the users created for each project
+	UserEmailDomain string `json:"userEmailDomain"`
+	// UserPassword is the password used when creating new per-project users
+	UserPassword string `json:"userPassword"`
+	// GenerateUserPassword enables generating random passwords for the per-project users,
+	// instead of using UserPassword
+	GenerateUserPassword bool `json:"generateUserPassword"`
+}
 
-// DefaultUserName returns default user name for new per-project user
-func DefaultUserName(org string) string {
-	return DefaultUserNamePrefix + "-" + org
+// UserName returns the user name for a new per-project user
+func (c GiteaConfig) UserName(org string) string {
+	return c.UserNamePrefix + "-" + org
 }
 
-// DefaultUserEmail returns default email for new per-project user
-func DefaultUserEmail(org string) string {
-	return DefaultUserName(org) + DefaultUserEmailDomain
+// UserEmail returns the email for a new per-project user
+func (c GiteaConfig) UserEmail(org string) string {
+	return c.UserName(org) + c.UserEmailDomain
+}
+
+// TektonConfig configures the Tekton workflow backend
+type TektonConfig struct {
+	// DashboardURL is the URL of the Tekton dashboard
+	DashboardURL string `json:"dashboardURL"`
+	// Namespace is the kubernetes namespace where FuseML workloads are created
+	Namespace string `json:"namespace"`
+	// PipelineRunServiceAccount is the service account used to run the workflows
+	PipelineRunServiceAccount string `json:"pipelineRunServiceAccount"`
+	// TriggersServiceAccount is the service account used by the workflow listeners
+	TriggersServiceAccount string `json:"triggersServiceAccount"`
+	// WorkspaceSize is the size of the volumes created for the workflow runs
+	WorkspaceSize string `json:"workspaceSize"`
+	// Registry is the host of the FuseML container registry
+	Registry string `json:"registry"`
+	// RegistryLocal is the address through which the kubernetes nodes reach the FuseML registry
+	RegistryLocal string `json:"registryLocal"`
+	// BuildPrepImage is the container image running the preparation step of the runnable builds
+	BuildPrepImage string `json:"buildPrepImage"`
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host:      "dev",
+			LogFormat: logging.DefaultFormat,
+			LogLevel:  logging.DefaultLevel,
+		},
+		Store: StoreConfig{
+			Dir: "./data",
+		},
+		Gitea: GiteaConfig{
+			UserNamePrefix:  "fuseml",
+			UserEmailDomain: "@fuseml.org",
+			UserPassword:    "changeme",
+		},
+		Tekton: TektonConfig{
+			Namespace:                 "fuseml-workloads",
+			PipelineRunServiceAccount: "fuseml-workloads",
+			TriggersServiceAccount:    "tekton-triggers",
+			WorkspaceSize:             "2Gi",
+			Registry:                  "registry.fuseml-registry",
+			RegistryLocal:             "127.0.0.1:30500",
+			BuildPrepImage:            "alpine:3.14",
+		},
+		Tracing: tracing.Options{
+			Exporter: tracing.ExporterNone,
+		},
+	}
+}
+
+// setting describes how a configuration value is set from the environment and the command line.
+// Secrets do not have a command line flag, so that they are not exposed in the process list.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	value interface{}
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"host", "FUSEML_HOST", "Server host (valid values: dev, prod)", &c.Server.Host},
+		{"domain", "FUSEML_DOMAIN", "Host domain name (overrides host domain specified in service design)", &c.Server.Domain},
+		{"http-port", "FUSEML_HTTP_PORT", "HTTP port (overrides host HTTP port specified in service design)", &c.Server.HTTPPort},
+		{"grpc-port", "FUSEML_GRPC_PORT", "gRPC port (overrides host gRPC port specified in service design)", &c.Server.GRPCPort},
+		{"secure", "FUSEML_SECURE", "Use secure scheme (https or grpcs), requires --tls-cert and --tls-key", &c.Server.Secure},
+		{"debug", "FUSEML_DEBUG", "Log request and response bodies", &c.Server.Debug},
+		{"log-format", "FUSEML_LOG_FORMAT", "Format of the log lines (valid values: json, console)", &c.Server.LogFormat},
+		{"log-level", "FUSEML_LOG_LEVEL", "Minimum level of the logged messages (valid values: debug, info, warn, error)", &c.Server.LogLevel},
+		{"tls-cert", "FUSEML_TLS_CERT", "PEM encoded TLS certificate file, reloaded when changed", &c.Server.TLS.CertFile},
+		{"tls-key", "FUSEML_TLS_KEY", "PEM encoded TLS private key file, reloaded when changed", &c.Server.TLS.KeyFile},
+		{"tls-client-ca", "FUSEML_TLS_CLIENT_CA", "PEM encoded CA certificates file used to verify the client certificates (enables mTLS)", &c.Server.TLS.ClientCAFile},
+		{"store-dir", "FUSEML_STORE_DIR", "Directory holding the store data", &c.Store.Dir},
+		{"audit-retention", "FUSEML_AUDIT_RETENTION", "How long the audit events are kept (e.g. 720h), kept forever when not set", &c.Store.AuditRetention},
+		{"", "FUSEML_AUTH_SECRET", "", &c.Auth.Secret},
+		{"", "FUSEML_API_KEYS", "", &c.Auth.APIKeys},
+		{"gitea-url", "GITEA_URL", "URL of the Gitea server", &c.Gitea.URL},
+		{"gitea-admin-username", "GITEA_ADMIN_USERNAME", "Name of the Gitea admin user", &c.Gitea.AdminUsername},
+		{"", "GITEA_ADMIN_PASSWORD", "", &c.Gitea.AdminPassword},
+		{"", "FUSEML_USER_PASSWORD", "", &c.Gitea.UserPassword},
+		{"generate-user-password", "FUSEML_GENERATE_USER_PASSWORD", "Generate random passwords for the per-project Gitea users", &c.Gitea.GenerateUserPassword},
+		{"tekton-dashboard-url", "TEKTON_DASHBOARD_URL", "URL of the Tekton dashboard", &c.Tekton.DashboardURL},
+		{"namespace", "FUSEML_NAMESPACE", "Kubernetes namespace where the FuseML workloads are created", &c.Tekton.Namespace},
+		{"workspace-size", "FUSEML_WORKSPACE_SIZE", "Size of the volumes created for the workflow runs", &c.Tekton.WorkspaceSize},
+		{"registry", "FUSEML_REGISTRY", "Host of the FuseML container registry", &c.Tekton.Registry},
+		{"build-prep-image", "FUSEML_BUILD_PREP_IMAGE", "Container image running the preparation step of the runnable builds", &c.Tekton.BuildPrepImage},
+		{"tracing-exporter", "FUSEML_TRACING_EXPORTER", "Exporter of the traces (valid values: none, otlp, stdout)", &c.Tracing.Exporter},
+		{"tracing-endpoint", "FUSEML_TRACING_ENDPOINT", "Address (host:port) of the OTLP collector receiving the traces", &c.Tracing.Endpoint},
+		{"tracing-insecure", "FUSEML_TRACING_INSECURE", "Connect to the OTLP collector without TLS", &c.Tracing.Insecure},
+	}
+}
+
+// Load returns the configuration loaded from the configuration file, the environment variables
+// and the command line arguments, in increasing order of precedence. The configuration file is
+// selected with the --config flag or the FUSEML_CONFIG environment variable.
+func Load(name string, args []string) (*Config, error) {
+	// the command line is parsed twice: first to find the configuration file, then to override
+	// the values loaded from the file and from the environment
+	file := os.Getenv(configFileEnvVar)
+	if err := Default().flagSet(name, &file).Parse(args); err != nil {
+		return nil, err
+	}
+
+	c := Default()
+	if file != "" {
+		if err := c.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := c.flagSet(name, &file).Parse(args); err != nil {
+		return nil, err
+	}
+	return c, c.Validate()
+}
+
+// flagSet returns the command line flags setting the configuration values.
+func (c *Config) flagSet(name string, file *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(file, configFileFlag, *file, fmt.Sprintf("(%s) YAML configuration file", configFileEnvVar))
+	for _, s := range c.settings() {
+		if s.flag == "" {
+			continue
+		}
+		usage := fmt.Sprintf("(%s) %s", s.env, s.usage)
+		switch v := s.value.(type) {
+		case *string:
+			fs.StringVar(v, s.flag, *v, usage)
+		case *bool:
+			fs.BoolVar(v, s.flag, *v, usage)
+		}
+	}
+	return fs
+}
+
+// loadFile reads the configuration values from a YAML file.
+func (c *Config) loadFile(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse the configuration file %s: %w", file, err)
+	}
+	return nil
+}
+
+// loadEnv reads the configuration values from the environment variables.
+func (c *Config) loadEnv() error {
+	for _, s := range c.settings() {
+		value, exists := os.LookupEnv(s.env)
+		if !exists {
+			continue
+		}
+		switch v := s.value.(type) {
+		case *string:
+			*v = value
+		case *bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", value, s.env, err)
+			}
+			*v = b
+		}
+	}
+	return nil
+}
+
+// Validate checks the configuration and returns an error describing all the invalid values.
+func (c *Config) Validate() error {
+	var errs []string
+	invalid := func(format string, v ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, v...))
+	}
+
+	if c.Server.Host != "dev" && c.Server.Host != "prod" {
+		invalid("invalid server host %q (valid hosts: dev|prod)", c.Server.Host)
+	}
+	if _, err := strconv.ParseUint(c.Server.HTTPPort, 10, 16); c.Server.HTTPPort != "" && err != nil {
+		invalid("invalid HTTP port %q", c.Server.HTTPPort)
+	}
+	if _, err := strconv.ParseUint(c.Server.GRPCPort, 10, 16); c.Server.GRPCPort != "" && err != nil {
+		invalid("invalid gRPC port %q", c.Server.GRPCPort)
+	}
+	if err := logging.Validate(c.Server.LogFormat, c.Server.LogLevel); err != nil {
+		invalid("%s", err)
+	}
+	if c.Server.Secure || c.Server.TLS.Enabled() {
+		if err := c.Server.TLS.Validate(); err != nil {
+			invalid("invalid TLS configuration: %s", err)
+		}
+	}
+	if c.Store.Dir == "" {
+		invalid("the store directory was not provided")
+	}
+	if d, err := time.ParseDuration(c.Store.AuditRetention); c.Store.AuditRetention != "" && (err != nil || d < 0) {
+		invalid("invalid audit retention %q", c.Store.AuditRetention)
+	}
+
+	if c.Gitea.URL == "" {
+		invalid("value for gitea URL (GITEA_URL) was not provided")
+	}
+	if c.Gitea.AdminUsername == "" {
+		invalid("value for gitea admin user name (GITEA_ADMIN_USERNAME) was not provided")
+	}
+	if c.Gitea.AdminPassword == "" {
+		invalid("value for gitea admin user password (GITEA_ADMIN_PASSWORD) was not provided")
+	}
+	if c.Gitea.UserNamePrefix == "" {
+		invalid("the prefix for the per-project user names was not provided")
+	}
+	if c.Gitea.UserPassword == "" && !c.Gitea.GenerateUserPassword {
+		invalid("the password for the per-project users was not provided")
+	}
+
+	if c.Tekton.DashboardURL == "" {
+		invalid("value for Tekton Dashboard URL (TEKTON_DASHBOARD_URL) was not provided")
+	}
+	if c.Tekton.Namespace == "" {
+		invalid("the namespace for the FuseML workloads was not provided")
+	}
+	if c.Tekton.PipelineRunServiceAccount == "" || c.Tekton.TriggersServiceAccount == "" {
+		invalid("the service accounts for the Tekton pipeline runs and triggers were not provided")
+	}
+	if _, err := resource.ParseQuantity(c.Tekton.WorkspaceSize); err != nil {
+		invalid("invalid workspace size %q: %s", c.Tekton.WorkspaceSize, err)
+	}
+	if c.Tekton.Registry == "" {
+		invalid("the FuseML registry host was not provided")
+	}
+	if c.Tekton.BuildPrepImage == "" {
+		invalid("the image for the runnable build preparation step was not provided")
+	}
+
+	if err := c.Tracing.Validate(); err != nil {
+		invalid("invalid tracing configuration: %s", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(errs, "\n  - "))
+	}
+	return nil
 }
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setEnv sets environment variables for the duration of a test.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for name, value := range env {
+		name := name
+		previous, exists := os.LookupEnv(name)
+		os.Setenv(name, value)
+		if exists {
+			t.Cleanup(func() { os.Setenv(name, previous) })
+		} else {
+			t.Cleanup(func() { os.Unsetenv(name) })
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	content := `
+server:
+  httpPort: "9000"
+store:
+  dir: /var/lib/fuseml
+gitea:
+  url: http://gitea.file
+  adminUsername: admin
+  adminPassword: secret
+tekton:
+  dashboardURL: http://tekton.file
+  namespace: file-namespace
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			"FUSEML_CONFIG":    file,
+			"GITEA_URL":        "http://gitea.env",
+			"FUSEML_NAMESPACE": "env-namespace",
+			"FUSEML_DEBUG":     "true",
+		})
+		c, err := Load("test", []string{"--namespace", "flag-namespace", "--grpc-port", "9001"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		checks := map[string][2]string{
+			"http port (file)":     {c.Server.HTTPPort, "9000"},
+			"grpc port (flag)":     {c.Server.GRPCPort, "9001"},
+			"store dir (file)":     {c.Store.Dir, "/var/lib/fuseml"},
+			"gitea url (env)":      {c.Gitea.URL, "http://gitea.env"},
+			"namespace (flag)":     {c.Tekton.Namespace, "flag-namespace"},
+			"dashboard url (file)": {c.Tekton.DashboardURL, "http://tekton.file"},
+			"workspace (default)":  {c.Tekton.WorkspaceSize, "2Gi"},
+		}
+		for name, check := range checks {
+			if check[0] != check[1] {
+				t.Errorf("unexpected %s: got %q, want %q", name, check[0], check[1])
+			}
+		}
+		if !c.Server.Debug {
+			t.Error("expected debug to be enabled from the environment")
+		}
+	})
+
+	t.Run("config flag", func(t *testing.T) {
+		c, err := Load("test", []string{"--config", file})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if c.Gitea.URL != "http://gitea.file" {
+			t.Errorf("unexpected gitea url: got %q", c.Gitea.URL)
+		}
+	})
+
+	t.Run("invalid env", func(t *testing.T) {
+		setEnv(t, map[string]string{"FUSEML_CONFIG": file, "FUSEML_SECURE": "maybe"})
+		if _, err := Load("test", nil); err == nil || !strings.Contains(err.Error(), "FUSEML_SECURE") {
+			t.Errorf("expected an error for the invalid FUSEML_SECURE value, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := Load("test", []string{"--config", filepath.Join(dir, "missing.yaml")}); err == nil {
+			t.Error("expected an error for a missing configuration file")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		c := Default()
+		c.Gitea.URL = "http://gitea.test"
+		c.Gitea.AdminUsername = "admin"
+		c.Gitea.AdminPassword = "secret"
+		c.Tekton.DashboardURL = "http://tekton.test"
+		return c
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"missing gitea url", func(c *Config) { c.Gitea.URL = "" }, "GITEA_URL"},
+		{"missing dashboard url", func(c *Config) { c.Tekton.DashboardURL = "" }, "TEKTON_DASHBOARD_URL"},
+		{"invalid host", func(c *Config) { c.Server.Host = "staging" }, "invalid server host"},
+		{"invalid port", func(c *Config) { c.Server.HTTPPort = "http" }, "invalid HTTP port"},
+		{"incomplete tls", func(c *Config) { c.Server.TLS.CertFile = "tls.crt" }, "invalid TLS configuration"},
+		{"secure without tls", func(c *Config) { c.Server.Secure = true }, "invalid TLS configuration"},
+		{"invalid log format", func(c *Config) { c.Server.LogFormat = "text" }, "invalid log format"},
+		{"invalid log level", func(c *Config) { c.Server.LogLevel = "verbose" }, "invalid log level"},
+		{"invalid audit retention", func(c *Config) { c.Store.AuditRetention = "30d" }, "invalid audit retention"},
+		{"invalid workspace size", func(c *Config) { c.Tekton.WorkspaceSize = "large" }, "invalid workspace size"},
+		{"missing build prep image", func(c *Config) { c.Tekton.BuildPrepImage = "" }, "build preparation step"},
+		{"missing user password", func(c *Config) { c.Gitea.UserPassword = "" }, "per-project users"},
+		{"invalid tracing exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }, "invalid exporter"},
+		{"missing otlp endpoint", func(c *Config) { c.Tracing.Exporter = "otlp" }, "OTLP collector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("all errors reported", func(t *testing.T) {
+		err := Default().Validate()
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		for _, want := range []string{"GITEA_URL", "GITEA_ADMIN_USERNAME", "GITEA_ADMIN_PASSWORD", "TEKTON_DASHBOARD_URL"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected the error to mention %s, got %s", want, err)
+			}
+		}
+	})
+
+	t.Run("generated user password", func(t *testing.T) {
+		c := valid()
+		c.Gitea.UserPassword = ""
+		c.Gitea.GenerateUserPassword = true
+		if err := c.Validate(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
+}
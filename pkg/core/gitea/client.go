@@ -1,15 +1,23 @@
 package gitea
 
 import (
-	"log"
+	"context"
+	"encoding/base64"
 	"math/rand"
-	"os"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
-	config "github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
@@ -17,6 +25,7 @@ import (
 type Client interface {
 	GetOrg(orgname string) (*gitea.Organization, *gitea.Response, error)
 	CreateOrg(gitea.CreateOrgOption) (*gitea.Organization, *gitea.Response, error)
+	EditOrg(string, gitea.EditOrgOption) (*gitea.Response, error)
 	GetUserInfo(string) (*gitea.User, *gitea.Response, error)
 	AdminCreateUser(gitea.CreateUserOption) (*gitea.User, *gitea.Response, error)
 	AdminDeleteUser(string) (*gitea.Response, error)
@@ -25,17 +34,27 @@ type Client interface {
 	ListTeamMembers(int64, gitea.ListTeamMembersOptions) ([]*gitea.User, *gitea.Response, error)
 	GetRepo(string, string) (*gitea.Repository, *gitea.Response, error)
 	CreateOrgRepo(string, gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error)
+	MigrateRepo(gitea.MigrateRepoOption) (*gitea.Repository, *gitea.Response, error)
 	AddRepoTopic(string, string, string) (*gitea.Response, error)
+	SetRepoTopics(string, string, []string) (*gitea.Response, error)
+	EditRepo(string, string, gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error)
 	ListRepoHooks(string, string, gitea.ListHooksOptions) ([]*gitea.Hook, *gitea.Response, error)
 	ListOrgRepos(string, gitea.ListOrgReposOptions) ([]*gitea.Repository, *gitea.Response, error)
 	CreateRepoHook(string, string, gitea.CreateHookOption) (*gitea.Hook, *gitea.Response, error)
 	DeleteRepoHook(string, string, int64) (*gitea.Response, error)
 	ListRepoTopics(string, string, gitea.ListRepoTopicsOptions) ([]string, *gitea.Response, error)
+	ListRepoBranches(string, string, gitea.ListRepoBranchesOptions) ([]*gitea.Branch, *gitea.Response, error)
+	ListRepoTags(string, string, gitea.ListRepoTagsOptions) ([]*gitea.Tag, *gitea.Response, error)
+	ListRepoCommits(string, string, gitea.ListCommitOptions) ([]*gitea.Commit, *gitea.Response, error)
+	CreateRelease(string, string, gitea.CreateReleaseOption) (*gitea.Release, *gitea.Response, error)
+	ListContents(string, string, string, string) ([]*gitea.ContentsResponse, *gitea.Response, error)
+	GetContents(string, string, string, string) (*gitea.ContentsResponse, *gitea.Response, error)
 	ListMyOrgs(gitea.ListOrgsOptions) ([]*gitea.Organization, *gitea.Response, error)
 	ListUserOrgs(string, gitea.ListOrgsOptions) ([]*gitea.Organization, *gitea.Response, error)
 	DeleteRepo(string, string) (*gitea.Response, error)
 	DeleteOrg(string) (*gitea.Response, error)
 	DeleteOrgMembership(org, user string) (*gitea.Response, error)
+	ServerVersion() (string, *gitea.Response, error)
 }
 
 // AdminClient is the struct holding information about gitea client
@@ -43,15 +62,12 @@ type Client interface {
 type AdminClient struct {
 	giteaClient Client
 	url         string
-	logger      *log.Logger
+	logger      *zap.SugaredLogger
+	config      config.GiteaConfig
 }
 
 const (
-	errGITEAURLMissing           = giteaErr("Value for gitea URL (GITEA_URL) was not provided.")
-	errGITEAADMINUSERNAMEMissing = giteaErr("Value for gitea admin user name (GITEA_ADMIN_USERNAME) was not provided.")
-	errGITEAADMINPASSWORDMissing = giteaErr("Value for gitea admin user password (GITEA_ADMIN_PASSWORD) was not provided.")
-	errRepoNotFound              = giteaErr("Repository by that name not found")
-	errProjectNotEmpty           = giteaErr("Project has still codesets assigned. Delete them first")
+	errProjectNotEmpty = giteaErr("Project has still codesets assigned. Delete them first")
 )
 
 type giteaErr string
@@ -60,53 +76,46 @@ func (e giteaErr) Error() string {
 	return string(e)
 }
 
+// listPageSize is the number of items requested per page when listing all the branches or tags of a
+// repository, which is the maximum page size allowed by Gitea by default
+const listPageSize = 50
+
 var lettersForPassword = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 var generatedPasswordLength = 16
 
-// For now, turn off the password generation
-// But we need to fine a way how user can alter this value (env variable, config file, client param...)
-var generateUserPassword = false
-
 // NewAdminClient creates a new gitea client and performs authentication
-// from the credentials provided as env variables
-func NewAdminClient(logger *log.Logger) (*AdminClient, error) {
-
-	url, exists := os.LookupEnv("GITEA_URL")
-	if !exists {
-		return nil, errGITEAURLMissing
-	}
-	username, exists := os.LookupEnv("GITEA_ADMIN_USERNAME")
-	if !exists {
-		return nil, errGITEAADMINUSERNAMEMissing
-	}
-	password, exists := os.LookupEnv("GITEA_ADMIN_PASSWORD")
-	if !exists {
-		return nil, errGITEAADMINPASSWORDMissing
-	}
-
-	client, err := gitea.NewClient(url)
+// with the admin credentials from the configuration
+func NewAdminClient(logger *zap.SugaredLogger, cfg config.GiteaConfig, m *metrics.Metrics) (*AdminClient, error) {
+	httpClient := &http.Client{Transport: m.InstrumentTransport("gitea", http.DefaultTransport)}
+	client, err := gitea.NewClient(cfg.URL, gitea.SetHTTPClient(httpClient))
 	if err != nil {
 		return nil, errors.Wrap(err, "gitea client failed")
 	}
 
-	client.SetBasicAuth(username, password)
+	client.SetBasicAuth(cfg.AdminUsername, cfg.AdminPassword)
 
-	logger.Printf("Using GITEA from: %s", url)
+	logger.Infow("Using gitea", "url", cfg.URL)
 
 	return &AdminClient{
 		giteaClient: client,
-		url:         url,
+		url:         cfg.URL,
 		logger:      logger,
+		config:      cfg,
 	}, nil
 }
 
-func generateUserName(org string) string {
-	return config.DefaultUserName(org)
+// log returns the logger for the operations performed while serving the request in the context
+func (gac *AdminClient) log(ctx context.Context) *zap.SugaredLogger {
+	return logging.FromContext(ctx, gac.logger)
+}
+
+func (gac *AdminClient) generateUserName(org string) string {
+	return gac.config.UserName(org)
 }
 
-func getUserPassword() string {
-	if !generateUserPassword {
-		return config.DefaultUserPassword
+func (gac *AdminClient) getUserPassword() string {
+	if !gac.config.GenerateUserPassword {
+		return gac.config.UserPassword
 	}
 	p := make([]rune, generatedPasswordLength)
 	for i := range p {
@@ -120,10 +129,22 @@ func (gac *AdminClient) GetGiteaURL() (string, error) {
 	return gac.url, nil
 }
 
+// CheckReadiness checks that the gitea server answers
+func (gac *AdminClient) CheckReadiness(ctx context.Context) error {
+	if _, _, err := gac.giteaClient.ServerVersion(); err != nil {
+		return errors.Wrap(err, "gitea server is not reachable")
+	}
+	return nil
+}
+
 // CreateProject creates a Project (= implemented as Organization in git).
 // If ignoreExisting argument is true, the call will not fail when a project with same name already exists.
-func (gac *AdminClient) CreateProject(name, desc string, ignoreExisting bool) (*domain.Project, error) {
-	gac.logger.Printf("Creating project %s....", name)
+func (gac *AdminClient) CreateProject(ctx context.Context, name, desc string, ignoreExisting bool) (_ *domain.Project, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.CreateProject", tracing.ProjectKey.String(name))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, name)
+	log.Info("Creating project")
 
 	_, resp, err := gac.giteaClient.GetOrg(name)
 	if resp == nil && err != nil {
@@ -131,7 +152,7 @@ func (gac *AdminClient) CreateProject(name, desc string, ignoreExisting bool) (*
 	}
 
 	if resp != nil && resp.StatusCode == 200 {
-		gac.logger.Printf("Project already exists.")
+		log.Info("Project already exists")
 		if ignoreExisting {
 			return nil, nil
 		}
@@ -154,29 +175,33 @@ func (gac *AdminClient) CreateProject(name, desc string, ignoreExisting bool) (*
 }
 
 // CreateOrg creates an Org in gitea. Does not return an error if it already exists
-func (gac *AdminClient) createOrganizationIfNotPresent(org string) error {
+func (gac *AdminClient) createOrganizationIfNotPresent(ctx context.Context, org string) error {
 
-	_, err := gac.CreateProject(org, "", true)
+	_, err := gac.CreateProject(ctx, org, "", true)
 	return err
 }
 
 // CreateUser creates user assigned to current project
-func (gac *AdminClient) CreateUser(org string) (*string, *string, error) {
-	username := generateUserName(org)
-	password := getUserPassword()
+func (gac *AdminClient) CreateUser(ctx context.Context, org string) (_, _ *string, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.CreateUser", tracing.ProjectKey.String(org))
+	defer tracing.End(span, &err)
+
+	username := gac.generateUserName(org)
+	log := gac.log(ctx).With(logging.ProjectKey, org, "user", username)
+	password := gac.getUserPassword()
 	user, resp, err := gac.giteaClient.GetUserInfo(username)
 	if resp == nil && err != nil {
 		return nil, nil, errors.Wrap(err, "Failed to make get user request")
 	}
 	if user != nil && user.ID != 0 {
-		gac.logger.Println("User already exists")
+		log.Info("User already exists")
 		return nil, nil, nil
 	}
 
-	gac.logger.Printf("Creating user '%s'", username)
+	log.Info("Creating user")
 	_, _, err = gac.giteaClient.AdminCreateUser(gitea.CreateUserOption{
 		Username:           username,
-		Email:              config.DefaultUserEmail(org),
+		Email:              gac.config.UserEmail(org),
 		Password:           password,
 		MustChangePassword: gitea.OptionalBool(false),
 		SendNotify:         false,
@@ -206,20 +231,46 @@ func (gac *AdminClient) CreateUser(org string) (*string, *string, error) {
 	return &username, &password, nil
 }
 
+// VerifyUser checks the credentials of a gitea user by retrieving the user information with them.
+// Implements domain.UserVerifier interface.
+func (gac *AdminClient) VerifyUser(ctx context.Context, username, password string) (*domain.Principal, error) {
+	client, err := gitea.NewClient(gac.url, gitea.SetBasicAuth(username, password))
+	if err != nil {
+		return nil, errors.Wrap(err, "gitea client failed")
+	}
+
+	user, resp, err := client.GetMyUserInfo()
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, errors.Wrap(err, "Failed to verify user credentials")
+	}
+
+	return &domain.Principal{
+		Name:  user.UserName,
+		Admin: user.IsAdmin,
+	}, nil
+}
+
 // CreateRepo creates a git repository with given name under given org
-func (gac *AdminClient) CreateRepo(c *domain.Codeset) error {
+func (gac *AdminClient) CreateRepo(ctx context.Context, c *domain.Codeset) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.CreateRepo", tracing.ProjectKey.String(c.Project), tracing.CodesetKey.String(c.Name))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, c.Project, logging.CodesetKey, c.Name)
 	repo, resp, err := gac.giteaClient.GetRepo(c.Project, c.Name)
 	if resp == nil && err != nil {
 		return errors.Wrap(err, "Failed to make get repo request")
 	}
 
 	if resp != nil && resp.StatusCode == 200 {
-		gac.logger.Printf("Repository '%s' already exists under '%s'", c.Name, c.Project)
+		log.Info("Repository already exists")
 		c.URL = repo.CloneURL
 		return nil
 	}
 
-	gac.logger.Printf("Creating repository '%s' under '%s'...", c.Name, c.Project)
+	log.Info("Creating repository")
 	repo, _, err = gac.giteaClient.CreateOrgRepo(c.Project, gitea.CreateRepoOption{
 		Name:          c.Name,
 		AutoInit:      true,
@@ -237,7 +288,10 @@ func (gac *AdminClient) CreateRepo(c *domain.Codeset) error {
 }
 
 // AddRepoTopics adds topics to given repository
-func (gac *AdminClient) AddRepoTopics(org, name string, labels []string) error {
+func (gac *AdminClient) AddRepoTopics(ctx context.Context, org, name string, labels []string) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.AddRepoTopics", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
 	for _, label := range labels {
 		_, err := gac.giteaClient.AddRepoTopic(org, name, label)
 		if err != nil {
@@ -247,10 +301,17 @@ func (gac *AdminClient) AddRepoTopics(org, name string, labels []string) error {
 	return nil
 }
 
-// CreateRepoWebhook creates webhook for given repository and wire it to the listenerURL
-func (gac *AdminClient) CreateRepoWebhook(org, name string, listenerURL *string) (*int64, error) {
+// CreateRepoWebhook creates webhook for given repository and wire it to the listenerURL. The events
+// sent by the webhook are signed with the secret. A webhook already wired to the listenerURL is
+// replaced, so that its secret is rotated.
+func (gac *AdminClient) CreateRepoWebhook(ctx context.Context, org, name string, listenerURL *string, secret string,
+	refs *domain.CodesetRefFilter) (_ *int64, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.CreateRepoWebhook", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, org, logging.CodesetKey, name)
 	if listenerURL == nil {
-		gac.logger.Printf("Webhook listener URL not provided, skipping creation")
+		log.Info("Webhook listener URL not provided, skipping creation")
 		return nil, nil
 	}
 	hooks, _, err := gac.giteaClient.ListRepoHooks(org, name, gitea.ListHooksOptions{})
@@ -261,34 +322,57 @@ func (gac *AdminClient) CreateRepoWebhook(org, name string, listenerURL *string)
 	for _, hook := range hooks {
 		url := hook.Config["url"]
 		if url == *listenerURL {
-			gac.logger.Printf("Webhook for '%s' already exists", name)
-			return &hook.ID, nil
+			log.Infow("Webhook already exists, replacing it", "id", hook.ID)
+			_, err = gac.giteaClient.DeleteRepoHook(org, name, hook.ID)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to delete webhook")
+			}
 		}
 	}
 
-	gac.logger.Printf("Creating Webhook for '%s' under '%s'...", name, org)
-	hook, _, _ := gac.giteaClient.CreateRepoHook(org, name, gitea.CreateHookOption{
+	log.Infow("Creating webhook", "url", *listenerURL)
+	hook, _, err := gac.giteaClient.CreateRepoHook(org, name, gitea.CreateHookOption{
 		Active:       true,
-		BranchFilter: "*",
+		BranchFilter: branchFilter(refs),
 		Config: map[string]string{
-			"secret":       config.HookSecret,
+			"secret":       secret,
 			"http_method":  "POST",
 			"url":          *listenerURL,
 			"content_type": "json",
 		},
 		Type: "gitea",
 	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create webhook")
+	}
 
 	return &hook.ID, nil
 }
 
+// branchFilter returns the Gitea webhook branch filter for the branches selected by the refs filter.
+// Gitea also applies the branch filter to the short names of the pushed tags, so all the pushes are sent
+// when tags are selected, and the webhook listener filters them.
+func branchFilter(refs *domain.CodesetRefFilter) string {
+	if refs == nil || len(refs.Branches) == 0 || len(refs.Tags) > 0 {
+		return "*"
+	}
+	if len(refs.Branches) == 1 {
+		return refs.Branches[0]
+	}
+	return "{" + strings.Join(refs.Branches, ",") + "}"
+}
+
 // DeleteRepoWebhook deletes a webhook for given repository
-func (gac *AdminClient) DeleteRepoWebhook(org, name string, hookID *int64) error {
-	gac.logger.Printf("Deleting Webhook for %q under %q...", name, org)
+func (gac *AdminClient) DeleteRepoWebhook(ctx context.Context, org, name string, hookID *int64) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.DeleteRepoWebhook", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, org, logging.CodesetKey, name)
+	log.Info("Deleting webhook")
 	resp, err := gac.giteaClient.DeleteRepoHook(org, name, *hookID)
 	if err != nil {
 		if resp.StatusCode == 404 {
-			gac.logger.Printf("Webhook not found, skipping deletion")
+			log.Info("Webhook not found, skipping deletion")
 			return nil
 		}
 		return errors.Wrap(err, "Failed to delete webhook")
@@ -297,39 +381,96 @@ func (gac *AdminClient) DeleteRepoWebhook(org, name string, hookID *int64) error
 }
 
 // PrepareRepository prepares the org, repository, and creates a user
-func (gac *AdminClient) PrepareRepository(code *domain.Codeset, listenerURL *string) (*string, *string, error) {
+func (gac *AdminClient) PrepareRepository(ctx context.Context, code *domain.Codeset, listenerURL *string) (_, _ *string, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.PrepareRepository", tracing.ProjectKey.String(code.Project), tracing.CodesetKey.String(code.Name))
+	defer tracing.End(span, &err)
 
-	err := gac.createOrganizationIfNotPresent(code.Project)
+	err = gac.createOrganizationIfNotPresent(ctx, code.Project)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Create org failed")
 	}
 
-	user, pass, err := gac.CreateUser(code.Project)
+	user, pass, err := gac.CreateUser(ctx, code.Project)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Create FuseML user failed")
 	}
 
-	err = gac.CreateRepo(code)
+	err = gac.CreateRepo(ctx, code)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Create repo failed")
 	}
 
-	err = gac.AddRepoTopics(code.Project, code.Name, code.Labels)
+	err = gac.AddRepoTopics(ctx, code.Project, code.Name, code.Labels)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Failed to add topics to repository")
 	}
 
-	_, err = gac.CreateRepoWebhook(code.Project, code.Name, listenerURL)
+	_, err = gac.CreateRepoWebhook(ctx, code.Project, code.Name, listenerURL, "", nil)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Creating webhook failed")
 	}
 	return user, pass, nil
 }
 
+// MirrorRepository prepares the org and creates a repository mirroring the external repository of the
+// codeset, which Gitea synchronizes at the interval of the codeset source
+func (gac *AdminClient) MirrorRepository(ctx context.Context, code *domain.Codeset) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.MirrorRepository", tracing.ProjectKey.String(code.Project), tracing.CodesetKey.String(code.Name))
+	defer tracing.End(span, &err)
+
+	err = gac.createOrganizationIfNotPresent(ctx, code.Project)
+	if err != nil {
+		return errors.Wrap(err, "Create org failed")
+	}
+
+	log := gac.log(ctx).With(logging.ProjectKey, code.Project, logging.CodesetKey, code.Name)
+	_, resp, err := gac.giteaClient.GetRepo(code.Project, code.Name)
+	if resp == nil && err != nil {
+		return errors.Wrap(err, "Failed to make get repo request")
+	}
+	if resp != nil && resp.StatusCode == http.StatusOK {
+		return domain.ErrCodesetExists
+	}
+
+	log.Infow("Creating mirror repository", "url", code.Source.URL)
+	repo, _, err := gac.giteaClient.MigrateRepo(gitea.MigrateRepoOption{
+		RepoName:       code.Name,
+		RepoOwner:      code.Project,
+		CloneAddr:      code.Source.URL,
+		AuthUsername:   code.Source.Username,
+		AuthPassword:   code.Source.Password,
+		Mirror:         true,
+		Description:    code.Description,
+		MirrorInterval: code.Source.Interval.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to create mirror repository")
+	}
+	code.URL = repo.CloneURL
+
+	err = gac.AddRepoTopics(ctx, code.Project, code.Name, code.Labels)
+	if err != nil {
+		return errors.Wrap(err, "Failed to add topics to repository")
+	}
+	return nil
+}
+
+// repoSource returns the source of a repository mirroring an external repository, or nil for the other
+// repositories
+func repoSource(repo *gitea.Repository) *domain.CodesetSource {
+	if !repo.Mirror {
+		return nil
+	}
+	// the interval is left unset if Gitea returns it in an unexpected format
+	interval, _ := time.ParseDuration(repo.MirrorInterval)
+	return &domain.CodesetSource{URL: repo.OriginalURL, Mode: domain.CodesetSourceMirror, Interval: interval,
+		DefaultBranch: repo.DefaultBranch}
+}
+
 // GetReposForOrg retrieves all repositories for given project, can be filtered by label
-func (gac *AdminClient) GetReposForOrg(org string, label *string) ([]*domain.Codeset, error) {
+func (gac *AdminClient) GetReposForOrg(ctx context.Context, org string, label *string) ([]*domain.Codeset, error) {
 	var codesets []*domain.Codeset
-	gac.logger.Printf("Listing repos for org '%s'...", org)
+	gac.log(ctx).Debugw("Listing repositories", logging.ProjectKey, org)
 	repos, _, err := gac.giteaClient.ListOrgRepos(org, gitea.ListOrgReposOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to list project repos")
@@ -350,20 +491,23 @@ func (gac *AdminClient) GetReposForOrg(org string, label *string) ([]*domain.Cod
 			Labels:      labels,
 			Description: repo.Description,
 			URL:         repo.CloneURL,
+			Source:      repoSource(repo),
+			Archived:    repo.Archived,
 		})
 	}
 	return codesets, nil
 }
 
 // GetRepositories retrieves all repositories, can be filtered by project(org) and label
-func (gac *AdminClient) GetRepositories(org, label *string) ([]*domain.Codeset, error) {
+func (gac *AdminClient) GetRepositories(ctx context.Context, org, label *string) (_ []*domain.Codeset, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepositories")
+	defer tracing.End(span, &err)
 
 	var allRepos []*domain.Codeset
 	var orgs []*gitea.Organization
 
 	if org == nil {
-		gac.logger.Printf("Going through all orgs...")
-		var err error
+		gac.log(ctx).Debug("Listing repositories of all projects")
 		orgs, _, err = gac.giteaClient.ListMyOrgs(gitea.ListOrgsOptions{})
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to list orgs")
@@ -373,7 +517,7 @@ func (gac *AdminClient) GetRepositories(org, label *string) ([]*domain.Codeset,
 	}
 
 	for _, o := range orgs {
-		repos, err := gac.GetReposForOrg(o.UserName, label)
+		repos, err := gac.GetReposForOrg(ctx, o.UserName, label)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to list repos for org "+o.UserName)
 		}
@@ -383,14 +527,20 @@ func (gac *AdminClient) GetRepositories(org, label *string) ([]*domain.Codeset,
 }
 
 // GetRepository retrieves information about the repository
-func (gac *AdminClient) GetRepository(org, name string) (*domain.Codeset, error) {
-	gac.logger.Printf("Get repo %s for org '%s'...", name, org)
-	repo, _, err := gac.giteaClient.GetRepo(org, name)
+func (gac *AdminClient) GetRepository(ctx context.Context, org, name string) (_ *domain.Codeset, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepository", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Fetching repository", logging.ProjectKey, org, logging.CodesetKey, name)
+	repo, resp, err := gac.giteaClient.GetRepo(org, name)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrCodesetNotFound
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to read repository")
 	}
 	if repo == nil || repo.Name == "" {
-		return nil, errRepoNotFound
+		return nil, domain.ErrCodesetNotFound
 	}
 
 	ret := domain.Codeset{
@@ -404,18 +554,53 @@ func (gac *AdminClient) GetRepository(org, name string) (*domain.Codeset, error)
 	}
 	ret.Labels = labels
 	ret.URL = repo.CloneURL
+	ret.Source = repoSource(repo)
+	ret.Archived = repo.Archived
 
 	return &ret, nil
 }
 
+// UpdateRepository sets the description, topics and archived flag of a repository from the codeset. The topics
+// of an archived repository cannot be changed, so they are set before archiving it or after unarchiving it.
+func (gac *AdminClient) UpdateRepository(ctx context.Context, c *domain.Codeset) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.UpdateRepository", tracing.ProjectKey.String(c.Project), tracing.CodesetKey.String(c.Name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Updating repository", logging.ProjectKey, c.Project, logging.CodesetKey, c.Name, "archived", c.Archived)
+	setTopics := func() error {
+		_, err := gac.giteaClient.SetRepoTopics(c.Project, c.Name, append([]string{}, c.Labels...))
+		return errors.Wrap(err, "Failed to set repo topics")
+	}
+	if c.Archived {
+		if err := setTopics(); err != nil {
+			return err
+		}
+	}
+	_, resp, err := gac.giteaClient.EditRepo(c.Project, c.Name, gitea.EditRepoOption{Description: &c.Description, Archived: &c.Archived})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return domain.ErrCodesetNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to edit repository")
+	}
+	if !c.Archived {
+		return setTopics()
+	}
+	return nil
+}
+
 // DeleteRepository delete a repository
-func (gac *AdminClient) DeleteRepository(org, name string) error {
-	gac.logger.Printf("Going to delete repo %s for org '%s'...", name, org)
+func (gac *AdminClient) DeleteRepository(ctx context.Context, org, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.DeleteRepository", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, org, logging.CodesetKey, name)
+	log.Info("Deleting repository")
 
 	_, resp, err := gac.giteaClient.GetRepo(org, name)
 
 	if resp.StatusCode == 404 {
-		gac.logger.Printf("Repo does not exist, no need to delete")
+		log.Info("Repository does not exist, no need to delete")
 		return nil
 	}
 	if err != nil {
@@ -429,6 +614,201 @@ func (gac *AdminClient) DeleteRepository(org, name string) error {
 	return nil
 }
 
+// GetRepoBranches retrieves all branches of a repository and the commits they point to
+func (gac *AdminClient) GetRepoBranches(ctx context.Context, org, name string) (_ []*domain.CodesetRef, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepoBranches", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Listing repository branches", logging.ProjectKey, org, logging.CodesetKey, name)
+	ret := []*domain.CodesetRef{}
+	for page := 1; ; page++ {
+		branches, _, err := gac.giteaClient.ListRepoBranches(org, name, gitea.ListRepoBranchesOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: listPageSize},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list repository branches")
+		}
+		for _, b := range branches {
+			ref := &domain.CodesetRef{Name: b.Name}
+			if b.Commit != nil {
+				ref.Commit = b.Commit.ID
+			}
+			ret = append(ret, ref)
+		}
+		if len(branches) < listPageSize {
+			return ret, nil
+		}
+	}
+}
+
+// GetRepoTags retrieves all tags of a repository and the commits they point to
+func (gac *AdminClient) GetRepoTags(ctx context.Context, org, name string) (_ []*domain.CodesetRef, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepoTags", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Listing repository tags", logging.ProjectKey, org, logging.CodesetKey, name)
+	ret := []*domain.CodesetRef{}
+	for page := 1; ; page++ {
+		tags, _, err := gac.giteaClient.ListRepoTags(org, name, gitea.ListRepoTagsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: listPageSize},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list repository tags")
+		}
+		for _, t := range tags {
+			ref := &domain.CodesetRef{Name: t.Name}
+			if t.Commit != nil {
+				ref.Commit = t.Commit.SHA
+			}
+			ret = append(ret, ref)
+		}
+		if len(tags) < listPageSize {
+			return ret, nil
+		}
+	}
+}
+
+// GetRepoCommits retrieves the most recent commits reachable from the given branch, tag or commit of a
+// repository, newest first. The commits of the default branch are returned when ref is empty.
+func (gac *AdminClient) GetRepoCommits(ctx context.Context, org, name, ref string, limit int) (_ []*domain.CodesetCommit, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepoCommits", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Listing repository commits", logging.ProjectKey, org, logging.CodesetKey, name, "ref", ref)
+	commits, resp, err := gac.giteaClient.ListRepoCommits(org, name, gitea.ListCommitOptions{
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: limit},
+		SHA:         ref,
+	})
+	if err != nil {
+		if ref != "" && resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, domain.ErrCodesetVersionNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+
+	ret := make([]*domain.CodesetCommit, 0, len(commits))
+	for _, c := range commits {
+		commit := &domain.CodesetCommit{}
+		if c.CommitMeta != nil {
+			commit.ID = c.SHA
+		}
+		if c.RepoCommit != nil {
+			commit.Message = c.RepoCommit.Message
+			if c.RepoCommit.Author != nil {
+				commit.Author = c.RepoCommit.Author.Name
+				// the date is left unset if Gitea returns it in an unexpected format
+				commit.Date, _ = time.Parse(time.RFC3339, c.RepoCommit.Author.Date)
+			}
+		}
+		ret = append(ret, commit)
+		if len(ret) == limit {
+			break
+		}
+	}
+	return ret, nil
+}
+
+// CreateRepoTag tags the given branch, tag or commit of a repository. The head of the default branch is
+// tagged when commit is empty.
+func (gac *AdminClient) CreateRepoTag(ctx context.Context, org, name, tag, commit, message string) (_ *domain.CodesetRef, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.CreateRepoTag", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	// resolve the version to a commit ID first, which also makes sure that it exists
+	commits, err := gac.GetRepoCommits(ctx, org, name, commit, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, domain.ErrCodesetVersionNotFound
+	}
+	target := commits[0].ID
+
+	gac.log(ctx).Infow("Creating repository tag", logging.ProjectKey, org, logging.CodesetKey, name, "tag", tag, "commit", target)
+	// the Gitea API version supported by the SDK can only create tags by creating a release
+	_, resp, err := gac.giteaClient.CreateRelease(org, name, gitea.CreateReleaseOption{
+		TagName: tag,
+		Target:  target,
+		Title:   tag,
+		Note:    message,
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return nil, domain.ErrCodesetVersionExists
+		}
+		return nil, errors.Wrap(err, "Failed to create repository tag")
+	}
+	return &domain.CodesetRef{Name: tag, Commit: target}, nil
+}
+
+// GetRepoFiles retrieves the entries of a repository directory, at the given branch, tag or commit (the default
+// branch when empty). A single entry is returned when the path is a file.
+func (gac *AdminClient) GetRepoFiles(ctx context.Context, org, name, ref, path string) (_ []*domain.CodesetFile, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepoFiles", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Listing repository files", logging.ProjectKey, org, logging.CodesetKey, name, "ref", ref, "path", path)
+	contents, resp, err := gac.giteaClient.ListContents(org, name, ref, path)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, domain.ErrCodesetFileNotFound
+		}
+		// the contents of a file are returned instead of a list
+		if resp != nil && resp.StatusCode == http.StatusOK {
+			file, _, err := gac.giteaClient.GetContents(org, name, ref, path)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to read repository file")
+			}
+			return []*domain.CodesetFile{toCodesetFile(file)}, nil
+		}
+		return nil, errors.Wrap(err, "Failed to list repository files")
+	}
+
+	ret := make([]*domain.CodesetFile, 0, len(contents))
+	for _, c := range contents {
+		ret = append(ret, toCodesetFile(c))
+	}
+	return ret, nil
+}
+
+// GetRepoFile retrieves the contents of a repository file, at the given branch, tag or commit (the default
+// branch when empty), unless the file is larger than domain.MaxCodesetFileSize
+func (gac *AdminClient) GetRepoFile(ctx context.Context, org, name, ref, path string) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetRepoFile", tracing.ProjectKey.String(org), tracing.CodesetKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Reading repository file", logging.ProjectKey, org, logging.CodesetKey, name, "ref", ref, "path", path)
+	file, resp, err := gac.giteaClient.GetContents(org, name, ref, path)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, domain.ErrCodesetFileNotFound
+		}
+		// a list of entries is returned for a directory
+		if resp != nil && resp.StatusCode == http.StatusOK {
+			return nil, domain.ErrCodesetNotAFile
+		}
+		return nil, errors.Wrap(err, "Failed to read repository file")
+	}
+	if file.Type != domain.CodesetFileTypeFile {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	if file.Size > domain.MaxCodesetFileSize {
+		return nil, domain.ErrCodesetFileTooLarge
+	}
+	if file.Content == nil {
+		return []byte{}, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(*file.Content)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode repository file")
+	}
+	return content, nil
+}
+
+func toCodesetFile(c *gitea.ContentsResponse) *domain.CodesetFile {
+	return &domain.CodesetFile{Name: c.Name, Path: c.Path, Type: c.Type, Size: c.Size}
+}
+
 // return all non-admin users that are Owners for given organization
 func (gac *AdminClient) getProjectOwners(name string) ([]*domain.User, error) {
 
@@ -460,8 +840,11 @@ func (gac *AdminClient) getProjectOwners(name string) ([]*domain.User, error) {
 }
 
 // GetProjects retrieves all projects (orgs)
-func (gac *AdminClient) GetProjects() ([]*domain.Project, error) {
-	gac.logger.Printf("listing git orgs....")
+func (gac *AdminClient) GetProjects(ctx context.Context) (_ []*domain.Project, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetProjects")
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debug("Listing projects")
 
 	orgs, _, err := gac.giteaClient.ListMyOrgs(gitea.ListOrgsOptions{})
 	if err != nil {
@@ -484,10 +867,16 @@ func (gac *AdminClient) GetProjects() ([]*domain.Project, error) {
 }
 
 // GetProject retrieves a project by its name
-func (gac *AdminClient) GetProject(name string) (*domain.Project, error) {
-	gac.logger.Printf("Fetching git org %s....", name)
+func (gac *AdminClient) GetProject(ctx context.Context, name string) (_ *domain.Project, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetProject", tracing.ProjectKey.String(name))
+	defer tracing.End(span, &err)
 
-	org, _, err := gac.giteaClient.GetOrg(name)
+	gac.log(ctx).Debugw("Fetching project", logging.ProjectKey, name)
+
+	org, resp, err := gac.giteaClient.GetOrg(name)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrProjectNotFound
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to make get org request")
 	}
@@ -503,9 +892,69 @@ func (gac *AdminClient) GetProject(name string) (*domain.Project, error) {
 	return &ret, nil
 }
 
+// UpdateProject changes the description of a project
+func (gac *AdminClient) UpdateProject(ctx context.Context, name, desc string) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.UpdateProject", tracing.ProjectKey.String(name))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Infow("Updating project", logging.ProjectKey, name)
+
+	org, _, err := gac.giteaClient.GetOrg(name)
+	if err != nil {
+		return errors.Wrap(err, "Failed to make get org request")
+	}
+	// all the organization settings are replaced, so the ones that are not changed are kept
+	_, err = gac.giteaClient.EditOrg(name, gitea.EditOrgOption{
+		FullName:    org.FullName,
+		Description: desc,
+		Website:     org.Website,
+		Location:    org.Location,
+		Visibility:  gitea.VisibleType(org.Visibility),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to update project")
+	}
+	return nil
+}
+
+// GetExclusiveProjectUsers returns the names of the users owning a project and no other one, which are deleted
+// along with the project
+func (gac *AdminClient) GetExclusiveProjectUsers(ctx context.Context, org string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "gitea.GetExclusiveProjectUsers", tracing.ProjectKey.String(org))
+	defer tracing.End(span, &err)
+
+	gac.log(ctx).Debugw("Listing exclusive project users", logging.ProjectKey, org)
+	return gac.exclusiveProjectOwners(org)
+}
+
+// exclusiveProjectOwners returns the sorted names of the owners of an organization that are not members of any
+// other organization
+func (gac *AdminClient) exclusiveProjectOwners(org string) ([]string, error) {
+	owners, err := gac.getProjectOwners(org)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list project owners")
+	}
+	var ret []string
+	for _, owner := range owners {
+		orgsForUser, _, err := gac.giteaClient.ListUserOrgs(owner.Name, gitea.ListOrgsOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list orgs for user")
+		}
+		if len(orgsForUser) == 1 {
+			ret = append(ret, owner.Name)
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
 // DeleteProject deletes a project
-func (gac *AdminClient) DeleteProject(org string) error {
-	gac.logger.Printf("Deleting project %s....", org)
+func (gac *AdminClient) DeleteProject(ctx context.Context, org string) (err error) {
+	ctx, span := tracing.Start(ctx, "gitea.DeleteProject", tracing.ProjectKey.String(org))
+	defer tracing.End(span, &err)
+
+	log := gac.log(ctx).With(logging.ProjectKey, org)
+	log.Info("Deleting project")
 	// 1. check if they are no repos
 	repos, _, err := gac.giteaClient.ListOrgRepos(org, gitea.ListOrgReposOptions{})
 	if err != nil {
@@ -517,31 +966,19 @@ func (gac *AdminClient) DeleteProject(org string) error {
 	}
 
 	// 2. delete all members of the project, if they are not owning any other project
-	owners, err := gac.getProjectOwners(org)
+	users, err := gac.exclusiveProjectOwners(org)
 	if err != nil {
-		return errors.Wrap(err, "Failed to list project owners")
+		return err
 	}
-	usersOrgs := make(map[string]int)
-	for _, owner := range owners {
-		orgsForUser, _, err := gac.giteaClient.ListUserOrgs(owner.Name, gitea.ListOrgsOptions{})
-		if err != nil {
-			return errors.Wrap(err, "Failed to list orgs for user")
-		}
-		for range orgsForUser {
-			usersOrgs[owner.Name]++
+	for _, userName := range users {
+		log.Infow("Removing user from project", "user", userName)
+		if _, err := gac.giteaClient.DeleteOrgMembership(org, userName); err != nil {
+			return errors.Wrap(err, "Failed to remove user from project")
 		}
-	}
-	for userName, orgNumber := range usersOrgs {
-		if orgNumber == 1 {
-			gac.logger.Printf("Removing user %s from %s ....", userName, org)
-			if _, err := gac.giteaClient.DeleteOrgMembership(org, userName); err != nil {
-				return errors.Wrap(err, "Failed to remove user from project")
-			}
 
-			gac.logger.Printf("Deleting user %s....", userName)
-			if _, err := gac.giteaClient.AdminDeleteUser(userName); err != nil {
-				return errors.Wrap(err, "Failed to delete user")
-			}
+		log.Infow("Deleting user", "user", userName)
+		if _, err := gac.giteaClient.AdminDeleteUser(userName); err != nil {
+			return errors.Wrap(err, "Failed to delete user")
 		}
 	}
 
@@ -1,13 +1,21 @@
 package gitea
 
 import (
-	"io"
-	"log"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
-	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
@@ -16,13 +24,20 @@ type TestStore struct {
 	projects       map[string]gitea.Organization
 	projects2repos map[string]map[string]gitea.Repository
 	teams          map[int64][]string
+	// commits of the main branch of each repository, newest first
+	commits map[string][]*gitea.Commit
+	tags    map[string][]*gitea.Tag
+	// topics set for each repository
+	topics map[string][]string
+	// contents of the files of each repository, by path
+	files map[string]map[string]string
 }
 
 // Replace all methods that are caled from actual gitea client with the ones operating
 // on local structures instead of git server
 type testGiteaClient struct {
 	testStore *TestStore
-	logger    *log.Logger
+	logger    *zap.SugaredLogger
 }
 
 func NewTestStore() *TestStore {
@@ -30,16 +45,17 @@ func NewTestStore() *TestStore {
 		projects:       make(map[string]gitea.Organization),
 		projects2repos: make(map[string]map[string]gitea.Repository),
 		teams:          make(map[int64][]string),
+		commits:        make(map[string][]*gitea.Commit),
+		tags:           make(map[string][]*gitea.Tag),
+		topics:         make(map[string][]string),
+		files:          make(map[string]map[string]string),
 	}
 }
 
 // Set a specific logger just for testing
-func testLogger() *log.Logger {
-
-	logger := log.New(os.Stderr, "[test] ", log.Ltime)
+func testLogger() *zap.SugaredLogger {
 	// suppress the regular output from app
-	logger.SetOutput(io.Discard)
-	return logger
+	return zap.NewNop().Sugar()
 }
 
 func newTestGiteaAdminClient(testStore *TestStore) *AdminClient {
@@ -47,6 +63,7 @@ func newTestGiteaAdminClient(testStore *TestStore) *AdminClient {
 		giteaClient: &testGiteaClient{testStore, testLogger()},
 		logger:      testLogger(),
 		url:         testURL,
+		config:      config.Default().Gitea,
 	}
 }
 
@@ -65,6 +82,13 @@ func (tc *testGiteaClient) CreateOrg(opt gitea.CreateOrgOption) (*gitea.Organiza
 	tc.testStore.projects2repos[opt.Name] = make(map[string]gitea.Repository)
 	return &org, nil, nil
 }
+func (tc *testGiteaClient) EditOrg(orgname string, opt gitea.EditOrgOption) (*gitea.Response, error) {
+	org := tc.testStore.projects[orgname]
+	org.FullName = opt.FullName
+	org.Description = opt.Description
+	tc.testStore.projects[orgname] = org
+	return nil, nil
+}
 func (tc *testGiteaClient) GetUserInfo(string) (*gitea.User, *gitea.Response, error) {
 	return &gitea.User{ID: 0}, nil, nil
 }
@@ -87,6 +111,10 @@ func (tc *testGiteaClient) DeleteOrgMembership(org, user string) (*gitea.Respons
 	return &gitea.Response{Response: &httpResp200}, nil
 }
 
+func (tc *testGiteaClient) ServerVersion() (string, *gitea.Response, error) {
+	return "1.14.0", &gitea.Response{Response: &httpResp200}, nil
+}
+
 func (tc *testGiteaClient) ListTeamMembers(id int64, opts gitea.ListTeamMembersOptions) ([]*gitea.User, *gitea.Response, error) {
 	users := make([]*gitea.User, 0)
 	return users, &gitea.Response{Response: &httpResp200}, nil
@@ -103,6 +131,11 @@ func (tc *testGiteaClient) CreateOrgRepo(org string, repo gitea.CreateRepoOption
 	tc.testStore.projects2repos[org][repo.Name] = r
 	return &r, nil, nil
 }
+func (tc *testGiteaClient) MigrateRepo(opt gitea.MigrateRepoOption) (*gitea.Repository, *gitea.Response, error) {
+	r := gitea.Repository{Name: opt.RepoName, Mirror: opt.Mirror, OriginalURL: opt.CloneAddr, MirrorInterval: opt.MirrorInterval}
+	tc.testStore.projects2repos[opt.RepoOwner][opt.RepoName] = r
+	return &r, nil, nil
+}
 func (tc *testGiteaClient) ListRepoHooks(string, string, gitea.ListHooksOptions) ([]*gitea.Hook, *gitea.Response, error) {
 	return nil, nil, nil
 }
@@ -127,7 +160,124 @@ func (tc *testGiteaClient) DeleteRepoHook(string, string, int64) (*gitea.Respons
 	return &gitea.Response{Response: &httpResp200}, nil
 }
 func (tc *testGiteaClient) ListRepoTopics(org, repo string, opt gitea.ListRepoTopicsOptions) ([]string, *gitea.Response, error) {
-	return nil, nil, nil
+	return tc.testStore.topics[org+"/"+repo], nil, nil
+}
+func (tc *testGiteaClient) SetRepoTopics(org, repo string, topics []string) (*gitea.Response, error) {
+	if tc.testStore.projects2repos[org][repo].Archived {
+		return &gitea.Response{Response: &httpResp422}, fmt.Errorf("repository is archived")
+	}
+	tc.testStore.topics[org+"/"+repo] = topics
+	return &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) EditRepo(owner, repo string, opt gitea.EditRepoOption) (*gitea.Repository, *gitea.Response, error) {
+	r, ok := tc.testStore.projects2repos[owner][repo]
+	if !ok {
+		return nil, &gitea.Response{Response: &httpResp404}, fmt.Errorf("not found")
+	}
+	if opt.Description != nil {
+		r.Description = *opt.Description
+	}
+	if opt.Archived != nil {
+		r.Archived = *opt.Archived
+	}
+	tc.testStore.projects2repos[owner][repo] = r
+	return &r, &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) ListRepoBranches(owner, repo string, opt gitea.ListRepoBranchesOptions) ([]*gitea.Branch, *gitea.Response, error) {
+	commits := tc.testStore.commits[owner+"/"+repo]
+	if len(commits) == 0 || opt.Page > 1 {
+		return nil, &gitea.Response{Response: &httpResp200}, nil
+	}
+	return []*gitea.Branch{{Name: "main", Commit: &gitea.PayloadCommit{ID: commits[0].SHA}}}, &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) ListRepoTags(owner, repo string, opt gitea.ListRepoTagsOptions) ([]*gitea.Tag, *gitea.Response, error) {
+	tags := tc.testStore.tags[owner+"/"+repo]
+	start := (opt.Page - 1) * opt.PageSize
+	if start >= len(tags) {
+		return nil, &gitea.Response{Response: &httpResp200}, nil
+	}
+	end := start + opt.PageSize
+	if end > len(tags) {
+		end = len(tags)
+	}
+	return tags[start:end], &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) ListRepoCommits(owner, repo string, opt gitea.ListCommitOptions) ([]*gitea.Commit, *gitea.Response, error) {
+	commits := tc.testStore.commits[owner+"/"+repo]
+	if opt.SHA == "" || opt.SHA == "main" {
+		return commits, &gitea.Response{Response: &httpResp200}, nil
+	}
+	sha := opt.SHA
+	for _, t := range tc.testStore.tags[owner+"/"+repo] {
+		if t.Name == opt.SHA {
+			sha = t.Commit.SHA
+		}
+	}
+	for i, c := range commits {
+		if strings.HasPrefix(c.SHA, sha) {
+			return commits[i:], &gitea.Response{Response: &httpResp200}, nil
+		}
+	}
+	return nil, &gitea.Response{Response: &httpResp404}, errors.New("404 Not Found")
+}
+func (tc *testGiteaClient) CreateRelease(owner, repo string, opt gitea.CreateReleaseOption) (*gitea.Release, *gitea.Response, error) {
+	for _, t := range tc.testStore.tags[owner+"/"+repo] {
+		if t.Name == opt.TagName {
+			return nil, &gitea.Response{Response: &httpResp409}, errors.New("409 Conflict")
+		}
+	}
+	tc.testStore.tags[owner+"/"+repo] = append(tc.testStore.tags[owner+"/"+repo],
+		&gitea.Tag{Name: opt.TagName, Commit: &gitea.CommitMeta{SHA: opt.Target}})
+	return &gitea.Release{TagName: opt.TagName, Target: opt.Target}, &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) ListContents(owner, repo, ref, filepath string) ([]*gitea.ContentsResponse, *gitea.Response, error) {
+	files := tc.testStore.files[owner+"/"+repo]
+	if _, ok := files[filepath]; ok {
+		return nil, &gitea.Response{Response: &httpResp200}, fmt.Errorf("expect directory, got file")
+	}
+	prefix := filepath + "/"
+	if filepath == "" {
+		prefix = ""
+	}
+	entries := map[string]*gitea.ContentsResponse{}
+	names := []string{}
+	for path, content := range files {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		entryName := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)[0]
+		if _, ok := entries[entryName]; ok {
+			continue
+		}
+		entry := &gitea.ContentsResponse{Name: entryName, Path: prefix + entryName, Type: "dir"}
+		if prefix+entryName == path {
+			entry.Type, entry.Size = "file", int64(len(content))
+		}
+		entries[entryName] = entry
+		names = append(names, entryName)
+	}
+	if len(names) == 0 {
+		return nil, &gitea.Response{Response: &httpResp404}, fmt.Errorf("not found")
+	}
+	sort.Strings(names)
+	ret := []*gitea.ContentsResponse{}
+	for _, n := range names {
+		ret = append(ret, entries[n])
+	}
+	return ret, &gitea.Response{Response: &httpResp200}, nil
+}
+func (tc *testGiteaClient) GetContents(owner, repo, ref, filepath string) (*gitea.ContentsResponse, *gitea.Response, error) {
+	content, ok := tc.testStore.files[owner+"/"+repo][filepath]
+	if !ok {
+		if _, resp, err := tc.ListContents(owner, repo, ref, filepath); err != nil {
+			return nil, resp, err
+		}
+		return nil, &gitea.Response{Response: &httpResp200}, fmt.Errorf("expect file, got directory")
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	parts := strings.Split(filepath, "/")
+	return &gitea.ContentsResponse{Name: parts[len(parts)-1], Path: filepath, Type: "file", Size: int64(len(content)),
+		Content: &encoded}, &gitea.Response{Response: &httpResp200}, nil
 }
 func (tc *testGiteaClient) ListMyOrgs(gitea.ListOrgsOptions) ([]*gitea.Organization, *gitea.Response, error) {
 	allOrgs := make([]*gitea.Organization, 0)
@@ -157,6 +307,8 @@ var (
 	testListenerURL       = &testListenerStringURL
 	httpResp200           = http.Response{StatusCode: 200}
 	httpResp404           = http.Response{StatusCode: 404}
+	httpResp409           = http.Response{StatusCode: 409}
+	httpResp422           = http.Response{StatusCode: 422}
 )
 
 func getTestCodeset() *domain.Codeset {
@@ -181,6 +333,7 @@ func TestPrepareRepository(t *testing.T) {
 
 	testStore := NewTestStore()
 	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	ctx := context.Background()
 	code := getTestCodeset()
 
 	// checking initial state of owners team members
@@ -188,7 +341,7 @@ func TestPrepareRepository(t *testing.T) {
 		t.Errorf("Initial number of teams is not empty")
 	}
 
-	_, _, err := testGiteaAdminClient.PrepareRepository(code, testListenerURL)
+	_, _, err := testGiteaAdminClient.PrepareRepository(ctx, code, testListenerURL)
 	if err != nil {
 		t.Errorf("Error preparing repository: %v", err)
 	}
@@ -207,7 +360,7 @@ func TestPrepareRepository(t *testing.T) {
 		t.Errorf("No users present in Owners team after adding repository")
 	}
 
-	if !util.StringInSlice(generateUserName(project1), usersInTeam) {
+	if !util.StringInSlice(testGiteaAdminClient.generateUserName(project1), usersInTeam) {
 		t.Errorf("New user is not present in the Owners team")
 	}
 }
@@ -215,17 +368,18 @@ func TestPrepareRepository(t *testing.T) {
 func TestGetRepository(t *testing.T) {
 
 	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
 
 	// Reading repo that was not added should throw error
-	_, err := testGiteaAdminClient.GetRepository(project1, name)
+	_, err := testGiteaAdminClient.GetRepository(ctx, project1, name)
 
-	assertError(t, err, errRepoNotFound)
+	assertError(t, err, domain.ErrCodesetNotFound)
 
 	// Prepare new repo
-	testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+	testGiteaAdminClient.PrepareRepository(ctx, getTestCodeset(), testListenerURL)
 
 	// Get the repo now
-	c, err := testGiteaAdminClient.GetRepository(project1, name)
+	c, err := testGiteaAdminClient.GetRepository(ctx, project1, name)
 	if err != nil {
 		t.Errorf("Error geting repository that was just created")
 	}
@@ -234,54 +388,138 @@ func TestGetRepository(t *testing.T) {
 	}
 }
 
+func TestUpdateRepository(t *testing.T) {
+
+	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
+
+	err := testGiteaAdminClient.UpdateRepository(ctx, getTestCodeset())
+	assertError(t, err, domain.ErrCodesetNotFound)
+
+	testGiteaAdminClient.PrepareRepository(ctx, getTestCodeset(), testListenerURL)
+
+	// update and archive the repository, whose topics can no longer be changed afterwards
+	code := getTestCodeset()
+	code.Description = "Updated description"
+	code.Labels = []string{"updated"}
+	code.Archived = true
+	if err := testGiteaAdminClient.UpdateRepository(ctx, code); err != nil {
+		t.Fatalf("Error updating repository: %v", err)
+	}
+	c, _ := testGiteaAdminClient.GetRepository(ctx, project1, name)
+	if c.Description != code.Description || !c.Archived || len(c.Labels) != 1 || c.Labels[0] != "updated" {
+		t.Errorf("Wrong codeset returned after update: %v", c)
+	}
+
+	// unarchive the repository and remove all its topics
+	code.Labels = nil
+	code.Archived = false
+	if err := testGiteaAdminClient.UpdateRepository(ctx, code); err != nil {
+		t.Fatalf("Error unarchiving repository: %v", err)
+	}
+	c, _ = testGiteaAdminClient.GetRepository(ctx, project1, name)
+	if c.Archived || len(c.Labels) != 0 {
+		t.Errorf("Wrong codeset returned after unarchiving: %v", c)
+	}
+}
+
+func TestBranchFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		refs *domain.CodesetRefFilter
+		want string
+	}{
+		{name: "no filter", want: "*"},
+		{name: "single branch", refs: &domain.CodesetRefFilter{Branches: []string{"main"}}, want: "main"},
+		{name: "branches", refs: &domain.CodesetRefFilter{Branches: []string{"main", "release-*"}}, want: "{main,release-*}"},
+		{name: "tags", refs: &domain.CodesetRefFilter{Tags: []string{"v*"}}, want: "*"},
+		{name: "branches and tags", refs: &domain.CodesetRefFilter{Branches: []string{"main"}, Tags: []string{"v*"}}, want: "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := branchFilter(tt.refs); got != tt.want {
+				t.Errorf("got branch filter %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDeleteRepository(t *testing.T) {
 
 	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
 
 	// Reading repo that was not added should throw error
-	_, err := testGiteaAdminClient.GetRepository(project1, name)
+	_, err := testGiteaAdminClient.GetRepository(ctx, project1, name)
 
-	assertError(t, err, errRepoNotFound)
+	assertError(t, err, domain.ErrCodesetNotFound)
 
 	// Prepare new repo
-	testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+	testGiteaAdminClient.PrepareRepository(ctx, getTestCodeset(), testListenerURL)
 
 	// Get the repo now
-	_, err = testGiteaAdminClient.GetRepository(project1, name)
+	_, err = testGiteaAdminClient.GetRepository(ctx, project1, name)
 	if err != nil {
 		t.Errorf("Error geting repository that was just created")
 	}
 
-	err = testGiteaAdminClient.DeleteRepository(project1, name)
+	err = testGiteaAdminClient.DeleteRepository(ctx, project1, name)
 	if err != nil {
 		t.Errorf("Error deleting repository")
 	}
 
-	c, _ := testGiteaAdminClient.GetRepository(project1, name)
+	c, _ := testGiteaAdminClient.GetRepository(ctx, project1, name)
 	if c != nil {
 		t.Errorf("Repository still present after deleting")
 	}
 
-	err = testGiteaAdminClient.DeleteRepository(project1, name)
+	err = testGiteaAdminClient.DeleteRepository(ctx, project1, name)
 	if err != nil {
 		t.Errorf("Error: deleting non existent repository should not fail")
 	}
 }
 
+func TestMirrorRepository(t *testing.T) {
+
+	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
+	code := getTestCodeset()
+	code.Source = &domain.CodesetSource{
+		URL:      "https://github.com/fuseml/fuseml-examples.git",
+		Mode:     domain.CodesetSourceMirror,
+		Interval: time.Hour,
+	}
+
+	err := testGiteaAdminClient.MirrorRepository(ctx, code)
+	assertError(t, err, nil)
+
+	c, err := testGiteaAdminClient.GetRepository(ctx, project1, name)
+	assertError(t, err, nil)
+	want := domain.CodesetSource{URL: code.Source.URL, Mode: domain.CodesetSourceMirror, Interval: time.Hour}
+	if c.Source == nil || !reflect.DeepEqual(*c.Source, want) {
+		t.Errorf("Wrong codeset source returned: %v", c.Source)
+	}
+
+	// mirroring an existing repository should fail
+	err = testGiteaAdminClient.MirrorRepository(ctx, code)
+	assertError(t, err, domain.ErrCodesetExists)
+}
+
 func TestGetRepositories(t *testing.T) {
 
 	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
 
-	repos, err := testGiteaAdminClient.GetRepositories(&project1, nil)
+	repos, err := testGiteaAdminClient.GetRepositories(ctx, &project1, nil)
 	if len(repos) > 0 {
 		t.Errorf("Initial set of repositories is not empty")
 	}
 	if err != nil {
 		t.Errorf("Error reading list of repositories")
 	}
-	testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+	testGiteaAdminClient.PrepareRepository(ctx, getTestCodeset(), testListenerURL)
 
-	repos, _ = testGiteaAdminClient.GetRepositories(&project1, nil)
+	repos, _ = testGiteaAdminClient.GetRepositories(ctx, &project1, nil)
 	if len(repos) < 1 {
 		t.Errorf("List of repositories is empty after adding")
 	}
@@ -289,9 +527,9 @@ func TestGetRepositories(t *testing.T) {
 	// now add new project+repo and list all repos accross projects
 	codeset2 := getTestCodeset()
 	codeset2.Project = project2
-	testGiteaAdminClient.PrepareRepository(codeset2, testListenerURL)
+	testGiteaAdminClient.PrepareRepository(ctx, codeset2, testListenerURL)
 
-	repos, _ = testGiteaAdminClient.GetRepositories(nil, nil)
+	repos, _ = testGiteaAdminClient.GetRepositories(ctx, nil, nil)
 	if len(repos) != 2 {
 		t.Errorf("There are not 2 repos in total")
 	}
@@ -300,17 +538,18 @@ func TestGetRepositories(t *testing.T) {
 func TestAddDeleteOrgs(t *testing.T) {
 
 	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
 
-	testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+	testGiteaAdminClient.PrepareRepository(ctx, getTestCodeset(), testListenerURL)
 
-	p1, err := testGiteaAdminClient.GetProject(project1)
+	p1, err := testGiteaAdminClient.GetProject(ctx, project1)
 	assertError(t, err, nil)
 
 	if p1.Name != project1 {
 		t.Errorf("wrong name of project: %v, not %s", p1.Name, project1)
 	}
 
-	p2, err := testGiteaAdminClient.CreateProject(project2, "description of "+project2, false)
+	p2, err := testGiteaAdminClient.CreateProject(ctx, project2, "description of "+project2, false)
 	assertError(t, err, nil)
 
 	if p2.Name != project2 {
@@ -318,15 +557,15 @@ func TestAddDeleteOrgs(t *testing.T) {
 	}
 
 	// create same project, ignore if it exists
-	_, err = testGiteaAdminClient.CreateProject(project2, "description of "+project2, true)
+	_, err = testGiteaAdminClient.CreateProject(ctx, project2, "description of "+project2, true)
 	assertError(t, err, nil)
 
 	// create same project, fail if it exists
-	_, err = testGiteaAdminClient.CreateProject(project2, "description of "+project2, false)
+	_, err = testGiteaAdminClient.CreateProject(ctx, project2, "description of "+project2, false)
 	assertError(t, err, domain.ErrProjectExists)
 
 	// list all projects, there should be 2
-	projects, err := testGiteaAdminClient.GetProjects()
+	projects, err := testGiteaAdminClient.GetProjects(ctx)
 	assertError(t, err, nil)
 
 	if len(projects) != 2 {
@@ -334,15 +573,15 @@ func TestAddDeleteOrgs(t *testing.T) {
 	}
 
 	// project2 is empty, should not be a problem to delete
-	err = testGiteaAdminClient.DeleteProject(project2)
+	err = testGiteaAdminClient.DeleteProject(ctx, project2)
 	assertError(t, err, nil)
 
 	// project1 is not empty, error on delete
-	err = testGiteaAdminClient.DeleteProject(project1)
+	err = testGiteaAdminClient.DeleteProject(ctx, project1)
 	assertError(t, err, errProjectNotEmpty)
 
 	// list all projects after delete
-	projects, err = testGiteaAdminClient.GetProjects()
+	projects, err = testGiteaAdminClient.GetProjects(ctx)
 	assertError(t, err, nil)
 
 	if len(projects) != 1 {
@@ -350,10 +589,154 @@ func TestAddDeleteOrgs(t *testing.T) {
 	}
 }
 
-func TestNewGiteaAdminClient(t *testing.T) {
+func TestUpdateProject(t *testing.T) {
+	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
+
+	_, err := testGiteaAdminClient.CreateProject(ctx, project1, "description of "+project1, false)
+	assertError(t, err, nil)
+
+	err = testGiteaAdminClient.UpdateProject(ctx, project1, "new description")
+	assertError(t, err, nil)
+
+	p, err := testGiteaAdminClient.GetProject(ctx, project1)
+	assertError(t, err, nil)
+	if p.Description != "new description" {
+		t.Errorf("Unexpected project description: %q", p.Description)
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
+	ctx := context.Background()
+	assertError(t, testGiteaAdminClient.CheckReadiness(ctx), nil)
+}
+
+// testSHA returns the ID of the n-th test commit
+func testSHA(n int) string {
+	return strings.Repeat(fmt.Sprint(n), 40)
+}
+
+func addTestCommits(testStore *TestStore, messages ...string) {
+	key := project1 + "/" + name
+	for i, m := range messages {
+		// the commits are stored newest first
+		testStore.commits[key] = append([]*gitea.Commit{{
+			CommitMeta: &gitea.CommitMeta{SHA: testSHA(len(testStore.commits[key]) + 1)},
+			RepoCommit: &gitea.RepoCommit{
+				Message: m,
+				Author: &gitea.CommitUser{
+					Identity: gitea.Identity{Name: "author", Email: "author@example.io"},
+					Date:     time.Date(2021, 6, i+1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339),
+				},
+			},
+		}}, testStore.commits[key]...)
+	}
+}
+
+func TestGetRepoVersions(t *testing.T) {
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	ctx := context.Background()
+	addTestCommits(testStore, "first", "second", "third")
+	testStore.tags[project1+"/"+name] = []*gitea.Tag{{Name: "v1", Commit: &gitea.CommitMeta{SHA: testSHA(1)}}}
+
+	branches, err := testGiteaAdminClient.GetRepoBranches(ctx, project1, name)
+	assertError(t, err, nil)
+	if len(branches) != 1 || branches[0].Name != "main" || branches[0].Commit != testSHA(3) {
+		t.Errorf("Unexpected branches: %v", branches)
+	}
+
+	tags, err := testGiteaAdminClient.GetRepoTags(ctx, project1, name)
+	assertError(t, err, nil)
+	if len(tags) != 1 || tags[0].Name != "v1" || tags[0].Commit != testSHA(1) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	commits, err := testGiteaAdminClient.GetRepoCommits(ctx, project1, name, "", 2)
+	assertError(t, err, nil)
+	if len(commits) != 2 || commits[0].Message != "third" || commits[1].Message != "second" {
+		t.Errorf("Unexpected commits: %v", commits)
+	}
+	if commits[0].Author != "author" || !commits[0].Date.Equal(time.Date(2021, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected commit author or date: %v", commits[0])
+	}
+
+	commits, err = testGiteaAdminClient.GetRepoCommits(ctx, project1, name, "v1", 10)
+	assertError(t, err, nil)
+	if len(commits) != 1 || commits[0].Message != "first" {
+		t.Errorf("Unexpected commits of tag v1: %v", commits)
+	}
+
+	_, err = testGiteaAdminClient.GetRepoCommits(ctx, project1, name, "unknown", 10)
+	assertError(t, err, domain.ErrCodesetVersionNotFound)
+}
+
+func TestCreateRepoTag(t *testing.T) {
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	ctx := context.Background()
+	addTestCommits(testStore, "first", "second")
+
+	// the head of the default branch is tagged when no commit is given
+	ref, err := testGiteaAdminClient.CreateRepoTag(ctx, project1, name, "latest", "", "")
+	assertError(t, err, nil)
+	if ref.Name != "latest" || ref.Commit != testSHA(2) {
+		t.Errorf("Unexpected tag: %v", ref)
+	}
+
+	// abbreviated commit IDs are resolved
+	ref, err = testGiteaAdminClient.CreateRepoTag(ctx, project1, name, "v1", testSHA(1)[:8], "first release")
+	assertError(t, err, nil)
+	if ref.Commit != testSHA(1) {
+		t.Errorf("Unexpected tag: %v", ref)
+	}
+
+	_, err = testGiteaAdminClient.CreateRepoTag(ctx, project1, name, "v1", "", "")
+	assertError(t, err, domain.ErrCodesetVersionExists)
+
+	_, err = testGiteaAdminClient.CreateRepoTag(ctx, project1, name, "v2", "unknown", "")
+	assertError(t, err, domain.ErrCodesetVersionNotFound)
+}
+
+func TestGetRepoFiles(t *testing.T) {
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	ctx := context.Background()
+	testStore.files[project1+"/"+name] = map[string]string{
+		"Dockerfile":        "FROM python:3.8",
+		"src/train.py":      "print('train')",
+		"src/conf/app.yaml": "name: app",
+	}
+
+	files, err := testGiteaAdminClient.GetRepoFiles(ctx, project1, name, "", "")
+	assertError(t, err, nil)
+	if len(files) != 2 || files[0].Name != "Dockerfile" || files[0].Type != domain.CodesetFileTypeFile ||
+		files[0].Size != 15 || files[1].Name != "src" || files[1].Type != domain.CodesetFileTypeDir {
+		t.Errorf("Unexpected files: %v", files)
+	}
+
+	files, err = testGiteaAdminClient.GetRepoFiles(ctx, project1, name, "main", "src/train.py")
+	assertError(t, err, nil)
+	if len(files) != 1 || files[0].Path != "src/train.py" {
+		t.Errorf("Unexpected files: %v", files)
+	}
+
+	_, err = testGiteaAdminClient.GetRepoFiles(ctx, project1, name, "", "unknown")
+	assertError(t, err, domain.ErrCodesetFileNotFound)
+
+	content, err := testGiteaAdminClient.GetRepoFile(ctx, project1, name, "", "src/conf/app.yaml")
+	assertError(t, err, nil)
+	if string(content) != "name: app" {
+		t.Errorf("Unexpected file contents: %q", content)
+	}
 
-	os.Unsetenv("GITEA_URL")
-	_, err := NewAdminClient(testLogger())
+	_, err = testGiteaAdminClient.GetRepoFile(ctx, project1, name, "", "src")
+	assertError(t, err, domain.ErrCodesetNotAFile)
 
-	assertError(t, err, errGITEAURLMissing)
+	_, err = testGiteaAdminClient.GetRepoFile(ctx, project1, name, "", "unknown")
+	assertError(t, err, domain.ErrCodesetFileNotFound)
+	testStore.files[project1+"/"+name]["model.bin"] = strings.Repeat("0", domain.MaxCodesetFileSize+1)
+	_, err = testGiteaAdminClient.GetRepoFile(ctx, project1, name, "", "model.bin")
+	assertError(t, err, domain.ErrCodesetFileTooLarge)
 }
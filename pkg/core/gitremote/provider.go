@@ -0,0 +1,375 @@
+// Package gitremote implements the access to the external git repositories tracked by the codesets
+package gitremote
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+// fetchedRefName is the ref the commits fetched by their ID are stored under
+const fetchedRefName = "refs/heads/fetched"
+
+// Provider accesses the external git repositories directly, with the git protocol.
+// Implements the domain.CodesetProvider interface.
+type Provider struct {
+	logger *zap.SugaredLogger
+	// maxFileSize is the maximum size of the files whose contents can be read
+	maxFileSize int64
+}
+
+// NewProvider returns a new git repositories provider
+func NewProvider(logger *zap.SugaredLogger) *Provider {
+	return &Provider{logger: logger, maxFileSize: domain.MaxCodesetFileSize}
+}
+
+// log returns the logger for the operations performed while serving the request in the context
+func (p *Provider) log(ctx context.Context) *zap.SugaredLogger {
+	return logging.FromContext(ctx, p.logger)
+}
+
+// auth returns the method used to authenticate to the repository, nil when no credentials are set
+func auth(source *domain.CodesetSource) transport.AuthMethod {
+	if source.Username == "" && source.Password == "" {
+		return nil
+	}
+	return &githttp.BasicAuth{Username: source.Username, Password: source.Password}
+}
+
+// listRefs returns all the refs advertised by the repository
+func (p *Provider) listRefs(ctx context.Context, source *domain.CodesetSource) ([]*plumbing.Reference, error) {
+	p.log(ctx).Debugw("Listing repository refs", "url", source.URL)
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{source.URL}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth(source)})
+	if err != nil && err != transport.ErrEmptyRemoteRepository {
+		return nil, errors.Wrap(err, "Failed to list repository refs")
+	}
+	return refs, nil
+}
+
+// GetRefs returns the branches and tags of the repository, sorted by name. Annotated tags point to the
+// tag object instead of the tagged commit.
+func (p *Provider) GetRefs(ctx context.Context, source *domain.CodesetSource) (branches, tags []*domain.CodesetRef, err error) {
+	ctx, span := tracing.Start(ctx, "gitremote.GetRefs")
+	defer tracing.End(span, &err)
+
+	refs, err := p.listRefs(ctx, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	branches, tags = []*domain.CodesetRef{}, []*domain.CodesetRef{}
+	for _, ref := range refs {
+		switch {
+		case ref.Name().IsBranch():
+			branches = append(branches, &domain.CodesetRef{Name: ref.Name().Short(), Commit: ref.Hash().String()})
+		case ref.Name().IsTag():
+			tags = append(tags, &domain.CodesetRef{Name: ref.Name().Short(), Commit: ref.Hash().String()})
+		}
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return branches, tags, nil
+}
+
+// GetDefaultBranch returns the name of the branch the HEAD of the repository points to, which is empty
+// for an empty repository
+func (p *Provider) GetDefaultBranch(ctx context.Context, source *domain.CodesetSource) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "gitremote.GetDefaultBranch")
+	defer tracing.End(span, &err)
+
+	refs, err := p.listRefs(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	return defaultBranch(refs), nil
+}
+
+// GetCommits returns the most recent commits reachable from the given branch, tag or full commit ID of the
+// repository (the default branch when empty), newest first. The repository is cloned in memory, only down
+// to the requested depth when a branch or tag is given.
+func (p *Provider) GetCommits(ctx context.Context, source *domain.CodesetSource, ref string, limit int) (_ []*domain.CodesetCommit, err error) {
+	ctx, span := tracing.Start(ctx, "gitremote.GetCommits")
+	defer tracing.End(span, &err)
+
+	repo, from, err := p.clone(ctx, source, ref, limit)
+	if err != nil {
+		return nil, err
+	}
+	if repo == nil {
+		return []*domain.CodesetCommit{}, nil
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: from.Hash})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the repository log")
+	}
+	defer commits.Close()
+
+	ret := []*domain.CodesetCommit{}
+	for len(ret) < limit {
+		c, err := commits.Next()
+		if err != nil {
+			// the end of the history, or the boundary of the shallow clone, is reached
+			break
+		}
+		ret = append(ret, toCodesetCommit(c))
+	}
+	return ret, nil
+}
+
+// GetFiles returns the entries of a directory (or the file itself) at the given path and branch, tag or full
+// commit ID of the repository (the default branch when empty), sorted by name
+func (p *Provider) GetFiles(ctx context.Context, source *domain.CodesetSource, ref, path string) (_ []*domain.CodesetFile, err error) {
+	ctx, span := tracing.Start(ctx, "gitremote.GetFiles")
+	defer tracing.End(span, &err)
+
+	tree, err := p.getTree(ctx, source, ref)
+	if err != nil {
+		return nil, err
+	}
+	path = strings.Trim(path, "/")
+	if path != "" {
+		entry, err := tree.FindEntry(path)
+		if err != nil {
+			return nil, domain.ErrCodesetFileNotFound
+		}
+		if entry.Mode != filemode.Dir {
+			return []*domain.CodesetFile{toCodesetFile(tree, path, entry)}, nil
+		}
+		if tree, err = tree.Tree(path); err != nil {
+			return nil, errors.Wrap(err, "Failed to read the repository tree")
+		}
+	}
+
+	ret := make([]*domain.CodesetFile, 0, len(tree.Entries))
+	for i, entry := range tree.Entries {
+		entryPath := entry.Name
+		if path != "" {
+			entryPath = path + "/" + entry.Name
+		}
+		ret = append(ret, toCodesetFile(tree, entryPath, &tree.Entries[i]))
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+// GetFile returns the contents of the file at the given path and branch, tag or full commit ID of the
+// repository (the default branch when empty). Files larger than the maximum size are not read.
+func (p *Provider) GetFile(ctx context.Context, source *domain.CodesetSource, ref, path string) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "gitremote.GetFile")
+	defer tracing.End(span, &err)
+
+	tree, err := p.getTree(ctx, source, ref)
+	if err != nil {
+		return nil, err
+	}
+	path = strings.Trim(path, "/")
+	entry, err := tree.FindEntry(path)
+	if err != nil {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	if !entry.Mode.IsFile() || entry.Mode == filemode.Symlink {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	file, err := tree.TreeEntryFile(entry)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the repository file")
+	}
+	if file.Size > p.maxFileSize {
+		return nil, domain.ErrCodesetFileTooLarge
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the repository file")
+	}
+	return []byte(contents), nil
+}
+
+// clone clones in memory the repository, down to the given depth, and returns the commit the ref (the default
+// branch when empty) points to. No repository is returned when it is empty. The whole repository is cloned
+// only for the commits that no branch or tag points to, when the server does not allow fetching them directly.
+func (p *Provider) clone(ctx context.Context, source *domain.CodesetSource, ref string, depth int) (*git.Repository, *object.Commit, error) {
+	refs, err := p.listRefs(ctx, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(refs) == 0 {
+		return nil, nil, nil
+	}
+
+	opts := &git.CloneOptions{URL: source.URL, Auth: auth(source), NoCheckout: true, Tags: git.NoTags}
+	var from *plumbing.Hash
+	if ref == "" {
+		ref = defaultBranch(refs)
+	}
+	switch {
+	case findRef(refs, plumbing.NewBranchReferenceName(ref)) != nil:
+		opts.ReferenceName = plumbing.NewBranchReferenceName(ref)
+		opts.SingleBranch, opts.Depth = true, depth
+	case findRef(refs, plumbing.NewTagReferenceName(ref)) != nil:
+		opts.ReferenceName = plumbing.NewTagReferenceName(ref)
+		opts.SingleBranch, opts.Depth = true, depth
+	case plumbing.IsHash(ref):
+		hash := plumbing.NewHash(ref)
+		from = &hash
+		if r := findHashRef(refs, hash); r != nil {
+			opts.ReferenceName = r.Name()
+			opts.SingleBranch, opts.Depth = true, depth
+			break
+		}
+		repo, commit, err := p.fetchCommit(ctx, source, hash, depth)
+		if err != git.ErrExactSHA1NotSupported {
+			return repo, commit, err
+		}
+	default:
+		return nil, nil, domain.ErrCodesetVersionNotFound
+	}
+
+	p.log(ctx).Debugw("Cloning repository", "url", source.URL, "ref", ref)
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to clone repository")
+	}
+	if from == nil {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to read the repository head")
+		}
+		hash := head.Hash()
+		from = &hash
+	}
+	commit, err := repo.CommitObject(*from)
+	if err != nil {
+		return nil, nil, domain.ErrCodesetVersionNotFound
+	}
+	return repo, commit, nil
+}
+
+// fetchCommit fetches in memory the given commit of the repository, down to the given depth. It returns
+// git.ErrExactSHA1NotSupported when the server does not allow fetching commits by their ID.
+func (p *Provider) fetchCommit(ctx context.Context, source *domain.CodesetSource, hash plumbing.Hash, depth int) (*git.Repository, *object.Commit, error) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to initialize repository")
+	}
+	remote, err := repo.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{source.URL}})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to initialize repository")
+	}
+
+	p.log(ctx).Debugw("Fetching repository commit", "url", source.URL, "commit", hash.String())
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(hash.String() + ":" + fetchedRefName)},
+		Auth:     auth(source),
+		Depth:    depth,
+		Tags:     git.NoTags,
+	})
+	if err == git.ErrExactSHA1NotSupported {
+		return nil, nil, err
+	}
+	if err != nil {
+		// the refs were listed already, the server refuses to send a commit it does not have
+		p.log(ctx).Debugw("Failed to fetch repository commit", "url", source.URL, "commit", hash.String(), logging.ErrorKey, err)
+		return nil, nil, domain.ErrCodesetVersionNotFound
+	}
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, nil, domain.ErrCodesetVersionNotFound
+	}
+	return repo, commit, nil
+}
+
+// getTree returns the tree of the commit the ref (the default branch when empty) points to
+func (p *Provider) getTree(ctx context.Context, source *domain.CodesetSource, ref string) (*object.Tree, error) {
+	repo, commit, err := p.clone(ctx, source, ref, 1)
+	if err != nil {
+		return nil, err
+	}
+	if repo == nil {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the repository tree")
+	}
+	return tree, nil
+}
+
+// defaultBranch returns the name of the branch the HEAD of the repository points to
+func defaultBranch(refs []*plumbing.Reference) string {
+	head := findRef(refs, plumbing.HEAD)
+	if head == nil {
+		return ""
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target().Short()
+	}
+	// the servers not advertising the HEAD target: pick the branch pointing to the same commit
+	for _, r := range refs {
+		if r.Name().IsBranch() && r.Hash() == head.Hash() {
+			return r.Name().Short()
+		}
+	}
+	return ""
+}
+
+// findHashRef returns the branch or tag pointing to the given commit
+func findHashRef(refs []*plumbing.Reference, hash plumbing.Hash) *plumbing.Reference {
+	for _, r := range refs {
+		if (r.Name().IsBranch() || r.Name().IsTag()) && r.Hash() == hash {
+			return r
+		}
+	}
+	return nil
+}
+
+func findRef(refs []*plumbing.Reference, name plumbing.ReferenceName) *plumbing.Reference {
+	for _, r := range refs {
+		if r.Name() == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func toCodesetFile(tree *object.Tree, path string, entry *object.TreeEntry) *domain.CodesetFile {
+	file := &domain.CodesetFile{Name: entry.Name, Path: path}
+	switch entry.Mode {
+	case filemode.Dir:
+		file.Type = domain.CodesetFileTypeDir
+	case filemode.Symlink:
+		file.Type = domain.CodesetFileTypeSymlink
+	case filemode.Submodule:
+		file.Type = domain.CodesetFileTypeSubmodule
+	default:
+		file.Type = domain.CodesetFileTypeFile
+		if f, err := tree.TreeEntryFile(entry); err == nil {
+			file.Size = f.Size
+		}
+	}
+	return file
+}
+
+func toCodesetCommit(c *object.Commit) *domain.CodesetCommit {
+	return &domain.CodesetCommit{
+		ID:      c.Hash.String(),
+		Author:  c.Author.Name,
+		Message: c.Message,
+		Date:    c.Author.When.UTC(),
+	}
+}
@@ -0,0 +1,227 @@
+package gitremote
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// newTestRepository creates a local repository with a commit for each message on the main branch, which writes
+// the message to the "file" and "src/file" files, tags the first commit as v1 and returns the repository
+// directory and the commit IDs, oldest first
+func newTestRepository(t *testing.T, messages ...string) (string, []string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "gitremote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits := []string{}
+	for i, m := range messages {
+		if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range []string{"file", "src/file"} {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(m), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := wt.Add(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		hash, err := wt.Commit(m, &git.CommitOptions{Author: &object.Signature{
+			Name:  "author",
+			Email: "author@example.io",
+			When:  time.Date(2021, 6, i+1, 0, 0, 0, 0, time.UTC),
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, hash.String())
+	}
+	if _, err := repo.CreateTag("v1", plumbing.NewHash(commits[0]), nil); err != nil {
+		t.Fatal(err)
+	}
+	return dir, commits
+}
+
+// allowFetchingCommits configures the repository in the given directory to let the clients fetch any commit
+// reachable from its branches and tags
+func allowFetchingCommits(t *testing.T, dir string) {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Raw.Section("uploadpack").SetOption("allowReachableSHA1InWant", "true")
+	if err := repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetRefs(t *testing.T) {
+	dir, commits := newTestRepository(t, "first", "second")
+	p := NewProvider(zap.NewNop().Sugar())
+
+	branches, tags, err := p.GetRefs(context.Background(), &domain.CodesetSource{URL: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 1 || branches[0].Name != "main" || branches[0].Commit != commits[1] {
+		t.Errorf("Unexpected branches: %v", branches)
+	}
+	if len(tags) != 1 || tags[0].Name != "v1" || tags[0].Commit != commits[0] {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	branch, err := p.GetDefaultBranch(context.Background(), &domain.CodesetSource{URL: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if branch != "main" {
+		t.Errorf("got default branch %q, want %q", branch, "main")
+	}
+}
+
+func TestGetCommits(t *testing.T) {
+	dir, commits := newTestRepository(t, "first", "second", "third")
+	p := NewProvider(zap.NewNop().Sugar())
+	source := &domain.CodesetSource{URL: dir}
+
+	tests := []struct {
+		name  string
+		ref   string
+		limit int
+		fetch bool
+		want  []string
+		err   error
+	}{
+		{name: "default branch", limit: 2, want: []string{"third", "second"}},
+		{name: "branch", ref: "main", limit: 10, want: []string{"third", "second", "first"}},
+		{name: "tag", ref: "v1", limit: 10, want: []string{"first"}},
+		{name: "commit", ref: commits[1], limit: 10, want: []string{"second", "first"}},
+		{name: "branch commit", ref: commits[2], limit: 2, want: []string{"third", "second"}},
+		{name: "fetched commit", ref: commits[1], limit: 1, fetch: true, want: []string{"second"}},
+		{name: "unknown commit", ref: "0123456789012345678901234567890123456789", limit: 10, fetch: true, err: domain.ErrCodesetVersionNotFound},
+		{name: "unknown", ref: "unknown", limit: 10, err: domain.ErrCodesetVersionNotFound},
+	}
+	fetchDir, _ := newTestRepository(t, "first", "second", "third")
+	allowFetchingCommits(t, fetchDir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := source
+			if tt.fetch {
+				source = &domain.CodesetSource{URL: fetchDir}
+			}
+			got, err := p.GetCommits(context.Background(), source, tt.ref, tt.limit)
+			if err != tt.err {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			messages := []string{}
+			for _, c := range got {
+				messages = append(messages, c.Message)
+			}
+			if len(messages) != len(tt.want) {
+				t.Fatalf("got commits %v, want %v", messages, tt.want)
+			}
+			for i := range messages {
+				if messages[i] != tt.want[i] {
+					t.Errorf("got commits %v, want %v", messages, tt.want)
+				}
+			}
+		})
+	}
+
+	got, _ := p.GetCommits(context.Background(), source, "", 1)
+	want := domain.CodesetCommit{ID: commits[2], Author: "author", Message: "third", Date: time.Date(2021, 6, 3, 0, 0, 0, 0, time.UTC)}
+	if len(got) != 1 || *got[0] != want {
+		t.Errorf("got commit %v, want %v", got, want)
+	}
+}
+
+func TestGetFiles(t *testing.T) {
+	dir, _ := newTestRepository(t, "first", "second")
+	p := NewProvider(zap.NewNop().Sugar())
+	source := &domain.CodesetSource{URL: dir}
+
+	files, err := p.GetFiles(context.Background(), source, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.CodesetFile{
+		{Name: "file", Path: "file", Type: domain.CodesetFileTypeFile, Size: 6},
+		{Name: "src", Path: "src", Type: domain.CodesetFileTypeDir},
+	}
+	if len(files) != len(want) || *files[0] != want[0] || *files[1] != want[1] {
+		t.Errorf("got files %v, want %v", files, want)
+	}
+
+	files, err = p.GetFiles(context.Background(), source, "v1", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []domain.CodesetFile{{Name: "file", Path: "src/file", Type: domain.CodesetFileTypeFile, Size: 5}}
+	if len(files) != 1 || *files[0] != want[0] {
+		t.Errorf("got files %v, want %v", files, want)
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		path string
+		want string
+		err  error
+	}{
+		{name: "default branch", path: "src/file", want: "second"},
+		{name: "tag", ref: "v1", path: "file", want: "first"},
+		{name: "directory", path: "src", err: domain.ErrCodesetNotAFile},
+		{name: "unknown path", path: "unknown", err: domain.ErrCodesetFileNotFound},
+		{name: "unknown ref", ref: "unknown", path: "file", err: domain.ErrCodesetVersionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.GetFile(context.Background(), source, tt.ref, tt.path)
+			if err != tt.err {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got contents %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	p.maxFileSize = 5
+	if _, err := p.GetFile(context.Background(), source, "", "file"); err != domain.ErrCodesetFileTooLarge {
+		t.Errorf("got error %v, want %v", err, domain.ErrCodesetFileTooLarge)
+	}
+}
@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+// runAdmissionInterval is the interval at which FuseML starts the queued workflow runs
+const runAdmissionInterval = 10 * time.Second
+
+// RunAdmitter starts the workflow runs triggered by the codeset webhooks, which are queued by the workflow
+// backend, as long as the quota of the codeset project allows it
+type RunAdmitter struct {
+	logger          *zap.SugaredLogger
+	quotas          domain.ProjectQuotaManager
+	workflowStore   domain.WorkflowStore
+	workflowBackend domain.WorkflowBackend
+	interval        time.Duration
+}
+
+// NewRunAdmitter initializes a Run Admitter
+func NewRunAdmitter(logger *zap.SugaredLogger, quotas domain.ProjectQuotaManager, workflowStore domain.WorkflowStore,
+	workflowBackend domain.WorkflowBackend) *RunAdmitter {
+	return &RunAdmitter{logger, quotas, workflowStore, workflowBackend, runAdmissionInterval}
+}
+
+// Run starts the queued workflow runs periodically, until the context is cancelled
+func (a *RunAdmitter) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		a.Admit(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Admit starts the queued runs of the workflows assigned to codesets, until the quota of each codeset project
+// is reached
+func (a *RunAdmitter) Admit(ctx context.Context) {
+	assignments := a.workflowStore.GetAllCodesetAssignments(ctx, nil)
+	workflows := make([]string, 0, len(assignments))
+	for name := range assignments {
+		workflows = append(workflows, name)
+	}
+	sort.Strings(workflows)
+
+	for _, name := range workflows {
+		wf, err := a.workflowStore.GetWorkflow(ctx, name)
+		if err != nil {
+			a.logger.Warnw("Failed to get the workflow with queued runs", logging.WorkflowKey, name, logging.ErrorKey, err)
+			continue
+		}
+		projects := map[string]bool{}
+		for _, assignment := range assignments[name] {
+			project := assignment.Codeset.Project
+			if projects[project] || ctx.Err() != nil {
+				continue
+			}
+			projects[project] = true
+			a.admitRuns(ctx, wf, project)
+		}
+	}
+}
+
+// admitRuns starts the queued runs of a workflow for the codesets of a project, while its quota allows it
+func (a *RunAdmitter) admitRuns(ctx context.Context, wf *domain.Workflow, project string) {
+	log := a.logger.With(logging.WorkflowKey, wf.Name, logging.ProjectKey, project)
+	filter := &domain.WorkflowRunFilter{CodesetProject: project, Status: []string{domain.WorkflowRunQueued}}
+	runs, _, err := a.workflowBackend.GetWorkflowRuns(ctx, wf, filter, nil)
+	if err != nil {
+		log.Warnw("Failed to list the queued workflow runs", logging.ErrorKey, err)
+		return
+	}
+	for i, run := range runs {
+		if err := a.quotas.CheckRunQuota(ctx, project); err != nil {
+			log.Debugw("Keeping the workflow runs queued", "runs", len(runs)-i, logging.ErrorKey, err)
+			return
+		}
+		if err := a.workflowBackend.StartWorkflowRun(ctx, run.Name); err != nil {
+			log.Warnw("Failed to start the queued workflow run", logging.RunKey, run.Name, logging.ErrorKey, err)
+			return
+		}
+		log.Infow("Started the queued workflow run", logging.RunKey, run.Name)
+	}
+}
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestAdmitRuns(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	ctx := context.Background()
+
+	wf, err := mgr.CreateWorkflow(ctx, &domain.Workflow{Name: "wf"})
+	assertError(t, err, nil)
+	codeset, _ := codesetStore.Find(ctx, "csproject1", "cs1")
+	_, _, err = mgr.AssignToCodeset(ctx, wf.Name, codeset.Project, codeset.Name, nil)
+	assertError(t, err, nil)
+
+	// the initial run is in progress and two more runs are queued by the codeset webhook
+	backend := workflowBackend.(*fakeWorkflowBackend)
+	for i := 0; i < 2; i++ {
+		assertError(t, backend.CreateWorkflowRun(ctx, wf.Name, codeset, "refs/heads/main", ""), nil)
+	}
+	runs := backend.workflows[wf.Name].runs
+	runs[0].Status = "Running"
+	runs[1].Status, runs[2].Status = domain.WorkflowRunQueued, domain.WorkflowRunQueued
+
+	quotas := mgr.quotas
+	quotas.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxConcurrentRuns: 2})
+	a := NewRunAdmitter(zap.NewNop().Sugar(), quotas, workflowStore, workflowBackend)
+	a.Admit(ctx)
+	if runs[1].Status != "Running" || runs[2].Status != domain.WorkflowRunQueued {
+		t.Errorf("Expected only one queued run to be started, got %q and %q", runs[1].Status, runs[2].Status)
+	}
+
+	// the queued run is started when another run completes
+	runs[0].Status = "Succeeded"
+	a.Admit(ctx)
+	if runs[2].Status != "Running" {
+		t.Errorf("Expected the queued run to be started, got %q", runs[2].Status)
+	}
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+const (
+	// applicationCheckInterval is the interval at which FuseML checks the status of the registered applications
+	applicationCheckInterval = 1 * time.Minute
+	// applicationProbeTimeout is the time that FuseML waits for an application URL to respond
+	applicationProbeTimeout = 5 * time.Second
+)
+
+// ApplicationReconciler periodically checks the health of the applications registered in FuseML
+// and updates their status accordingly
+type ApplicationReconciler struct {
+	logger     *zap.SugaredLogger
+	store      domain.ApplicationStore
+	inspector  domain.KubernetesResourceInspector
+	httpClient *http.Client
+	interval   time.Duration
+}
+
+// NewApplicationReconciler initializes an Application Reconciler
+func NewApplicationReconciler(
+	logger *zap.SugaredLogger,
+	store domain.ApplicationStore,
+	inspector domain.KubernetesResourceInspector) *ApplicationReconciler {
+	return &ApplicationReconciler{logger, store, inspector, &http.Client{Timeout: applicationProbeTimeout}, applicationCheckInterval}
+}
+
+// Run checks the status of the registered applications periodically, until the context is cancelled
+func (r *ApplicationReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.Reconcile(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks the status of all the registered applications once
+func (r *ApplicationReconciler) Reconcile(ctx context.Context) {
+	apps, _, err := r.store.GetAll(ctx, nil, nil)
+	if err != nil {
+		r.logger.Errorw("Failed to list applications", logging.ErrorKey, err)
+		return
+	}
+	for _, app := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+		status := r.checkApplication(ctx, app)
+		if err := r.store.UpdateStatus(ctx, app.Name, status); err != nil {
+			r.logger.Errorw("Failed to update application status", "application", app.Name, logging.ErrorKey, err)
+		}
+	}
+}
+
+// checkApplication inspects the Kubernetes resources of an application and probes its URL
+func (r *ApplicationReconciler) checkApplication(ctx context.Context, app *domain.Application) *domain.ApplicationStatus {
+	status := &domain.ApplicationStatus{State: domain.ApplicationReady, LastChecked: time.Now()}
+	messages := []string{}
+
+	missing := 0
+	for _, res := range app.K8sResources {
+		found, ready, message, err := r.inspector.InspectResource(ctx, app.K8sNamespace, res)
+		if err != nil {
+			message = fmt.Sprintf("failed to inspect %s %s: %v", res.Kind, res.Name, err)
+		}
+		if !found && err == nil {
+			missing++
+		}
+		if err != nil || !ready {
+			status.State = domain.ApplicationDegraded
+			messages = append(messages, message)
+		}
+	}
+	if len(app.K8sResources) > 0 && missing == len(app.K8sResources) {
+		status.State = domain.ApplicationMissing
+		status.Message = strings.Join(messages, "; ")
+		return status
+	}
+
+	if err := r.probeURL(ctx, app.URL); err != nil {
+		status.State = domain.ApplicationDegraded
+		messages = append(messages, err.Error())
+	}
+	status.Message = strings.Join(messages, "; ")
+	return status
+}
+
+// probeURL checks whether the application URL is reachable. Any response other than a server error
+// is considered a sign that the application is alive, given that the application may not accept
+// GET requests at its URL (e.g. prediction endpoints).
+func (r *ApplicationReconciler) probeURL(ctx context.Context, url string) error {
+	if url == "" {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid application URL %q: %w", url, err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("application URL %s is not reachable: %w", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("application URL %s returned %s", url, resp.Status)
+	}
+	return nil
+}
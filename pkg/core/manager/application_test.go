@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestReconcileApplications(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		resources map[string]fakeResourceState
+		want      string
+	}{
+		{"ready", healthy.URL, map[string]fakeResourceState{"svc": {true, true}, "deploy": {true, true}}, domain.ApplicationReady},
+		{"resource not ready", healthy.URL, map[string]fakeResourceState{"svc": {true, true}, "deploy": {true, false}}, domain.ApplicationDegraded},
+		{"resource missing", healthy.URL, map[string]fakeResourceState{"svc": {true, true}, "deploy": {false, false}}, domain.ApplicationDegraded},
+		{"all resources missing", healthy.URL, map[string]fakeResourceState{"svc": {false, false}, "deploy": {false, false}}, domain.ApplicationMissing},
+		{"url failing", failing.URL, map[string]fakeResourceState{"svc": {true, true}}, domain.ApplicationDegraded},
+		{"no resources", healthy.URL, nil, domain.ApplicationReady},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := core.NewApplicationStore()
+			app := &domain.Application{Name: "app", URL: tc.url, K8sNamespace: "test"}
+			for name := range tc.resources {
+				app.K8sResources = append(app.K8sResources, &domain.KubernetesResource{Name: name, Kind: "Deployment"})
+			}
+			store.Add(context.TODO(), app)
+
+			r := NewApplicationReconciler(zap.NewNop().Sugar(), store, fakeResourceInspector(tc.resources))
+			r.Reconcile(context.TODO())
+
+			got := store.Find(context.TODO(), app.Name)
+			if got.Status == nil {
+				t.Fatalf("Expected application status to be set")
+			}
+			assertStrings(t, got.Status.State, tc.want)
+			if got.Status.LastChecked.IsZero() {
+				t.Errorf("Expected last checked time to be set")
+			}
+			if tc.want != domain.ApplicationReady && got.Status.Message == "" {
+				t.Errorf("Expected a status message for state %q", got.Status.State)
+			}
+		})
+	}
+}
+
+type fakeResourceState struct {
+	found bool
+	ready bool
+}
+
+type fakeResourceInspector map[string]fakeResourceState
+
+func (i fakeResourceInspector) InspectResource(ctx context.Context, namespace string, resource *domain.KubernetesResource) (bool, bool, string, error) {
+	state := i[resource.Name]
+	if !state.found {
+		return false, false, fmt.Sprintf("%s %s not found", resource.Kind, resource.Name), nil
+	}
+	if !state.ready {
+		return true, false, fmt.Sprintf("%s %s is not ready", resource.Kind, resource.Name), nil
+	}
+	return true, true, "", nil
+}
@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+// assignmentCheckInterval is the interval at which FuseML checks that the codesets assigned to workflows still exist
+const assignmentCheckInterval = 5 * time.Minute
+
+// AssignmentReconciler keeps the workflow manager subscribed to the codesets assigned to workflows, which is
+// not persisted by the codeset store, and removes the assignments to codesets that no longer exist
+type AssignmentReconciler struct {
+	logger   *zap.SugaredLogger
+	mgr      *WorkflowManager
+	interval time.Duration
+}
+
+// NewAssignmentReconciler initializes an Assignment Reconciler
+func NewAssignmentReconciler(logger *zap.SugaredLogger, mgr *WorkflowManager) *AssignmentReconciler {
+	return &AssignmentReconciler{logger, mgr, assignmentCheckInterval}
+}
+
+// Run reconciles the codeset assignments periodically, until the context is cancelled. The first pass,
+// run right away, restores the codeset subscriptions after a restart.
+func (r *AssignmentReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.Reconcile(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks all the codeset assignments once
+func (r *AssignmentReconciler) Reconcile(ctx context.Context) {
+	assignments := r.mgr.workflowStore.GetAllCodesetAssignments(ctx, nil)
+	workflows := make([]string, 0, len(assignments))
+	for name := range assignments {
+		workflows = append(workflows, name)
+	}
+	sort.Strings(workflows)
+
+	for _, name := range workflows {
+		for _, a := range assignments[name] {
+			if ctx.Err() != nil {
+				return
+			}
+			r.reconcileAssignment(ctx, name, a.Codeset)
+		}
+	}
+}
+
+// reconcileAssignment subscribes the workflow manager to an assigned codeset, or removes the assignment when
+// the codeset was deleted
+func (r *AssignmentReconciler) reconcileAssignment(ctx context.Context, name string, codeset *domain.Codeset) {
+	log := r.logger.With(logging.WorkflowKey, name, logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	found, err := r.mgr.codesetStore.Find(ctx, codeset.Project, codeset.Name)
+	if err == nil {
+		if err := r.mgr.codesetStore.Subscribe(ctx, r.mgr, found); err != nil {
+			log.Errorw("Failed to subscribe to the assigned codeset", logging.ErrorKey, err)
+		}
+		return
+	}
+	if !errors.Is(err, domain.ErrCodesetNotFound) {
+		log.Errorw("Failed to check the assigned codeset", logging.ErrorKey, err)
+		return
+	}
+	// the webhook was deleted along with the codeset repository
+	if err := r.mgr.deleteAssignment(ctx, name, codeset); err != nil {
+		log.Errorw("Failed to remove the assignment to a deleted codeset", logging.ErrorKey, err)
+		return
+	}
+	log.Info("Removed the assignment to a deleted codeset")
+}
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestReconcileAssignments(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	ctx := context.Background()
+
+	wf, err := mgr.CreateWorkflow(ctx, &domain.Workflow{Name: "wf"})
+	assertError(t, err, nil)
+	codesets := []*domain.Codeset{}
+	for _, name := range []string{"cs1", "cs2"} {
+		codeset, _ := codesetStore.Find(ctx, "csproject1", name)
+		codesets = append(codesets, codeset)
+		_, _, err := mgr.AssignToCodeset(ctx, wf.Name, codeset.Project, codeset.Name, nil)
+		assertError(t, err, nil)
+	}
+
+	// simulate a restart, which loses the subscriptions, and the deletion of a codeset meanwhile
+	for id, sc := range codesetStore.store {
+		sc.subscribers = nil
+		codesetStore.store[id] = sc
+	}
+	delete(codesetStore.store, codesetID{codesets[1].Name, codesets[1].Project})
+
+	r := NewAssignmentReconciler(zap.NewNop().Sugar(), mgr)
+	r.Reconcile(ctx)
+	r.Reconcile(ctx)
+
+	if got := codesetStore.getSubscribers(ctx, codesets[0]); len(got) != 1 || got[0] != mgr {
+		t.Errorf("Unexpected subscribers of the assigned codeset: %v", got)
+	}
+	got := workflowStore.GetCodesetAssignments(ctx, wf.Name)
+	if len(got) != 1 || got[0].Codeset.Name != codesets[0].Name {
+		t.Errorf("Unexpected codeset assignments: %v", got)
+	}
+	secrets := getListenerSecrets(wf.Name)
+	if _, ok := secrets[codesets[1].Project+"/"+codesets[1].Name]; ok {
+		t.Errorf("The listener trigger of the deleted codeset was not removed")
+	}
+
+	// removing the last assignment deletes the workflow listener
+	delete(codesetStore.store, codesetID{codesets[0].Name, codesets[0].Project})
+	r.Reconcile(ctx)
+	if got := workflowStore.GetCodesetAssignments(ctx, wf.Name); len(got) != 0 {
+		t.Errorf("Unexpected codeset assignments: %v", got)
+	}
+	if workflowBackend.(*fakeWorkflowBackend).workflows[wf.Name].listener != nil {
+		t.Errorf("The workflow listener was not deleted")
+	}
+}
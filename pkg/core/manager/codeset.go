@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+// codesetPollInterval is the interval at which FuseML checks which tracked codesets are due to be polled
+const codesetPollInterval = 1 * time.Minute
+
+// CodesetPoller periodically polls the external repositories tracked by the codesets for new commits,
+// and runs the workflows assigned to the codesets for the branches and tags that were pushed to
+type CodesetPoller struct {
+	logger          *zap.SugaredLogger
+	store           domain.ExternalCodesetStore
+	credentials     domain.CodesetCredentialsStore
+	provider        domain.CodesetProvider
+	workflowStore   domain.WorkflowStore
+	workflowBackend domain.WorkflowBackend
+	quotas          domain.ProjectQuotaManager
+	interval        time.Duration
+}
+
+// NewCodesetPoller initializes a Codeset Poller
+func NewCodesetPoller(
+	logger *zap.SugaredLogger,
+	store domain.ExternalCodesetStore,
+	credentials domain.CodesetCredentialsStore,
+	provider domain.CodesetProvider,
+	workflowStore domain.WorkflowStore,
+	workflowBackend domain.WorkflowBackend,
+	quotas domain.ProjectQuotaManager) *CodesetPoller {
+	return &CodesetPoller{logger, store, credentials, provider, workflowStore, workflowBackend, quotas, codesetPollInterval}
+}
+
+// Run polls the tracked codesets periodically, until the context is cancelled
+func (p *CodesetPoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll polls once the tracked codesets that were not polled during their interval. The archived codesets
+// are not polled.
+func (p *CodesetPoller) Poll(ctx context.Context) {
+	codesets, err := p.store.GetCodesets(ctx, nil)
+	if err != nil {
+		p.logger.Errorw("Failed to list tracked codesets", logging.ErrorKey, err)
+		return
+	}
+	now := time.Now()
+	for _, c := range codesets {
+		if ctx.Err() != nil {
+			return
+		}
+		if !c.IsTracked() || c.Archived || now.Sub(c.Source.Polled) < c.Source.Interval {
+			continue
+		}
+		p.pollCodeset(ctx, c, now)
+	}
+}
+
+// pollCodeset reads the branches and tags of the repository tracked by a codeset, runs the assigned workflows
+// for the ones pointing to a new commit and records them
+func (p *CodesetPoller) pollCodeset(ctx context.Context, c *domain.Codeset, now time.Time) {
+	log := p.logger.With(logging.ProjectKey, c.Project, logging.CodesetKey, c.Name)
+
+	source := *c.Source
+	var err error
+	source.Username, source.Password, err = p.credentials.GetCodesetCredentials(ctx, c)
+	if err != nil {
+		log.Errorw("Failed to get the codeset repository credentials", logging.ErrorKey, err)
+		return
+	}
+	branches, tags, err := p.provider.GetRefs(ctx, &source)
+	if err != nil {
+		log.Warnw("Failed to poll the codeset repository", "url", source.URL, logging.ErrorKey, err)
+		return
+	}
+
+	heads := domain.RefHeads(branches, tags)
+	if changed := changedRefs(c.Source.Heads, heads); len(changed) > 0 {
+		log.Infow("Found new commits in the codeset repository", "refs", changed)
+		p.runWorkflows(ctx, c, changed, heads)
+	}
+
+	if err := p.store.UpdateCodesetHeads(ctx, c.Project, c.Name, heads, now); err != nil {
+		log.Errorw("Failed to update the tracked codeset", logging.ErrorKey, err)
+	}
+}
+
+// runWorkflows runs the workflows assigned to the codeset, whose ref filter selects them, for each one of
+// the changed refs
+func (p *CodesetPoller) runWorkflows(ctx context.Context, c *domain.Codeset, refs []string, heads map[string]string) {
+	assignments := p.workflowStore.GetAllCodesetAssignments(ctx, nil)
+	workflows := make([]string, 0, len(assignments))
+	for wf := range assignments {
+		workflows = append(workflows, wf)
+	}
+	sort.Strings(workflows)
+
+	for _, wf := range workflows {
+		for _, a := range assignments[wf] {
+			if a.Codeset.Project != c.Project || a.Codeset.Name != c.Name {
+				continue
+			}
+			for _, ref := range refs {
+				if !a.Refs.Match(ref) {
+					continue
+				}
+				// the tags are checked out by name, as annotated tags do not point to a commit
+				revision := heads[ref]
+				if strings.HasPrefix(ref, "refs/tags/") {
+					revision = strings.TrimPrefix(ref, "refs/tags/")
+				}
+				// the runs exceeding the quota of the project are queued, like the runs triggered by the
+				// webhooks, and started by the run admitter
+				if err := p.quotas.CheckRunQuota(ctx, c.Project); err != nil {
+					p.logger.Infow("Queueing the workflow run exceeding the project quota", logging.WorkflowKey, wf,
+						logging.ProjectKey, c.Project, logging.CodesetKey, c.Name, "ref", ref, logging.ErrorKey, err)
+					if err := p.workflowBackend.QueueWorkflowRun(ctx, wf, c, ref, revision); err != nil {
+						p.logger.Warnw("Failed to queue the workflow assigned to the codeset", logging.WorkflowKey, wf,
+							logging.ProjectKey, c.Project, logging.CodesetKey, c.Name, "ref", ref, logging.ErrorKey, err)
+					}
+					continue
+				}
+				if err := p.workflowBackend.CreateWorkflowRun(ctx, wf, c, ref, revision); err != nil {
+					p.logger.Warnw("Failed to run the workflow assigned to the codeset", logging.WorkflowKey, wf,
+						logging.ProjectKey, c.Project, logging.CodesetKey, c.Name, "ref", ref, logging.ErrorKey, err)
+				}
+			}
+		}
+	}
+}
+
+// changedRefs returns the sorted names of the refs that were created or that point to a different commit
+func changedRefs(old, new map[string]string) []string {
+	changed := []string{}
+	for ref, commit := range new {
+		if old[ref] != commit {
+			changed = append(changed, ref)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestPollCodesets(t *testing.T) {
+	tracked := func(heads map[string]string, polled time.Time) *domain.Codeset {
+		return &domain.Codeset{Project: "prj", Name: "cs", URL: "https://git.test/cs", Source: &domain.CodesetSource{
+			URL: "https://git.test/cs", Mode: domain.CodesetSourceTrack, Interval: time.Minute, Heads: heads, Polled: polled}}
+	}
+	stale := time.Now().Add(-2 * time.Minute)
+	branches := []*domain.CodesetRef{{Name: "dev", Commit: "b"}, {Name: "main", Commit: "a"}}
+	tags := []*domain.CodesetRef{{Name: "v1", Commit: "t"}}
+
+	tests := []struct {
+		name     string
+		codeset  *domain.Codeset
+		refs     *domain.CodesetRefFilter
+		getErr   error
+		quota    *domain.ProjectQuota
+		wantRuns []string
+		polled   bool
+	}{
+		{
+			name:     "new commits",
+			codeset:  tracked(map[string]string{"refs/heads/main": "0", "refs/heads/dev": "b"}, stale),
+			wantRuns: []string{"refs/heads/main@a", "refs/tags/v1@v1"},
+			polled:   true,
+		},
+		{
+			name:     "ref filter",
+			codeset:  tracked(map[string]string{}, stale),
+			refs:     &domain.CodesetRefFilter{Branches: []string{"d*"}},
+			wantRuns: []string{"refs/heads/dev@b"},
+			polled:   true,
+		},
+		{
+			name:     "run quota",
+			codeset:  tracked(map[string]string{"refs/heads/main": "0", "refs/heads/dev": "b"}, stale),
+			quota:    &domain.ProjectQuota{Project: "prj", MaxWorkspaceSize: "1Gi"},
+			wantRuns: []string{"refs/heads/main@a (queued)", "refs/tags/v1@v1 (queued)"},
+			polled:   true,
+		},
+		{
+			name:    "no new commits",
+			codeset: tracked(map[string]string{"refs/heads/main": "a", "refs/heads/dev": "b", "refs/tags/v1": "t"}, stale),
+			polled:  true,
+		},
+		{
+			name:    "not due",
+			codeset: tracked(map[string]string{}, time.Now()),
+		},
+		{
+			name: "archived",
+			codeset: func() *domain.Codeset {
+				c := tracked(map[string]string{}, stale)
+				c.Archived = true
+				return c
+			}(),
+		},
+		{
+			name:    "repository not reachable",
+			codeset: tracked(map[string]string{}, stale),
+			getErr:  errors.New("unreachable"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := fakeExternalCodesetStore{}
+			store.AddCodeset(ctx, tt.codeset)
+			polled := tt.codeset.Source.Polled
+
+			wfStore := core.NewWorkflowStore()
+			backend := &revisionsWorkflowBackend{fakeWorkflowBackend: &fakeWorkflowBackend{t, make(map[string]*fakeStorableWorkflow)}}
+			wf := &domain.Workflow{Name: "wf"}
+			wfStore.AddWorkflow(ctx, wf)
+			backend.CreateWorkflow(ctx, wf)
+			wfStore.AddCodesetAssignment(ctx, wf.Name, tt.codeset, nil, tt.refs)
+
+			provider := &fakeCodesetProvider{branches: branches, tags: tags, err: tt.getErr}
+			quotas := NewQuotaManager(fakeQuotaStore{}, &fakeCodesetStore{t, make(map[codesetID]fakeStorableCodeset)}, wfStore,
+				backend, config.TektonConfig{WorkspaceSize: "2Gi"})
+			if tt.quota != nil {
+				quotas.SetQuota(ctx, tt.quota)
+			}
+			p := NewCodesetPoller(zap.NewNop().Sugar(), store, fakeCredentialsStore{}, provider, wfStore, backend, quotas)
+			p.Poll(ctx)
+
+			if d := cmp.Diff(tt.wantRuns, backend.runs); d != "" {
+				t.Errorf("Unexpected workflow runs: %s", diff.PrintWantGot(d))
+			}
+
+			got, _ := store.GetCodeset(ctx, "prj", "cs")
+			if got.Source.Polled.After(polled) != tt.polled {
+				t.Errorf("Unexpected polled time %v, previously %v", got.Source.Polled, polled)
+			}
+			if tt.polled {
+				if d := cmp.Diff(domain.RefHeads(branches, tags), got.Source.Heads); d != "" {
+					t.Errorf("Unexpected codeset heads: %s", diff.PrintWantGot(d))
+				}
+			}
+			if (tt.polled || tt.getErr != nil) && provider.username != "user" {
+				t.Errorf("Expected the repository to be accessed with the stored credentials")
+			}
+		})
+	}
+}
+
+// revisionsWorkflowBackend records the ref and revision of the workflow runs, as ref@revision, followed by
+// " (queued)" for the queued runs
+type revisionsWorkflowBackend struct {
+	*fakeWorkflowBackend
+	runs []string
+}
+
+func (b *revisionsWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset, ref, revision string) error {
+	b.runs = append(b.runs, ref+"@"+revision)
+	return b.fakeWorkflowBackend.CreateWorkflowRun(ctx, workflowName, codeset, ref, revision)
+}
+
+func (b *revisionsWorkflowBackend) QueueWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset, ref, revision string) error {
+	b.runs = append(b.runs, ref+"@"+revision+" (queued)")
+	return b.fakeWorkflowBackend.QueueWorkflowRun(ctx, workflowName, codeset, ref, revision)
+}
+
+type fakeExternalCodesetStore map[string]*domain.Codeset
+
+func (s fakeExternalCodesetStore) AddCodeset(ctx context.Context, c *domain.Codeset) error {
+	s[c.Project+"/"+c.Name] = c
+	return nil
+}
+
+func (s fakeExternalCodesetStore) GetCodeset(ctx context.Context, project, name string) (*domain.Codeset, error) {
+	if c, ok := s[project+"/"+name]; ok {
+		return c, nil
+	}
+	return nil, domain.ErrCodesetNotFound
+}
+
+func (s fakeExternalCodesetStore) GetCodesets(ctx context.Context, project *string) ([]*domain.Codeset, error) {
+	result := []*domain.Codeset{}
+	for _, c := range s {
+		if project == nil || c.Project == *project {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (s fakeExternalCodesetStore) UpdateCodeset(ctx context.Context, c *domain.Codeset) error {
+	return s.AddCodeset(ctx, c)
+}
+
+func (s fakeExternalCodesetStore) UpdateCodesetHeads(ctx context.Context, project, name string, heads map[string]string, polled time.Time) error {
+	c, ok := s[project+"/"+name]
+	if !ok {
+		return domain.ErrCodesetNotFound
+	}
+	c.Source.Heads, c.Source.Polled = heads, polled
+	return nil
+}
+
+func (s fakeExternalCodesetStore) DeleteCodeset(ctx context.Context, project, name string) error {
+	delete(s, project+"/"+name)
+	return nil
+}
+
+type fakeCredentialsStore struct{}
+
+func (fakeCredentialsStore) SetCodesetCredentials(ctx context.Context, c *domain.Codeset, username, password string) error {
+	return nil
+}
+
+func (fakeCredentialsStore) GetCodesetCredentials(ctx context.Context, c *domain.Codeset) (string, string, error) {
+	return "user", "pass", nil
+}
+
+func (fakeCredentialsStore) DeleteCodesetCredentials(ctx context.Context, c *domain.Codeset) error {
+	return nil
+}
+
+type fakeCodesetProvider struct {
+	branches []*domain.CodesetRef
+	tags     []*domain.CodesetRef
+	err      error
+	// the username the repository was last accessed with
+	username string
+}
+
+func (p *fakeCodesetProvider) GetRefs(ctx context.Context, source *domain.CodesetSource) ([]*domain.CodesetRef, []*domain.CodesetRef, error) {
+	p.username = source.Username
+	return p.branches, p.tags, p.err
+}
+
+func (p *fakeCodesetProvider) GetDefaultBranch(ctx context.Context, source *domain.CodesetSource) (string, error) {
+	return "main", p.err
+}
+
+func (p *fakeCodesetProvider) GetCommits(ctx context.Context, source *domain.CodesetSource, ref string, limit int) ([]*domain.CodesetCommit, error) {
+	return []*domain.CodesetCommit{}, p.err
+}
+
+func (p *fakeCodesetProvider) GetFiles(ctx context.Context, source *domain.CodesetSource, ref, path string) ([]*domain.CodesetFile, error) {
+	return []*domain.CodesetFile{}, p.err
+}
+
+func (p *fakeCodesetProvider) GetFile(ctx context.Context, source *domain.CodesetSource, ref, path string) ([]byte, error) {
+	return nil, domain.ErrCodesetFileNotFound
+}
@@ -60,12 +60,31 @@ func (registry *ExtensionRegistry) AddCredentials(
 	return registry.extensionStore.StoreCredentials(ctx, credentials)
 }
 
-// ListExtensions - list all registered extensions that match the supplied query parameters
-func (registry *ExtensionRegistry) ListExtensions(ctx context.Context, query *domain.ExtensionQuery) (result []*domain.ExtensionRecord, err error) {
+// ListExtensions - list a page of the registered extensions that match the supplied query parameters
+func (registry *ExtensionRegistry) ListExtensions(ctx context.Context, query *domain.ExtensionQuery,
+	opts *domain.ListOptions) (result []*domain.ExtensionRecord, next string, err error) {
 	if query == nil {
-		return registry.extensionStore.GetAllExtensions(ctx)
+		result, err = registry.extensionStore.GetAllExtensions(ctx)
+	} else {
+		result, err = registry.extensionStore.RunExtensionQuery(ctx, query)
 	}
-	return registry.extensionStore.RunExtensionQuery(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = opts.SortItems(result, "id", domain.SortKeys{
+		"id":         func(i int) string { return result[i].ID },
+		"product":    func(i int) string { return result[i].Product + "/" + result[i].ID },
+		"registered": func(i int) string { return domain.TimeSortKey(result[i].Registered) + "/" + result[i].ID },
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(result))
+	if err != nil {
+		return nil, "", err
+	}
+	return result[start:end], next, nil
 }
 
 // GetExtension - retrieve an extension by ID and, optionally, its entire service/endpoint/credentials subtree
@@ -0,0 +1,175 @@
+package manager
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+// ProjectManager manages the lifecycle of the projects and of the resources created for them
+type ProjectManager struct {
+	logger           *zap.SugaredLogger
+	projectStore     domain.ProjectStore
+	codesetStore     domain.CodesetStore
+	workflowManager  domain.WorkflowManager
+	workflowBackend  domain.WorkflowBackend
+	applicationStore domain.ApplicationStore
+	cluster          domain.KubernetesResourceRemover
+	members          domain.ProjectMemberStore
+	quotas           domain.ProjectQuotaManager
+	namespace        string
+}
+
+// NewProjectManager initializes a Project Manager
+func NewProjectManager(
+	logger *zap.SugaredLogger,
+	projectStore domain.ProjectStore,
+	codesetStore domain.CodesetStore,
+	workflowManager domain.WorkflowManager,
+	workflowBackend domain.WorkflowBackend,
+	applicationStore domain.ApplicationStore,
+	cluster domain.KubernetesResourceRemover,
+	members domain.ProjectMemberStore,
+	quotas domain.ProjectQuotaManager,
+	cfg config.TektonConfig) *ProjectManager {
+	return &ProjectManager{logger, projectStore, codesetStore, workflowManager, workflowBackend, applicationStore,
+		cluster, members, quotas, cfg.Namespace}
+}
+
+// DeleteProject deletes a project along with its codesets, the workflow assignments to them, the workflow runs
+// and applications created for them, the project members and quota and the git users of the project. With
+// dryRun, the resources that would be deleted are only listed.
+func (mgr *ProjectManager) DeleteProject(ctx context.Context, name string, dryRun bool) (res *domain.ProjectResources, err error) {
+	ctx, span := tracing.Start(ctx, "ProjectManager.DeleteProject", tracing.ProjectKey.String(name))
+	defer tracing.End(span, &err)
+
+	project, err := mgr.projectStore.Find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	res, apps, err := mgr.projectResources(ctx, project)
+	if err != nil || dryRun {
+		return res, err
+	}
+
+	log := logging.FromContext(ctx, mgr.logger).With(logging.ProjectKey, name)
+	for _, a := range res.Assignments {
+		if err := mgr.workflowManager.UnassignFromCodeset(ctx, a.Workflow, name, a.Codeset); err != nil {
+			return nil, errors.Wrapf(err, "failed unassigning workflow %s from codeset %s", a.Workflow, a.Codeset)
+		}
+	}
+	for _, run := range res.WorkflowRuns {
+		if err := mgr.workflowBackend.DeleteWorkflowRun(ctx, run); err != nil {
+			return nil, err
+		}
+	}
+	for _, app := range apps {
+		// the resources of the applications registered before they were restricted to the workloads
+		// namespace are not deleted
+		if app.K8sNamespace != mgr.namespace {
+			log.Warnw("Not deleting the application resources outside of the workloads namespace",
+				"application", app.Name, "namespace", app.K8sNamespace)
+		} else {
+			for _, r := range app.K8sResources {
+				if err := mgr.cluster.DeleteResource(ctx, r.Name, app.K8sNamespace, r.Kind); err != nil {
+					return nil, errors.Wrap(err, "failed deleting kubernetes resource "+r.Name)
+				}
+			}
+		}
+		if err := mgr.applicationStore.Delete(ctx, app.Name); err != nil {
+			return nil, err
+		}
+	}
+	for _, codeset := range res.Codesets {
+		if err := mgr.codesetStore.Delete(ctx, name, codeset); err != nil {
+			return nil, errors.Wrap(err, "failed deleting codeset "+codeset)
+		}
+	}
+	// the git users of the project are deleted along with it
+	if err := mgr.projectStore.Delete(ctx, name); err != nil {
+		return nil, err
+	}
+	if err := mgr.quotas.RemoveQuota(ctx, name); err != nil {
+		return nil, err
+	}
+	if err := mgr.members.RemoveProject(ctx, name); err != nil {
+		return nil, err
+	}
+	log.Infow("Deleted project", "codesets", len(res.Codesets), "assignments", len(res.Assignments),
+		"runs", len(res.WorkflowRuns), "applications", len(apps))
+	return res, nil
+}
+
+// projectResources lists the resources of a project, along with the applications created from its codesets
+func (mgr *ProjectManager) projectResources(ctx context.Context, project *domain.Project) (*domain.ProjectResources, []*domain.Application, error) {
+	res := &domain.ProjectResources{
+		Codesets:     []string{},
+		Assignments:  []*domain.ProjectAssignment{},
+		WorkflowRuns: []string{},
+		Applications: []string{},
+		Members:      []string{},
+		Users:        []string{},
+	}
+
+	codesets, _, err := mgr.codesetStore.GetAll(ctx, &project.Name, nil, true, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range codesets {
+		res.Codesets = append(res.Codesets, c.Name)
+	}
+	sort.Strings(res.Codesets)
+
+	for wf, assignments := range mgr.workflowManager.GetAllCodesetAssignments(ctx, nil) {
+		for _, a := range assignments {
+			if a.Codeset.Project == project.Name {
+				res.Assignments = append(res.Assignments, &domain.ProjectAssignment{Workflow: wf, Codeset: a.Codeset.Name})
+			}
+		}
+	}
+	sort.Slice(res.Assignments, func(i, j int) bool {
+		if res.Assignments[i].Workflow != res.Assignments[j].Workflow {
+			return res.Assignments[i].Workflow < res.Assignments[j].Workflow
+		}
+		return res.Assignments[i].Codeset < res.Assignments[j].Codeset
+	})
+
+	runs, _, err := mgr.workflowManager.GetWorkflowRuns(ctx, &domain.WorkflowRunFilter{CodesetProject: project.Name}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range runs {
+		res.WorkflowRuns = append(res.WorkflowRuns, r.Name)
+	}
+	sort.Strings(res.WorkflowRuns)
+
+	apps, _, err := mgr.applicationStore.GetAll(ctx, &domain.ApplicationFilter{CodesetProject: &project.Name}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, a := range apps {
+		res.Applications = append(res.Applications, a.Name)
+	}
+	sort.Strings(res.Applications)
+
+	members, err := mgr.members.GetMembers(ctx, project.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, m := range members {
+		res.Members = append(res.Members, m.User)
+	}
+	// only the users that belong to no other project are deleted along with the project
+	res.Users, err = mgr.projectStore.ExclusiveUsers(ctx, project.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, apps, nil
+}
@@ -0,0 +1,215 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestDeleteProject(t *testing.T) {
+	newManager := func(t *testing.T) (*ProjectManager, *fakeProjectStore, *core.ApplicationStore, fakeResourceRemover, fakeMemberStore) {
+		wfm := newFakeWorkflowManager(t)
+		ctx := context.Background()
+		// the shared user also belongs to another project, so it is not deleted along with the project
+		projects := &fakeProjectStore{
+			"csproject0": {Name: "csproject0", Users: []*domain.User{{Name: "fuseml-csproject0"}, {Name: "shared"}}},
+			"csproject1": {Name: "csproject1", Users: []*domain.User{{Name: "fuseml-csproject1"}, {Name: "shared"}}},
+		}
+		apps := core.NewApplicationStore()
+		cluster := fakeResourceRemover{}
+		members := fakeMemberStore{"csproject1": {{Project: "csproject1", User: "user", Role: domain.ProjectRoleAdmin}}}
+		quotaStore.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxCodesets: 5})
+
+		// the workflow is assigned to a codeset of the project, whose run created an application, and to a
+		// codeset of another project
+		wf, err := wfm.CreateWorkflow(ctx, &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+		for _, cs := range []struct{ project, name string }{{"csproject1", "cs1"}, {"csproject0", "cs0"}} {
+			_, _, err = wfm.AssignToCodeset(ctx, wf.Name, cs.project, cs.name, nil)
+			assertError(t, err, nil)
+		}
+		apps.Add(ctx, &domain.Application{Name: "app", K8sNamespace: "test",
+			K8sResources: []*domain.KubernetesResource{{Name: "svc", Kind: "Service"}},
+			Codeset:      &domain.ApplicationCodeset{Project: "csproject1", Name: "cs1"}})
+		// the resources of the applications outside of the workloads namespace are not deleted
+		apps.Add(ctx, &domain.Application{Name: "legacy", K8sNamespace: "kube-system",
+			K8sResources: []*domain.KubernetesResource{{Name: "coredns", Kind: "Deployment"}},
+			Codeset:      &domain.ApplicationCodeset{Project: "csproject1", Name: "cs1"}})
+		apps.Add(ctx, &domain.Application{Name: "other", Codeset: &domain.ApplicationCodeset{Project: "csproject0", Name: "cs0"}})
+
+		return NewProjectManager(zap.NewNop().Sugar(), projects, codesetStore, wfm, workflowBackend, apps, cluster,
+			members, wfm.quotas, config.TektonConfig{Namespace: "test"}), projects, apps, cluster, members
+	}
+	want := &domain.ProjectResources{
+		Codesets:     []string{"cs1", "cs2"},
+		Assignments:  []*domain.ProjectAssignment{{Workflow: "wf", Codeset: "cs1"}},
+		WorkflowRuns: []string{"wf-run0"},
+		Applications: []string{"app", "legacy"},
+		Members:      []string{"user"},
+		Users:        []string{"fuseml-csproject1"},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		mgr, projects, apps, cluster, _ := newManager(t)
+		ctx := context.Background()
+
+		got, err := mgr.DeleteProject(ctx, "csproject1", true)
+		assertError(t, err, nil)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected ProjectResources: %s", diff.PrintWantGot(d))
+		}
+		if _, ok := (*projects)["csproject1"]; !ok {
+			t.Errorf("The project was deleted")
+		}
+		if len(workflowStore.GetCodesetAssignments(ctx, "wf")) != 2 || apps.Find(ctx, "app") == nil || len(cluster) != 0 {
+			t.Errorf("The project resources were deleted")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mgr, projects, apps, cluster, members := newManager(t)
+		ctx := context.Background()
+
+		got, err := mgr.DeleteProject(ctx, "csproject1", false)
+		assertError(t, err, nil)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected ProjectResources: %s", diff.PrintWantGot(d))
+		}
+		if _, ok := (*projects)["csproject1"]; ok {
+			t.Errorf("The project was not deleted")
+		}
+		codesets, _, _ := codesetStore.GetAll(ctx, nil, nil, true, nil, nil)
+		if len(codesets) != 1 || codesets[0].Project != "csproject0" {
+			t.Errorf("Unexpected remaining codesets: %v", codesets)
+		}
+		assignments := workflowStore.GetCodesetAssignments(ctx, "wf")
+		if len(assignments) != 1 || assignments[0].Codeset.Project != "csproject0" {
+			t.Errorf("Unexpected remaining codeset assignments: %v", assignments)
+		}
+		runs, _, _ := mgr.workflowManager.GetWorkflowRuns(ctx, &domain.WorkflowRunFilter{}, nil)
+		if len(runs) != 1 || runs[0].Name != "wf-run1" {
+			t.Errorf("Unexpected remaining workflow runs: %v", runs)
+		}
+		if apps.Find(ctx, "app") != nil || apps.Find(ctx, "legacy") != nil || apps.Find(ctx, "other") == nil {
+			t.Errorf("Unexpected remaining applications")
+		}
+		if d := cmp.Diff(fakeResourceRemover{"test/Service/svc": true}, cluster); d != "" {
+			t.Errorf("Unexpected deleted kubernetes resources: %s", diff.PrintWantGot(d))
+		}
+		if len(members["csproject1"]) != 0 {
+			t.Errorf("The project members were not removed")
+		}
+		if quota, _ := quotaStore.GetQuota(ctx, "csproject1"); quota.MaxCodesets != 0 {
+			t.Errorf("The project quota was not removed")
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mgr, _, _, _, _ := newManager(t)
+
+		_, err := mgr.DeleteProject(context.Background(), "missing", true)
+		assertError(t, err, errProjectNotFound)
+	})
+}
+
+const errProjectNotFound = projectErr("project not found")
+
+type projectErr string
+
+func (e projectErr) Error() string {
+	return string(e)
+}
+
+type fakeProjectStore map[string]*domain.Project
+
+func (s *fakeProjectStore) Find(ctx context.Context, name string) (*domain.Project, error) {
+	if p, ok := (*s)[name]; ok {
+		return p, nil
+	}
+	return nil, errProjectNotFound
+}
+
+func (s *fakeProjectStore) GetAll(ctx context.Context, visible domain.ProjectVisibility, opts *domain.ListOptions) ([]*domain.Project, string, error) {
+	return nil, "", nil
+}
+
+func (s *fakeProjectStore) Delete(ctx context.Context, name string) error {
+	delete(*s, name)
+	return nil
+}
+
+func (s *fakeProjectStore) Create(ctx context.Context, name, desc string) (*domain.Project, error) {
+	(*s)[name] = &domain.Project{Name: name, Description: desc}
+	return (*s)[name], nil
+}
+
+func (s *fakeProjectStore) Update(ctx context.Context, name, desc string) (*domain.Project, error) {
+	(*s)[name].Description = desc
+	return (*s)[name], nil
+}
+
+func (s *fakeProjectStore) ExclusiveUsers(ctx context.Context, name string) ([]string, error) {
+	var users []string
+	for _, u := range (*s)[name].Users {
+		exclusive := true
+		for _, p := range *s {
+			for _, other := range p.Users {
+				if p.Name != name && other.Name == u.Name {
+					exclusive = false
+				}
+			}
+		}
+		if exclusive {
+			users = append(users, u.Name)
+		}
+	}
+	return users, nil
+}
+
+// fakeResourceRemover records the deleted kubernetes resources, as namespace/kind/name
+type fakeResourceRemover map[string]bool
+
+func (r fakeResourceRemover) DeleteResource(ctx context.Context, name, namespace, kind string) error {
+	r[namespace+"/"+kind+"/"+name] = true
+	return nil
+}
+
+type fakeMemberStore map[string][]*domain.ProjectMember
+
+func (s fakeMemberStore) GetMember(ctx context.Context, project, user string) (*domain.ProjectMember, error) {
+	for _, m := range s[project] {
+		if m.User == user {
+			return m, nil
+		}
+	}
+	return nil, domain.ErrProjectMemberNotFound
+}
+
+func (s fakeMemberStore) GetMembers(ctx context.Context, project string) ([]*domain.ProjectMember, error) {
+	return s[project], nil
+}
+
+func (s fakeMemberStore) GetMemberships(ctx context.Context, user string) ([]*domain.ProjectMember, error) {
+	return nil, nil
+}
+
+func (s fakeMemberStore) SetMember(ctx context.Context, member *domain.ProjectMember) (*domain.ProjectMember, error) {
+	s[member.Project] = append(s[member.Project], member)
+	return member, nil
+}
+
+func (s fakeMemberStore) RemoveMember(ctx context.Context, project, user string) error {
+	return nil
+}
+
+func (s fakeMemberStore) RemoveProject(ctx context.Context, project string) error {
+	delete(s, project)
+	return nil
+}
@@ -0,0 +1,164 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// activeRunStatus lists the status of the workflow runs that are in progress, including the ones that were just
+// created and have no status yet
+var activeRunStatus = []string{"Unknown", "Pending", "Started", "Running"}
+
+// QuotaManager manages the quotas of the projects and computes the resources they consume
+type QuotaManager struct {
+	store           domain.ProjectQuotaStore
+	codesetStore    domain.CodesetStore
+	workflowStore   domain.WorkflowStore
+	workflowBackend domain.WorkflowBackend
+	// workspaceSize is the size of the workspace volume created for each workflow run
+	workspaceSize string
+}
+
+// NewQuotaManager initializes a Quota Manager
+func NewQuotaManager(
+	store domain.ProjectQuotaStore,
+	codesetStore domain.CodesetStore,
+	workflowStore domain.WorkflowStore,
+	workflowBackend domain.WorkflowBackend,
+	cfg config.TektonConfig) *QuotaManager {
+	return &QuotaManager{store, codesetStore, workflowStore, workflowBackend, cfg.WorkspaceSize}
+}
+
+// GetQuota returns the quota of a project
+func (mgr *QuotaManager) GetQuota(ctx context.Context, project string) (*domain.ProjectQuota, error) {
+	return mgr.store.GetQuota(ctx, project)
+}
+
+// SetQuota validates and sets the quota of a project
+func (mgr *QuotaManager) SetQuota(ctx context.Context, quota *domain.ProjectQuota) (*domain.ProjectQuota, error) {
+	if quota.MaxCodesets < 0 || quota.MaxConcurrentRuns < 0 {
+		return nil, fmt.Errorf("%w: the limits cannot be negative", domain.ErrInvalidProjectQuota)
+	}
+	if quota.MaxWorkspaceSize != "" {
+		size, err := resource.ParseQuantity(quota.MaxWorkspaceSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid workspace size %q: %s", domain.ErrInvalidProjectQuota, quota.MaxWorkspaceSize, err)
+		}
+		if size.Sign() < 0 {
+			return nil, fmt.Errorf("%w: the limits cannot be negative", domain.ErrInvalidProjectQuota)
+		}
+	}
+	return mgr.store.SetQuota(ctx, quota)
+}
+
+// RemoveQuota removes the quota of a project
+func (mgr *QuotaManager) RemoveQuota(ctx context.Context, project string) error {
+	return mgr.store.RemoveQuota(ctx, project)
+}
+
+// GetUsage returns the resources currently consumed by a project
+func (mgr *QuotaManager) GetUsage(ctx context.Context, project string) (*domain.ProjectUsage, error) {
+	codesets, err := mgr.countCodesets(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := mgr.countActiveRuns(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	size, err := mgr.workspaceUsage(runs)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ProjectUsage{Codesets: codesets, ConcurrentRuns: runs, WorkspaceSize: size.String()}, nil
+}
+
+// CheckCodesetQuota returns ErrProjectQuotaExceeded if a new codeset cannot be registered in the project
+func (mgr *QuotaManager) CheckCodesetQuota(ctx context.Context, project string) error {
+	quota, err := mgr.store.GetQuota(ctx, project)
+	if err != nil || quota.MaxCodesets == 0 {
+		return err
+	}
+	codesets, err := mgr.countCodesets(ctx, project)
+	if err != nil {
+		return err
+	}
+	if codesets >= quota.MaxCodesets {
+		return fmt.Errorf("%w: project %q already has %d codesets, the maximum allowed is %d",
+			domain.ErrProjectQuotaExceeded, project, codesets, quota.MaxCodesets)
+	}
+	return nil
+}
+
+// CheckRunQuota returns ErrProjectQuotaExceeded if a new workflow run cannot be started for a codeset of the project
+func (mgr *QuotaManager) CheckRunQuota(ctx context.Context, project string) error {
+	quota, err := mgr.store.GetQuota(ctx, project)
+	if err != nil || (quota.MaxConcurrentRuns == 0 && quota.MaxWorkspaceSize == "") {
+		return err
+	}
+	runs, err := mgr.countActiveRuns(ctx, project)
+	if err != nil {
+		return err
+	}
+	if quota.MaxConcurrentRuns != 0 && runs >= quota.MaxConcurrentRuns {
+		return fmt.Errorf("%w: project %q already has %d workflow runs in progress, the maximum allowed is %d",
+			domain.ErrProjectQuotaExceeded, project, runs, quota.MaxConcurrentRuns)
+	}
+	if quota.MaxWorkspaceSize != "" {
+		maxSize, err := resource.ParseQuantity(quota.MaxWorkspaceSize)
+		if err != nil {
+			return err
+		}
+		// the workspace of the new run is included
+		size, err := mgr.workspaceUsage(runs + 1)
+		if err != nil {
+			return err
+		}
+		if size.Cmp(maxSize) > 0 {
+			return fmt.Errorf("%w: the workspaces of the workflow runs of project %q would use %s, the maximum allowed is %s",
+				domain.ErrProjectQuotaExceeded, project, size.String(), maxSize.String())
+		}
+	}
+	return nil
+}
+
+// countCodesets returns the number of codesets registered in a project, including the archived ones
+func (mgr *QuotaManager) countCodesets(ctx context.Context, project string) (int, error) {
+	codesets, _, err := mgr.codesetStore.GetAll(ctx, &project, nil, true, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(codesets), nil
+}
+
+// countActiveRuns returns the number of workflow runs in progress for the codesets of a project
+func (mgr *QuotaManager) countActiveRuns(ctx context.Context, project string) (int, error) {
+	workflows, _, err := mgr.workflowStore.GetWorkflows(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	filter := &domain.WorkflowRunFilter{CodesetProject: project, Status: activeRunStatus}
+	count := 0
+	for _, wf := range workflows {
+		runs, _, err := mgr.workflowBackend.GetWorkflowRuns(ctx, wf, filter, nil)
+		if err != nil {
+			return 0, err
+		}
+		count += len(runs)
+	}
+	return count, nil
+}
+
+// workspaceUsage returns the total size of the workspace volumes used by the given number of workflow runs
+func (mgr *QuotaManager) workspaceUsage(runs int) (*resource.Quantity, error) {
+	size, err := resource.ParseQuantity(mgr.workspaceSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace size %q: %w", mgr.workspaceSize, err)
+	}
+	return resource.NewQuantity(size.Value()*int64(runs), size.Format), nil
+}
@@ -0,0 +1,115 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestSetQuota(t *testing.T) {
+	newFakeWorkflowManager(t)
+	quotas := newFakeQuotaManager()
+	ctx := context.Background()
+
+	for _, quota := range []*domain.ProjectQuota{
+		{Project: "prj", MaxCodesets: -1},
+		{Project: "prj", MaxConcurrentRuns: -1},
+		{Project: "prj", MaxWorkspaceSize: "ten"},
+		{Project: "prj", MaxWorkspaceSize: "-1Gi"},
+	} {
+		if _, err := quotas.SetQuota(ctx, quota); !errors.Is(err, domain.ErrInvalidProjectQuota) {
+			t.Errorf("Expected quota %v to be rejected, got %v", quota, err)
+		}
+	}
+
+	quota := &domain.ProjectQuota{Project: "prj", MaxCodesets: 1, MaxConcurrentRuns: 2, MaxWorkspaceSize: "10Gi"}
+	_, err := quotas.SetQuota(ctx, quota)
+	assertError(t, err, nil)
+	got, err := quotas.GetQuota(ctx, "prj")
+	assertError(t, err, nil)
+	if d := cmp.Diff(quota, got); d != "" {
+		t.Errorf("Unexpected ProjectQuota: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestCheckQuotas(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	quotas := mgr.quotas
+	ctx := context.Background()
+
+	// csproject1 has two codesets
+	assertError(t, quotas.CheckCodesetQuota(ctx, "csproject1"), nil)
+	quotas.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxCodesets: 2})
+	if err := quotas.CheckCodesetQuota(ctx, "csproject1"); !errors.Is(err, domain.ErrProjectQuotaExceeded) {
+		t.Errorf("Expected the codeset quota to be exceeded, got %v", err)
+	}
+	assertError(t, quotas.CheckCodesetQuota(ctx, "csproject0"), nil)
+
+	// assigning the workflow starts a run, which is kept in progress
+	wf, err := mgr.CreateWorkflow(ctx, &domain.Workflow{Name: "wf"})
+	assertError(t, err, nil)
+	quotas.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxConcurrentRuns: 1})
+	_, _, err = mgr.AssignToCodeset(ctx, wf.Name, "csproject1", "cs1", nil)
+	assertError(t, err, nil)
+	workflowBackend.(*fakeWorkflowBackend).workflows[wf.Name].runs[0].Status = "Running"
+
+	_, _, err = mgr.AssignToCodeset(ctx, wf.Name, "csproject1", "cs2", nil)
+	if !errors.Is(err, domain.ErrProjectQuotaExceeded) {
+		t.Errorf("Expected the run quota to be exceeded, got %v", err)
+	}
+	// reassigning the workflow does not start a run
+	_, _, err = mgr.AssignToCodeset(ctx, wf.Name, "csproject1", "cs1", nil)
+	assertError(t, err, nil)
+	// the workspace of each run uses 2Gi
+	quotas.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxWorkspaceSize: "3Gi"})
+	if err := quotas.CheckRunQuota(ctx, "csproject1"); !errors.Is(err, domain.ErrProjectQuotaExceeded) {
+		t.Errorf("Expected the workspace quota to be exceeded, got %v", err)
+	}
+	quotas.SetQuota(ctx, &domain.ProjectQuota{Project: "csproject1", MaxWorkspaceSize: "4Gi"})
+	assertError(t, quotas.CheckRunQuota(ctx, "csproject1"), nil)
+	assertError(t, quotas.CheckRunQuota(ctx, "csproject0"), nil)
+
+	usage, err := quotas.GetUsage(ctx, "csproject1")
+	assertError(t, err, nil)
+	want := &domain.ProjectUsage{Codesets: 2, ConcurrentRuns: 1, WorkspaceSize: "2Gi"}
+	if d := cmp.Diff(want, usage); d != "" {
+		t.Errorf("Unexpected ProjectUsage: %s", diff.PrintWantGot(d))
+	}
+
+	// the runs that were just created, with no status yet, are in progress too
+	workflowBackend.(*fakeWorkflowBackend).workflows[wf.Name].runs[0].Status = "Unknown"
+	usage, err = quotas.GetUsage(ctx, "csproject1")
+	assertError(t, err, nil)
+	if d := cmp.Diff(want, usage); d != "" {
+		t.Errorf("Unexpected ProjectUsage: %s", diff.PrintWantGot(d))
+	}
+}
+
+func newFakeQuotaManager() *QuotaManager {
+	return NewQuotaManager(quotaStore, codesetStore, workflowStore, workflowBackend, config.TektonConfig{WorkspaceSize: "2Gi"})
+}
+
+type fakeQuotaStore map[string]*domain.ProjectQuota
+
+func (s fakeQuotaStore) GetQuota(ctx context.Context, project string) (*domain.ProjectQuota, error) {
+	if quota, ok := s[project]; ok {
+		return quota, nil
+	}
+	return &domain.ProjectQuota{Project: project}, nil
+}
+
+func (s fakeQuotaStore) SetQuota(ctx context.Context, quota *domain.ProjectQuota) (*domain.ProjectQuota, error) {
+	s[quota.Project] = quota
+	return quota, nil
+}
+
+func (s fakeQuotaStore) RemoveQuota(ctx context.Context, project string) error {
+	delete(s, project)
+	return nil
+}
@@ -0,0 +1,163 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+const (
+	// runnableBuildPollInterval is the interval at which FuseML checks the status of a runnable build
+	runnableBuildPollInterval = 10 * time.Second
+	// runnableBuildTimeout is the time that FuseML waits for a runnable build to complete
+	runnableBuildTimeout = 1 * time.Hour
+	// runnableImageRepository is the built-in registry repository where runnable images are pushed
+	runnableImageRepository = "runnables"
+)
+
+// RunnableManager implements the domain.RunnableManager interface
+type RunnableManager struct {
+	logger          *zap.SugaredLogger
+	runnableBuilder domain.RunnableBuilder
+	runnableStore   domain.RunnableStore
+	codesetStore    domain.CodesetStore
+	pollInterval    time.Duration
+}
+
+// NewRunnableManager initializes a Runnable Manager
+func NewRunnableManager(
+	logger *zap.SugaredLogger,
+	runnableBuilder domain.RunnableBuilder,
+	runnableStore domain.RunnableStore,
+	codesetStore domain.CodesetStore) *RunnableManager {
+	return &RunnableManager{logger, runnableBuilder, runnableStore, codesetStore, runnableBuildPollInterval}
+}
+
+// BuildRunnable starts building the container image for a runnable. The build runs in the background and,
+// when it succeeds, the runnable is registered (using the supplied descriptor) or its container image is
+// updated to point to the image stored in the built-in registry.
+func (mgr *RunnableManager) BuildRunnable(ctx context.Context, build *domain.RunnableBuild, runnable *domain.Runnable) (_ *domain.RunnableBuild, err error) {
+	ctx, span := tracing.Start(ctx, "RunnableManager.BuildRunnable", tracing.RunnableKey.String(build.RunnableID))
+	defer tracing.End(span, &err)
+
+	if build.Codeset != nil {
+		codeset, err := mgr.codesetStore.Find(ctx, build.Codeset.Project, build.Codeset.Name)
+		if err != nil {
+			return nil, err
+		}
+		build.Codeset = codeset
+		build.SourceURL = codeset.URL
+		if build.Dockerfile != "" {
+			_, err := mgr.codesetStore.GetFile(ctx, codeset.Project, codeset.Name, build.Revision, build.Dockerfile)
+			if errors.Is(err, domain.ErrCodesetFileNotFound) || errors.Is(err, domain.ErrCodesetNotAFile) {
+				return nil, domain.ErrRunnableBuildDockerfileMissing
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if build.SourceURL == "" {
+		return nil, domain.ErrRunnableBuildSourceMissing
+	}
+
+	existing, err := mgr.runnableStore.Get(ctx, build.RunnableID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil && runnable == nil {
+		return nil, domain.ErrRunnableNotFound
+	}
+	if existing != nil && !build.Replace {
+		return nil, domain.ErrRunnableBuildReplace
+	}
+
+	if build.Image == "" {
+		build.Image = fmt.Sprintf("%s/%s:latest", runnableImageRepository, build.RunnableID)
+	}
+
+	res, err := mgr.runnableBuilder.CreateRunnableBuild(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+
+	// the build is watched after the request is served, keep logging with the request ID
+	log := logging.FromContext(ctx, mgr.logger).With("runnable", res.RunnableID, logging.RunKey, res.Name)
+	log.Info("Started runnable build")
+	go mgr.waitForBuild(log, res, runnable)
+	return res, nil
+}
+
+// waitForBuild waits for a runnable build to complete and registers or updates the runnable if it succeeds
+func (mgr *RunnableManager) waitForBuild(log *zap.SugaredLogger, build *domain.RunnableBuild, runnable *domain.Runnable) {
+	ctx, cancel := context.WithTimeout(context.Background(), runnableBuildTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(mgr.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Warn("Timed out waiting for runnable build to complete")
+			return
+		case <-ticker.C:
+			b, err := mgr.runnableBuilder.GetRunnableBuild(ctx, build.Name)
+			if err != nil {
+				log.Errorw("Failed to get status of runnable build", logging.ErrorKey, err)
+				continue
+			}
+			if b.CompletionTime.IsZero() {
+				continue
+			}
+			if b.Status != "Succeeded" && b.Status != "Completed" {
+				log.Warnw("Runnable build failed", "status", b.Status)
+				return
+			}
+			// the builder may not report everything that was requested, keep the original values
+			b.RunnableID, b.Image, b.SourceURL, b.Replace = build.RunnableID, build.Image, build.SourceURL, build.Replace
+			if err := mgr.updateRunnableImage(ctx, b, runnable); err != nil {
+				log.Errorw("Failed to update runnable with the built image", logging.ErrorKey, err)
+				return
+			}
+			log.Infow("Updated runnable with the built image", "image", b.Image)
+			return
+		}
+	}
+}
+
+// updateRunnableImage registers the runnable or updates an existing one with the image resulting from a build
+func (mgr *RunnableManager) updateRunnableImage(ctx context.Context, build *domain.RunnableBuild, runnable *domain.Runnable) (err error) {
+	existing, err := mgr.runnableStore.Get(ctx, build.RunnableID)
+	if err != nil {
+		return err
+	}
+	// the runnable may have been registered while it was being built
+	if existing != nil && !build.Replace {
+		return domain.ErrRunnableBuildReplace
+	}
+
+	r := runnable
+	if r == nil {
+		if existing == nil {
+			return domain.ErrRunnableNotFound
+		}
+		r = existing
+	}
+	r.Container.Image = build.Image
+	r.Container.LocalImage = true
+	r.Source = build.SourceURL
+
+	if existing == nil {
+		_, err = mgr.runnableStore.Register(ctx, r)
+	} else {
+		_, err = mgr.runnableStore.Update(ctx, r)
+	}
+	return
+}
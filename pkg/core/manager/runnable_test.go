@@ -0,0 +1,170 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestBuildRunnable(t *testing.T) {
+	t.Run("register new runnable from codeset", func(t *testing.T) {
+		mgr, builder, store := newFakeRunnableManager(t, "Succeeded")
+		build := &domain.RunnableBuild{RunnableID: "trainer", Codeset: &domain.Codeset{Project: "csproject0", Name: "cs0"},
+			Revision: "main", Dockerfile: "Dockerfile"}
+		codesetStore.addFile(build.Codeset, "Dockerfile", "FROM python:3.8")
+		runnable := &domain.Runnable{ID: "trainer", Kind: "trainer"}
+
+		got, err := mgr.BuildRunnable(context.Background(), build, runnable)
+		assertError(t, err, nil)
+		assertStrings(t, got.SourceURL, "http://codeset/test-project0/cs0")
+		assertStrings(t, got.Image, "runnables/trainer:latest")
+
+		r := waitForRunnableImage(t, store, "trainer")
+		assertStrings(t, r.Container.Image, "runnables/trainer:latest")
+		assertStrings(t, r.Source, "http://codeset/test-project0/cs0")
+		if !r.Container.LocalImage {
+			t.Errorf("Expected runnable image to be stored in the local registry")
+		}
+		if len(builder.builds) != 1 {
+			t.Errorf("Expected 1 build, got %d", len(builder.builds))
+		}
+	})
+
+	t.Run("update existing runnable from git URL", func(t *testing.T) {
+		mgr, _, store := newFakeRunnableManager(t, "Succeeded")
+		_, err := store.Register(context.Background(), &domain.Runnable{ID: "predictor",
+			Container: domain.RunnableContainer{Image: "docker.io/predictor:v1"}})
+		assertError(t, err, nil)
+
+		build := &domain.RunnableBuild{RunnableID: "predictor", SourceURL: "http://git/predictor.git",
+			Revision: "v2", Dockerfile: "docker/Dockerfile", Image: "runnables/predictor:v2", Replace: true}
+		_, err = mgr.BuildRunnable(context.Background(), build, nil)
+		assertError(t, err, nil)
+
+		r := waitForRunnableImage(t, store, "predictor")
+		assertStrings(t, r.Container.Image, "runnables/predictor:v2")
+	})
+
+	t.Run("failed build", func(t *testing.T) {
+		mgr, _, store := newFakeRunnableManager(t, "Failed")
+		_, err := store.Register(context.Background(), &domain.Runnable{ID: "predictor",
+			Container: domain.RunnableContainer{Image: "docker.io/predictor:v1"}})
+		assertError(t, err, nil)
+
+		build := &domain.RunnableBuild{RunnableID: "predictor", SourceURL: "http://git/predictor.git", Replace: true}
+		_, err = mgr.BuildRunnable(context.Background(), build, nil)
+		assertError(t, err, nil)
+
+		time.Sleep(50 * time.Millisecond)
+		r, _ := store.Get(context.Background(), "predictor")
+		assertStrings(t, r.Container.Image, "docker.io/predictor:v1")
+	})
+
+	t.Run("existing runnable without replace", func(t *testing.T) {
+		mgr, builder, store := newFakeRunnableManager(t, "Succeeded")
+		_, err := store.Register(context.Background(), &domain.Runnable{ID: "predictor",
+			Container: domain.RunnableContainer{Image: "docker.io/predictor:v1"}})
+		assertError(t, err, nil)
+
+		build := &domain.RunnableBuild{RunnableID: "predictor", SourceURL: "http://git/predictor.git"}
+		_, err = mgr.BuildRunnable(context.Background(), build, &domain.Runnable{ID: "predictor"})
+		assertError(t, err, domain.ErrRunnableBuildReplace)
+		if len(builder.builds) != 0 {
+			t.Errorf("Expected no build, got %d", len(builder.builds))
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		mgr, _, _ := newFakeRunnableManager(t, "Succeeded")
+		build := &domain.RunnableBuild{RunnableID: "trainer"}
+		_, err := mgr.BuildRunnable(context.Background(), build, &domain.Runnable{ID: "trainer"})
+		assertError(t, err, domain.ErrRunnableBuildSourceMissing)
+	})
+
+	t.Run("missing Dockerfile", func(t *testing.T) {
+		mgr, _, _ := newFakeRunnableManager(t, "Succeeded")
+		build := &domain.RunnableBuild{RunnableID: "trainer", Codeset: &domain.Codeset{Project: "csproject0", Name: "cs0"},
+			Revision: "main", Dockerfile: "docker/Dockerfile"}
+		_, err := mgr.BuildRunnable(context.Background(), build, &domain.Runnable{ID: "trainer"})
+		assertError(t, err, domain.ErrRunnableBuildDockerfileMissing)
+	})
+
+	t.Run("missing codeset", func(t *testing.T) {
+		mgr, _, _ := newFakeRunnableManager(t, "Succeeded")
+		build := &domain.RunnableBuild{RunnableID: "trainer", Codeset: &domain.Codeset{Project: "csproject0", Name: "missing"}}
+		_, err := mgr.BuildRunnable(context.Background(), build, &domain.Runnable{ID: "trainer"})
+		assertError(t, err, errCodesetNotFound)
+	})
+
+	t.Run("unregistered runnable without descriptor", func(t *testing.T) {
+		mgr, _, _ := newFakeRunnableManager(t, "Succeeded")
+		build := &domain.RunnableBuild{RunnableID: "trainer", SourceURL: "http://git/trainer.git"}
+		_, err := mgr.BuildRunnable(context.Background(), build, nil)
+		assertError(t, err, domain.ErrRunnableNotFound)
+	})
+}
+
+func newFakeRunnableManager(t *testing.T, status string) (*RunnableManager, *fakeRunnableBuilder, *core.RunnableStore) {
+	t.Helper()
+
+	// initializes the global codeset store with the test codesets
+	newFakeWorkflowManager(t)
+	builder := &fakeRunnableBuilder{status: status, builds: make(map[string]*domain.RunnableBuild)}
+	store := core.NewRunnableStore()
+	mgr := NewRunnableManager(zap.NewNop().Sugar(), builder, store, codesetStore)
+	mgr.pollInterval = time.Millisecond
+	return mgr, builder, store
+}
+
+func waitForRunnableImage(t *testing.T, store *core.RunnableStore, id string) *domain.Runnable {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		r, _ := store.Get(context.Background(), id)
+		if r != nil && r.Container.LocalImage {
+			return r
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for runnable %q to be updated", id)
+	return nil
+}
+
+type fakeRunnableBuilder struct {
+	sync.Mutex
+	status string
+	builds map[string]*domain.RunnableBuild
+}
+
+func (b *fakeRunnableBuilder) CreateRunnableBuild(ctx context.Context, build *domain.RunnableBuild) (*domain.RunnableBuild, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	res := *build
+	res.Name = fmt.Sprintf("build-%s-%d", build.RunnableID, len(b.builds))
+	res.Status = "Running"
+	res.StartTime = time.Now()
+	b.builds[res.Name] = &res
+	return &res, nil
+}
+
+func (b *fakeRunnableBuilder) GetRunnableBuild(ctx context.Context, name string) (*domain.RunnableBuild, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	build, exists := b.builds[name]
+	if !exists {
+		return nil, fmt.Errorf("build %q not found", name)
+	}
+	res := *build
+	res.Status = b.status
+	res.CompletionTime = time.Now()
+	return &res, nil
+}
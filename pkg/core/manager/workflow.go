@@ -2,43 +2,67 @@ package manager
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 )
 
 // createWorkflowListenerTimeout is the time (in minutes) that FuseML waits for the workflow listener
 // to be available
 const createWorkflowListenerTimeout = 1
 
+// webhookSecretLength is the number of random bytes of the secret used to sign the webhook events
+// sent for a codeset assigned to a workflow
+const webhookSecretLength = 32
+
 // WorkflowManager implements the domain.WorkflowManager interface
 type WorkflowManager struct {
+	logger            *zap.SugaredLogger
 	workflowBackend   domain.WorkflowBackend
 	workflowStore     domain.WorkflowStore
 	codesetStore      domain.CodesetStore
 	extensionRegistry domain.ExtensionRegistry
+	quotas            domain.ProjectQuotaManager
 }
 
 // NewWorkflowManager initializes a Workflow Manager
 // FIXME: instead of CodesetStore, receive a CodesetManager
 func NewWorkflowManager(
+	logger *zap.SugaredLogger,
 	workflowBackend domain.WorkflowBackend,
 	workflowStore domain.WorkflowStore,
 	codesetStore domain.CodesetStore,
-	extensionRegistry domain.ExtensionRegistry) *WorkflowManager {
-	return &WorkflowManager{workflowBackend, workflowStore, codesetStore, extensionRegistry}
+	extensionRegistry domain.ExtensionRegistry,
+	quotas domain.ProjectQuotaManager) *WorkflowManager {
+	return &WorkflowManager{logger, workflowBackend, workflowStore, codesetStore, extensionRegistry, quotas}
 }
 
-// GetWorkflows returns a list of Workflows.
-func (mgr *WorkflowManager) GetWorkflows(ctx context.Context, name *string) []*domain.Workflow {
-	return mgr.workflowStore.GetWorkflows(ctx, name)
+// log returns the logger for the operations performed while serving the request in the context
+func (mgr *WorkflowManager) log(ctx context.Context) *zap.SugaredLogger {
+	return logging.FromContext(ctx, mgr.logger)
+}
+
+// GetWorkflows returns a page of Workflows and the token used to retrieve the next page.
+func (mgr *WorkflowManager) GetWorkflows(ctx context.Context, name *string, opts *domain.ListOptions) ([]*domain.Workflow, string, error) {
+	return mgr.workflowStore.GetWorkflows(ctx, name, opts)
 }
 
 // CreateWorkflow creates a new Workflow.
-func (mgr *WorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workflow) (*domain.Workflow, error) {
+func (mgr *WorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workflow) (_ *domain.Workflow, err error) {
+	ctx, span := tracing.Start(ctx, "WorkflowManager.CreateWorkflow", tracing.WorkflowKey.String(wf.Name))
+	defer tracing.End(span, &err)
+
 	wf.Created = time.Now()
-	err := mgr.resolveExtensionReferences(ctx, wf)
+	err = mgr.resolveExtensionReferences(ctx, wf)
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +70,7 @@ func (mgr *WorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workf
 	if err != nil {
 		return nil, err
 	}
+	mgr.log(ctx).Infow("Created workflow", logging.WorkflowKey, wf.Name)
 	return mgr.workflowStore.AddWorkflow(ctx, wf)
 }
 
@@ -55,7 +80,10 @@ func (mgr *WorkflowManager) GetWorkflow(ctx context.Context, name string) (*doma
 }
 
 // DeleteWorkflow deletes a Workflow and its assignments.
-func (mgr *WorkflowManager) DeleteWorkflow(ctx context.Context, name string) error {
+func (mgr *WorkflowManager) DeleteWorkflow(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "WorkflowManager.DeleteWorkflow", tracing.WorkflowKey.String(name))
+	defer tracing.End(span, &err)
+
 	// unassign all assigned codesets, if there's any
 	codesetAssignments := mgr.workflowStore.GetCodesetAssignments(ctx, name)
 	for _, ca := range codesetAssignments {
@@ -66,7 +94,7 @@ func (mgr *WorkflowManager) DeleteWorkflow(ctx context.Context, name string) err
 	}
 
 	// delete tekton pipeline
-	err := mgr.workflowBackend.DeleteWorkflow(ctx, name)
+	err = mgr.workflowBackend.DeleteWorkflow(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -76,12 +104,23 @@ func (mgr *WorkflowManager) DeleteWorkflow(ctx context.Context, name string) err
 	if err != nil {
 		return err
 	}
+	mgr.log(ctx).Infow("Deleted workflow", logging.WorkflowKey, name)
 	return nil
 }
 
-// AssignToCodeset assigns a Workflow to a Codeset.
-func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string) (wfListener *domain.WorkflowListener, webhookID *int64, err error) {
-	_, err = mgr.workflowStore.GetWorkflow(ctx, name)
+// AssignToCodeset assigns a Workflow to a Codeset, so that the pushes to the codeset refs selected by the filter
+// trigger the Workflow.
+func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string,
+	refs *domain.CodesetRefFilter) (wfListener *domain.WorkflowListener, webhookID *int64, err error) {
+	ctx, span := tracing.Start(ctx, "WorkflowManager.AssignToCodeset", tracing.WorkflowKey.String(name),
+		tracing.ProjectKey.String(codesetProject), tracing.CodesetKey.String(codesetName))
+	defer tracing.End(span, &err)
+
+	if err = refs.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	wf, err := mgr.workflowStore.GetWorkflow(ctx, name)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,29 +130,58 @@ func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetPr
 		return nil, nil, err
 	}
 
+	if err = mgr.checkDockerfiles(ctx, wf, codeset, refs); err != nil {
+		return nil, nil, err
+	}
+
+	// a new assignment starts a workflow run, which must fit in the quota of the codeset project
+	if _, assignErr := mgr.workflowStore.GetCodesetAssignment(ctx, name, codeset); assignErr != nil {
+		if err = mgr.quotas.CheckRunQuota(ctx, codeset.Project); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	wfListener, err = mgr.workflowBackend.CreateWorkflowListener(ctx, name, createWorkflowListenerTimeout*time.Minute)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	assignment, err := mgr.workflowStore.GetCodesetAssignment(ctx, name, codeset)
-	if err == nil {
-		return wfListener, assignment.WebhookID, nil
+	// a new webhook secret is generated on every assignment, so reassigning the workflow rotates it
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = mgr.workflowBackend.SetWorkflowListenerTrigger(ctx, name, codeset, secret, refs)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	webhookID, err = mgr.codesetStore.CreateWebhook(ctx, codeset, wfListener.URL)
+	webhookID, err = mgr.codesetStore.CreateWebhook(ctx, codeset, wfListener.URL, secret, refs)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	mgr.workflowStore.AddCodesetAssignment(ctx, name, codeset, webhookID)
+	log := mgr.log(ctx).With(logging.WorkflowKey, name, logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	_, assignErr := mgr.workflowStore.GetCodesetAssignment(ctx, name, codeset)
+	mgr.workflowStore.AddCodesetAssignment(ctx, name, codeset, webhookID, refs)
+	if assignErr == nil {
+		log.Info("Updated the codeset assignment and rotated the webhook secret")
+		return
+	}
 	mgr.codesetStore.Subscribe(ctx, mgr, codeset)
-	mgr.workflowBackend.CreateWorkflowRun(ctx, name, codeset)
+	log.Info("Assigned workflow to codeset")
+	if err := mgr.workflowBackend.CreateWorkflowRun(ctx, name, codeset, "", ""); err != nil {
+		log.Warnw("Failed to create the initial workflow run", logging.ErrorKey, err)
+	}
 	return
 }
 
 // UnassignFromCodeset unassign a Workflow from a Codeset
 func (mgr *WorkflowManager) UnassignFromCodeset(ctx context.Context, name, codesetProject, codesetName string) (err error) {
+	ctx, span := tracing.Start(ctx, "WorkflowManager.UnassignFromCodeset", tracing.WorkflowKey.String(name),
+		tracing.ProjectKey.String(codesetProject), tracing.CodesetKey.String(codesetName))
+	defer tracing.End(span, &err)
+
 	codeset, err := mgr.codesetStore.Find(ctx, codesetProject, codesetName)
 	if err != nil {
 		return err
@@ -131,16 +199,29 @@ func (mgr *WorkflowManager) UnassignFromCodeset(ctx context.Context, name, codes
 		}
 	}
 
+	if err = mgr.deleteAssignment(ctx, name, codeset); err != nil {
+		return err
+	}
+	mgr.log(ctx).Infow("Unassigned workflow from codeset", logging.WorkflowKey, name,
+		logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	return
+}
+
+// deleteAssignment removes the workflow listener trigger for the codeset (or the whole listener, for the last
+// assignment of the workflow) and the assignment itself
+func (mgr *WorkflowManager) deleteAssignment(ctx context.Context, name string, codeset *domain.Codeset) (err error) {
 	if len(mgr.workflowStore.GetCodesetAssignments(ctx, name)) == 1 {
 		err = mgr.workflowBackend.DeleteWorkflowListener(ctx, name)
-		if err != nil {
-			return err
-		}
+	} else {
+		err = mgr.workflowBackend.DeleteWorkflowListenerTrigger(ctx, name, codeset)
+	}
+	if err != nil {
+		return err
 	}
 
 	mgr.workflowStore.DeleteCodesetAssignment(ctx, name, codeset)
 	mgr.codesetStore.Unsubscribe(ctx, mgr, codeset)
-	return
+	return nil
 }
 
 // GetAllCodesetAssignments lists Workflow assignments.
@@ -161,33 +242,98 @@ func (mgr *WorkflowManager) GetAssignmentStatus(ctx context.Context, name string
 	return &status
 }
 
-// GetWorkflowRuns returns a lists Workflow runs.
-func (mgr *WorkflowManager) GetWorkflowRuns(ctx context.Context, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+// GetWorkflowRuns returns a page of Workflow runs and the token used to retrieve the next page.
+func (mgr *WorkflowManager) GetWorkflowRuns(ctx context.Context, filter *domain.WorkflowRunFilter,
+	opts *domain.ListOptions) (_ []*domain.WorkflowRun, next string, err error) {
+	ctx, span := tracing.Start(ctx, "WorkflowManager.GetWorkflowRuns")
+	defer tracing.End(span, &err)
+
 	workflowRuns := []*domain.WorkflowRun{}
 	var wfName *string
 	if filter != nil {
 		wfName = filter.WorkflowName
 	}
-	workflows := mgr.workflowStore.GetWorkflows(ctx, wfName)
+	workflows, _, err := mgr.workflowStore.GetWorkflows(ctx, wfName, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// the runs of a single workflow are paged by the backend
+	if wfName != nil {
+		if len(workflows) == 0 {
+			return workflowRuns, "", nil
+		}
+		return mgr.workflowBackend.GetWorkflowRuns(ctx, workflows[0], filter, opts)
+	}
 
 	for _, workflow := range workflows {
-		runs, err := mgr.workflowBackend.GetWorkflowRuns(ctx, workflow, filter)
+		runs, _, err := mgr.workflowBackend.GetWorkflowRuns(ctx, workflow, filter, nil)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		workflowRuns = append(workflowRuns, runs...)
 	}
-
-	return workflowRuns, nil
+	if err := domain.SortWorkflowRuns(workflowRuns, opts); err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(workflowRuns))
+	if err != nil {
+		return nil, "", err
+	}
+	return workflowRuns[start:end], next, nil
 }
 
 // OnDeletingCodeset perform operations on workflows when a codeset is deleted
 func (mgr *WorkflowManager) OnDeletingCodeset(ctx context.Context, codeset *domain.Codeset) {
-	for _, wf := range mgr.GetWorkflows(ctx, nil) {
+	workflows, _, err := mgr.GetWorkflows(ctx, nil, nil)
+	if err != nil {
+		mgr.log(ctx).Errorw("Failed to list the workflows assigned to the deleted codeset", logging.ErrorKey, err)
+		return
+	}
+	for _, wf := range workflows {
 		mgr.UnassignFromCodeset(ctx, wf.Name, codeset.Project, codeset.Name)
 	}
 }
 
+// checkDockerfiles checks that the codeset contains the Dockerfiles used to build the images of the workflow
+// steps, on its default branch. Only the Dockerfiles located in the codeset, with no references to be resolved
+// in their path, are checked, and an empty codeset is not checked at all. The check is skipped when the refs
+// filter is set, since the default branch may not be one of the refs that trigger the workflow.
+func (mgr *WorkflowManager) checkDockerfiles(ctx context.Context, wf *domain.Workflow, codeset *domain.Codeset,
+	refs *domain.CodesetRefFilter) error {
+	if !refs.IsEmpty() {
+		return nil
+	}
+	for _, step := range wf.Steps {
+		codesetPath := ""
+		for _, input := range step.Inputs {
+			if input.Codeset != nil {
+				codesetPath = strings.TrimSuffix(input.Codeset.Path, "/")
+			}
+		}
+		for _, output := range step.Outputs {
+			if output.Image == nil || codesetPath == "" || strings.Contains(output.Image.Dockerfile, "{") ||
+				!strings.HasPrefix(output.Image.Dockerfile, codesetPath+"/") {
+				continue
+			}
+			path := strings.TrimPrefix(output.Image.Dockerfile, codesetPath+"/")
+			_, err := mgr.codesetStore.GetFile(ctx, codeset.Project, codeset.Name, "", path)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, domain.ErrCodesetFileNotFound) {
+				return err
+			}
+			// the code may not have been pushed yet
+			if _, err := mgr.codesetStore.GetFiles(ctx, codeset.Project, codeset.Name, "", ""); errors.Is(err, domain.ErrCodesetFileNotFound) {
+				return nil
+			}
+			return fmt.Errorf("%w: %s", domain.ErrWorkflowDockerfileNotFound, output.Image.Dockerfile)
+		}
+	}
+	return nil
+}
+
 // Resolve all the extension references in the workflow steps and update them with actual
 // extension endpoints and credentials
 func (mgr *WorkflowManager) resolveExtensionReferences(ctx context.Context, wf *domain.Workflow) error {
@@ -228,3 +374,12 @@ func (mgr *WorkflowManager) resolveExtensionReferences(ctx context.Context, wf *
 
 	return nil
 }
+
+// generateWebhookSecret returns a random secret used to sign the webhook events
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate the webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -11,13 +12,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/tektoncd/pipeline/test/diff"
+	"go.uber.org/zap"
 
 	"github.com/fuseml/fuseml-core/pkg/core"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
-const errCodesetNotFound = codesetErr("codeset not found")
+const errCodesetNotFound = domain.ErrCodesetNotFound
 
 var (
 	// workflowBackend stores WorkflowListener and WorkflowRuns for a workflow created by fakeWorkflowBackend
@@ -36,18 +38,15 @@ var (
 	// extensionRegistry stores extensions
 	extensionRegistry *ExtensionRegistry
 
+	// quotaStore stores the project quotas, without any limit unless set by the tests
+	quotaStore fakeQuotaStore
+
 	// workflowRunStatuses are the possible Status for a WorkflowRun. The status of a WorkflowRun is set
 	// accordingly to its order, cycling between the workflowRunStatuses. E.g. run0: Succeeded, run1: Failed,
 	// run2: Succeeded, ...
 	workflowRunStatuses = []string{"Succeeded", "Failed"}
 )
 
-type codesetErr string
-
-func (e codesetErr) Error() string {
-	return string(e)
-}
-
 func TestCreateWorkflow(t *testing.T) {
 	t.Run("new workflow", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
@@ -60,8 +59,8 @@ func TestCreateWorkflow(t *testing.T) {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0])
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0], "", "")
 		assertError(t, err, nil)
 	})
 
@@ -74,7 +73,7 @@ func TestCreateWorkflow(t *testing.T) {
 		_, err = mgr.CreateWorkflow(context.Background(), &wf)
 		assertError(t, err, domain.ErrWorkflowExists)
 
-		got := workflowStore.GetWorkflows(context.TODO(), nil)
+		got, _, _ := workflowStore.GetWorkflows(context.TODO(), nil, nil)
 		want := []*domain.Workflow{&wf}
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
@@ -107,8 +106,8 @@ func TestCreateWorkflow(t *testing.T) {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0])
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0], "", "")
 		assertError(t, err, nil)
 	})
 
@@ -143,7 +142,7 @@ func TestGetWorkflows(t *testing.T) {
 		want := []*domain.Workflow{}
 
 		// no workflows
-		got := mgr.GetWorkflows(context.TODO(), nil)
+		got, _, _ := mgr.GetWorkflows(context.TODO(), nil, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow list: %s", diff.PrintWantGot(d))
 		}
@@ -155,7 +154,7 @@ func TestGetWorkflows(t *testing.T) {
 			want = append(want, wf)
 		}
 
-		got = mgr.GetWorkflows(context.TODO(), nil)
+		got, _, _ = mgr.GetWorkflows(context.TODO(), nil, nil)
 		if d := cmp.Diff(want, got, cmpopts.SortSlices(func(x, y *domain.Workflow) bool { return x.Name < y.Name })); d != "" {
 			t.Errorf("Unexpected Workflow list: %s", diff.PrintWantGot(d))
 		}
@@ -167,7 +166,7 @@ func TestGetWorkflows(t *testing.T) {
 
 		// no workflows
 		wfName := "does-not-exist"
-		got := mgr.GetWorkflows(context.TODO(), &wfName)
+		got, _, _ := mgr.GetWorkflows(context.TODO(), &wfName, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow list: %s", diff.PrintWantGot(d))
 		}
@@ -181,7 +180,7 @@ func TestGetWorkflows(t *testing.T) {
 
 		for i := 0; i < len(want); i++ {
 			name := fmt.Sprintf("wf%d", i)
-			got := mgr.GetWorkflows(context.TODO(), &name)
+			got, _, _ := mgr.GetWorkflows(context.TODO(), &name, nil)
 			if d := cmp.Diff([]*domain.Workflow{want[i]}, got, cmpopts.SortSlices(func(x, y *domain.Workflow) bool { return x.Name < y.Name })); d != "" {
 				t.Errorf("Unexpected Workflow list: %s", diff.PrintWantGot(d))
 			}
@@ -219,12 +218,12 @@ func TestDeleteWorkflow(t *testing.T) {
 		err = mgr.DeleteWorkflow(context.Background(), wf.Name)
 		assertError(t, err, nil)
 
-		got := workflowStore.GetWorkflows(context.TODO(), &wf.Name)
+		got, _, _ := workflowStore.GetWorkflows(context.TODO(), &wf.Name, nil)
 		if d := cmp.Diff([]*domain.Workflow{}, got); d != "" {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
 
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, nil)
+		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, nil, "", "")
 		assertStrings(t, err.Error(), "workflow not found")
 
 	})
@@ -235,8 +234,8 @@ func TestDeleteWorkflow(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 		assertError(t, got, nil)
 
 		err = mgr.DeleteWorkflow(context.Background(), wf.Name)
@@ -257,9 +256,9 @@ func TestAssignToCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		codeset := codesets[0]
-		wantListener, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+		wantListener, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		ignoreUnexported := cmpopts.IgnoreUnexported(WorkflowManager{})
@@ -281,7 +280,7 @@ func TestAssignToCodeset(t *testing.T) {
 			t.Errorf("Unexpected Listener: %s", diff.PrintWantGot(d))
 		}
 
-		workflowRuns, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		workflowRuns, _, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil, nil)
 		assertError(t, err, nil)
 		gotRuns := len(workflowRuns)
 		wantRuns := 1
@@ -295,17 +294,32 @@ func TestAssignToCodeset(t *testing.T) {
 
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		codeset := codesets[0]
 
+		secrets := []string{}
+		var webhookID *int64
 		for i := 0; i < 2; i++ {
-			_, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, webhookID, err = mgr.AssignToCodeset(context.TODO(), wf.Name, codeset.Project, codeset.Name, nil)
 			assertError(t, err, nil)
+			secrets = append(secrets, getListenerSecrets(wf.Name)[codeset.Project+"/"+codeset.Name])
+		}
+
+		// reassigning rotates the webhook secret
+		if secrets[0] == "" || secrets[0] == secrets[1] {
+			t.Errorf("Expected the webhook secret to be rotated, got %q and %q", secrets[0], secrets[1])
+		}
+
+		got := workflowStore.GetAllCodesetAssignments(context.TODO(), &wf.Name)
+		want := map[string][]*domain.CodesetAssignment{wf.Name: {{Codeset: codeset, WebhookID: webhookID}}}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Assignment: %s", diff.PrintWantGot(d))
 		}
 
 		_, err = workflowBackend.GetWorkflowListener(context.TODO(), wf.Name)
 		assertError(t, err, nil)
 
-		workflowRuns, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		workflowRuns, _, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil, nil)
 		assertError(t, err, nil)
 
 		gotRuns := len(workflowRuns)
@@ -315,12 +329,44 @@ func TestAssignToCodeset(t *testing.T) {
 		}
 	})
 
+	t.Run("ref filter", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		codeset := codesets[0]
+
+		refs := &domain.CodesetRefFilter{Branches: []string{"main", "release/*"}, Tags: []string{"v*"}}
+		_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, refs)
+		assertError(t, err, nil)
+
+		got := workflowStore.GetAllCodesetAssignments(context.TODO(), &wf.Name)
+		want := map[string][]*domain.CodesetAssignment{wf.Name: {{Codeset: codeset, WebhookID: webhookID, Refs: refs}}}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Assignment: %s", diff.PrintWantGot(d))
+		}
+
+		for ref, wantMatch := range map[string]bool{"refs/heads/main": true, "refs/heads/release/1.0": true,
+			"refs/heads/experiment": false, "refs/tags/v1.0": true, "refs/tags/test": false, "refs/heads/v1": false} {
+			if gotMatch := refs.Match(ref); gotMatch != wantMatch {
+				t.Errorf("Expected the match of %q to be %t, got %t", ref, wantMatch, gotMatch)
+			}
+		}
+
+		invalid := &domain.CodesetRefFilter{Branches: []string{"main'"}}
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, invalid)
+		if !errors.Is(err, domain.ErrInvalidRefPattern) {
+			t.Errorf("Expected an invalid pattern error, got %v", err)
+		}
+	})
+
 	t.Run("workflow not found", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
 
 		wfName := "unknownWf"
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		_, _, got := mgr.AssignToCodeset(context.Background(), wfName, codesets[0].Project, codesets[0].Name)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		_, _, got := mgr.AssignToCodeset(context.Background(), wfName, codesets[0].Project, codesets[0].Name, nil)
 		assertError(t, got, domain.ErrWorkflowNotFound)
 
 		gotAss := workflowStore.GetAllCodesetAssignments(context.TODO(), nil)
@@ -340,7 +386,7 @@ func TestAssignToCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, "unknownProj", "unknownCs")
+		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, "unknownProj", "unknownCs", nil)
 		assertError(t, got, errCodesetNotFound)
 
 		gotAss := workflowStore.GetAllCodesetAssignments(context.TODO(), nil)
@@ -352,6 +398,38 @@ func TestAssignToCodeset(t *testing.T) {
 		_, err = workflowBackend.GetWorkflowListener(context.TODO(), wf.Name)
 		assertStrings(t, err.Error(), "listener not found")
 	})
+
+	t.Run("dockerfile", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf", Steps: []*domain.WorkflowStep{{
+			Name:    "builder",
+			Inputs:  []*domain.WorkflowStepInput{{Name: "codeset", Codeset: &domain.WorkflowStepInputCodeset{Path: "/project"}}},
+			Outputs: []*domain.WorkflowStepOutput{{Name: "image", Image: &domain.WorkflowStepOutputImage{Dockerfile: "/project/.fuseml/Dockerfile"}}},
+		}}})
+		assertError(t, err, nil)
+
+		// the empty codesets are not checked
+		codeset, _ := codesetStore.Find(context.TODO(), "csproject1", "cs1")
+		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
+		assertError(t, got, nil)
+
+		codeset, _ = codesetStore.Find(context.TODO(), "csproject1", "cs2")
+		codesetStore.addFile(codeset, "train.py", "")
+		_, _, got = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
+		if !errors.Is(got, domain.ErrWorkflowDockerfileNotFound) {
+			t.Errorf("got error %q want %q", got, domain.ErrWorkflowDockerfileNotFound)
+		}
+
+		// the default branch is not checked when the workflow is triggered by other refs
+		refs := &domain.CodesetRefFilter{Tags: []string{"v*"}}
+		_, _, got = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, refs)
+		assertError(t, got, nil)
+
+		codesetStore.addFile(codeset, ".fuseml/Dockerfile", "FROM python:3.8")
+		_, _, got = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
+		assertError(t, got, nil)
+	})
 }
 
 func TestUnassignFromCodeset(t *testing.T) {
@@ -361,13 +439,13 @@ func TestUnassignFromCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		var listener *domain.WorkflowListener
 		var webhookID *int64
 		webhooks := map[*domain.Codeset][]*int64{}
 		for i := 0; i < 2; i++ {
 			codeset := codesets[i]
-			listener, webhookID, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+			listener, webhookID, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 			assertError(t, err, nil)
 
 			if webhook, exists := webhooks[codeset]; exists {
@@ -400,6 +478,12 @@ func TestUnassignFromCodeset(t *testing.T) {
 			t.Errorf("Unexpected Listener: %s", diff.PrintWantGot(d))
 		}
 
+		// listener should no longer accept the events of cs0
+		gotSecrets := len(getListenerSecrets(wf.Name))
+		if gotSecrets != 1 {
+			t.Errorf("Expected 1 webhook secret got %d", gotSecrets)
+		}
+
 		// delete wf assignment to cs1
 		err = mgr.UnassignFromCodeset(context.Background(), wf.Name, codesets[1].Project, codesets[1].Name)
 		assertError(t, err, nil)
@@ -420,7 +504,7 @@ func TestUnassignFromCodeset(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
 
 		wfName := "unknownWf"
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		got := mgr.UnassignFromCodeset(context.Background(), wfName, codesets[0].Project, codesets[0].Name)
 		assertError(t, got, domain.ErrWorkflowNotFound)
 
@@ -464,7 +548,7 @@ func TestUnassignFromCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		got := mgr.UnassignFromCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
 		assertError(t, got, domain.ErrWorkflowNotAssignedToCodeset)
 	})
@@ -475,9 +559,9 @@ func TestUnassignFromCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		codeset := codesets[0]
-		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		codesetStore.Delete(context.TODO(), codeset.Project, codeset.Name)
@@ -495,7 +579,7 @@ func TestGetAllCodesetAssignments(t *testing.T) {
 	t.Run("list", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		want := make(map[string][]*domain.CodesetAssignment, len(codesets))
 
 		addToWantAssignment := func(wf string, cs *domain.Codeset, webhookID *int64) {
@@ -516,11 +600,11 @@ func TestGetAllCodesetAssignments(t *testing.T) {
 			if i != 0 {
 				if i == 2 {
 					cs := codesets[i-2]
-					_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, cs.Project, cs.Name)
+					_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, cs.Project, cs.Name, nil)
 					assertError(t, err, nil)
 					addToWantAssignment(wf.Name, cs, webhookID)
 				}
-				_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name)
+				_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name, nil)
 				assertError(t, err, nil)
 				addToWantAssignment(wf.Name, codesets[i], webhookID)
 			}
@@ -551,7 +635,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		want := []*domain.WorkflowRun{}
 
 		// filter nil, no runs
-		got, err := mgr.GetWorkflowRuns(context.Background(), nil)
+		got, _, err := mgr.GetWorkflowRuns(context.Background(), nil, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -559,7 +643,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		// with filter, no runs
 		filter := domain.WorkflowRunFilter{}
-		got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+		got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -568,17 +652,17 @@ func TestGetWorkflowRuns(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		// create 3 runs with (cs0, csproject0, "Succeeded", "Failed", "Succeeded") and list
 		for i := 0; i < 3; i++ {
 			// currently, assigning a workflow to a codeset is the only function that creates a workflow run
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 			assertError(t, err, nil)
 
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ = workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+			want, _, _ = workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
@@ -593,7 +677,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// non existing workflow, no runs
 		wfName := "unknownWf"
 		filterNoRunsNoWf := domain.WorkflowRunFilter{WorkflowName: &wfName}
-		got, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNoWf)
+		got, _, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNoWf, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -605,7 +689,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		// existing workflow, no runs
 		filterNoRunsExistingWf := domain.WorkflowRunFilter{WorkflowName: &wf.Name}
-		got, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsExistingWf)
+		got, _, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsExistingWf, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -615,32 +699,84 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// wf0 -> 0 runs
 		// wf1 -> 1 run (cs0, csproject0, Succeeded)
 		// wf2 -> 2 runs (cs0, csproject0, Succeeded, Failed)
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		for i := 0; i < len(codesets); i++ {
 			wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: fmt.Sprintf("wf%d", i)})
 			assertError(t, err, nil)
 
 			for j := 0; j < i; j++ {
-				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 				assertError(t, err, nil)
 			}
 
 		}
 
 		// iterate over each workflow listing its runs
-		workflows := workflowStore.GetWorkflows(context.TODO(), nil)
+		workflows, _, _ := workflowStore.GetWorkflows(context.TODO(), nil, nil)
 		for _, wf := range workflows {
 			filter := domain.WorkflowRunFilter{WorkflowName: &wf.Name}
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), &domain.Workflow{Name: wf.Name}, nil)
+			want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), &domain.Workflow{Name: wf.Name}, nil, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
 		}
 	})
 
+	t.Run("paged", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		// create 2 workflows with 2 runs each
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
+		all := []*domain.WorkflowRun{}
+		for i := 0; i < 2; i++ {
+			wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: fmt.Sprintf("wf%d", i)})
+			assertError(t, err, nil)
+
+			for j := 0; j < 2; j++ {
+				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[j].Project, codesets[j].Name, nil)
+				assertError(t, err, nil)
+			}
+			runs, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil, nil)
+			all = append(all, runs...)
+		}
+
+		// the runs of all the workflows are paged together, in descending order of their names
+		opts := &domain.ListOptions{Limit: 3, Sort: "-name"}
+		got, next, err := mgr.GetWorkflowRuns(context.Background(), &domain.WorkflowRunFilter{}, opts)
+		assertError(t, err, nil)
+		if d := cmp.Diff([]*domain.WorkflowRun{all[3], all[2], all[1]}, got); d != "" {
+			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
+		}
+		opts.Continue = next
+		got, next, err = mgr.GetWorkflowRuns(context.Background(), &domain.WorkflowRunFilter{}, opts)
+		assertError(t, err, nil)
+		if d := cmp.Diff([]*domain.WorkflowRun{all[0]}, got); d != "" {
+			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
+		}
+		if next != "" {
+			t.Errorf("Unexpected continue token after the last page: %q", next)
+		}
+
+		// the runs of a single workflow are paged by the backend
+		filter := domain.WorkflowRunFilter{WorkflowName: &all[0].WorkflowRef}
+		got, next, err = mgr.GetWorkflowRuns(context.Background(), &filter, &domain.ListOptions{Limit: 1})
+		assertError(t, err, nil)
+		if d := cmp.Diff([]*domain.WorkflowRun{all[0]}, got); d != "" {
+			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
+		}
+		if next == "" {
+			t.Error("Expected a continue token for the next page")
+		}
+
+		// invalid continue token
+		_, _, err = mgr.GetWorkflowRuns(context.Background(), &domain.WorkflowRunFilter{},
+			&domain.ListOptions{Limit: 1, Continue: "invalid"})
+		assertError(t, err, domain.ErrInvalidContinueToken)
+	})
+
 	t.Run("filter by codeset", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
 
@@ -649,16 +785,16 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// non existing codeset, no runs
 		csName := "unknownCs"
 		filterNoRunsNoCs := domain.WorkflowRunFilter{CodesetName: csName}
-		got, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNoCs)
+		got, _, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNoCs, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 		}
 
 		// existing codeset, no runs
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		filterNoRuns := domain.WorkflowRunFilter{CodesetName: codesets[0].Name}
-		got, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRuns)
+		got, _, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRuns, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -672,17 +808,17 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// 2. (cs1, csproject1, Failed)
 		// 3. (cs2, csproject1, Succeeded)
 		for i := 0; i < len(codesets); i++ {
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name, nil)
 			assertError(t, err, nil)
 		}
 
 		// iterate over each codeset and list runs by codeset name
 		for _, cs := range codesets {
 			filter := domain.WorkflowRunFilter{CodesetName: cs.Name}
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter)
+			want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
@@ -691,10 +827,10 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// iterate over each codeset and list runs by codeset project
 		for _, cs := range codesets {
 			filter := domain.WorkflowRunFilter{CodesetProject: cs.Project}
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter)
+			want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
@@ -703,10 +839,10 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// iterate over each codeset and list runs by codeset name and project
 		for _, cs := range codesets {
 			filter := domain.WorkflowRunFilter{CodesetName: cs.Name, CodesetProject: cs.Project}
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter)
+			want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
@@ -720,7 +856,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		// nil status, no runs
 		filterNoRunsNilStatus := domain.WorkflowRunFilter{Status: nil}
-		got, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNilStatus)
+		got, _, err := mgr.GetWorkflowRuns(context.Background(), &filterNoRunsNilStatus, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -728,7 +864,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		// empty status, no runs
 		filterNoRunsEmptyStatus := domain.WorkflowRunFilter{Status: []string{}}
-		got, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsEmptyStatus)
+		got, _, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsEmptyStatus, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -736,7 +872,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		// with status, no runs
 		filterNoRunsWithStatus := domain.WorkflowRunFilter{Status: []string{"Succeeded"}}
-		got, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsWithStatus)
+		got, _, err = mgr.GetWorkflowRuns(context.Background(), &filterNoRunsWithStatus, nil)
 		assertError(t, err, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
@@ -749,9 +885,9 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// 1. (cs0, csproject0, Succeeded)
 		// 2. (cs0, csproject0, Failed)
 		// 3. (cs0, csproject0, Succeeded)
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		for i := 0; i < len(codesets); i++ {
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 			assertError(t, err, nil)
 		}
 
@@ -759,10 +895,10 @@ func TestGetWorkflowRuns(t *testing.T) {
 		for _, s := range workflowRunStatuses {
 			status := []string{s}
 			filter := domain.WorkflowRunFilter{Status: status}
-			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
+			got, _, err = mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 			assertError(t, err, nil)
 
-			want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter)
+			want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, &filter, nil)
 			if d := cmp.Diff(want, got); d != "" {
 				t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 			}
@@ -776,7 +912,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// wf0 -> 0 runs
 		// wf1 -> 1 run (cs0, project0, Succeeded)
 		// wf2 -> 2 runs (cs1, project1, Succeeded) (cs2, project1, Failed)
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		for i := 0; i < len(codesets); i++ {
 			wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: fmt.Sprintf("wf%d", i)})
 			assertError(t, err, nil)
@@ -786,13 +922,13 @@ func TestGetWorkflowRuns(t *testing.T) {
 				if i == 2 {
 					csIndex = j + 1
 				}
-				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[csIndex].Project, codesets[csIndex].Name)
+				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[csIndex].Project, codesets[csIndex].Name, nil)
 				assertError(t, err, nil)
 			}
 		}
 
 		// iterate over all workflows, codesets, status listing runs and filtering for each combination
-		workflows := workflowStore.GetWorkflows(context.TODO(), nil)
+		workflows, _, _ := workflowStore.GetWorkflows(context.TODO(), nil, nil)
 		for _, wf := range workflows {
 			wfName := wf.Name
 			for _, cs := range codesets {
@@ -801,10 +937,10 @@ func TestGetWorkflowRuns(t *testing.T) {
 				for _, status := range workflowRunStatuses {
 					status := []string{status}
 					filter := domain.WorkflowRunFilter{WorkflowName: &wfName, CodesetName: csName, CodesetProject: csProject, Status: status}
-					got, err := mgr.GetWorkflowRuns(context.Background(), &filter)
+					got, _, err := mgr.GetWorkflowRuns(context.Background(), &filter, nil)
 					assertError(t, err, nil)
 
-					want, _ := workflowBackend.GetWorkflowRuns(context.TODO(), &domain.Workflow{Name: wfName}, &filter)
+					want, _, _ := workflowBackend.GetWorkflowRuns(context.TODO(), &domain.Workflow{Name: wfName}, &filter, nil)
 					if d := cmp.Diff(want, got); d != "" {
 						t.Errorf("Unexpected Workflow Runs: %s", diff.PrintWantGot(d))
 					}
@@ -834,10 +970,10 @@ func TestGetAssignmentStatus(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.TODO(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		codesets, _, _ := codesetStore.GetAll(context.TODO(), nil, nil, false, nil, nil)
 		codeset := codesets[0]
 
-		listener, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codeset.Project, codeset.Name)
+		listener, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		got := mgr.GetAssignmentStatus(context.TODO(), wf.Name)
@@ -891,7 +1027,9 @@ func newFakeWorkflowManager(t *testing.T) *WorkflowManager {
 		}
 	}
 
-	return NewWorkflowManager(workflowBackend, workflowStore, codesetStore, extensionRegistry)
+	quotaStore = fakeQuotaStore{}
+	return NewWorkflowManager(zap.NewNop().Sugar(), workflowBackend, workflowStore, codesetStore, extensionRegistry,
+		newFakeQuotaManager())
 }
 
 func createFakeExtension(t *testing.T, wfm *WorkflowManager, prefix string) *domain.ExtensionRecord {
@@ -951,6 +1089,7 @@ func createFakeExtension(t *testing.T, wfm *WorkflowManager, prefix string) *dom
 type fakeStorableWorkflow struct {
 	listener *domain.WorkflowListener
 	runs     []*domain.WorkflowRun
+	secrets  map[string]string
 }
 
 type fakeWorkflowBackend struct {
@@ -964,7 +1103,7 @@ func (b *fakeWorkflowBackend) CreateWorkflow(ctx context.Context, w *domain.Work
 	if _, exists := b.workflows[w.Name]; exists {
 		return domain.ErrWorkflowExists
 	}
-	b.workflows[w.Name] = &fakeStorableWorkflow{nil, []*domain.WorkflowRun{}}
+	b.workflows[w.Name] = &fakeStorableWorkflow{nil, []*domain.WorkflowRun{}, map[string]string{}}
 	return nil
 }
 
@@ -975,7 +1114,7 @@ func (b *fakeWorkflowBackend) DeleteWorkflow(ctx context.Context, workflowName s
 	return nil
 }
 
-func (b *fakeWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset) error {
+func (b *fakeWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset, ref, revision string) error {
 	b.t.Helper()
 
 	if _, exists := b.workflows[workflowName]; !exists {
@@ -989,15 +1128,68 @@ func (b *fakeWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowNam
 		Inputs: []*domain.WorkflowRunInput{
 			{Input: &domain.WorkflowInput{Name: "codeset-name", Type: "codeset"}, Value: fmt.Sprintf("%s/%s", codeset.Project, codeset.Name)},
 			{Input: &domain.WorkflowInput{Name: "predictor", Type: "string"}, Value: "sklearn"}},
+		Ref:    ref,
 		Status: workflowRunStatuses[len(runs)%len(workflowRunStatuses)]}
 
 	b.workflows[workflowName].runs = append(b.workflows[workflowName].runs, run)
 	return nil
 }
 
-func (b *fakeWorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+func (b *fakeWorkflowBackend) QueueWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset, ref, revision string) error {
+	b.t.Helper()
+
+	if err := b.CreateWorkflowRun(ctx, workflowName, codeset, ref, revision); err != nil {
+		return err
+	}
+	runs := b.workflows[workflowName].runs
+	runs[len(runs)-1].Status = domain.WorkflowRunQueued
+	return nil
+}
+
+func (b *fakeWorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter,
+	opts *domain.ListOptions) ([]*domain.WorkflowRun, string, error) {
+	b.t.Helper()
+
+	runs, err := b.filterWorkflowRuns(wf, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(runs))
+	if err != nil {
+		return nil, "", err
+	}
+	return runs[start:end], next, nil
+}
+
+func (b *fakeWorkflowBackend) StartWorkflowRun(ctx context.Context, name string) error {
+	b.t.Helper()
+
+	for _, wf := range b.workflows {
+		for _, run := range wf.runs {
+			if run.Name == name && run.Status == domain.WorkflowRunQueued {
+				run.Status = "Running"
+			}
+		}
+	}
+	return nil
+}
+
+func (b *fakeWorkflowBackend) DeleteWorkflowRun(ctx context.Context, name string) error {
 	b.t.Helper()
 
+	for _, wf := range b.workflows {
+		for i, run := range wf.runs {
+			if run.Name == name {
+				wf.runs = append(wf.runs[:i], wf.runs[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func (b *fakeWorkflowBackend) filterWorkflowRuns(wf *domain.Workflow, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+
 	res := []*domain.WorkflowRun{}
 	if sw, exists := b.workflows[wf.Name]; !exists || len(sw.runs) == 0 {
 		return res, nil
@@ -1068,6 +1260,28 @@ func (b *fakeWorkflowBackend) DeleteWorkflowListener(ctx context.Context, workfl
 	return nil
 }
 
+func (b *fakeWorkflowBackend) SetWorkflowListenerTrigger(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	secret string, refs *domain.CodesetRefFilter) error {
+	b.t.Helper()
+
+	b.workflows[workflowName].secrets[codeset.Project+"/"+codeset.Name] = secret
+	return nil
+}
+
+func (b *fakeWorkflowBackend) DeleteWorkflowListenerTrigger(ctx context.Context, workflowName string, codeset *domain.Codeset) error {
+	b.t.Helper()
+
+	if wf, exists := b.workflows[workflowName]; exists {
+		delete(wf.secrets, codeset.Project+"/"+codeset.Name)
+	}
+	return nil
+}
+
+// getListenerSecrets returns the webhook secrets set for the listener of a workflow, by codeset
+func getListenerSecrets(workflowName string) map[string]string {
+	return workflowBackend.(*fakeWorkflowBackend).workflows[workflowName].secrets
+}
+
 func (b *fakeWorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName string) (*domain.WorkflowListener, error) {
 	b.t.Helper()
 
@@ -1088,6 +1302,8 @@ type fakeStorableCodeset struct {
 	codeset     *domain.Codeset
 	webhooks    map[int64]string
 	subscribers []domain.CodesetSubscriber
+	// contents of the codeset files, by path
+	files map[string]string
 }
 
 type fakeCodesetStore struct {
@@ -1098,11 +1314,12 @@ type fakeCodesetStore struct {
 func (fcs *fakeCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.Codeset, *string, *string, error) {
 	fcs.t.Helper()
 
-	fcs.store[codesetID{c.Name, c.Project}] = fakeStorableCodeset{codeset: c, webhooks: make(map[int64]string)}
+	fcs.store[codesetID{c.Name, c.Project}] = fakeStorableCodeset{codeset: c, webhooks: make(map[int64]string), files: make(map[string]string)}
 	return c, nil, nil, nil
 }
 
-func (fcs *fakeCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset, url string) (*int64, error) {
+func (fcs *fakeCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset, url, secret string,
+	refs *domain.CodesetRefFilter) (*int64, error) {
 	fcs.t.Helper()
 
 	id := rand.Int63()
@@ -1141,15 +1358,56 @@ func (fcs *fakeCodesetStore) Find(ctx context.Context, project, name string) (*d
 	return nil, errCodesetNotFound
 }
 
-func (fcs *fakeCodesetStore) GetAll(ctx context.Context, project, label *string) (res []*domain.Codeset, err error) {
+func (fcs *fakeCodesetStore) GetAll(ctx context.Context, project, label *string, archived bool, visible domain.ProjectVisibility,
+	opts *domain.ListOptions) (res []*domain.Codeset, next string, err error) {
 	fcs.t.Helper()
 
 	for _, c := range fcs.store {
-		res = append(res, c.codeset)
+		if (project == nil || c.codeset.Project == *project) && visible.Includes(c.codeset.Project) {
+			res = append(res, c.codeset)
+		}
+	}
+	return res, "", nil
+}
+
+func (fcs *fakeCodesetStore) Update(ctx context.Context, c *domain.Codeset) (*domain.Codeset, error) {
+	return c, nil
+}
+
+func (fcs *fakeCodesetStore) GetVersions(ctx context.Context, project, name, ref string, commits int) (*domain.CodesetVersions, error) {
+	return nil, nil
+}
+
+func (fcs *fakeCodesetStore) AddTag(ctx context.Context, project, name, tag, commit, message string) (*domain.CodesetRef, error) {
+	return nil, nil
+}
+
+func (fcs *fakeCodesetStore) GetFiles(ctx context.Context, project, name, ref, path string) ([]*domain.CodesetFile, error) {
+	res := []*domain.CodesetFile{}
+	for p := range fcs.store[codesetID{name, project}].files {
+		if path == "" || strings.HasPrefix(p, path+"/") || p == path {
+			res = append(res, &domain.CodesetFile{Name: p, Path: p, Type: domain.CodesetFileTypeFile})
+		}
+	}
+	if len(res) == 0 {
+		return nil, domain.ErrCodesetFileNotFound
 	}
 	return res, nil
 }
 
+func (fcs *fakeCodesetStore) GetFile(ctx context.Context, project, name, ref, path string) ([]byte, error) {
+	content, ok := fcs.store[codesetID{name, project}].files[path]
+	if !ok {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	return []byte(content), nil
+}
+
+// addFile adds a file to a codeset of the store
+func (fcs *fakeCodesetStore) addFile(c *domain.Codeset, path, content string) {
+	fcs.store[codesetID{c.Name, c.Project}].files[path] = content
+}
+
 func (fcs *fakeCodesetStore) Subscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
 	fcs.t.Helper()
 
@@ -1157,6 +1415,11 @@ func (fcs *fakeCodesetStore) Subscribe(ctx context.Context, subscriber domain.Co
 	if !ok {
 		return fmt.Errorf("codeset not found")
 	}
+	for _, s := range sc.subscribers {
+		if s == subscriber {
+			return nil
+		}
+	}
 	sc.subscribers = append(sc.subscribers, subscriber)
 	fcs.store[codesetID{codeset.Name, codeset.Project}] = sc
 	return nil
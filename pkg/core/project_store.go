@@ -22,7 +22,7 @@ func NewGitProjectStore(gitAdmin domain.GitAdminClient) *GitProjectStore {
 
 // Find returns a project identified by project and name
 func (cs *GitProjectStore) Find(ctx context.Context, project string) (*domain.Project, error) {
-	result, err := cs.gitAdmin.GetProject(project)
+	result, err := cs.gitAdmin.GetProject(ctx, project)
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Project failed")
 	}
@@ -31,25 +31,61 @@ func (cs *GitProjectStore) Find(ctx context.Context, project string) (*domain.Pr
 
 // Create creates a new project
 func (cs *GitProjectStore) Create(ctx context.Context, name, desc string) (*domain.Project, error) {
-	result, err := cs.gitAdmin.CreateProject(name, desc, false)
+	result, err := cs.gitAdmin.CreateProject(ctx, name, desc, false)
 	if err != nil {
 		return nil, errors.Wrap(err, "Creating Project failed")
 	}
 	return result, nil
 }
 
-// GetAll returns all projects matching given project and label
-func (cs *GitProjectStore) GetAll(ctx context.Context) ([]*domain.Project, error) {
-	result, err := cs.gitAdmin.GetProjects()
+// GetAll returns the page of the visible projects selected by the list options, and the token used to
+// retrieve the next page
+func (cs *GitProjectStore) GetAll(ctx context.Context, visible domain.ProjectVisibility, opts *domain.ListOptions) ([]*domain.Project, string, error) {
+	projects, err := cs.gitAdmin.GetProjects(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "Fetching Projects failed")
+		return nil, "", errors.Wrap(err, "Fetching Projects failed")
 	}
-	return result, nil
+	// the projects are filtered before they are paged, so that the pages are filled with visible projects
+	result := make([]*domain.Project, 0, len(projects))
+	for _, p := range projects {
+		if visible.Includes(p.Name) {
+			result = append(result, p)
+		}
+	}
+	err = opts.SortItems(result, "name", domain.SortKeys{
+		"name": func(i int) string { return result[i].Name },
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(result))
+	if err != nil {
+		return nil, "", err
+	}
+	return result[start:end], next, nil
+}
+
+// Update changes the description of a project
+func (cs *GitProjectStore) Update(ctx context.Context, name, desc string) (*domain.Project, error) {
+	err := cs.gitAdmin.UpdateProject(ctx, name, desc)
+	if err != nil {
+		return nil, errors.Wrap(err, "Updating Project failed")
+	}
+	return cs.Find(ctx, name)
+}
+
+// ExclusiveUsers returns the names of the users of a project that belong to no other project
+func (cs *GitProjectStore) ExclusiveUsers(ctx context.Context, name string) ([]string, error) {
+	users, err := cs.gitAdmin.GetExclusiveProjectUsers(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Project users failed")
+	}
+	return users, nil
 }
 
 // Delete removes a project identified by project and name
 func (cs *GitProjectStore) Delete(ctx context.Context, project string) error {
-	err := cs.gitAdmin.DeleteProject(project)
+	err := cs.gitAdmin.DeleteProject(ctx, project)
 	if err != nil {
 		return errors.Wrap(err, "Deleting Project failed")
 	}
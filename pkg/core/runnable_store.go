@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
@@ -12,6 +13,8 @@ import (
 
 // RunnableStore describes in memory store for runnables
 type RunnableStore struct {
+	// runnables may be updated in the background, when a runnable build completes
+	sync.RWMutex
 	items map[string]*domain.Runnable
 }
 
@@ -23,13 +26,18 @@ func NewRunnableStore() *RunnableStore {
 }
 
 const (
-	errRunnableExists = "a runnable with that ID already exists"
+	errRunnableExists   = "a runnable with that ID already exists"
+	errRunnableNotFound = "a runnable with that ID does not exist"
 )
 
-// Find returns a list of runnables matching the input query.
+// Find returns a page of the runnables matching the input query and the token used to retrieve the next page.
 // Runnables may be matched by id, kind or labels. Only runnables that match all the
 // supplied criteria will be returned.
-func (s *RunnableStore) Find(ctx context.Context, id string, kind string, labels map[string]string) (res []*domain.Runnable, err error) {
+func (s *RunnableStore) Find(ctx context.Context, id string, kind string, labels map[string]string,
+	opts *domain.ListOptions) (res []*domain.Runnable, next string, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
 	res = make([]*domain.Runnable, 0)
 
 RUNNABLES:
@@ -71,11 +79,27 @@ RUNNABLES:
 		copier.Copy(&rMatch, r)
 		res = append(res, rMatch)
 	}
-	return
+
+	err = opts.SortItems(res, "id", domain.SortKeys{
+		"id":      func(i int) string { return res[i].ID },
+		"kind":    func(i int) string { return res[i].Kind + "/" + res[i].ID },
+		"created": func(i int) string { return domain.TimeSortKey(res[i].Created) + "/" + res[i].ID },
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(res))
+	if err != nil {
+		return nil, "", err
+	}
+	return res[start:end], next, nil
 }
 
 // Register adds a new runnable, based on the Runnable structure provided as argument
 func (s *RunnableStore) Register(ctx context.Context, r *domain.Runnable) (res *domain.Runnable, err error) {
+	s.Lock()
+	defer s.Unlock()
+
 	if _, found := s.items[r.ID]; found {
 		return nil, errors.New(errRunnableExists)
 	}
@@ -89,5 +113,32 @@ func (s *RunnableStore) Register(ctx context.Context, r *domain.Runnable) (res *
 
 // Get returns a runnable identified by id
 func (s *RunnableStore) Get(ctx context.Context, id string) (res *domain.Runnable, err error) {
-	return s.items[id], nil
+	s.RLock()
+	defer s.RUnlock()
+
+	r, found := s.items[id]
+	if !found {
+		return nil, nil
+	}
+	res = &domain.Runnable{}
+	// return a deep copy of the internal runnable
+	copier.Copy(&res, r)
+	return res, nil
+}
+
+// Update replaces an existing runnable with the Runnable structure provided as argument
+func (s *RunnableStore) Update(ctx context.Context, r *domain.Runnable) (res *domain.Runnable, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	current, found := s.items[r.ID]
+	if !found {
+		return nil, errors.New(errRunnableNotFound)
+	}
+	res = &domain.Runnable{}
+	// return a deep copy of the internal runnable
+	copier.Copy(&res, r)
+	res.Created = current.Created
+	s.items[res.ID] = res
+	return res, nil
 }
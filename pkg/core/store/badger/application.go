@@ -2,6 +2,7 @@ package badger
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/timshannon/badgerhold/v3"
@@ -27,43 +28,114 @@ func (as *ApplicationStore) Find(ctx context.Context, name string) *domain.Appli
 	return &app
 }
 
-// GetAll returns all applications of a given type.
-// If type is not specified, return all applications.
-func (as *ApplicationStore) GetAll(ctx context.Context, applicationType *string, applicationWorkflow *string) ([]*domain.Application, error) {
+// GetAll returns the page of applications matching the filter selected by the list options, and the
+// token used to retrieve the next page.
+// If filter is not specified, return all applications.
+func (as *ApplicationStore) GetAll(ctx context.Context, filter *domain.ApplicationFilter, opts *domain.ListOptions) ([]*domain.Application, string, error) {
 	result := []*domain.Application{}
 	query := &badgerhold.Query{}
 
-	if applicationType != nil && applicationWorkflow != nil {
-		query = badgerhold.Where("Type").Eq(*applicationType).And("Workflow").Eq(*applicationWorkflow)
-	} else if applicationType != nil {
-		query = badgerhold.Where("Type").Eq(*applicationType)
-	} else if applicationWorkflow != nil {
-		query = badgerhold.Where("Workflow").Eq(*applicationWorkflow)
+	if filter != nil {
+		if filter.Type != nil && filter.Workflow != nil {
+			query = badgerhold.Where("Type").Eq(*filter.Type).And("Workflow").Eq(*filter.Workflow)
+		} else if filter.Type != nil {
+			query = badgerhold.Where("Type").Eq(*filter.Type)
+		} else if filter.Workflow != nil {
+			query = badgerhold.Where("Workflow").Eq(*filter.Workflow)
+		}
+	}
+
+	if filter == nil || (filter.CodesetProject == nil && filter.CodesetName == nil && filter.Visible == nil) {
+		query, err := pageQuery(query, opts, "name", map[string][]string{
+			"name":     {"Name"},
+			"type":     {"Type", "Name"},
+			"workflow": {"Workflow", "Name"},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if err := as.store.Find(&result, query); err != nil {
+			return nil, "", err
+		}
+		end, next := pageEnd(len(result), opts)
+		return result[:end], next, nil
 	}
 
 	err := as.store.Find(&result, query)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return result, nil
+	// the codeset is a nested field, filter, sort and page the applications after querying the store
+	filtered := []*domain.Application{}
+	for _, app := range result {
+		if filter.Matches(app) {
+			filtered = append(filtered, app)
+		}
+	}
+	if err := domain.SortApplications(filtered, opts); err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(filtered))
+	if err != nil {
+		return nil, "", err
+	}
+	return filtered[start:end], next, nil
 }
 
 // Add adds a new application, based on the Application structure provided as argument
 func (as *ApplicationStore) Add(ctx context.Context, a *domain.Application) (*domain.Application, error) {
-	// TODO What if such application already exist? Should we thrown an error, or silently replace it?
+	a.Version = 1
 	err := as.store.Insert(a.Name, a)
 	if err != nil {
-		as.store.Delete(a.Name, a)
-		err := as.store.Insert(a.Name, a)
-		if err != nil {
-			return nil, err
+		if err == badgerhold.ErrKeyExists {
+			return nil, domain.ErrApplicationExists
 		}
+		return nil, err
 	}
 	return a, nil
 }
 
+// Update replaces an application registered by FuseML, as long as the version of the application
+// provided as argument matches the version of the stored application
+func (as *ApplicationStore) Update(ctx context.Context, a *domain.Application) (*domain.Application, error) {
+	found := false
+	updated := *a
+	err := as.store.UpdateMatching(&domain.Application{}, badgerhold.Where(badgerhold.Key).Eq(a.Name), func(record interface{}) error {
+		app, ok := record.(*domain.Application)
+		if !ok {
+			return fmt.Errorf("record is not an application: %T", record)
+		}
+		found = true
+		if app.Version != a.Version {
+			return domain.ErrApplicationVersionConflict
+		}
+		updated.Version++
+		*app = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, domain.ErrApplicationNotFound
+	}
+	return &updated, nil
+}
+
 // Delete deletes the application registered by FuseML
 func (as *ApplicationStore) Delete(ctx context.Context, name string) error {
 	a := domain.Application{}
 	return as.store.Delete(name, a)
 }
+
+// UpdateStatus updates the status of an application registered by FuseML
+func (as *ApplicationStore) UpdateStatus(ctx context.Context, name string, status *domain.ApplicationStatus) error {
+	return as.store.UpdateMatching(&domain.Application{}, badgerhold.Where(badgerhold.Key).Eq(name), func(record interface{}) error {
+		app, ok := record.(*domain.Application)
+		if !ok {
+			return fmt.Errorf("record is not an application: %T", record)
+		}
+		app.Status = status
+		return nil
+	})
+}
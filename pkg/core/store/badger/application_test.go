@@ -2,12 +2,11 @@ package badger
 
 import (
 	"context"
-	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/tektoncd/pipeline/test/diff"
-	"github.com/timshannon/badgerhold/v3"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
 )
@@ -40,14 +39,81 @@ func TestApplicationAdd(t *testing.T) {
 
 		store.Add(context.TODO(), &app)
 
-		app.Type = "testType2"
-		got, err := store.Add(context.TODO(), &app)
+		replacement := domain.Application{
+			Name: "test-app",
+			Type: "testType2",
+		}
+		_, err := store.Add(context.TODO(), &replacement)
+		assertError(t, err, domain.ErrApplicationExists)
+
+		got := store.Find(context.TODO(), app.Name)
+		if d := cmp.Diff(&app, got); d != "" {
+			t.Errorf("Unexpected Application: %s", diff.PrintWantGot(d))
+		}
+	})
+}
+
+func TestApplicationUpdate(t *testing.T) {
+	t.Run("matching version", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		app := domain.Application{
+			Name: "test-app",
+			Type: "testType1",
+		}
+		store.Add(context.TODO(), &app)
+
+		update := app
+		update.Type = "testType2"
+		got, err := store.Update(context.TODO(), &update)
 		assertNoError(t, err)
 
+		want := &domain.Application{
+			Name:    "test-app",
+			Type:    "testType2",
+			Version: 2,
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Application: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want, store.Find(context.TODO(), app.Name)); d != "" {
+			t.Errorf("Unexpected stored Application: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		app := domain.Application{
+			Name: "test-app",
+			Type: "testType1",
+		}
+		store.Add(context.TODO(), &app)
+
+		update := app
+		update.Type = "testType2"
+		update.Version = 5
+		_, err := store.Update(context.TODO(), &update)
+		assertError(t, err, domain.ErrApplicationVersionConflict)
+
+		got := store.Find(context.TODO(), app.Name)
 		if d := cmp.Diff(&app, got); d != "" {
 			t.Errorf("Unexpected Application: %s", diff.PrintWantGot(d))
 		}
 	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		app := domain.Application{
+			Name: "test-app",
+		}
+		_, err := store.Update(context.TODO(), &app)
+		assertError(t, err, domain.ErrApplicationNotFound)
+	})
 }
 
 func TestApplicationFind(t *testing.T) {
@@ -97,7 +163,7 @@ func TestApplicationGetAll(t *testing.T) {
 		store.Add(context.TODO(), &app2)
 		store.Add(context.TODO(), &app3)
 
-		got, err := store.GetAll(context.TODO(), nil, nil)
+		got, _, err := store.GetAll(context.TODO(), nil, nil)
 		assertNoError(t, err)
 		want := []*domain.Application{&app1, &app2, &app3}
 		if d := cmp.Diff(want, got); d != "" {
@@ -122,7 +188,7 @@ func TestApplicationGetAll(t *testing.T) {
 		store.Add(context.TODO(), &app1)
 		store.Add(context.TODO(), &app2)
 
-		got, err := store.GetAll(context.TODO(), &app1.Type, nil)
+		got, _, err := store.GetAll(context.TODO(), &domain.ApplicationFilter{Type: &app1.Type}, nil)
 		assertNoError(t, err)
 
 		want := []*domain.Application{&app1}
@@ -150,7 +216,7 @@ func TestApplicationGetAll(t *testing.T) {
 		store.Add(context.TODO(), &app1)
 		store.Add(context.TODO(), &app2)
 
-		got, err := store.GetAll(context.TODO(), nil, &app1.Workflow)
+		got, _, err := store.GetAll(context.TODO(), &domain.ApplicationFilter{Workflow: &app1.Workflow}, nil)
 		assertNoError(t, err)
 
 		want := []*domain.Application{&app1}
@@ -192,7 +258,7 @@ func TestApplicationGetAll(t *testing.T) {
 		store.Add(context.TODO(), &app3)
 		store.Add(context.TODO(), &app4)
 
-		got, err := store.GetAll(context.TODO(), &app1.Type, &app1.Workflow)
+		got, _, err := store.GetAll(context.TODO(), &domain.ApplicationFilter{Type: &app1.Type, Workflow: &app1.Workflow}, nil)
 		assertNoError(t, err)
 
 		want := []*domain.Application{&app1}
@@ -200,6 +266,91 @@ func TestApplicationGetAll(t *testing.T) {
 			t.Errorf("Unexpected Applications: %s", diff.PrintWantGot(d))
 		}
 	})
+
+	t.Run("by codeset", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		app1 := domain.Application{
+			Name:     "test-app1",
+			Type:     "testType1",
+			Workflow: "wf-1",
+			Codeset:  &domain.ApplicationCodeset{Project: "project-1", Name: "cs-1"},
+		}
+
+		app2 := domain.Application{
+			Name:     "test-app2",
+			Type:     "testType1",
+			Workflow: "wf-1",
+			Codeset:  &domain.ApplicationCodeset{Project: "project-1", Name: "cs-2"},
+		}
+
+		app3 := domain.Application{
+			Name:     "test-app3",
+			Type:     "testType1",
+			Workflow: "wf-1",
+		}
+
+		store.Add(context.TODO(), &app1)
+		store.Add(context.TODO(), &app2)
+		store.Add(context.TODO(), &app3)
+
+		got, _, err := store.GetAll(context.TODO(), &domain.ApplicationFilter{Type: &app1.Type,
+			CodesetProject: &app1.Codeset.Project, CodesetName: &app1.Codeset.Name}, nil)
+		assertNoError(t, err)
+
+		want := []*domain.Application{&app1}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Applications: %s", diff.PrintWantGot(d))
+		}
+
+		got, _, err = store.GetAll(context.TODO(), &domain.ApplicationFilter{CodesetProject: &app1.Codeset.Project}, nil)
+		assertNoError(t, err)
+
+		want = []*domain.Application{&app1, &app2}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Applications: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("visible projects", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		app1 := domain.Application{
+			Name:    "test-app1",
+			Codeset: &domain.ApplicationCodeset{Project: "project-1", Name: "cs-1"},
+		}
+		app2 := domain.Application{
+			Name:    "test-app2",
+			Codeset: &domain.ApplicationCodeset{Project: "project-2", Name: "cs-2"},
+		}
+		app3 := domain.Application{
+			Name: "test-app3",
+		}
+
+		store.Add(context.TODO(), &app1)
+		store.Add(context.TODO(), &app2)
+		store.Add(context.TODO(), &app3)
+
+		// the applications of the other projects are filtered out before the page is selected
+		visible := func(project string) bool { return project == "project-2" }
+		got, next, err := store.GetAll(context.TODO(), &domain.ApplicationFilter{Visible: visible}, &domain.ListOptions{Limit: 1})
+		assertNoError(t, err)
+
+		want := []*domain.Application{&app2}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Applications: %s", diff.PrintWantGot(d))
+		}
+
+		got, _, err = store.GetAll(context.TODO(), &domain.ApplicationFilter{Visible: visible}, &domain.ListOptions{Limit: 1, Continue: next})
+		assertNoError(t, err)
+
+		want = []*domain.Application{&app3}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Applications: %s", diff.PrintWantGot(d))
+		}
+	})
 }
 
 func TestApplicationDelete(t *testing.T) {
@@ -234,24 +385,50 @@ func TestApplicationDelete(t *testing.T) {
 	})
 }
 
-func newApplicationStore(t *testing.T) (*ApplicationStore, func()) {
-	t.Helper()
+func TestApplicationUpdateStatus(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
 
-	dir := tmpDir(t)
-	opt := badgerhold.DefaultOptions
-	opt.Logger = nil
-	opt.Dir = dir
-	opt.ValueDir = dir
+		app := domain.Application{
+			Name: "test-app",
+			Type: "testType1",
+		}
 
-	store, err := badgerhold.Open(opt)
-	if err != nil {
-		t.Fatalf("failed to open store: %v", err)
-	}
+		store.Add(context.TODO(), &app)
 
-	applicationStore := NewApplicationStore(store)
+		status := domain.ApplicationStatus{
+			State:       domain.ApplicationDegraded,
+			LastChecked: time.Now().UTC().Truncate(time.Second),
+			Message:     "Deployment test-app is not ready",
+		}
+		err := store.UpdateStatus(context.TODO(), app.Name, &status)
+		assertNoError(t, err)
+
+		app.Status = &status
+		got := store.Find(context.TODO(), app.Name)
+		if d := cmp.Diff(&app, got); d != "" {
+			t.Errorf("Unexpected Application: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newApplicationStore(t)
+		defer done()
+
+		err := store.UpdateStatus(context.TODO(), "non-existing", &domain.ApplicationStatus{State: domain.ApplicationReady})
+		assertNoError(t, err)
+
+		got := store.Find(context.TODO(), "non-existing")
+		if got != nil {
+			t.Errorf("Expected nil, got %v", got)
+		}
+	})
+}
+
+func newApplicationStore(t *testing.T) (*ApplicationStore, func()) {
+	t.Helper()
 
-	return applicationStore, func() {
-		store.Close()
-		os.RemoveAll(dir)
-	}
+	store, done := openTestStore(t)
+	return NewApplicationStore(store), done
 }
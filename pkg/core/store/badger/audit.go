@@ -0,0 +1,103 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/timshannon/badgerhold/v3"
+)
+
+// AuditStore is a wrapper around a badgerhold.Store that implements the domain.AuditStore interface.
+type AuditStore struct {
+	store *badgerhold.Store
+}
+
+// NewAuditStore creates a new AuditStore.
+func NewAuditStore(store *badgerhold.Store) *AuditStore {
+	return &AuditStore{store: store}
+}
+
+// Add records a new audit event. The event is assigned an ID, unless it already has one.
+func (as *AuditStore) Add(ctx context.Context, e *domain.AuditEvent) error {
+	if e.ID == "" {
+		e.ID = fmt.Sprintf("%s-%08x", timestampPrefix(e.Timestamp), rand.Uint32())
+	}
+	return as.store.Insert(e.ID, e)
+}
+
+// GetAll returns the page of audit events matching the filter selected by the list options, ordered
+// by time, and the token used to retrieve the next page.
+// If filter is not specified, return all audit events.
+func (as *AuditStore) GetAll(ctx context.Context, filter *domain.AuditFilter, opts *domain.ListOptions) ([]*domain.AuditEvent, string, error) {
+	result := []*domain.AuditEvent{}
+	var query *badgerhold.Query
+	where := func(field string) *badgerhold.Criterion {
+		if query == nil {
+			return badgerhold.Where(field)
+		}
+		return query.And(field)
+	}
+
+	if filter != nil {
+		if filter.Projects != nil && len(filter.Projects) == 0 {
+			return result, "", nil
+		}
+		// the IDs start with the event timestamp, so the time filters are applied to the IDs
+		if filter.Since != nil {
+			query = where("ID").Ge(timestampPrefix(*filter.Since))
+		}
+		if filter.Until != nil {
+			query = where("ID").Lt(timestampPrefix(*filter.Until))
+		}
+		if filter.Service != nil {
+			query = where("Service").Eq(*filter.Service)
+		}
+		if filter.Resource != nil {
+			query = where("Resource").Eq(*filter.Resource)
+		}
+		if filter.Projects != nil {
+			projects := make([]interface{}, 0, len(filter.Projects))
+			for _, p := range filter.Projects {
+				projects = append(projects, p)
+			}
+			query = where("Project").In(projects...)
+		}
+	}
+	if query == nil {
+		query = &badgerhold.Query{}
+	}
+
+	// the IDs start with the event timestamp, so sorting by ID orders the events by time
+	query, err := pageQuery(query, opts, "timestamp", map[string][]string{
+		"timestamp": {"ID"},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if err := as.store.Find(&result, query); err != nil {
+		return nil, "", err
+	}
+	end, next := pageEnd(len(result), opts)
+	return result[:end], next, nil
+}
+
+// DeleteBefore removes the audit events recorded before the given time, returning their number.
+func (as *AuditStore) DeleteBefore(ctx context.Context, t time.Time) (int, error) {
+	query := badgerhold.Where("ID").Lt(timestampPrefix(t))
+	n, err := as.store.Count(&domain.AuditEvent{}, query)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, as.store.DeleteMatching(&domain.AuditEvent{}, query)
+}
+
+// timestampPrefix returns the prefix of the IDs of the events recorded at the given time.
+func timestampPrefix(t time.Time) string {
+	return fmt.Sprintf("%019d", t.UnixNano())
+}
@@ -0,0 +1,124 @@
+package badger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestAuditGetAll(t *testing.T) {
+	store, done := newAuditStore(t)
+	defer done()
+
+	now := time.Now().UTC()
+	events := []*domain.AuditEvent{
+		{Timestamp: now.Add(-2 * time.Hour), Principal: "user1", Service: "codeset", Method: "delete", Project: "prj1", Resource: "cs1", Result: domain.AuditResultSuccess},
+		{Timestamp: now.Add(-1 * time.Hour), Principal: "user2", Service: "workflow", Method: "unassign", Project: "prj2", Resource: "wf1", Result: domain.AuditResultSuccess},
+		{Timestamp: now, Principal: "user1", Service: "codeset", Method: "register", Project: "prj2", Resource: "cs2", Result: domain.AuditResultFailure, Error: "failed"},
+	}
+	// add the events out of order, they are always returned ordered by time
+	for _, i := range []int{2, 0, 1} {
+		assertNoError(t, store.Add(context.TODO(), events[i]))
+	}
+
+	since := now.Add(-90 * time.Minute)
+	until := now.Add(-30 * time.Minute)
+	codeset := "codeset"
+	resource := "cs2"
+
+	tests := []struct {
+		name   string
+		filter *domain.AuditFilter
+		want   []*domain.AuditEvent
+	}{
+		{name: "all", filter: nil, want: events},
+		{name: "by service", filter: &domain.AuditFilter{Service: &codeset}, want: []*domain.AuditEvent{events[0], events[2]}},
+		{name: "by resource", filter: &domain.AuditFilter{Service: &codeset, Resource: &resource}, want: []*domain.AuditEvent{events[2]}},
+		{name: "by time", filter: &domain.AuditFilter{Since: &since, Until: &until}, want: []*domain.AuditEvent{events[1]}},
+		{name: "by project", filter: &domain.AuditFilter{Projects: []string{"prj2"}}, want: []*domain.AuditEvent{events[1], events[2]}},
+		{name: "no project", filter: &domain.AuditFilter{Projects: []string{}}, want: []*domain.AuditEvent{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := store.GetAll(context.TODO(), tt.filter, nil)
+			assertNoError(t, err)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Unexpected AuditEvents: %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestAuditGetAllPages(t *testing.T) {
+	store, done := newAuditStore(t)
+	defer done()
+
+	now := time.Now().UTC()
+	events := []*domain.AuditEvent{}
+	for i := 0; i < 5; i++ {
+		e := &domain.AuditEvent{Timestamp: now.Add(time.Duration(i) * time.Minute), Service: "codeset", Method: "register", Project: "prj1"}
+		assertNoError(t, store.Add(context.TODO(), e))
+		events = append(events, e)
+	}
+
+	opts := &domain.ListOptions{Limit: 2, Sort: "-timestamp"}
+	got := []*domain.AuditEvent{}
+	for pages := 0; ; pages++ {
+		if pages > len(events) {
+			t.Fatal("too many pages")
+		}
+		page, next, err := store.GetAll(context.TODO(), &domain.AuditFilter{Projects: []string{"prj1"}}, opts)
+		assertNoError(t, err)
+		got = append(got, page...)
+		if next == "" {
+			break
+		}
+		opts.Continue = next
+	}
+	want := []*domain.AuditEvent{events[4], events[3], events[2], events[1], events[0]}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected AuditEvents: %s", diff.PrintWantGot(d))
+	}
+
+	if _, _, err := store.GetAll(context.TODO(), nil, &domain.ListOptions{Continue: "invalid"}); err == nil {
+		t.Error("expected an error for an invalid continue token")
+	}
+}
+
+func TestAuditDeleteBefore(t *testing.T) {
+	store, done := newAuditStore(t)
+	defer done()
+
+	now := time.Now().UTC()
+	events := []*domain.AuditEvent{
+		{Timestamp: now.Add(-48 * time.Hour), Service: "codeset", Method: "delete"},
+		{Timestamp: now.Add(-25 * time.Hour), Service: "codeset", Method: "register"},
+		{Timestamp: now, Service: "workflow", Method: "assign"},
+	}
+	for _, e := range events {
+		assertNoError(t, store.Add(context.TODO(), e))
+	}
+
+	n, err := store.DeleteBefore(context.TODO(), now.Add(-24*time.Hour))
+	assertNoError(t, err)
+	if n != 2 {
+		t.Errorf("Unexpected number of removed events: got %d, want 2", n)
+	}
+	got, _, err := store.GetAll(context.TODO(), nil, nil)
+	assertNoError(t, err)
+	if d := cmp.Diff(events[2:], got); d != "" {
+		t.Errorf("Unexpected AuditEvents: %s", diff.PrintWantGot(d))
+	}
+}
+
+func newAuditStore(t *testing.T) (*AuditStore, func()) {
+	t.Helper()
+
+	store, done := openTestStore(t)
+	return NewAuditStore(store), done
+}
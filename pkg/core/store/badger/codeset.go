@@ -0,0 +1,105 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/timshannon/badgerhold/v3"
+)
+
+// ExternalCodesetStore is a wrapper around a badgerhold.Store that implements the domain.ExternalCodesetStore interface.
+type ExternalCodesetStore struct {
+	store *badgerhold.Store
+}
+
+// NewExternalCodesetStore creates a new ExternalCodesetStore.
+func NewExternalCodesetStore(store *badgerhold.Store) *ExternalCodesetStore {
+	return &ExternalCodesetStore{store: store}
+}
+
+// codesetKey returns the key under which a codeset is stored
+func codesetKey(project, name string) string {
+	return project + "/" + name
+}
+
+// AddCodeset adds a codeset to the store.
+func (cs *ExternalCodesetStore) AddCodeset(ctx context.Context, c *domain.Codeset) error {
+	err := cs.store.Insert(codesetKey(c.Project, c.Name), c)
+	if err == badgerhold.ErrKeyExists {
+		return domain.ErrCodesetExists
+	}
+	return err
+}
+
+// GetCodeset returns a codeset identified by its project and name.
+func (cs *ExternalCodesetStore) GetCodeset(ctx context.Context, project, name string) (*domain.Codeset, error) {
+	c := &domain.Codeset{}
+	err := cs.store.Get(codesetKey(project, name), c)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return nil, domain.ErrCodesetNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCodesets returns all the codesets, or the codesets of a given project.
+func (cs *ExternalCodesetStore) GetCodesets(ctx context.Context, project *string) ([]*domain.Codeset, error) {
+	result := []*domain.Codeset{}
+	query := &badgerhold.Query{}
+	if project != nil {
+		query = badgerhold.Where("Project").Eq(*project)
+	}
+	err := cs.store.Find(&result, query.SortBy("Project", "Name"))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateCodeset replaces a codeset in the store.
+func (cs *ExternalCodesetStore) UpdateCodeset(ctx context.Context, c *domain.Codeset) error {
+	err := cs.store.Update(codesetKey(c.Project, c.Name), c)
+	if err == badgerhold.ErrNotFound {
+		return domain.ErrCodesetNotFound
+	}
+	return err
+}
+
+// UpdateCodesetHeads records the polled heads of a codeset in a single transaction, so that the changes made
+// to the rest of the codeset while it was being polled are preserved.
+func (cs *ExternalCodesetStore) UpdateCodesetHeads(ctx context.Context, project, name string, heads map[string]string, polled time.Time) error {
+	found := false
+	err := cs.store.UpdateMatching(&domain.Codeset{}, badgerhold.Where(badgerhold.Key).Eq(codesetKey(project, name)), func(record interface{}) error {
+		c, ok := record.(*domain.Codeset)
+		if !ok {
+			return fmt.Errorf("record is not a codeset: %T", record)
+		}
+		found = true
+		if c.Source == nil {
+			return nil
+		}
+		c.Source.Heads = heads
+		c.Source.Polled = polled
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.ErrCodesetNotFound
+	}
+	return nil
+}
+
+// DeleteCodeset deletes a codeset from the store.
+func (cs *ExternalCodesetStore) DeleteCodeset(ctx context.Context, project, name string) error {
+	err := cs.store.Delete(codesetKey(project, name), domain.Codeset{})
+	if err == badgerhold.ErrNotFound {
+		return domain.ErrCodesetNotFound
+	}
+	return err
+}
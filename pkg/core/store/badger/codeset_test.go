@@ -0,0 +1,168 @@
+package badger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func newTestCodeset(project, name string) *domain.Codeset {
+	return &domain.Codeset{
+		Project: project,
+		Name:    name,
+		Labels:  []string{"label"},
+		URL:     "https://github.com/fuseml/" + name,
+		Source: &domain.CodesetSource{
+			URL:      "https://github.com/fuseml/" + name,
+			Mode:     domain.CodesetSourceTrack,
+			Interval: time.Minute,
+			Heads:    map[string]string{"refs/heads/main": "abc"},
+		},
+	}
+}
+
+func TestAddCodeset(t *testing.T) {
+	t.Run("new", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		c := newTestCodeset("prj", "cs")
+		err := store.AddCodeset(context.TODO(), c)
+		assertNoError(t, err)
+
+		got, err := store.GetCodeset(context.TODO(), "prj", "cs")
+		assertNoError(t, err)
+		if d := cmp.Diff(c, got); d != "" {
+			t.Errorf("Unexpected Codeset: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		store.AddCodeset(context.TODO(), newTestCodeset("prj", "cs"))
+		err := store.AddCodeset(context.TODO(), newTestCodeset("prj", "cs"))
+		assertError(t, err, domain.ErrCodesetExists)
+	})
+}
+
+func TestGetCodesets(t *testing.T) {
+	store, done := newExternalCodesetStore(t)
+	defer done()
+
+	codesets := []*domain.Codeset{newTestCodeset("prj1", "cs1"), newTestCodeset("prj1", "cs2"), newTestCodeset("prj2", "cs1")}
+	for _, c := range codesets {
+		store.AddCodeset(context.TODO(), c)
+	}
+
+	got, err := store.GetCodesets(context.TODO(), nil)
+	assertNoError(t, err)
+	if d := cmp.Diff(codesets, got); d != "" {
+		t.Errorf("Unexpected Codesets: %s", diff.PrintWantGot(d))
+	}
+
+	project := "prj1"
+	got, err = store.GetCodesets(context.TODO(), &project)
+	assertNoError(t, err)
+	if d := cmp.Diff(codesets[:2], got); d != "" {
+		t.Errorf("Unexpected Codesets: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestUpdateCodeset(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		c := newTestCodeset("prj", "cs")
+		store.AddCodeset(context.TODO(), c)
+		c.Source.Heads["refs/heads/main"] = "def"
+		c.Source.Polled = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		err := store.UpdateCodeset(context.TODO(), c)
+		assertNoError(t, err)
+
+		got, err := store.GetCodeset(context.TODO(), "prj", "cs")
+		assertNoError(t, err)
+		if d := cmp.Diff(c, got); d != "" {
+			t.Errorf("Unexpected Codeset: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		err := store.UpdateCodeset(context.TODO(), newTestCodeset("prj", "cs"))
+		assertError(t, err, domain.ErrCodesetNotFound)
+	})
+}
+
+func TestUpdateCodesetHeads(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		c := newTestCodeset("prj", "cs")
+		store.AddCodeset(context.TODO(), c)
+
+		// the codeset is archived while it is being polled
+		c.Archived = true
+		c.Description = "archived"
+		assertNoError(t, store.UpdateCodeset(context.TODO(), c))
+
+		heads := map[string]string{"refs/heads/main": "def"}
+		when := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+		err := store.UpdateCodesetHeads(context.TODO(), "prj", "cs", heads, when)
+		assertNoError(t, err)
+
+		got, err := store.GetCodeset(context.TODO(), "prj", "cs")
+		assertNoError(t, err)
+		c.Source.Heads, c.Source.Polled = heads, when
+		if d := cmp.Diff(c, got); d != "" {
+			t.Errorf("Unexpected Codeset: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		err := store.UpdateCodesetHeads(context.TODO(), "prj", "cs", map[string]string{}, time.Now())
+		assertError(t, err, domain.ErrCodesetNotFound)
+	})
+}
+
+func TestDeleteCodeset(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		store.AddCodeset(context.TODO(), newTestCodeset("prj", "cs"))
+		err := store.DeleteCodeset(context.TODO(), "prj", "cs")
+		assertNoError(t, err)
+
+		_, err = store.GetCodeset(context.TODO(), "prj", "cs")
+		assertError(t, err, domain.ErrCodesetNotFound)
+	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newExternalCodesetStore(t)
+		defer done()
+
+		err := store.DeleteCodeset(context.TODO(), "prj", "cs")
+		assertError(t, err, domain.ErrCodesetNotFound)
+	})
+}
+
+func newExternalCodesetStore(t *testing.T) (*ExternalCodesetStore, func()) {
+	t.Helper()
+
+	store, done := openTestStore(t)
+	return NewExternalCodesetStore(store), done
+}
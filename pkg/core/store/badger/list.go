@@ -0,0 +1,46 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// pageQuery sorts the records matching the query by the field selected by the list options, or by
+// defaultField if none is selected, and restricts them to the selected page. The fields map the
+// fields that the items can be sorted by to the record fields used to sort them. One record more
+// than the page limit is queried, to find out whether there is a next page.
+func pageQuery(query *badgerhold.Query, opts *domain.ListOptions, defaultField string, fields map[string][]string) (*badgerhold.Query, error) {
+	field, desc := opts.SortField(defaultField)
+	sortBy, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("the items cannot be sorted by %q", field)
+	}
+	offset, err := opts.Offset()
+	if err != nil {
+		return nil, err
+	}
+	query = query.SortBy(sortBy...)
+	if desc {
+		query = query.Reverse()
+	}
+	if offset > 0 {
+		query = query.Skip(offset)
+	}
+	if opts.Paged() {
+		query = query.Limit(opts.Limit + 1)
+	}
+	return query, nil
+}
+
+// pageEnd returns the number of records, out of the n records returned by a query built with
+// pageQuery, that belong to the page, and the continue token of the next page, if there is one.
+func pageEnd(n int, opts *domain.ListOptions) (end int, next string) {
+	if !opts.Paged() || n <= opts.Limit {
+		return n, ""
+	}
+	offset, _ := opts.Offset()
+	return opts.Limit, domain.NewContinueToken(offset + opts.Limit)
+}
@@ -0,0 +1,79 @@
+package badger
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/timshannon/badgerhold/v3"
+)
+
+// ProjectMemberStore is a wrapper around a badgerhold.Store that implements the domain.ProjectMemberStore interface.
+type ProjectMemberStore struct {
+	store *badgerhold.Store
+}
+
+// NewProjectMemberStore creates a new ProjectMemberStore.
+func NewProjectMemberStore(store *badgerhold.Store) *ProjectMemberStore {
+	return &ProjectMemberStore{store: store}
+}
+
+// projectMemberKey returns the key under which the membership of a user in a project is stored
+func projectMemberKey(project, user string) string {
+	return project + "/" + user
+}
+
+// GetMember returns the membership of a user in a project.
+func (ps *ProjectMemberStore) GetMember(ctx context.Context, project, user string) (*domain.ProjectMember, error) {
+	member := &domain.ProjectMember{}
+	err := ps.store.Get(projectMemberKey(project, user), member)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return nil, domain.ErrProjectMemberNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// GetMembers returns the members of a project.
+func (ps *ProjectMemberStore) GetMembers(ctx context.Context, project string) ([]*domain.ProjectMember, error) {
+	result := []*domain.ProjectMember{}
+	err := ps.store.Find(&result, badgerhold.Where("Project").Eq(project).SortBy("User"))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetMemberships returns the memberships of a user in all projects.
+func (ps *ProjectMemberStore) GetMemberships(ctx context.Context, user string) ([]*domain.ProjectMember, error) {
+	result := []*domain.ProjectMember{}
+	err := ps.store.Find(&result, badgerhold.Where("User").Eq(user).SortBy("Project"))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetMember adds a user to a project or changes the role of an existing member.
+func (ps *ProjectMemberStore) SetMember(ctx context.Context, member *domain.ProjectMember) (*domain.ProjectMember, error) {
+	err := ps.store.Upsert(projectMemberKey(member.Project, member.User), member)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a user from a project.
+func (ps *ProjectMemberStore) RemoveMember(ctx context.Context, project, user string) error {
+	err := ps.store.Delete(projectMemberKey(project, user), domain.ProjectMember{})
+	if err == badgerhold.ErrNotFound {
+		return domain.ErrProjectMemberNotFound
+	}
+	return err
+}
+
+// RemoveProject removes all the members of a project.
+func (ps *ProjectMemberStore) RemoveProject(ctx context.Context, project string) error {
+	return ps.store.DeleteMatching(&domain.ProjectMember{}, badgerhold.Where("Project").Eq(project))
+}
@@ -0,0 +1,126 @@
+package badger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestSetMember(t *testing.T) {
+	t.Run("new", func(t *testing.T) {
+		store, done := newProjectMemberStore(t)
+		defer done()
+
+		member := domain.ProjectMember{Project: "prj", User: "user", Role: domain.ProjectRoleViewer}
+		_, err := store.SetMember(context.TODO(), &member)
+		assertNoError(t, err)
+
+		got, err := store.GetMember(context.TODO(), "prj", "user")
+		assertNoError(t, err)
+		if d := cmp.Diff(&member, got); d != "" {
+			t.Errorf("Unexpected ProjectMember: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		store, done := newProjectMemberStore(t)
+		defer done()
+
+		store.SetMember(context.TODO(), &domain.ProjectMember{Project: "prj", User: "user", Role: domain.ProjectRoleViewer})
+		member := domain.ProjectMember{Project: "prj", User: "user", Role: domain.ProjectRoleAdmin}
+		_, err := store.SetMember(context.TODO(), &member)
+		assertNoError(t, err)
+
+		got, err := store.GetMembers(context.TODO(), "prj")
+		assertNoError(t, err)
+		want := []*domain.ProjectMember{&member}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected ProjectMembers: %s", diff.PrintWantGot(d))
+		}
+	})
+}
+
+func TestGetMemberships(t *testing.T) {
+	store, done := newProjectMemberStore(t)
+	defer done()
+
+	members := []*domain.ProjectMember{
+		{Project: "prj1", User: "user1", Role: domain.ProjectRoleAdmin},
+		{Project: "prj2", User: "user1", Role: domain.ProjectRoleViewer},
+		{Project: "prj1", User: "user2", Role: domain.ProjectRoleEditor},
+	}
+	for _, m := range members {
+		store.SetMember(context.TODO(), m)
+	}
+
+	got, err := store.GetMemberships(context.TODO(), "user1")
+	assertNoError(t, err)
+	if d := cmp.Diff(members[:2], got); d != "" {
+		t.Errorf("Unexpected ProjectMembers: %s", diff.PrintWantGot(d))
+	}
+
+	got, err = store.GetMembers(context.TODO(), "prj1")
+	assertNoError(t, err)
+	want := []*domain.ProjectMember{members[0], members[2]}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected ProjectMembers: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newProjectMemberStore(t)
+		defer done()
+
+		store.SetMember(context.TODO(), &domain.ProjectMember{Project: "prj", User: "user", Role: domain.ProjectRoleViewer})
+		err := store.RemoveMember(context.TODO(), "prj", "user")
+		assertNoError(t, err)
+
+		_, err = store.GetMember(context.TODO(), "prj", "user")
+		assertError(t, err, domain.ErrProjectMemberNotFound)
+	})
+
+	t.Run("non-existing", func(t *testing.T) {
+		store, done := newProjectMemberStore(t)
+		defer done()
+
+		err := store.RemoveMember(context.TODO(), "prj", "user")
+		assertError(t, err, domain.ErrProjectMemberNotFound)
+	})
+}
+
+func TestRemoveProject(t *testing.T) {
+	store, done := newProjectMemberStore(t)
+	defer done()
+
+	store.SetMember(context.TODO(), &domain.ProjectMember{Project: "prj1", User: "user1", Role: domain.ProjectRoleAdmin})
+	store.SetMember(context.TODO(), &domain.ProjectMember{Project: "prj1", User: "user2", Role: domain.ProjectRoleViewer})
+	other := &domain.ProjectMember{Project: "prj2", User: "user1", Role: domain.ProjectRoleEditor}
+	store.SetMember(context.TODO(), other)
+
+	err := store.RemoveProject(context.TODO(), "prj1")
+	assertNoError(t, err)
+
+	got, err := store.GetMembers(context.TODO(), "prj1")
+	assertNoError(t, err)
+	if len(got) != 0 {
+		t.Errorf("Expected no members, got %v", got)
+	}
+
+	got, err = store.GetMemberships(context.TODO(), "user1")
+	assertNoError(t, err)
+	if d := cmp.Diff([]*domain.ProjectMember{other}, got); d != "" {
+		t.Errorf("Unexpected ProjectMembers: %s", diff.PrintWantGot(d))
+	}
+}
+
+func newProjectMemberStore(t *testing.T) (*ProjectMemberStore, func()) {
+	t.Helper()
+
+	store, done := openTestStore(t)
+	return NewProjectMemberStore(store), done
+}
@@ -0,0 +1,49 @@
+package badger
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/timshannon/badgerhold/v3"
+)
+
+// ProjectQuotaStore is a wrapper around a badgerhold.Store that implements the domain.ProjectQuotaStore interface.
+type ProjectQuotaStore struct {
+	store *badgerhold.Store
+}
+
+// NewProjectQuotaStore creates a new ProjectQuotaStore.
+func NewProjectQuotaStore(store *badgerhold.Store) *ProjectQuotaStore {
+	return &ProjectQuotaStore{store: store}
+}
+
+// GetQuota returns the quota of a project, without any limit if it was not set.
+func (qs *ProjectQuotaStore) GetQuota(ctx context.Context, project string) (*domain.ProjectQuota, error) {
+	quota := &domain.ProjectQuota{}
+	err := qs.store.Get(project, quota)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return &domain.ProjectQuota{Project: project}, nil
+		}
+		return nil, err
+	}
+	return quota, nil
+}
+
+// SetQuota sets the quota of a project.
+func (qs *ProjectQuotaStore) SetQuota(ctx context.Context, quota *domain.ProjectQuota) (*domain.ProjectQuota, error) {
+	err := qs.store.Upsert(quota.Project, quota)
+	if err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+// RemoveQuota removes the quota of a project.
+func (qs *ProjectQuotaStore) RemoveQuota(ctx context.Context, project string) error {
+	err := qs.store.Delete(project, domain.ProjectQuota{})
+	if err == badgerhold.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package badger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestProjectQuota(t *testing.T) {
+	store, done := newProjectQuotaStore(t)
+	defer done()
+
+	got, err := store.GetQuota(context.TODO(), "prj")
+	assertNoError(t, err)
+	if d := cmp.Diff(&domain.ProjectQuota{Project: "prj"}, got); d != "" {
+		t.Errorf("Unexpected default ProjectQuota: %s", diff.PrintWantGot(d))
+	}
+
+	quota := domain.ProjectQuota{Project: "prj", MaxCodesets: 5, MaxConcurrentRuns: 2, MaxWorkspaceSize: "10Gi"}
+	_, err = store.SetQuota(context.TODO(), &quota)
+	assertNoError(t, err)
+	store.SetQuota(context.TODO(), &domain.ProjectQuota{Project: "other", MaxCodesets: 1})
+
+	got, err = store.GetQuota(context.TODO(), "prj")
+	assertNoError(t, err)
+	if d := cmp.Diff(&quota, got); d != "" {
+		t.Errorf("Unexpected ProjectQuota: %s", diff.PrintWantGot(d))
+	}
+
+	assertNoError(t, store.RemoveQuota(context.TODO(), "prj"))
+	assertNoError(t, store.RemoveQuota(context.TODO(), "prj"))
+	got, err = store.GetQuota(context.TODO(), "prj")
+	assertNoError(t, err)
+	if d := cmp.Diff(&domain.ProjectQuota{Project: "prj"}, got); d != "" {
+		t.Errorf("Unexpected ProjectQuota after removal: %s", diff.PrintWantGot(d))
+	}
+}
+
+func newProjectQuotaStore(t *testing.T) (*ProjectQuotaStore, func()) {
+	t.Helper()
+
+	store, done := openTestStore(t)
+	return NewProjectQuotaStore(store), done
+}
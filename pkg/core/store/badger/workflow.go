@@ -27,8 +27,9 @@ func (ws *WorkflowStore) GetWorkflow(ctx context.Context, name string) (*domain.
 	return wf, nil
 }
 
-// GetWorkflows returns all workflows or the one that matches a given name.
-func (ws *WorkflowStore) GetWorkflows(ctx context.Context, name *string) []*domain.Workflow {
+// GetWorkflows returns the page of workflows selected by the list options, or the one that matches a given name,
+// and the token used to retrieve the next page.
+func (ws *WorkflowStore) GetWorkflows(ctx context.Context, name *string, opts *domain.ListOptions) ([]*domain.Workflow, string, error) {
 	result := []*domain.Workflow{}
 	if name != nil {
 		wf := &domain.Workflow{}
@@ -36,14 +37,21 @@ func (ws *WorkflowStore) GetWorkflows(ctx context.Context, name *string) []*doma
 		if err == nil {
 			result = append(result, wf)
 		}
-		return result
+		return result, "", nil
 	}
 
-	err := ws.store.Find(&result, nil)
+	query, err := pageQuery(&badgerhold.Query{}, opts, "name", map[string][]string{
+		"name":    {"Name"},
+		"created": {"Created", "Name"},
+	})
 	if err != nil {
-		return result
+		return nil, "", err
 	}
-	return result
+	if err := ws.store.Find(&result, query); err != nil {
+		return nil, "", err
+	}
+	end, next := pageEnd(len(result), opts)
+	return result[:end], next, nil
 }
 
 // AddWorkflow adds a new workflow based on the Workflow structure provided as argument.
@@ -116,16 +124,17 @@ func (ws *WorkflowStore) GetAllCodesetAssignments(ctx context.Context, workflowN
 	return
 }
 
-// AddCodesetAssignment adds a codeset to the list of assigned codesets of a workflow if it does not already exists.
+// AddCodesetAssignment adds a codeset to the list of assigned codesets of a workflow, or updates its webhook and ref
+// filter if it is already assigned.
 func (ws *WorkflowStore) AddCodesetAssignment(ctx context.Context, workflowName string, codeset *domain.Codeset,
-	webhookID *int64) ([]*domain.CodesetAssignment, error) {
+	webhookID *int64, refs *domain.CodesetRefFilter) ([]*domain.CodesetAssignment, error) {
 	wf := domain.Workflow{}
 	err := ws.store.Get(workflowName, &wf)
 	if err != nil {
 		return nil, domain.ErrWorkflowNotFound
 	}
 
-	err = wf.AssignToCodeset(ctx, codeset, webhookID)
+	err = wf.AssignToCodeset(ctx, codeset, webhookID, refs)
 	if err != nil {
 		return nil, err
 	}
@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/tektoncd/pipeline/test/diff"
 	"github.com/timshannon/badgerhold/v3"
 
@@ -47,7 +49,7 @@ func TestGetWorkflows(t *testing.T) {
 
 		// empty
 		want := []*domain.Workflow{}
-		got := store.GetWorkflows(context.TODO(), nil)
+		got, _, _ := store.GetWorkflows(context.TODO(), nil, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
@@ -62,7 +64,7 @@ func TestGetWorkflows(t *testing.T) {
 		}
 
 		// should return all
-		got = store.GetWorkflows(context.TODO(), nil)
+		got, _, _ = store.GetWorkflows(context.TODO(), nil, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
@@ -75,7 +77,7 @@ func TestGetWorkflows(t *testing.T) {
 		// empty
 		want := []*domain.Workflow{}
 		name := "test"
-		got := store.GetWorkflows(context.TODO(), &name)
+		got, _, _ := store.GetWorkflows(context.TODO(), &name, nil)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
 		}
@@ -90,18 +92,71 @@ func TestGetWorkflows(t *testing.T) {
 		}
 
 		// should return one workflow
-		got = store.GetWorkflows(context.TODO(), &want[0].Name)
+		got, _, _ = store.GetWorkflows(context.TODO(), &want[0].Name, nil)
 		if d := cmp.Diff(want[0], got[0]); d != "" {
 			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
 		}
 
 		// should return no workflows
 		name = "no-wf"
-		got = store.GetWorkflows(context.TODO(), &name)
+		got, _, _ = store.GetWorkflows(context.TODO(), &name, nil)
 		if d := cmp.Diff([]*domain.Workflow{}, got); d != "" {
 			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
 		}
 	})
+
+	t.Run("paged", func(t *testing.T) {
+		store, done := newWorkflowStore(t)
+		defer done()
+
+		// add workflows, created in the reverse order of their names
+		want := []*domain.Workflow{}
+		created := time.Now()
+		for i := 0; i < 5; i++ {
+			wf := domain.Workflow{Name: fmt.Sprintf("test-%d", i), Created: created.Add(-time.Duration(i) * time.Minute)}
+			_, err := store.AddWorkflow(context.TODO(), &wf)
+			assertNoError(t, err)
+
+			want = append(want, &wf)
+		}
+
+		// should return all the workflows, two at a time
+		opts := &domain.ListOptions{Limit: 2}
+		for _, page := range [][]*domain.Workflow{want[0:2], want[2:4], want[4:]} {
+			got, next, err := store.GetWorkflows(context.TODO(), nil, opts)
+			assertNoError(t, err)
+			if d := cmp.Diff(page, got, cmpopts.EquateApproxTime(0)); d != "" {
+				t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
+			}
+			opts.Continue = next
+		}
+		if opts.Continue != "" {
+			t.Errorf("Unexpected continue token after the last page: %q", opts.Continue)
+		}
+
+		// should return the workflows by creation time
+		got, next, err := store.GetWorkflows(context.TODO(), nil, &domain.ListOptions{Limit: 3, Sort: "created"})
+		assertNoError(t, err)
+		if d := cmp.Diff([]*domain.Workflow{want[4], want[3], want[2]}, got, cmpopts.EquateApproxTime(0)); d != "" {
+			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
+		}
+		got, _, err = store.GetWorkflows(context.TODO(), nil, &domain.ListOptions{Limit: 3, Continue: next, Sort: "created"})
+		assertNoError(t, err)
+		if d := cmp.Diff([]*domain.Workflow{want[1], want[0]}, got, cmpopts.EquateApproxTime(0)); d != "" {
+			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
+		}
+
+		// should return the workflows in descending order of their names
+		got, _, err = store.GetWorkflows(context.TODO(), nil, &domain.ListOptions{Limit: 1, Sort: "-name"})
+		assertNoError(t, err)
+		if d := cmp.Diff([]*domain.Workflow{want[4]}, got, cmpopts.EquateApproxTime(0)); d != "" {
+			t.Errorf("Unexpected Workflows: %s", diff.PrintWantGot(d))
+		}
+
+		// should fail with an invalid continue token
+		_, _, err = store.GetWorkflows(context.TODO(), nil, &domain.ListOptions{Limit: 2, Continue: "invalid"})
+		assertError(t, err, domain.ErrInvalidContinueToken)
+	})
 }
 
 func TestAddWorkflow(t *testing.T) {
@@ -175,7 +230,7 @@ func TestDeleteWorkflow(t *testing.T) {
 		}
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		err = store.DeleteWorkflow(context.TODO(), wfName)
 		assertError(t, err, domain.ErrCannotDeleteAssignedWorkflow)
@@ -192,7 +247,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		_, err := store.AddCodesetAssignment(context.TODO(), "", &cs, &webhookID)
+		_, err := store.AddCodesetAssignment(context.TODO(), "", &cs, &webhookID, nil)
 		assertError(t, err, domain.ErrWorkflowNotFound)
 	})
 
@@ -211,7 +266,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -236,7 +291,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -244,7 +299,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 			t.Errorf("Unexpected Assignments: %s", diff.PrintWantGot(d))
 		}
 
-		got, err = store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err = store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Assignments: %s", diff.PrintWantGot(d))
@@ -296,7 +351,7 @@ func TestGetCodesetAssignments(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		got := store.GetCodesetAssignments(context.TODO(), wfName)
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -340,7 +395,7 @@ func TestGetAllCodesetAssignments(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		// with name
 		got := store.GetAllCodesetAssignments(context.TODO(), &wfName)
@@ -411,8 +466,8 @@ func TestDeleteCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs1, &webhookID)
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs2, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs1, &webhookID, nil)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs2, &webhookID, nil)
 
 		got, _ := store.DeleteCodesetAssignment(context.TODO(), wfName, &cs1)
 		want := []*domain.CodesetAssignment{{Codeset: &cs2, WebhookID: &webhookID}}
@@ -474,7 +529,7 @@ func TestGetCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		got, err := store.GetCodesetAssignment(context.TODO(), wfName, &cs)
 		assertNoError(t, err)
@@ -504,6 +559,15 @@ func assertNoError(t testing.TB, got error) {
 func newWorkflowStore(t *testing.T) (*WorkflowStore, func()) {
 	t.Helper()
 
+	store, done := openTestStore(t)
+	return NewWorkflowStore(store), done
+}
+
+// openTestStore opens a badgerhold store in a temporary dir, and returns it along with the function that closes
+// and removes it
+func openTestStore(t *testing.T) (*badgerhold.Store, func()) {
+	t.Helper()
+
 	dir := tmpDir(t)
 	opt := badgerhold.DefaultOptions
 	opt.Logger = nil
@@ -515,9 +579,7 @@ func newWorkflowStore(t *testing.T) (*WorkflowStore, func()) {
 		t.Fatalf("failed to open store: %v", err)
 	}
 
-	workflowStore := NewWorkflowStore(store)
-
-	return workflowStore, func() {
+	return store, func() {
 		store.Close()
 		os.RemoveAll(dir)
 	}
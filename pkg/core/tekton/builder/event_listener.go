@@ -1,7 +1,10 @@
 package builder
 
 import (
+	"encoding/json"
+
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,3 +45,42 @@ func (b *EventListenerBuilder) TriggerBinding(templateName string, bindingsName
 		Bindings: bindings,
 	})
 }
+
+// Trigger adds a named EventListenerTrigger to the EventListener spec. The events are run through the
+// interceptors, in order, before being bound to the template.
+func (b *EventListenerBuilder) Trigger(name, templateName, bindingName string, interceptors ...*v1alpha1.EventInterceptor) {
+	b.EventListener.Spec.Triggers = append(b.EventListener.Spec.Triggers, v1alpha1.EventListenerTrigger{
+		Name: name,
+		Template: &v1alpha1.TriggerSpecTemplate{
+			Ref: &templateName,
+		},
+		Bindings: []*v1alpha1.TriggerSpecBinding{{
+			Ref: bindingName,
+		}},
+		Interceptors: interceptors,
+	})
+}
+
+// CELInterceptor returns an interceptor that only lets through the events matching the CEL filter.
+func CELInterceptor(filter string) *v1alpha1.EventInterceptor {
+	return interceptor("cel", "filter", filter)
+}
+
+// GitHubInterceptor returns an interceptor that only lets through the events whose HMAC signature,
+// sent in the X-Hub-Signature header, is computed with the secret stored under secretKey in the
+// secretName Secret.
+func GitHubInterceptor(secretName, secretKey string) *v1alpha1.EventInterceptor {
+	return interceptor("github", "secretRef", &v1alpha1.SecretRef{SecretName: secretName, SecretKey: secretKey})
+}
+
+func interceptor(name, param string, value interface{}) *v1alpha1.EventInterceptor {
+	// the parameter values are strings and structs of strings, they are always marshalled
+	raw, _ := json.Marshal(value)
+	return &v1alpha1.EventInterceptor{
+		Ref: v1alpha1.InterceptorRef{Name: name},
+		Params: []v1alpha1.InterceptorParams{{
+			Name:  param,
+			Value: apiextensionsv1.JSON{Raw: raw},
+		}},
+	}
+}
@@ -51,6 +51,20 @@ func (b *PipelineRunBuilder) ServiceAccount(name string) {
 	b.PipelineRun.Spec.ServiceAccountName = name
 }
 
+// TaskServiceAccount sets the ServiceAccountName used to run the pipeline task with the given name,
+// instead of the one of the PipelineRun.
+func (b *PipelineRunBuilder) TaskServiceAccount(pipelineTaskName, name string) {
+	b.PipelineRun.Spec.TaskRunSpecs = append(b.PipelineRun.Spec.TaskRunSpecs, v1beta1.PipelineTaskRunSpec{
+		PipelineTaskName:       pipelineTaskName,
+		TaskServiceAccountName: name,
+	})
+}
+
+// Pending sets the PipelineRun as pending, so that it is not started until its spec status is cleared.
+func (b *PipelineRunBuilder) Pending() {
+	b.PipelineRun.Spec.Status = v1beta1.PipelineRunSpecStatusPending
+}
+
 // PipelineRef sets a PipelineRef to the PipelineRun spec.
 func (b *PipelineRunBuilder) PipelineRef(name string) {
 	b.PipelineRun.Spec.PipelineRef = &v1beta1.PipelineRef{
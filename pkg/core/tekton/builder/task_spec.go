@@ -66,6 +66,22 @@ func (b *TaskSpecBuilder) Env(name, value string) {
 	})
 }
 
+// EnvFromSecret adds a Env to the TaskSpec step, set from a key of a Secret. The step does not fail to
+// start when the Secret does not exist.
+func (b *TaskSpecBuilder) EnvFromSecret(name, secret, key string) {
+	optional := true
+	b.TaskSpec.Steps[0].Env = append(b.TaskSpec.Steps[0].Env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+				Optional:             &optional,
+			},
+		},
+	})
+}
+
 // Image sets the image on the TaskSpec step.
 func (b *TaskSpecBuilder) Image(image string) {
 	b.TaskSpec.Steps[0].Image = image
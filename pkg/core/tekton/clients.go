@@ -1,14 +1,21 @@
 package tekton
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	pipelineclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1beta1"
 	triggersclient "github.com/tektoncd/triggers/pkg/client/clientset/versioned"
 	"github.com/tektoncd/triggers/pkg/client/clientset/versioned/typed/triggers/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 )
 
 // Clients holds instances of interfaces for making requests to the tekton controllers.
@@ -19,11 +26,14 @@ type clients struct {
 	TriggerTemplateClient v1alpha1.TriggerTemplateInterface
 	TriggerBindingClient  v1alpha1.TriggerBindingInterface
 	EventListenerClient   v1alpha1.EventListenerInterface
+	SecretClient          corev1.SecretInterface
+	ServiceAccountClient  corev1.ServiceAccountInterface
 }
 
 // NewClients instantiates and returns several clientsets required for making requests to
-// tekton. Clients can make requests within namespace.
-func newClients(namespace string) (*clients, error) {
+// tekton, as well as to kubernetes for the secrets used by the tekton resources. Clients can make requests within namespace. The requests are recorded in the metrics
+// and traced as children of the span in the request context.
+func newClients(namespace string, m *metrics.Metrics) (*clients, error) {
 	var err error
 	c := &clients{}
 
@@ -31,6 +41,10 @@ func newClients(namespace string) (*clients, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error getting kubernetes client config: %w", err)
 	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return m.InstrumentTransport("tekton", rt)
+	})
+	cfg.Wrap(tracing.Transport)
 
 	cs, err := pipelineclient.NewForConfig(cfg)
 	if err != nil {
@@ -48,5 +62,35 @@ func newClients(namespace string) (*clients, error) {
 	c.TriggerBindingClient = cst.TriggersV1alpha1().TriggerBindings(namespace)
 	c.EventListenerClient = cst.TriggersV1alpha1().EventListeners(namespace)
 
+	kcs, err := kubeclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client set: %w", err)
+	}
+	c.SecretClient = kcs.CoreV1().Secrets(namespace)
+	c.ServiceAccountClient = kcs.CoreV1().ServiceAccounts(namespace)
+
 	return c, nil
 }
+
+// checkResources checks that the tekton resources can be listed, that is, that the tekton CRDs
+// are installed and reachable with the configured permissions.
+func (c *clients) checkResources(ctx context.Context) error {
+	opts := metav1.ListOptions{Limit: 1}
+	checks := []struct {
+		resource string
+		list     func() error
+	}{
+		{"pipelines", func() error { _, err := c.PipelineClient.List(ctx, opts); return err }},
+		{"pipelineruns", func() error { _, err := c.PipelineRunClient.List(ctx, opts); return err }},
+		{"tasks", func() error { _, err := c.TaskClient.List(ctx, opts); return err }},
+		{"triggertemplates", func() error { _, err := c.TriggerTemplateClient.List(ctx, opts); return err }},
+		{"triggerbindings", func() error { _, err := c.TriggerBindingClient.List(ctx, opts); return err }},
+		{"eventlisteners", func() error { _, err := c.EventListenerClient.List(ctx, opts); return err }},
+	}
+	for _, check := range checks {
+		if err := check.list(); err != nil {
+			return fmt.Errorf("error listing tekton %s: %w", check.resource, err)
+		}
+	}
+	return nil
+}
@@ -1,26 +1,34 @@
 package tekton
 
 const (
-	pipelineRunPrefix         = "fuseml-"
-	pipelineRunServiceAccount = "fuseml-workloads"
-	triggersServiceAccount    = "tekton-triggers"
-	workspaceAccessMode       = "ReadWriteOnce"
-	workspaceSize             = "2Gi"
-	codesetWorkspaceName      = "source"
-	builderTaskName           = "kaniko"
-	builderPrepTaskName       = "builder-prep"
-	cloneTaskName             = "clone"
-	codesetNameParam          = "codeset-name"
-	codesetVersionParam       = "codeset-version"
-	codesetProjectParam       = "codeset-project"
-	codesetURLParam           = "codeset-url"
-	fuseMLRegistry            = "registry.fuseml-registry"
-	fuseMLRegistryLocal       = "127.0.0.1:30500"
-	imageParamName            = "IMAGE"
-	stepOutputVarName         = "TASK_RESULT"
-	inputsVarPrefix           = "FUSEML_"
-	envVarPrefix              = "FUSEML_ENV_"
-	stepDefaultCmd            = "run"
+	pipelineRunPrefix       = "fuseml-"
+	workspaceAccessMode     = "ReadWriteOnce"
+	codesetWorkspaceName    = "source"
+	builderTaskName         = "kaniko"
+	builderPrepTaskName     = "builder-prep"
+	cloneTaskName           = "clone"
+	clonePipelineTaskName   = "clone"
+	codesetNameParam        = "codeset-name"
+	codesetVersionParam     = "codeset-version"
+	codesetProjectParam     = "codeset-project"
+	codesetURLParam         = "codeset-url"
+	codesetRefParam         = "codeset-ref"
+	defaultCodesetBranch    = "main"
+	workflowRunParam        = "fuseml-workflow-run"
+	runCodesetNameParam     = "fuseml-codeset-name"
+	runCodesetProjectParam  = "fuseml-codeset-project"
+	runCodesetVersionParam  = "fuseml-codeset-version"
+	imageParamName          = "IMAGE"
+	stepOutputVarName       = "TASK_RESULT"
+	inputsVarPrefix         = "FUSEML_"
+	envVarPrefix            = "FUSEML_ENV_"
+	stepDefaultCmd          = "run"
+	apiKeyVarName           = "FUSEML_API_KEY"
+	workflowKeySecretKey    = "api-key"
+	runnableBuildPipeline   = "fuseml-runnable-build"
+	runnableBuildPrefix     = "fuseml-build-"
+	runnableDockerfileParam = "dockerfile"
+	runnableImageParam      = "image"
 
 	// LabelCodesetName is the label key for the codeset name
 	LabelCodesetName = "fuseml/codeset-name"
@@ -30,4 +38,8 @@ const (
 	LabelCodesetVersion = "fuseml/codeset-version"
 	// LabelWorkflowRef is the label key for the reference of the workflow
 	LabelWorkflowRef = "fuseml/workflow-ref"
+	// LabelRunnableRef is the label key for the reference of the runnable being built
+	LabelRunnableRef = "fuseml/runnable-ref"
+	// AnnotationCodesetRef is the annotation key for the git ref whose push triggered a run
+	AnnotationCodesetRef = "fuseml/codeset-ref"
 )
@@ -0,0 +1,190 @@
+package tekton
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+// gitCredentialsAnnotation is the annotation tekton uses to select the git server a basic-auth Secret
+// linked to the service account of a task run authenticates to
+const gitCredentialsAnnotation = "tekton.dev/git-0"
+
+// codesetCredentialsSecretName returns the name of the Secret holding the credentials used to access the
+// external repository of a codeset, which is also the name of the service account the runs of the codeset
+// clone it with. Codeset and project names may contain characters not allowed in Secret names, so they
+// are hashed.
+func codesetCredentialsSecretName(codeset *domain.Codeset) string {
+	return fmt.Sprintf("fuseml-codeset-%x", sha1.Sum([]byte(codeset.Project+"/"+codeset.Name)))[:25]
+}
+
+// SetCodesetCredentials stores the credentials used to access the external repository of a codeset as a
+// basic-auth Secret, linked to a service account dedicated to the codeset. Only the clone task of the runs
+// of that codeset uses the service account, so the credentials are not exposed to the other runs.
+// Implements the domain.CodesetCredentialsStore interface.
+func (w *WorkflowBackend) SetCodesetCredentials(ctx context.Context, codeset *domain.Codeset, username, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.SetCodesetCredentials",
+		tracing.CodesetKey.String(codeset.Name), tracing.ProjectKey.String(codeset.Project))
+	defer tracing.End(span, &err)
+
+	u, err := url.Parse(codeset.Source.URL)
+	if err != nil {
+		return fmt.Errorf("error parsing codeset source URL: %w", err)
+	}
+	name := codesetCredentialsSecretName(codeset)
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: w.config.Namespace,
+		Labels:    map[string]string{LabelCodesetName: codeset.Name, LabelCodesetProject: codeset.Project},
+	}
+	s := &corev1.Secret{
+		ObjectMeta: meta,
+		Type:       corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(username),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		},
+	}
+	s.Annotations = map[string]string{gitCredentialsAnnotation: u.Scheme + "://" + u.Host}
+
+	logger := w.log(ctx).With(logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	logger.Infof("Setting codeset credentials secret: %s...", name)
+	_, err = w.tektonClients.SecretClient.Create(ctx, s, metav1.CreateOptions{})
+	if k8serr.IsAlreadyExists(err) {
+		_, err = w.tektonClients.SecretClient.Update(ctx, s, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error setting codeset credentials secret %q: %w", name, err)
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: meta,
+		Secrets:    []corev1.ObjectReference{{Name: name}},
+	}
+	logger.Infof("Setting codeset service account: %s...", name)
+	_, err = w.tektonClients.ServiceAccountClient.Create(ctx, sa, metav1.CreateOptions{})
+	if k8serr.IsAlreadyExists(err) {
+		_, err = w.tektonClients.ServiceAccountClient.Update(ctx, sa, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error setting codeset service account %q: %w", name, err)
+	}
+	return nil
+}
+
+// GetCodesetCredentials returns the credentials used to access the external repository of a codeset, which
+// are empty if none are stored
+func (w *WorkflowBackend) GetCodesetCredentials(ctx context.Context, codeset *domain.Codeset) (username, password string, err error) {
+	ctx, span := tracing.Start(ctx, "tekton.GetCodesetCredentials",
+		tracing.CodesetKey.String(codeset.Name), tracing.ProjectKey.String(codeset.Project))
+	defer tracing.End(span, &err)
+
+	name := codesetCredentialsSecretName(codeset)
+	s, err := w.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("error getting codeset credentials secret %q: %w", name, err)
+	}
+	return string(s.Data[corev1.BasicAuthUsernameKey]), string(s.Data[corev1.BasicAuthPasswordKey]), nil
+}
+
+// DeleteCodesetCredentials deletes the Secret holding the credentials used to access the external repository
+// of a codeset and the service account it is linked to
+func (w *WorkflowBackend) DeleteCodesetCredentials(ctx context.Context, codeset *domain.Codeset) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.DeleteCodesetCredentials",
+		tracing.CodesetKey.String(codeset.Name), tracing.ProjectKey.String(codeset.Project))
+	defer tracing.End(span, &err)
+
+	name := codesetCredentialsSecretName(codeset)
+	logger := w.log(ctx).With(logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	logger.Infof("Deleting codeset service account: %s...", name)
+	err = w.tektonClients.ServiceAccountClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting codeset service account %q: %w", name, err)
+		}
+		logger.Infof("Codeset service account %q not found, skipping delete...", name)
+	}
+	logger.Infof("Deleting codeset credentials secret: %s...", name)
+	err = w.tektonClients.SecretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting codeset credentials secret %q: %w", name, err)
+		}
+		logger.Infof("Codeset credentials secret %q not found, skipping delete...", name)
+	}
+	return nil
+}
+
+// codesetCloneServiceAccount returns the name of the service account holding the credentials used to clone
+// the external repository of a codeset, or an empty name if the codeset has no credentials
+func (w *WorkflowBackend) codesetCloneServiceAccount(ctx context.Context, codeset *domain.Codeset) (string, error) {
+	if codeset == nil || !codeset.IsTracked() {
+		return "", nil
+	}
+	name := codesetCredentialsSecretName(codeset)
+	_, err := w.tektonClients.ServiceAccountClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting codeset service account %q: %w", name, err)
+	}
+	return name, nil
+}
+
+// workflowKeySecretName returns the name of the Secret holding the API key used by the steps of a workflow
+func workflowKeySecretName(workflow string) string {
+	return "fuseml-workflow-" + workflow
+}
+
+// setWorkflowKey stores the API key used by the steps of a workflow to call the FuseML API in a Secret
+// read only by the steps of that workflow
+func (w *WorkflowBackend) setWorkflowKey(ctx context.Context, workflow string) error {
+	name := workflowKeySecretName(workflow)
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: w.config.Namespace,
+			Labels:    map[string]string{LabelWorkflowRef: workflow},
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: map[string]string{workflowKeySecretKey: w.keys.WorkflowAPIKey(workflow)},
+	}
+
+	w.log(ctx).With(logging.WorkflowKey, workflow).Infof("Setting workflow API key secret: %s...", name)
+	_, err := w.tektonClients.SecretClient.Create(ctx, s, metav1.CreateOptions{})
+	if k8serr.IsAlreadyExists(err) {
+		_, err = w.tektonClients.SecretClient.Update(ctx, s, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error setting workflow API key secret %q: %w", name, err)
+	}
+	return nil
+}
+
+// deleteWorkflowKey deletes the Secret holding the API key used by the steps of a workflow
+func (w *WorkflowBackend) deleteWorkflowKey(ctx context.Context, workflow string) error {
+	name := workflowKeySecretName(workflow)
+	logger := w.log(ctx).With(logging.WorkflowKey, workflow)
+	logger.Infof("Deleting workflow API key secret: %s...", name)
+	err := w.tektonClients.SecretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting workflow API key secret %q: %w", name, err)
+		}
+		logger.Infof("Workflow API key secret %q not found, skipping delete...", name)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package tekton
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestCodesetCredentials(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+	b.createTestServiceAccount(ctx, t)
+
+	cs := createCodeset(t, 0, 0)
+	cs.Source = &domain.CodesetSource{URL: "https://github.com/fuseml/mlflow-app.git", Mode: domain.CodesetSourceTrack}
+	name := codesetCredentialsSecretName(cs)
+
+	username, password, err := b.GetCodesetCredentials(ctx, cs)
+	assertError(t, err, nil)
+	if username != "" || password != "" {
+		t.Errorf("Expected no credentials, got %q/%q", username, password)
+	}
+
+	for _, password := range []string{"old", "new"} {
+		err = b.SetCodesetCredentials(ctx, cs, "user", password)
+		assertError(t, err, nil)
+	}
+	username, password, err = b.GetCodesetCredentials(ctx, cs)
+	assertError(t, err, nil)
+	if username != "user" || password != "new" {
+		t.Errorf("Unexpected credentials %q/%q", username, password)
+	}
+
+	s, err := b.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	assertError(t, err, nil)
+	assertStrings(t, s.Annotations[gitCredentialsAnnotation], "https://github.com")
+	assertStrings(t, string(s.Type), string(corev1.SecretTypeBasicAuth))
+	// the credentials are only linked to the service account of the codeset, not the shared one
+	b.assertServiceAccountSecrets(ctx, t, name, []corev1.ObjectReference{{Name: name}})
+	b.assertServiceAccountSecrets(ctx, t, b.config.PipelineRunServiceAccount, []corev1.ObjectReference{{Name: "existing"}})
+	sa, err := b.codesetCloneServiceAccount(ctx, cs)
+	assertError(t, err, nil)
+	assertStrings(t, sa, name)
+
+	err = b.DeleteCodesetCredentials(ctx, cs)
+	assertError(t, err, nil)
+	_, err = b.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("Expected the credentials secret to be deleted, got %v", err)
+	}
+	_, err = b.tektonClients.ServiceAccountClient.Get(ctx, name, metav1.GetOptions{})
+	if !k8serr.IsNotFound(err) {
+		t.Errorf("Expected the codeset service account to be deleted, got %v", err)
+	}
+	sa, err = b.codesetCloneServiceAccount(ctx, cs)
+	assertError(t, err, nil)
+	assertStrings(t, sa, "")
+
+	// deleting missing credentials is a no-op
+	err = b.DeleteCodesetCredentials(ctx, cs)
+	assertError(t, err, nil)
+}
+
+func TestCodesetCredentialsSecretName(t *testing.T) {
+	names := map[string]bool{}
+	for _, cs := range []*domain.Codeset{
+		{Project: "prj", Name: "cs_1"},
+		{Project: "prj", Name: "cs-1"},
+		{Project: "prj-cs", Name: "1"},
+		{Project: "PRJ", Name: "cs-1"},
+	} {
+		name := codesetCredentialsSecretName(cs)
+		if names[name] {
+			t.Errorf("Duplicate secret name %q for codeset %s/%s", name, cs.Project, cs.Name)
+		}
+		names[name] = true
+	}
+}
+
+func (b *WorkflowBackend) createTestServiceAccount(ctx context.Context, t *testing.T) {
+	t.Helper()
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: b.config.PipelineRunServiceAccount, Namespace: b.config.Namespace},
+		Secrets:    []corev1.ObjectReference{{Name: "existing"}},
+	}
+	if _, err := b.tektonClients.ServiceAccountClient.Create(ctx, sa, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (b *WorkflowBackend) assertServiceAccountSecrets(ctx context.Context, t *testing.T, name string, want []corev1.ObjectReference) {
+	t.Helper()
+
+	sa, err := b.tektonClients.ServiceAccountClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, sa.Secrets); d != "" {
+		t.Errorf("Unexpected ServiceAccount secrets: %s", diff.PrintWantGot(d))
+	}
+}
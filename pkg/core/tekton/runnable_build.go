@@ -0,0 +1,159 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/core/tekton/builder"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+// CreateRunnableBuild creates a PipelineRun that clones the runnable sources and builds its container image
+// using the same builder tasks used for workflow steps with image outputs.
+func (w *WorkflowBackend) CreateRunnableBuild(ctx context.Context, build *domain.RunnableBuild) (_ *domain.RunnableBuild, err error) {
+	ctx, span := tracing.Start(ctx, "tekton.CreateRunnableBuild", tracing.RunnableKey.String(build.RunnableID))
+	defer tracing.End(span, &err)
+
+	pipeline, err := w.ensureRunnableBuildPipeline(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneServiceAccount, err := w.codesetCloneServiceAccount(ctx, build.Codeset)
+	if err != nil {
+		return nil, err
+	}
+	pipelineRun := generateRunnableBuildPipelineRun(pipeline, build, cloneServiceAccount, w.config)
+	w.log(ctx).With("runnable", build.RunnableID).Infof("Creating tekton pipeline run for runnable build: %s...", build.RunnableID)
+	pr, err := w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating tekton pipeline run for runnable %q: %w", build.RunnableID, err)
+	}
+	return w.toRunnableBuild(*pr), nil
+}
+
+// GetRunnableBuild returns the runnable build associated with the PipelineRun with the specified name
+func (w *WorkflowBackend) GetRunnableBuild(ctx context.Context, name string) (*domain.RunnableBuild, error) {
+	pr, err := w.tektonClients.PipelineRunClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tekton pipeline run %q: %w", name, err)
+	}
+	return w.toRunnableBuild(*pr), nil
+}
+
+// ensureRunnableBuildPipeline creates the pipeline used to build runnables, or updates it
+// if it already exists
+func (w *WorkflowBackend) ensureRunnableBuildPipeline(ctx context.Context) (*v1beta1.Pipeline, error) {
+	pipeline := generateRunnableBuildPipeline(w.config)
+	current, err := w.tektonClients.PipelineClient.Get(ctx, pipeline.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting tekton pipeline %q: %w", pipeline.Name, err)
+		}
+		w.log(ctx).Infof("Creating tekton pipeline for runnable builds: %s...", pipeline.Name)
+		pipeline, err = w.tektonClients.PipelineClient.Create(ctx, pipeline, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error creating tekton pipeline %q: %w", runnableBuildPipeline, err)
+		}
+		return pipeline, nil
+	}
+
+	pipeline.ResourceVersion = current.ResourceVersion
+	pipeline, err = w.tektonClients.PipelineClient.Update(ctx, pipeline, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error updating tekton pipeline %q: %w", runnableBuildPipeline, err)
+	}
+	return pipeline, nil
+}
+
+func generateRunnableBuildPipeline(cfg config.TektonConfig) *v1beta1.Pipeline {
+	pb := builder.NewPipelineBuilder(runnableBuildPipeline, cfg.Namespace)
+	pb.Description("Builds a runnable container image from source")
+	pb.Workspace(codesetWorkspaceName, false)
+	pb.Resource("source-repo", "git", false)
+	pb.Param(runnableDockerfileParam, "Path to the Dockerfile, relative to the root of the sources")
+	pb.Param(runnableImageParam, "Location where the resulting image is pushed")
+	pb.Task(clonePipelineTaskName, cloneTaskName, nil, map[string]string{codesetWorkspaceName: codesetWorkspaceName},
+		map[string]string{"source-repo": "source-repo"})
+	// the Dockerfile is provided by the sources, so the builder-prep task only needs to
+	// validate it and report its path to the builder task
+	pb.Task("build-prep", builderPrepTaskName, map[string]string{"IMAGE": cfg.BuildPrepImage,
+		"DOCKERFILE": fmt.Sprintf("$(params.%s)", runnableDockerfileParam)},
+		map[string]string{codesetWorkspaceName: codesetWorkspaceName}, nil)
+	pb.Task("build", builderTaskName, map[string]string{"IMAGE": fmt.Sprintf("$(params.%s)", runnableImageParam),
+		"DOCKERFILE": "$(tasks.build-prep.results.DOCKERFILE-PATH)"},
+		map[string]string{codesetWorkspaceName: codesetWorkspaceName}, nil)
+	return &pb.Pipeline
+}
+
+func generateRunnableBuildPipelineRun(p *v1beta1.Pipeline, build *domain.RunnableBuild, cloneServiceAccount string,
+	cfg config.TektonConfig) *v1beta1.PipelineRun {
+	prb := builder.NewPipelineRunBuilder(fmt.Sprintf("%s%s-", runnableBuildPrefix, build.RunnableID))
+	prb.Meta(builder.Label(LabelRunnableRef, build.RunnableID))
+	if build.Codeset != nil {
+		prb.Meta(builder.Label(LabelCodesetName, build.Codeset.Name), builder.Label(LabelCodesetProject, build.Codeset.Project),
+			builder.Label(LabelCodesetVersion, build.Revision))
+	}
+	prb.ServiceAccount(cfg.PipelineRunServiceAccount)
+	if cloneServiceAccount != "" {
+		prb.TaskServiceAccount(clonePipelineTaskName, cloneServiceAccount)
+	}
+	prb.PipelineRef(p.Name)
+	prb.Param(runnableDockerfileParam, build.Dockerfile)
+	prb.Param(runnableImageParam, fmt.Sprintf("%s/%s", cfg.Registry, build.Image))
+	for _, ws := range p.Spec.Workspaces {
+		prb.Workspace(ws.Name, workspaceAccessMode, cfg.WorkspaceSize)
+	}
+	for _, res := range p.Spec.Resources {
+		if res.Type == "git" {
+			prb.ResourceGit(res.Name, build.SourceURL, build.Revision)
+		}
+	}
+	return &prb.PipelineRun
+}
+
+func (w *WorkflowBackend) toRunnableBuild(p v1beta1.PipelineRun) *domain.RunnableBuild {
+	build := domain.RunnableBuild{
+		Name:       p.ObjectMeta.Name,
+		RunnableID: p.Labels[LabelRunnableRef],
+		Status:     "Unknown",
+	}
+
+	if len(p.Spec.Resources) > 0 {
+		if url := getPipelineResourceParamValue("url", p.Spec.Resources[0]); url != nil {
+			build.SourceURL = *url
+		}
+		if revision := getPipelineResourceParamValue("revision", p.Spec.Resources[0]); revision != nil {
+			build.Revision = *revision
+		}
+	}
+	if dockerfile := getPipelineRunParamValue(runnableDockerfileParam, p.Spec.Params); dockerfile != nil {
+		build.Dockerfile = *dockerfile
+	}
+	if image := getPipelineRunParamValue(runnableImageParam, p.Spec.Params); image != nil {
+		build.Image = strings.TrimPrefix(*image, w.config.Registry+"/")
+	}
+	if name, ok := p.Labels[LabelCodesetName]; ok {
+		build.Codeset = &domain.Codeset{Name: name, Project: p.Labels[LabelCodesetProject], URL: build.SourceURL}
+	}
+
+	if p.Status.StartTime != nil {
+		build.StartTime = p.Status.StartTime.Time
+	}
+	if p.Status.CompletionTime != nil {
+		build.CompletionTime = p.Status.CompletionTime.Time
+	}
+	if len(p.Status.Conditions) > 0 {
+		build.Status = pipelineReasonToWorkflowStatus(p.Status.Conditions[0].Reason)
+	}
+	build.URL = fmt.Sprintf("%s/#/namespaces/%s/pipelineruns/%s", w.config.DashboardURL, w.config.Namespace, build.Name)
+
+	return &build
+}
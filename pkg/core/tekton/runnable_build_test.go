@@ -0,0 +1,71 @@
+package tekton
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestCreateRunnableBuild(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+
+	build := &domain.RunnableBuild{
+		RunnableID: "mlflow-trainer",
+		Codeset:    &domain.Codeset{Name: "mlflow-app-01", Project: "workspace"},
+		SourceURL:  "http://gitea.10.160.5.140.nip.io/workspace/mlflow-app-01.git",
+		Revision:   "v1",
+		Dockerfile: "docker/Dockerfile",
+		Image:      "runnables/mlflow-trainer:v1",
+	}
+	got, err := b.CreateRunnableBuild(ctx, build)
+	if err != nil {
+		t.Fatalf("Failed to create runnable build: %s", err)
+	}
+
+	pipeline, err := b.tektonClients.PipelineClient.Get(ctx, runnableBuildPipeline, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get runnable build Pipeline: %s", err)
+	}
+	wantTasks := []string{"clone", "build-prep", "build"}
+	if len(pipeline.Spec.Tasks) != len(wantTasks) {
+		t.Fatalf("Expected %d pipeline tasks, got %d", len(wantTasks), len(pipeline.Spec.Tasks))
+	}
+	for i, task := range pipeline.Spec.Tasks {
+		assertStrings(t, task.Name, wantTasks[i])
+	}
+	assertStrings(t, pipeline.Spec.Tasks[1].TaskRef.Name, builderPrepTaskName)
+	assertStrings(t, pipeline.Spec.Tasks[2].TaskRef.Name, builderTaskName)
+
+	runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list PipelineRuns: %s", err)
+	}
+	if len(runs.Items) != 1 {
+		t.Fatalf("Expected 1 PipelineRun, got %d", len(runs.Items))
+	}
+	run := runs.Items[0]
+	assertStrings(t, run.GenerateName, fmt.Sprintf("%s%s-", runnableBuildPrefix, build.RunnableID))
+	assertStrings(t, run.Labels[LabelRunnableRef], build.RunnableID)
+	assertStrings(t, *getPipelineRunParamValue(runnableImageParam, run.Spec.Params),
+		"registry.fuseml-registry/runnables/mlflow-trainer:v1")
+	assertStrings(t, *getPipelineRunParamValue(runnableDockerfileParam, run.Spec.Params), build.Dockerfile)
+
+	assertStrings(t, got.RunnableID, build.RunnableID)
+	assertStrings(t, got.Image, build.Image)
+	assertStrings(t, got.SourceURL, build.SourceURL)
+	assertStrings(t, got.Revision, build.Revision)
+	assertStrings(t, got.Codeset.Name, build.Codeset.Name)
+	assertStrings(t, got.Status, "Unknown")
+
+	expectedLog := fmt.Sprintf("Creating tekton pipeline for runnable builds: %s...\n"+
+		"Creating tekton pipeline run for runnable build: %s...\n", runnableBuildPipeline, build.RunnableID)
+	assertStrings(t, logMessages(t, logsOutput), expectedLog)
+
+	t.Run("existing pipeline", func(t *testing.T) {
+		_, err := b.ensureRunnableBuildPipeline(ctx)
+		assertError(t, err, nil)
+	})
+}
@@ -5,27 +5,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"knative.dev/pkg/apis"
 
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/core/tekton/builder"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/metrics"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 const (
-	errDashboardURLMissing = WorkflowBackendErr("value for Tekton Dashboard URL (TEKTON_DASHBOARD_URL) was not provided.")
 	errWaitListenerTimeout = WorkflowBackendErr("time out waiting for listener to become ready")
 )
 
@@ -38,32 +42,51 @@ type EnvVar struct {
 	value string
 }
 
+// runContextParam describes a task parameter used to expose the workflow run context to the step
+// containers as an environment variable
+type runContextParam struct {
+	name   string
+	envVar string
+	value  string
+}
+
 // WorkflowBackend implements the FuseML WorkflowBackend interface for tekton
 type WorkflowBackend struct {
-	dashboardURL  string
-	namespace     string
-	logger        *log.Logger
+	config        config.TektonConfig
+	logger        *zap.SugaredLogger
 	tektonClients *clients
+	keys          domain.WorkflowKeyIssuer
 }
 
 // NewWorkflowBackend initializes Tekton backend
-func NewWorkflowBackend(logger *log.Logger, namespace string) (*WorkflowBackend, error) {
-	dashboardURL, exists := os.LookupEnv("TEKTON_DASHBOARD_URL")
-	if !exists {
-		return nil, errDashboardURLMissing
-	}
-	clients, err := newClients(namespace)
+func NewWorkflowBackend(logger *zap.SugaredLogger, cfg config.TektonConfig, m *metrics.Metrics,
+	keys domain.WorkflowKeyIssuer) (*WorkflowBackend, error) {
+	clients, err := newClients(cfg.Namespace, m)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing tekton workflow backend: %w", err)
 	}
-	return &WorkflowBackend{strings.TrimSuffix(dashboardURL, "/"), namespace, logger, clients}, nil
+	cfg.DashboardURL = strings.TrimSuffix(cfg.DashboardURL, "/")
+	return &WorkflowBackend{cfg, logger, clients, keys}, nil
+}
+
+// log returns the logger for the operations performed while serving the request in the context
+func (w *WorkflowBackend) log(ctx context.Context) *zap.SugaredLogger {
+	return logging.FromContext(ctx, w.logger)
+}
+
+// CheckReadiness checks that the tekton resources used by the backend are reachable
+func (w *WorkflowBackend) CheckReadiness(ctx context.Context) error {
+	return w.tektonClients.checkResources(ctx)
 }
 
 // CreateWorkflow receives a FuseML workflow and creates a Tekton pipeline from it
-func (w *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
-	pipeline := generatePipeline(*workflow, w.namespace)
-	w.logger.Printf("Creating tekton pipeline for workflow: %s...", workflow.Name)
-	_, err := w.tektonClients.PipelineClient.Create(ctx, pipeline, metav1.CreateOptions{})
+func (w *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.Workflow) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.CreateWorkflow", tracing.WorkflowKey.String(workflow.Name))
+	defer tracing.End(span, &err)
+
+	pipeline := generatePipeline(*workflow, w.config)
+	w.log(ctx).With(logging.WorkflowKey, workflow.Name).Infof("Creating tekton pipeline for workflow: %s...", workflow.Name)
+	_, err = w.tektonClients.PipelineClient.Create(ctx, pipeline, metav1.CreateOptions{})
 	if err != nil {
 		if k8serr.IsAlreadyExists(err) {
 			return domain.ErrWorkflowExists
@@ -71,44 +94,88 @@ func (w *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.W
 		return fmt.Errorf("error creating tekton pipeline for workflow %q: %w", workflow.Name, err)
 	}
 
-	return nil
+	return w.setWorkflowKey(ctx, workflow.Name)
 }
 
 // DeleteWorkflow deletes a tekton pipeline with the specified name
-func (w *WorkflowBackend) DeleteWorkflow(ctx context.Context, name string) error {
-	w.logger.Printf("Deleting tekton pipeline: %s...", name)
-	err := w.tektonClients.PipelineClient.Delete(ctx, name, metav1.DeleteOptions{})
+func (w *WorkflowBackend) DeleteWorkflow(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.DeleteWorkflow", tracing.WorkflowKey.String(name))
+	defer tracing.End(span, &err)
+
+	logger := w.log(ctx).With(logging.WorkflowKey, name)
+	logger.Infof("Deleting tekton pipeline: %s...", name)
+	err = w.tektonClients.PipelineClient.Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return fmt.Errorf("error deleting tekton pipeline %q: %w", name, err)
 		}
-		w.logger.Printf("Tekton pipeline %q not found, skipping delete...", name)
+		logger.Infof("Tekton pipeline %q not found, skipping delete...", name)
 	}
-	return nil
+	return w.deleteWorkflowKey(ctx, name)
+}
+
+// CreateWorkflowRun creates a PipelineRun with its default values for the specified workflow and codeset revision
+// pushed to the given ref, or for the default branch of the codeset when they are empty
+func (w *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	ref, revision string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.CreateWorkflowRun", tracing.WorkflowKey.String(workflowName), tracing.ProjectKey.String(codeset.Project), tracing.CodesetKey.String(codeset.Name))
+	defer tracing.End(span, &err)
+
+	return w.createWorkflowRun(ctx, workflowName, codeset, ref, revision, false)
+}
+
+// QueueWorkflowRun creates a pending PipelineRun, like CreateWorkflowRun, which is started by StartWorkflowRun
+func (w *WorkflowBackend) QueueWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	ref, revision string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.QueueWorkflowRun", tracing.WorkflowKey.String(workflowName), tracing.ProjectKey.String(codeset.Project), tracing.CodesetKey.String(codeset.Name))
+	defer tracing.End(span, &err)
+
+	return w.createWorkflowRun(ctx, workflowName, codeset, ref, revision, true)
 }
 
-// CreateWorkflowRun creates a PipelineRun with its default values for the specified workflow and codeset
-func (w *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset) error {
+// createWorkflowRun creates a PipelineRun for the codeset revision, which is pending if it is queued
+func (w *WorkflowBackend) createWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	ref, revision string, queued bool) error {
 	pipeline, err := w.tektonClients.PipelineClient.Get(ctx, workflowName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("error getting tekton pipeline %q: %w", workflowName, err)
 	}
 
-	pipelineRun, err := generatePipelineRun(pipeline, codeset)
+	if revision == "" {
+		revision = defaultCodesetBranch
+		if codeset.Source != nil && codeset.Source.DefaultBranch != "" {
+			revision = codeset.Source.DefaultBranch
+		}
+		ref = "refs/heads/" + revision
+	}
+	cloneServiceAccount, err := w.codesetCloneServiceAccount(ctx, codeset)
+	if err != nil {
+		return err
+	}
+	pipelineRun, err := generatePipelineRun(pipeline, codeset, ref, revision, cloneServiceAccount, w.config)
 	if err != nil {
 		return fmt.Errorf("error generating tekton pipeline run for workflow %q: %w", workflowName, err)
 	}
 
-	w.logger.Printf("Creating tekton pipeline run for workflow: %s...", workflowName)
-	_, err = w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
+	logger := w.log(ctx).With(logging.WorkflowKey, workflowName, logging.ProjectKey, codeset.Project, logging.CodesetKey, codeset.Name)
+	if queued {
+		pipelineRun.Spec.Status = v1beta1.PipelineRunSpecStatusPending
+		logger = logger.With("queued", true)
+	}
+	logger.Infof("Creating tekton pipeline run for workflow: %s...", workflowName)
+	pr, err := w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("error creating tekton pipeline run %q: %w", pipelineRun.Name, err)
 	}
+	logger.Infow("Created tekton pipeline run", logging.RunKey, pr.Name)
 	return nil
 }
 
-// GetWorkflowRuns returns a list of WorkflowRun for the given Workflow
-func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+// GetWorkflowRuns returns the page of WorkflowRun for the given Workflow selected by the list options, and the
+// token used to retrieve the next page. The pipeline runs are paged by Kubernetes when they are listed in the
+// order in which they are stored (by name) and are not filtered by status.
+func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter,
+	opts *domain.ListOptions) ([]*domain.WorkflowRun, string, error) {
 	labelSelector := fmt.Sprintf("%s=%s", LabelWorkflowRef, wf.Name)
 	if filter.CodesetName != "" {
 		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetName, filter.CodesetName)
@@ -116,47 +183,111 @@ func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workfl
 	if filter.CodesetProject != "" {
 		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetProject, filter.CodesetProject)
 	}
-	runs, err := w.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	listOpts := metav1.ListOptions{LabelSelector: labelSelector}
+	field, desc := opts.SortField("name")
+	paged := opts.Paged() && len(filter.Status) == 0 && field == "name" && !desc
+	if paged {
+		cont, err := opts.BackendContinue()
+		if err != nil {
+			return nil, "", err
+		}
+		listOpts.Limit = int64(opts.Limit)
+		listOpts.Continue = cont
+	}
+	runs, err := w.tektonClients.PipelineRunClient.List(ctx, listOpts)
 	if err != nil {
-		return nil, fmt.Errorf("error getting tekton pipeline run %q: %w", wf.Name, err)
+		return nil, "", fmt.Errorf("error getting tekton pipeline run %q: %w", wf.Name, err)
 	}
 	workflowRuns := []*domain.WorkflowRun{}
 
-	if filter.Status != nil && len(filter.Status) > 0 {
-		for _, run := range runs.Items {
-			if len(run.Status.Conditions) > 0 && util.StringInSlice(
-				pipelineReasonToWorkflowStatus(run.Status.Conditions[0].Reason), filter.Status) {
-				workflowRuns = append(workflowRuns, w.toWorkflowRun(wf, run))
-			}
+	for _, run := range runs.Items {
+		// the pipeline runs that were just created, with no conditions yet, have the Unknown status
+		wfr := w.toWorkflowRun(wf, run)
+		if len(filter.Status) == 0 || util.StringInSlice(wfr.Status, filter.Status) {
+			workflowRuns = append(workflowRuns, wfr)
 		}
-	} else {
-		for _, run := range runs.Items {
-			workflowRuns = append(workflowRuns, w.toWorkflowRun(wf, run))
+	}
+	if paged {
+		return workflowRuns, domain.NewBackendContinueToken(runs.Continue), nil
+	}
+
+	if err := domain.SortWorkflowRuns(workflowRuns, opts); err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(workflowRuns))
+	if err != nil {
+		return nil, "", err
+	}
+	return workflowRuns[start:end], next, nil
+}
+
+// DeleteWorkflowRun deletes the PipelineRun with the specified name
+func (w *WorkflowBackend) DeleteWorkflowRun(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.DeleteWorkflowRun")
+	defer tracing.End(span, &err)
+
+	logger := w.log(ctx).With(logging.RunKey, name)
+	logger.Infof("Deleting tekton pipeline run: %s...", name)
+	err = w.tektonClients.PipelineRunClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting tekton pipeline run %q: %w", name, err)
 		}
+		logger.Infof("Tekton pipeline run %q not found, skipping delete...", name)
 	}
-	return workflowRuns, nil
+	return nil
+}
+
+// StartWorkflowRun starts the PipelineRun with the specified name, if it is pending
+func (w *WorkflowBackend) StartWorkflowRun(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.StartWorkflowRun")
+	defer tracing.End(span, &err)
+
+	run, err := w.tektonClients.PipelineRunClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting tekton pipeline run %q: %w", name, err)
+	}
+	if !run.IsPending() {
+		return nil
+	}
+	w.log(ctx).With(logging.RunKey, name).Infof("Starting tekton pipeline run: %s...", name)
+	run.Spec.Status = ""
+	if _, err = w.tektonClients.PipelineRunClient.Update(ctx, run, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error starting tekton pipeline run %q: %w", name, err)
+	}
+	return nil
 }
 
 // CreateWorkflowListener creates tekton resources required to have a listener ready for triggering the pipeline
-func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*domain.WorkflowListener, error) {
+func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (_ *domain.WorkflowListener, err error) {
+	ctx, span := tracing.Start(ctx, "tekton.CreateWorkflowListener", tracing.WorkflowKey.String(workflowName))
+	defer tracing.End(span, &err)
+
 	pipeline, err := w.tektonClients.PipelineClient.Get(ctx, workflowName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("error getting tekton pipeline %q: %w", workflowName, err)
 	}
 
-	triggerTemplate := generateTriggerTemplate(pipeline)
-	_, err = w.tektonClients.TriggerTemplateClient.Get(ctx, workflowName, metav1.GetOptions{})
+	logger := w.log(ctx).With(logging.WorkflowKey, workflowName)
+	triggerTemplate := generateTriggerTemplate(pipeline, w.config)
+	tt, err := w.tektonClients.TriggerTemplateClient.Get(ctx, workflowName, metav1.GetOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return nil, fmt.Errorf("error getting tekton trigger template %q: %w", workflowName, err)
 		}
-		w.logger.Printf("Creating tekton trigger template for workflow: %s...", workflowName)
-		var tt *v1alpha1.TriggerTemplate
+		logger.Infof("Creating tekton trigger template for workflow: %s...", workflowName)
 		tt, err = w.tektonClients.TriggerTemplateClient.Create(ctx, triggerTemplate, metav1.CreateOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("error creating tekton trigger template %q: %w", workflowName, err)
 		}
 		defer w.tektonDeleteIfError(ctx, &err, tt)
+	} else if !equality.Semantic.DeepEqual(tt.Spec, triggerTemplate.Spec) {
+		// the templates created by previous versions start the pipeline runs right away
+		logger.Infof("Updating tekton trigger template for workflow: %s...", workflowName)
+		tt.Spec = triggerTemplate.Spec
+		if _, err = w.tektonClients.TriggerTemplateClient.Update(ctx, tt, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("error updating tekton trigger template %q: %w", workflowName, err)
+		}
 	}
 
 	triggerBinding := generateTriggerBinding(triggerTemplate)
@@ -165,7 +296,7 @@ func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowNa
 		if !k8serr.IsNotFound(err) {
 			return nil, fmt.Errorf("error getting tekton trigger binding %q: %w", workflowName, err)
 		}
-		w.logger.Printf("Creating tekton trigger binding for workflow: %s...", workflowName)
+		logger.Infof("Creating tekton trigger binding for workflow: %s...", workflowName)
 		var tb *v1alpha1.TriggerBinding
 		tb, err = w.tektonClients.TriggerBindingClient.Create(ctx, triggerBinding, metav1.CreateOptions{})
 		if err != nil {
@@ -174,14 +305,14 @@ func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowNa
 		defer w.tektonDeleteIfError(ctx, &err, tb)
 	}
 
-	eventListener := generateEventListener(triggerTemplate, triggerBinding)
+	eventListener := generateEventListener(triggerTemplate, w.config)
 	var el *v1alpha1.EventListener
 	el, err = w.tektonClients.EventListenerClient.Get(ctx, workflowName, metav1.GetOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return nil, fmt.Errorf("error getting tekton event listener %q: %w", workflowName, err)
 		}
-		w.logger.Printf("Creating tekton event listener for workflow: %s...", workflowName)
+		logger.Infof("Creating tekton event listener for workflow: %s...", workflowName)
 		el, err = w.tektonClients.EventListenerClient.Create(ctx, eventListener, metav1.CreateOptions{})
 		if err != nil {
 			return nil, fmt.Errorf("error creating tekton event listener %q: %w", workflowName, err)
@@ -189,10 +320,13 @@ func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowNa
 		defer w.tektonDeleteIfError(ctx, &err, el)
 	}
 
-	listenerURL := fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", workflowName, w.namespace)
+	listenerURL := fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", workflowName, w.config.Namespace)
 	if timeout > 0 {
 		interval := 1 * time.Second
-		if err = waitFor(w.eventListenerReady(ctx, el.Name), interval, timeout); err != nil {
+		waitCtx, waitSpan := tracing.Start(ctx, "tekton.waitForEventListener")
+		err = waitFor(w.eventListenerReady(waitCtx, el.Name), interval, timeout)
+		tracing.End(waitSpan, &err)
+		if err != nil {
 			return nil, errWaitListenerTimeout
 		}
 
@@ -203,40 +337,44 @@ func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowNa
 		listenerURL = el.Status.Address.URL.String()
 	}
 	available := listenerIsAvailable(el.Status)
-	dashboardURL := fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", w.dashboardURL, w.namespace, el.Name)
+	dashboardURL := fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", w.config.DashboardURL, w.config.Namespace, el.Name)
 	return &domain.WorkflowListener{Name: el.Name, URL: listenerURL, Available: available,
 		DashboardURL: dashboardURL}, nil
 }
 
 // DeleteWorkflowListener deletes all tekton resources associated to the specified listener name
-func (w *WorkflowBackend) DeleteWorkflowListener(ctx context.Context, name string) error {
-	w.logger.Printf("Deleting tekton event listener: %s...", name)
-	err := w.tektonClients.EventListenerClient.Delete(ctx, name, metav1.DeleteOptions{})
+func (w *WorkflowBackend) DeleteWorkflowListener(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.DeleteWorkflowListener", tracing.WorkflowKey.String(name))
+	defer tracing.End(span, &err)
+
+	logger := w.log(ctx).With(logging.WorkflowKey, name)
+	logger.Infof("Deleting tekton event listener: %s...", name)
+	err = w.tektonClients.EventListenerClient.Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return fmt.Errorf("error deleting tekton event listener %q: %w", name, err)
 		}
-		w.logger.Printf("Tekton event listener %q not found, skipping delete...", name)
+		logger.Infof("Tekton event listener %q not found, skipping delete...", name)
 	}
 
-	w.logger.Printf("Deleting tekton trigger binding: %s...", name)
+	logger.Infof("Deleting tekton trigger binding: %s...", name)
 	err = w.tektonClients.TriggerBindingClient.Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return fmt.Errorf("error deleting tekton trigger binding %q: %w", name, err)
 		}
-		w.logger.Printf("Tekton trigger binding %q not found, skipping delete...", name)
+		logger.Infof("Tekton trigger binding %q not found, skipping delete...", name)
 	}
 
-	w.logger.Printf("Deleting tekton trigger template: %s...", name)
+	logger.Infof("Deleting tekton trigger template: %s...", name)
 	err = w.tektonClients.TriggerTemplateClient.Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
 			return fmt.Errorf("error deleting tekton trigger template %q: %w", name, err)
 		}
-		w.logger.Printf("Tekton trigger template %q not found, skipping delete...", name)
+		logger.Infof("Tekton trigger template %q not found, skipping delete...", name)
 	}
-	return nil
+	return w.deleteWebhookSecret(ctx, name)
 }
 
 // GetWorkflowListener returns the listener for a given workflow
@@ -246,7 +384,7 @@ func (w *WorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName
 		return nil, fmt.Errorf("error getting tekton event listener %q: %w", workflowName, err)
 	}
 	available := listenerIsAvailable(el.Status)
-	dashboardURL := fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", w.dashboardURL, w.namespace, el.Name)
+	dashboardURL := fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", w.config.DashboardURL, w.config.Namespace, el.Name)
 	wl = &domain.WorkflowListener{Name: el.Name, Available: available,
 		DashboardURL: dashboardURL}
 	if available {
@@ -290,9 +428,9 @@ func listenerIsAvailable(status v1alpha1.EventListenerStatus) bool {
 	return status.Address.URL != nil
 }
 
-func generatePipeline(w domain.Workflow, namespace string) *v1beta1.Pipeline {
+func generatePipeline(w domain.Workflow, cfg config.TektonConfig) *v1beta1.Pipeline {
 	resolver := newVariablesResolver()
-	pb := builder.NewPipelineBuilder(w.Name, namespace)
+	pb := builder.NewPipelineBuilder(w.Name, cfg.Namespace)
 	// label the pipeline with a reference to the workflow name
 	pb.Meta(builder.Label(LabelWorkflowRef, w.Name))
 	pb.Description(w.Description)
@@ -307,7 +445,7 @@ func generatePipeline(w domain.Workflow, namespace string) *v1beta1.Pipeline {
 		if input.Type == domain.WorkflowIOTypeCodeset {
 			pb.Workspace(codesetWorkspaceName, false)
 			pb.Resource("source-repo", "git", false)
-			pb.Task("clone", cloneTaskName, nil, map[string]string{codesetWorkspaceName: codesetWorkspaceName},
+			pb.Task(clonePipelineTaskName, cloneTaskName, nil, map[string]string{codesetWorkspaceName: codesetWorkspaceName},
 				map[string]string{"source-repo": "source-repo"})
 			pb.Param(codesetNameParam, "Reference to the codeset (git project)")
 			resolver.addReference(fmt.Sprintf("inputs.%s.name", input.Name), fmt.Sprintf("$(params.%s)", codesetNameParam))
@@ -321,6 +459,19 @@ func generatePipeline(w domain.Workflow, namespace string) *v1beta1.Pipeline {
 		}
 	}
 
+	// the workflow run context is passed to the steps, so that they can report it back to FuseML
+	// (e.g. when registering applications)
+	runContext := []runContextParam{{workflowRunParam, envVarPrefix + "WORKFLOW_RUN", "$(context.pipelineRun.name)"}}
+	for _, input := range w.Inputs {
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			runContext = append(runContext,
+				runContextParam{runCodesetNameParam, envVarPrefix + "CODESET_NAME", fmt.Sprintf("$(params.%s)", codesetNameParam)},
+				runContextParam{runCodesetProjectParam, envVarPrefix + "CODESET_PROJECT", fmt.Sprintf("$(params.%s)", codesetProjectParam)},
+				runContextParam{runCodesetVersionParam, envVarPrefix + "CODESET_VERSION", fmt.Sprintf("$(params.%s)", codesetVersionParam)})
+			break
+		}
+	}
+
 	// process the FuseML workflow steps
 STEPS:
 	for _, step := range w.Steps {
@@ -357,7 +508,7 @@ STEPS:
 		}
 
 		envVars := []EnvVar{
-			{envVarPrefix + "WORKFLOW_NAMESPACE", namespace},
+			{envVarPrefix + "WORKFLOW_NAMESPACE", cfg.Namespace},
 			{envVarPrefix + "WORKFLOW_NAME", w.Name},
 		}
 		stepResolver := resolver.clone()
@@ -395,9 +546,12 @@ STEPS:
 		// if the workflow step is not a pipeline task that references an existing TektonTask,
 		// build the task spec from the FuseML workflow step.
 		// generates a v1beta1.TaskSpec from a workflow.WorkflowStep
-		taskSpec := toTektonTaskSpec(step, stepResolver, envVars)
+		taskSpec := toTektonTaskSpec(step, stepResolver, envVars, runContext, workflowKeySecretName(w.Name))
 		taskWs := make(map[string]string)
 		taskParams := make(map[string]string)
+		for _, param := range runContext {
+			taskParams[param.name] = param.value
+		}
 		for _, input := range step.Inputs {
 			// if the step has a codeset as input add the workspace
 			// TODO: for now it only supports 1 workspace
@@ -414,8 +568,8 @@ STEPS:
 			// from the local FuseML registry, replace registry.fuseml-registry with
 			// 127.0.0.1:30500
 			image := resolver.resolve(step.Image)
-			if strings.HasPrefix(image, fuseMLRegistry) {
-				image = strings.Replace(image, fuseMLRegistry, fuseMLRegistryLocal, 1)
+			if strings.HasPrefix(image, cfg.Registry) && cfg.RegistryLocal != "" {
+				image = strings.Replace(image, cfg.Registry, cfg.RegistryLocal, 1)
 			}
 			taskParams[imageParamName] = image
 		}
@@ -424,8 +578,11 @@ STEPS:
 	return &pb.Pipeline
 }
 
-func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset) (*v1beta1.PipelineRun, error) {
-	codesetVersion := "main"
+// generatePipelineRun returns a PipelineRun running the workflow pipeline for a codeset version. When
+// cloneServiceAccount is set, the codeset is cloned with that service account, holding the credentials
+// used to access its external repository.
+func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset, ref, codesetVersion, cloneServiceAccount string,
+	cfg config.TektonConfig) (*v1beta1.PipelineRun, error) {
 	prb := builder.NewPipelineRunBuilder(fmt.Sprintf("%s%s-%s-", pipelineRunPrefix, codeset.Project, codeset.Name))
 
 	for _, param := range p.Spec.Params {
@@ -446,11 +603,15 @@ func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset) (*v1beta1
 	}
 
 	prb.Meta(builder.Label(LabelCodesetName, codeset.Name), builder.Label(LabelCodesetProject, codeset.Project),
-		builder.Label(LabelCodesetVersion, codesetVersion), builder.Label(LabelWorkflowRef, p.Labels[LabelWorkflowRef]))
-	prb.ServiceAccount(pipelineRunServiceAccount)
+		builder.Label(LabelCodesetVersion, codesetVersion), builder.Label(LabelWorkflowRef, p.Labels[LabelWorkflowRef]),
+		builder.Annotation(AnnotationCodesetRef, ref))
+	prb.ServiceAccount(cfg.PipelineRunServiceAccount)
+	if cloneServiceAccount != "" {
+		prb.TaskServiceAccount(clonePipelineTaskName, cloneServiceAccount)
+	}
 	prb.PipelineRef(p.Name)
 	for _, ws := range p.Spec.Workspaces {
-		prb.Workspace(ws.Name, workspaceAccessMode, workspaceSize)
+		prb.Workspace(ws.Name, workspaceAccessMode, cfg.WorkspaceSize)
 	}
 
 	for _, res := range p.Spec.Resources {
@@ -461,7 +622,7 @@ func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset) (*v1beta1
 	return &prb.PipelineRun, nil
 }
 
-func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
+func generateTriggerTemplate(p *v1beta1.Pipeline, cfg config.TektonConfig) *v1alpha1.TriggerTemplate {
 	ttb := builder.NewTriggerTemplateBuilder(p.Name, p.Namespace)
 	prb := builder.NewPipelineRunBuilder(pipelineRunPrefix)
 	resolver := newVariablesResolver()
@@ -480,8 +641,10 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 		case codesetNameParam:
 			ttb.Param(codesetURLParam, "The codeset URL (git repository URL)")
 			resolver.addReference(codesetURLParam, fmt.Sprintf("$(tt.params.%s)", codesetURLParam))
+			ttb.ParamWithDefaultValue(codesetRefParam, "The codeset git ref (branch or tag) that was pushed", "")
 			codesetName = resolver.resolve(param.Name)
-			prb.Meta(builder.Label(LabelCodesetName, codesetName))
+			prb.Meta(builder.Label(LabelCodesetName, codesetName),
+				builder.Annotation(AnnotationCodesetRef, fmt.Sprintf("$(tt.params.%s)", codesetRefParam)))
 		case codesetProjectParam:
 			codesetProject = resolver.resolve(param.Name)
 			prb.Meta(builder.Label(LabelCodesetProject, codesetProject))
@@ -493,7 +656,7 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 	prb.GenerateName(fmt.Sprintf("%s%s-%s-", pipelineRunPrefix, codesetProject, codesetName))
 
 	for _, ws := range p.Spec.Workspaces {
-		prb.Workspace(ws.Name, workspaceAccessMode, workspaceSize)
+		prb.Workspace(ws.Name, workspaceAccessMode, cfg.WorkspaceSize)
 	}
 
 	for _, res := range p.Spec.Resources {
@@ -502,8 +665,11 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 		}
 	}
 
-	prb.ServiceAccount(pipelineRunServiceAccount)
+	prb.ServiceAccount(cfg.PipelineRunServiceAccount)
 	prb.PipelineRef(p.Name)
+	// the pipeline runs triggered by the codeset webhooks are queued, and started by FuseML when the quota of
+	// the codeset project allows it
+	prb.Pending()
 
 	prBytes, err := json.Marshal(prb.PipelineRun)
 	if err != nil {
@@ -517,7 +683,8 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 func generateTriggerBinding(template *v1alpha1.TriggerTemplate) *v1alpha1.TriggerBinding {
 	webhookParamsMap := map[string]string{
 		codesetNameParam:    "$(body.repository.name)",
-		codesetVersionParam: "$(body.commits[0].id)",
+		codesetVersionParam: "$(body.after)",
+		codesetRefParam:     "$(body.ref)",
 		codesetProjectParam: "$(body.repository.owner.username)",
 		codesetURLParam:     "$(body.repository.clone_url)",
 	}
@@ -532,14 +699,17 @@ func generateTriggerBinding(template *v1alpha1.TriggerTemplate) *v1alpha1.Trigge
 	return &tbb.TriggerBinding
 }
 
-func generateEventListener(template *v1alpha1.TriggerTemplate, binding *v1alpha1.TriggerBinding) *v1alpha1.EventListener {
+// generateEventListener returns an event listener without triggers. A trigger is added for each
+// codeset assigned to the workflow, when its webhook secret is set.
+func generateEventListener(template *v1alpha1.TriggerTemplate, cfg config.TektonConfig) *v1alpha1.EventListener {
 	elb := builder.NewEventListenerBuilder(template.Name, template.Namespace)
-	elb.ServiceAccount(triggersServiceAccount)
-	elb.TriggerBinding(template.Name, binding.Name)
+	elb.ServiceAccount(cfg.TriggersServiceAccount)
+	elb.EventListener.Spec.Triggers = []v1alpha1.EventListenerTrigger{}
 	return &elb.EventListener
 }
 
-func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variablesResolver, envVars []EnvVar) v1beta1.TaskSpec {
+func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variablesResolver, envVars []EnvVar, runContext []runContextParam,
+	keySecret string) v1beta1.TaskSpec {
 	tb := builder.NewTaskSpecBuilder(step.Name, step.Image, stepDefaultCmd)
 
 	for _, input := range step.Inputs {
@@ -583,6 +753,13 @@ func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variablesResolver, en
 	for _, envVar := range envVars {
 		tb.Env(envVar.name, envVar.value)
 	}
+	// export the workflow run context, received as task parameters
+	for _, param := range runContext {
+		tb.Param(param.name)
+		tb.Env(param.envVar, fmt.Sprintf("$(params.%s)", param.name))
+	}
+	// the API key authenticating the step to FuseML, e.g. to register applications
+	tb.EnvFromSecret(apiKeyVarName, keySecret, workflowKeySecretKey)
 
 	return tb.TaskSpec
 }
@@ -642,11 +819,14 @@ func (w *WorkflowBackend) toWorkflowRun(wf *domain.Workflow, p v1beta1.PipelineR
 		})
 	}
 	status := "Unknown"
-	if len(p.Status.Conditions) > 0 {
+	if p.IsPending() {
+		status = domain.WorkflowRunQueued
+	} else if len(p.Status.Conditions) > 0 {
 		status = pipelineReasonToWorkflowStatus(p.Status.Conditions[0].Reason)
 	}
 	wfr.Status = status
-	wfr.URL = fmt.Sprintf("%s/#/namespaces/%s/pipelineruns/%s", w.dashboardURL, w.namespace, wfr.Name)
+	wfr.URL = fmt.Sprintf("%s/#/namespaces/%s/pipelineruns/%s", w.config.DashboardURL, w.config.Namespace, wfr.Name)
+	wfr.Ref = p.Annotations[AnnotationCodesetRef]
 
 	return &wfr
 }
@@ -692,15 +872,16 @@ func getPipelineRunResultValue(resultName string, results []v1beta1.PipelineRunR
 
 func (w *WorkflowBackend) tektonDeleteIfError(ctx context.Context, err *error, tektonWorkload interface{}) {
 	if *err != nil {
+		logger := w.log(ctx).With(logging.ErrorKey, *err)
 		switch tw := tektonWorkload.(type) {
 		case *v1alpha1.TriggerTemplate:
-			w.logger.Printf("Deleting TriggerTemplate: %s... (creating listener failed)", tw.Name)
+			logger.Infof("Deleting TriggerTemplate: %s... (creating listener failed)", tw.Name)
 			w.tektonClients.TriggerTemplateClient.Delete(ctx, tw.Name, metav1.DeleteOptions{})
 		case *v1alpha1.TriggerBinding:
-			w.logger.Printf("Deleting TriggerBinding: %s... (creating listener failed)", tw.Name)
+			logger.Infof("Deleting TriggerBinding: %s... (creating listener failed)", tw.Name)
 			w.tektonClients.TriggerBindingClient.Delete(ctx, tw.Name, metav1.DeleteOptions{})
 		case *v1alpha1.EventListener:
-			w.logger.Printf("Deleting EventListener: %s... (creating listener failed)", tw.Name)
+			logger.Infof("Deleting EventListener: %s... (creating listener failed)", tw.Name)
 			w.tektonClients.EventListenerClient.Delete(ctx, tw.Name, metav1.DeleteOptions{})
 		}
 	}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +18,7 @@ import (
 	"github.com/tektoncd/pipeline/test/diff"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	faketriggersclient "github.com/tektoncd/triggers/pkg/client/injection/client/fake"
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -26,9 +26,12 @@ import (
 	v1 "knative.dev/pkg/apis/duck/v1"
 	knalpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 	knbeta1 "knative.dev/pkg/apis/duck/v1beta1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
 )
 
 const (
@@ -41,6 +44,14 @@ const (
 	testNamespace             = "test-namespace"
 )
 
+func TestCheckReadiness(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	if err := b.CheckReadiness(ctx); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
 func TestCreateWorkflow(t *testing.T) {
 	t.Run("new workflow", func(t *testing.T) {
 		ctx, b, logsOutput := initBackend(t)
@@ -51,7 +62,14 @@ func TestCreateWorkflow(t *testing.T) {
 		err := b.CreateWorkflow(ctx, &w)
 
 		assertError(t, err, nil)
-		assertStrings(t, strings.TrimSuffix(logsOutput.String(), "\n"), "Creating tekton pipeline for workflow: mlflow-sklearn-e2e...")
+		assertStrings(t, strings.TrimSuffix(logMessages(t, logsOutput), "\n"), `Creating tekton pipeline for workflow: mlflow-sklearn-e2e...
+Setting workflow API key secret: fuseml-workflow-mlflow-sklearn-e2e...`)
+
+		secret, err := b.tektonClients.SecretClient.Get(ctx, "fuseml-workflow-"+w.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get the workflow API key Secret: %s", err)
+		}
+		assertStrings(t, secret.StringData["api-key"], "key-"+w.Name)
 
 		got, err := b.tektonClients.PipelineClient.Get(ctx, w.Name, metav1.GetOptions{})
 		if err != nil {
@@ -108,9 +126,16 @@ func TestDeleteWorkflow(t *testing.T) {
 		if len(pipelines.Items) > 0 {
 			t.Errorf("Expected 0 Pipeline, got %d", len(pipelines.Items))
 		}
+		secrets, err := b.tektonClients.SecretClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets.Items) > 0 {
+			t.Errorf("Expected 0 Secret, got %d", len(secrets.Items))
+		}
 
-		expectedLog := fmt.Sprintf("Deleting tekton pipeline: %s...\n", w.Name)
-		assertStrings(t, logsOutput.String(), expectedLog)
+		expectedLog := fmt.Sprintf("Deleting tekton pipeline: %s...\nDeleting workflow API key secret: fuseml-workflow-%s...\n", w.Name, w.Name)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 
 	t.Run("skip not found", func(t *testing.T) {
@@ -123,8 +148,10 @@ func TestDeleteWorkflow(t *testing.T) {
 
 		expectedLog := fmt.Sprintf(`Deleting tekton pipeline: %s...
 Tekton pipeline %q not found, skipping delete...
-`, name, name)
-		assertStrings(t, logsOutput.String(), expectedLog)
+Deleting workflow API key secret: fuseml-workflow-%s...
+Workflow API key secret "fuseml-workflow-%s" not found, skipping delete...
+`, name, name, name, name)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 }
 
@@ -145,7 +172,7 @@ func TestCreateWorkflowRun(t *testing.T) {
 		Project: "workspace",
 		URL:     "http://gitea.10.160.5.140.nip.io/workspace/mlflow-app-01.git",
 	}
-	err = b.CreateWorkflowRun(ctx, w.Name, cs)
+	err = b.CreateWorkflowRun(ctx, w.Name, cs, "", "")
 	if err != nil {
 		t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
 	}
@@ -168,8 +195,155 @@ func TestCreateWorkflowRun(t *testing.T) {
 		t.Errorf("Unexpected PipelineRun: %s", diff.PrintWantGot(d))
 	}
 
-	expectedLog := fmt.Sprintf("Creating tekton pipeline run for workflow: %s...\n", w.Name)
-	assertStrings(t, logsOutput.String(), expectedLog)
+	expectedLog := fmt.Sprintf("Creating tekton pipeline run for workflow: %s...\nCreated tekton pipeline run\n", w.Name)
+	assertStrings(t, logMessages(t, logsOutput), expectedLog)
+
+	entries := logEntries(t, logsOutput)
+	for key, want := range map[string]string{logging.WorkflowKey: w.Name, logging.ProjectKey: cs.Project, logging.CodesetKey: cs.Name} {
+		if entries[0][key] != want {
+			t.Errorf("Unexpected %s field: got %v, want %q", key, entries[0][key], want)
+		}
+	}
+}
+
+func TestCreateWorkflowRunCredentials(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := createCodeset(t, 0, 0)
+	cs.Source = &domain.CodesetSource{URL: "https://github.com/fuseml/mlflow-app.git", Mode: domain.CodesetSourceTrack}
+	if err := b.SetCodesetCredentials(ctx, cs, "user", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CreateWorkflowRun(ctx, w.Name, cs, "", ""); err != nil {
+		t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+	}
+
+	runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil || len(runs.Items) != 1 {
+		t.Fatalf("Failed to list PipelineRuns: %v", err)
+	}
+	// only the clone task runs with the service account holding the codeset credentials
+	assertStrings(t, runs.Items[0].Spec.ServiceAccountName, b.config.PipelineRunServiceAccount)
+	want := []v1beta1.PipelineTaskRunSpec{{PipelineTaskName: clonePipelineTaskName, TaskServiceAccountName: codesetCredentialsSecretName(cs)}}
+	if d := cmp.Diff(want, runs.Items[0].Spec.TaskRunSpecs); d != "" {
+		t.Errorf("Unexpected PipelineRun task run specs: %s", diff.PrintWantGot(d))
+	}
+}
+
+func TestCreateWorkflowRunRevision(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       *domain.CodesetSource
+		ref          string
+		revision     string
+		wantRef      string
+		wantRevision string
+	}{
+		{name: "default branch", wantRef: "refs/heads/main", wantRevision: "main"},
+		{name: "external default branch", source: &domain.CodesetSource{DefaultBranch: "master"},
+			wantRef: "refs/heads/master", wantRevision: "master"},
+		{name: "pushed tag", source: &domain.CodesetSource{DefaultBranch: "master"}, ref: "refs/tags/v1", revision: "v1",
+			wantRef: "refs/tags/v1", wantRevision: "v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, b, _ := initBackend(t)
+
+			w := domain.Workflow{}
+			readYaml(t, fuseMLWorkflow, &w)
+			if err := b.CreateWorkflow(ctx, &w); err != nil {
+				t.Fatal(err)
+			}
+
+			cs := createCodeset(t, 0, 0)
+			cs.Source = tt.source
+			if err := b.CreateWorkflowRun(ctx, w.Name, cs, tt.ref, tt.revision); err != nil {
+				t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+			}
+
+			runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+			if err != nil || len(runs.Items) != 1 {
+				t.Fatalf("Failed to list PipelineRuns: %v", err)
+			}
+			assertStrings(t, runs.Items[0].Annotations[AnnotationCodesetRef], tt.wantRef)
+			assertStrings(t, runs.Items[0].Labels[LabelCodesetVersion], tt.wantRevision)
+		})
+	}
+}
+
+func TestDeleteWorkflowRun(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+	cs := &domain.Codeset{Name: "mlflow-app-01", Project: "workspace", URL: "http://gitea.test/workspace/mlflow-app-01.git"}
+	if err := b.CreateWorkflowRun(ctx, w.Name, cs, "", ""); err != nil {
+		t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+	}
+	runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list PipelineRuns: %s", err)
+	}
+	name := runs.Items[0].Name
+	logsOutput.Reset()
+
+	assertError(t, b.DeleteWorkflowRun(ctx, name), nil)
+	runs, err = b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list PipelineRuns: %s", err)
+	}
+	if len(runs.Items) > 0 {
+		t.Errorf("Expected 0 PipelineRun, got %d", len(runs.Items))
+	}
+
+	// deleting a run that does not exist is not an error
+	assertError(t, b.DeleteWorkflowRun(ctx, name), nil)
+	expectedLog := fmt.Sprintf(`Deleting tekton pipeline run: %s...
+Deleting tekton pipeline run: %s...
+Tekton pipeline run %q not found, skipping delete...
+`, name, name, name)
+	assertStrings(t, logMessages(t, logsOutput), expectedLog)
+}
+
+func TestStartWorkflowRun(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+	cs := &domain.Codeset{Name: "mlflow-app-01", Project: "workspace", URL: "http://gitea.test/workspace/mlflow-app-01.git"}
+	if err := b.QueueWorkflowRun(ctx, w.Name, cs, "", ""); err != nil {
+		t.Fatalf("Failed to queue workflow run %q: %s", w.Name, err)
+	}
+
+	queued := domain.WorkflowRunFilter{Status: []string{domain.WorkflowRunQueued}}
+	got, _, err := b.GetWorkflowRuns(ctx, &w, &queued, nil)
+	assertError(t, err, nil)
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 queued WorkflowRun, got %d", len(got))
+	}
+	logsOutput.Reset()
+
+	assertError(t, b.StartWorkflowRun(ctx, got[0].Name), nil)
+	// starting a run that is not pending has no effect
+	assertError(t, b.StartWorkflowRun(ctx, got[0].Name), nil)
+	got, _, err = b.GetWorkflowRuns(ctx, &w, &queued, nil)
+	assertError(t, err, nil)
+	if len(got) != 0 {
+		t.Errorf("Expected 0 queued WorkflowRun, got %d", len(got))
+	}
+	assertStrings(t, logMessages(t, logsOutput), "Starting tekton pipeline run: ...\n")
 }
 
 func TestGetWorkflowRuns(t *testing.T) {
@@ -202,11 +376,12 @@ func TestGetWorkflowRuns(t *testing.T) {
 				CompletionTime: completionTime,
 				Status:         runStatus,
 				URL:            "http://tekton.test/#/namespaces/test-namespace/pipelineruns/" + runName,
+				Ref:            "refs/heads/main",
 			})
 		}
 
 		filter := domain.WorkflowRunFilter{}
-		got, err := b.GetWorkflowRuns(ctx, &w, &filter)
+		got, _, err := b.GetWorkflowRuns(ctx, &w, &filter, nil)
 		if err != nil {
 			t.Fatalf("Failed to list PipelineRun: %s", err)
 		}
@@ -244,13 +419,14 @@ func TestGetWorkflowRuns(t *testing.T) {
 				CompletionTime: completionTime,
 				Status:         runStatus,
 				URL:            "http://tekton.test/#/namespaces/test-namespace/pipelineruns/" + runName,
+				Ref:            "refs/heads/main",
 			})
 			codesets = append(codesets, cs)
 		}
 
 		filterNil := domain.WorkflowRunFilter{}
 		want := wants
-		got, err := b.GetWorkflowRuns(ctx, &w, &filterNil)
+		got, _, err := b.GetWorkflowRuns(ctx, &w, &filterNil, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -260,7 +436,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		filterEmptyCsName := domain.WorkflowRunFilter{CodesetName: ""}
 		want = wants
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterEmptyCsName)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterEmptyCsName, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -270,7 +446,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		filterEmptyCsProject := domain.WorkflowRunFilter{CodesetName: ""}
 		want = wants
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterEmptyCsProject)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterEmptyCsProject, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -280,7 +456,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		filterNoResult := domain.WorkflowRunFilter{CodesetName: "do-no-exist"}
 		want = []*domain.WorkflowRun{}
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterNoResult)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterNoResult, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -291,7 +467,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		for i := 0; i < len(codesets); i++ {
 			filterCodesetName := domain.WorkflowRunFilter{CodesetName: codesets[i].Name}
 			want := []*domain.WorkflowRun{wants[i]}
-			got, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetName)
+			got, _, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetName, nil)
 			if err != nil {
 				t.Fatalf("Failed to list WorkflowRun: %s", err)
 			}
@@ -304,7 +480,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		for i := 0; i < len(codesets); i++ {
 			filterCodesetProject := domain.WorkflowRunFilter{CodesetProject: codesets[i].Project}
 			want := []*domain.WorkflowRun{wants[i]}
-			got, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetProject)
+			got, _, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetProject, nil)
 			if err != nil {
 				t.Fatalf("Failed to list WorkflowRun: %s", err)
 			}
@@ -317,7 +493,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		for i := 0; i < len(codesets); i++ {
 			filterCodesetNameProject := domain.WorkflowRunFilter{CodesetName: codesets[i].Name, CodesetProject: codesets[i].Project}
 			want := []*domain.WorkflowRun{wants[i]}
-			got, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetNameProject)
+			got, _, err := b.GetWorkflowRuns(ctx, &w, &filterCodesetNameProject, nil)
 			if err != nil {
 				t.Fatalf("Failed to list WorkflowRun: %s", err)
 			}
@@ -361,12 +537,13 @@ func TestGetWorkflowRuns(t *testing.T) {
 				CompletionTime: completionTime,
 				Status:         status,
 				URL:            "http://tekton.test/#/namespaces/test-namespace/pipelineruns/" + runName,
+				Ref:            "refs/heads/main",
 			})
 		}
 
 		filterNil := domain.WorkflowRunFilter{Status: nil}
 		want := wants
-		got, err := b.GetWorkflowRuns(ctx, &w, &filterNil)
+		got, _, err := b.GetWorkflowRuns(ctx, &w, &filterNil, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -376,7 +553,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		filterEmpty := domain.WorkflowRunFilter{Status: []string{}}
 		want = wants
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterEmpty)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterEmpty, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -386,7 +563,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 
 		filterNoResult := domain.WorkflowRunFilter{Status: []string{"Timeout"}}
 		want = []*domain.WorkflowRun{}
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterNoResult)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterNoResult, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -397,7 +574,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		for i := 0; i < len(runsStatus); i++ {
 			filterStatus := domain.WorkflowRunFilter{Status: []string{pipelineReasonToWorkflowStatus(runsStatus[i])}}
 			want := []*domain.WorkflowRun{wants[i]}
-			got, err := b.GetWorkflowRuns(ctx, &w, &filterStatus)
+			got, _, err := b.GetWorkflowRuns(ctx, &w, &filterStatus, nil)
 			if err != nil {
 				t.Fatalf("Failed to list WorkflowRun: %s", err)
 			}
@@ -411,7 +588,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 			filterMultipleStatus.Status = append(filterMultipleStatus.Status, pipelineReasonToWorkflowStatus(runsStatus[i]))
 		}
 		want = wants
-		got, err = b.GetWorkflowRuns(ctx, &w, &filterMultipleStatus)
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterMultipleStatus, nil)
 		if err != nil {
 			t.Fatalf("Failed to list WorkflowRun: %s", err)
 		}
@@ -419,6 +596,19 @@ func TestGetWorkflowRuns(t *testing.T) {
 			t.Errorf("Unexpected WorkflowRun: %s", diff.PrintWantGot(d))
 		}
 
+		// a pipeline run that was just created has no conditions yet
+		if err := b.CreateWorkflowRun(ctx, w.Name, createCodeset(t, 9, 9), "", ""); err != nil {
+			t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+		}
+		filterUnknown := domain.WorkflowRunFilter{Status: []string{"Unknown"}}
+		got, _, err = b.GetWorkflowRuns(ctx, &w, &filterUnknown, nil)
+		if err != nil {
+			t.Fatalf("Failed to list WorkflowRun: %s", err)
+		}
+		if len(got) != 2 || got[0].Name != "" || got[1].Name != wants[0].Name {
+			t.Errorf("Unexpected WorkflowRuns: %v", got)
+		}
+
 	})
 
 }
@@ -442,9 +632,9 @@ func TestCreateWorkflowListener(t *testing.T) {
 		wantAvailable := false
 		wantListener := domain.WorkflowListener{
 			Name:         w.Name,
-			URL:          fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", w.Name, b.namespace),
+			URL:          fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", w.Name, b.config.Namespace),
 			Available:    wantAvailable,
-			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.dashboardURL, b.namespace, w.Name),
+			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.config.DashboardURL, b.config.Namespace, w.Name),
 		}
 
 		if d := cmp.Diff(wantListener, *wfListener); d != "" {
@@ -456,7 +646,7 @@ Creating tekton trigger binding for workflow: mlflow-sklearn-e2e...
 Creating tekton event listener for workflow: mlflow-sklearn-e2e...
 `
 
-		assertStrings(t, logsOutput.String(), expectedLog)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 
 		gotTriggerTemplate, err := b.tektonClients.TriggerTemplateClient.Get(ctx, w.Name, metav1.GetOptions{})
 		if err != nil {
@@ -527,9 +717,9 @@ Creating tekton event listener for workflow: mlflow-sklearn-e2e...
 		wantAvailable := false
 		wantListener := domain.WorkflowListener{
 			Name:         w.Name,
-			URL:          fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", w.Name, b.namespace),
+			URL:          fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", w.Name, b.config.Namespace),
 			Available:    wantAvailable,
-			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.dashboardURL, b.namespace, w.Name),
+			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.config.DashboardURL, b.config.Namespace, w.Name),
 		}
 
 		if d := cmp.Diff(wantListener, *wfListener); d != "" {
@@ -537,7 +727,47 @@ Creating tekton event listener for workflow: mlflow-sklearn-e2e...
 		}
 
 		expectedLog := ""
-		assertStrings(t, logsOutput.String(), expectedLog)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
+	})
+
+	t.Run("outdated trigger template", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = b.CreateWorkflowListener(ctx, w.Name, 0)
+		if err != nil {
+			t.Fatalf("Failed to create listener for workflow %q: %s", w.Name, err)
+		}
+		tt, err := b.tektonClients.TriggerTemplateClient.Get(ctx, w.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := tt.Spec.ResourceTemplates[0]
+		tt.Spec.ResourceTemplates[0].Raw = []byte("{}")
+		if _, err := b.tektonClients.TriggerTemplateClient.Update(ctx, tt, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		logsOutput.Reset()
+
+		_, err = b.CreateWorkflowListener(ctx, w.Name, 0)
+		assertError(t, err, nil)
+
+		tt, err = b.tektonClients.TriggerTemplateClient.Get(ctx, w.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(string(want.Raw), string(tt.Spec.ResourceTemplates[0].Raw)); d != "" {
+			t.Errorf("Unexpected TriggerTemplate ResourceTemplate: %s", diff.PrintWantGot(d))
+		}
+		expectedLog := fmt.Sprintf("Updating tekton trigger template for workflow: %s...\n", w.Name)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 
 	t.Run("clean if fail", func(t *testing.T) {
@@ -586,7 +816,7 @@ Deleting EventListener: mlflow-sklearn-e2e... (creating listener failed)
 Deleting TriggerBinding: mlflow-sklearn-e2e... (creating listener failed)
 Deleting TriggerTemplate: mlflow-sklearn-e2e... (creating listener failed)
 `
-		assertStrings(t, logsOutput.String(), expectedLog)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 
 }
@@ -639,8 +869,10 @@ func TestDeleteWorkflowListener(t *testing.T) {
 		expectedLog := fmt.Sprintf(`Deleting tekton event listener: %s...
 Deleting tekton trigger binding: %s...
 Deleting tekton trigger template: %s...
-`, wfListener.Name, wfListener.Name, wfListener.Name)
-		assertStrings(t, logsOutput.String(), expectedLog)
+Deleting webhook secret: %s-webhook...
+Webhook secret "%s-webhook" not found, skipping delete...
+`, wfListener.Name, wfListener.Name, wfListener.Name, wfListener.Name, wfListener.Name)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 
 	t.Run("skip not found", func(t *testing.T) {
@@ -656,8 +888,10 @@ Deleting tekton trigger binding: %s...
 Tekton trigger binding %q not found, skipping delete...
 Deleting tekton trigger template: %s...
 Tekton trigger template %q not found, skipping delete...
-`, name, name, name, name, name, name)
-		assertStrings(t, logsOutput.String(), expectedLog)
+Deleting webhook secret: %s-webhook...
+Webhook secret "%s-webhook" not found, skipping delete...
+`, name, name, name, name, name, name, name, name)
+		assertStrings(t, logMessages(t, logsOutput), expectedLog)
 	})
 }
 
@@ -681,7 +915,7 @@ func TestGetWorkflowListener(t *testing.T) {
 		available := false
 		if i == 1 {
 			available = true
-			url = fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", listenerName, b.namespace)
+			url = fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", listenerName, b.config.Namespace)
 		}
 
 		b.createTestListener(ctx, t, listenerName, available)
@@ -689,7 +923,7 @@ func TestGetWorkflowListener(t *testing.T) {
 			Name:         listenerName,
 			URL:          url,
 			Available:    available,
-			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.dashboardURL, b.namespace, w.Name),
+			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", b.config.DashboardURL, b.config.Namespace, w.Name),
 		})
 	}
 
@@ -726,11 +960,41 @@ func initBackend(t *testing.T) (context context.Context, backend *WorkflowBacken
 
 	context, _ = rtesting.SetupFakeContext(t)
 	logsOutput = &bytes.Buffer{}
-	logger := log.New(logsOutput, "", 0)
+	logger, err := logging.New(logsOutput, logging.FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
 	backend = fakeNewWorkflowBackend(context, t, logger, testNamespace)
 	return
 }
 
+// logEntries returns the decoded JSON log lines written by the backend
+func logEntries(t *testing.T, logsOutput *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	entries := []map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(logsOutput.Bytes()))
+	for decoder.More() {
+		entry := map[string]interface{}{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("Failed to decode log line: %s", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// logMessages returns the messages of the log lines written by the backend, one per line
+func logMessages(t *testing.T, logsOutput *bytes.Buffer) string {
+	t.Helper()
+
+	messages := strings.Builder{}
+	for _, entry := range logEntries(t, logsOutput) {
+		messages.WriteString(fmt.Sprintf("%v\n", entry["msg"]))
+	}
+	return messages.String()
+}
+
 func readYaml(t *testing.T, path string, obj interface{}) {
 	t.Helper()
 
@@ -763,19 +1027,33 @@ func newFakeClients(context context.Context, t *testing.T, namespace string) *cl
 	pcs := fakepipelineclient.Get(context)
 	fc.PipelineClient = pcs.TektonV1beta1().Pipelines(namespace)
 	fc.PipelineRunClient = pcs.TektonV1beta1().PipelineRuns(namespace)
+	fc.TaskClient = pcs.TektonV1beta1().Tasks(namespace)
 
 	tcs := faketriggersclient.Get(context)
 	fc.TriggerTemplateClient = tcs.TriggersV1alpha1().TriggerTemplates(namespace)
 	fc.TriggerBindingClient = tcs.TriggersV1alpha1().TriggerBindings(namespace)
 	fc.EventListenerClient = tcs.TriggersV1alpha1().EventListeners(namespace)
+
+	fc.SecretClient = fakekubeclient.Get(context).CoreV1().Secrets(namespace)
+	fc.ServiceAccountClient = fakekubeclient.Get(context).CoreV1().ServiceAccounts(namespace)
 	return fc
 }
 
-func fakeNewWorkflowBackend(context context.Context, t *testing.T, logger *log.Logger, namespace string) *WorkflowBackend {
+func fakeNewWorkflowBackend(context context.Context, t *testing.T, logger *zap.SugaredLogger, namespace string) *WorkflowBackend {
 	t.Helper()
 
 	clients := newFakeClients(context, t, namespace)
-	return &WorkflowBackend{"http://tekton.test", namespace, logger, clients}
+	cfg := config.Default().Tekton
+	cfg.DashboardURL = "http://tekton.test"
+	cfg.Namespace = namespace
+	return &WorkflowBackend{cfg, logger, clients, fakeKeyIssuer{}}
+}
+
+// fakeKeyIssuer issues predictable workflow API keys
+type fakeKeyIssuer struct{}
+
+func (fakeKeyIssuer) WorkflowAPIKey(workflow string) string {
+	return "key-" + workflow
 }
 
 func createCodeset(t *testing.T, nameID, projectID int) *domain.Codeset {
@@ -791,7 +1069,7 @@ func (b WorkflowBackend) createTestWorkflowRun(ctx context.Context, t *testing.T
 	cs *domain.Codeset, runName string, status string, startTime time.Time, completionTime time.Time) {
 	t.Helper()
 
-	err := b.CreateWorkflowRun(ctx, workflow, cs)
+	err := b.CreateWorkflowRun(ctx, workflow, cs, "", "")
 	if err != nil {
 		t.Fatalf("Failed to create workflow run %q: %s", workflow, err)
 	}
@@ -837,7 +1115,7 @@ func (b WorkflowBackend) createTestListener(ctx context.Context, t *testing.T, w
 			t.Fatalf("Failed to get event listener: %s", err)
 		}
 		address := knalpha1.Addressable{Addressable: knbeta1.Addressable{
-			URL: &apis.URL{Scheme: "http", Host: fmt.Sprintf("el-%s.%s.svc.cluster.local:8080", workflow, b.namespace)},
+			URL: &apis.URL{Scheme: "http", Host: fmt.Sprintf("el-%s.%s.svc.cluster.local:8080", workflow, b.config.Namespace)},
 		}}
 		el.Status.AddressStatus.Address = &address
 		el.Status.Conditions = v1.Conditions{apis.Condition{Reason: "MinimumReplicasAvailable", Status: "True", Type: "Available"}}
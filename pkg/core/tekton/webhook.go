@@ -0,0 +1,170 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/core/tekton/builder"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/tracing"
+)
+
+// webhookSecretName returns the name of the Secret holding the webhook secrets of the codesets
+// assigned to a workflow
+func webhookSecretName(workflowName string) string {
+	return workflowName + "-webhook"
+}
+
+// webhookSecretKey returns the key under which the webhook secret of a codeset is stored. Project
+// and codeset names cannot contain dots, so the key is unambiguous.
+func webhookSecretKey(codeset *domain.Codeset) string {
+	return codeset.Project + "." + codeset.Name
+}
+
+// SetWorkflowListenerTrigger stores the secret used to sign the webhook events sent for the codeset
+// and configures the workflow listener to only accept the events for the codeset signed with it and
+// pushed to one of the refs selected by the filter
+func (w *WorkflowBackend) SetWorkflowListenerTrigger(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	secret string, refs *domain.CodesetRefFilter) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.SetWorkflowListenerTrigger", tracing.WorkflowKey.String(workflowName),
+		tracing.CodesetKey.String(codeset.Name), tracing.ProjectKey.String(codeset.Project))
+	defer tracing.End(span, &err)
+
+	name := webhookSecretName(workflowName)
+	key := webhookSecretKey(codeset)
+	s, err := w.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error getting webhook secret %q: %w", name, err)
+		}
+		w.log(ctx).With(logging.WorkflowKey, workflowName).Infof("Creating webhook secret: %s...", name)
+		s = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: w.config.Namespace,
+				Labels:    map[string]string{LabelWorkflowRef: workflowName},
+			},
+			Data: map[string][]byte{key: []byte(secret)},
+		}
+		_, err = w.tektonClients.SecretClient.Create(ctx, s, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("error creating webhook secret %q: %w", name, err)
+		}
+	} else {
+		if s.Data == nil {
+			s.Data = map[string][]byte{}
+		}
+		s.Data[key] = []byte(secret)
+		_, err = w.tektonClients.SecretClient.Update(ctx, s, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("error updating webhook secret %q: %w", name, err)
+		}
+	}
+
+	filter := fmt.Sprintf("body.repository.full_name == '%s/%s'", codeset.Project, codeset.Name)
+	if !refs.IsEmpty() {
+		filter += fmt.Sprintf(" && body.ref.matches(r'%s')", refs.Regexp())
+	}
+	elb := builder.EventListenerBuilder{}
+	elb.Trigger(key, workflowName, workflowName, builder.CELInterceptor(filter), builder.GitHubInterceptor(name, key))
+	return w.setListenerTrigger(ctx, workflowName, key, &elb.EventListener.Spec.Triggers[0])
+}
+
+// DeleteWorkflowListenerTrigger removes the webhook secret of the codeset and its trigger from the
+// workflow listener, so that it no longer accepts the events sent for the codeset
+func (w *WorkflowBackend) DeleteWorkflowListenerTrigger(ctx context.Context, workflowName string,
+	codeset *domain.Codeset) (err error) {
+	ctx, span := tracing.Start(ctx, "tekton.DeleteWorkflowListenerTrigger", tracing.WorkflowKey.String(workflowName),
+		tracing.CodesetKey.String(codeset.Name), tracing.ProjectKey.String(codeset.Project))
+	defer tracing.End(span, &err)
+
+	name := webhookSecretName(workflowName)
+	key := webhookSecretKey(codeset)
+	s, err := w.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error getting webhook secret %q: %w", name, err)
+		}
+	} else if _, ok := s.Data[key]; ok {
+		delete(s.Data, key)
+		_, err = w.tektonClients.SecretClient.Update(ctx, s, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("error updating webhook secret %q: %w", name, err)
+		}
+	}
+	return w.setListenerTrigger(ctx, workflowName, key, nil)
+}
+
+// deleteWebhookSecret deletes the Secret holding the webhook secrets of the codesets assigned to
+// the workflow
+func (w *WorkflowBackend) deleteWebhookSecret(ctx context.Context, workflowName string) error {
+	name := webhookSecretName(workflowName)
+	logger := w.log(ctx).With(logging.WorkflowKey, workflowName)
+	logger.Infof("Deleting webhook secret: %s...", name)
+	err := w.tektonClients.SecretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting webhook secret %q: %w", name, err)
+		}
+		logger.Infof("Webhook secret %q not found, skipping delete...", name)
+	}
+	return nil
+}
+
+// setListenerTrigger replaces the trigger with the given name of the workflow listener, or removes it
+// when the trigger is nil. The triggers are kept sorted by name. The unsigned triggers, created before
+// the webhook events were signed, are removed as well: they accept the events sent for any codeset
+// without checking their signature.
+func (w *WorkflowBackend) setListenerTrigger(ctx context.Context, workflowName, name string,
+	trigger *v1alpha1.EventListenerTrigger) error {
+	el, err := w.tektonClients.EventListenerClient.Get(ctx, workflowName, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) && trigger == nil {
+			return nil
+		}
+		return fmt.Errorf("error getting tekton event listener %q: %w", workflowName, err)
+	}
+
+	triggers := []v1alpha1.EventListenerTrigger{}
+	for _, t := range el.Spec.Triggers {
+		switch {
+		case t.Name == name:
+		case !isSignedTrigger(&t):
+			// the codesets must be assigned to the workflow again, so that their webhooks sign the events
+			w.log(ctx).With(logging.WorkflowKey, workflowName).Infof("Removing unsigned trigger %q from tekton event listener: %s...",
+				t.Name, workflowName)
+		default:
+			triggers = append(triggers, t)
+		}
+	}
+	if trigger != nil {
+		triggers = append(triggers, *trigger)
+		sort.Slice(triggers, func(i, j int) bool { return triggers[i].Name < triggers[j].Name })
+	} else if len(triggers) == len(el.Spec.Triggers) {
+		return nil
+	}
+	el.Spec.Triggers = triggers
+
+	_, err = w.tektonClients.EventListenerClient.Update(ctx, el, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("error updating tekton event listener %q: %w", workflowName, err)
+	}
+	return nil
+}
+
+// isSignedTrigger returns whether a listener trigger only accepts the events signed with a webhook secret
+func isSignedTrigger(t *v1alpha1.EventListenerTrigger) bool {
+	for _, i := range t.Interceptors {
+		if i != nil && i.Ref.Name == "github" {
+			return true
+		}
+	}
+	return false
+}
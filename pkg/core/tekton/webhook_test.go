@@ -0,0 +1,189 @@
+package tekton
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/core/tekton/builder"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestSetWorkflowListenerTrigger(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		codesets := []*domain.Codeset{createCodeset(t, 1, 0), createCodeset(t, 0, 0)}
+		for _, cs := range codesets {
+			err := b.SetWorkflowListenerTrigger(ctx, w.Name, cs, "secret-"+cs.Name, nil)
+			assertError(t, err, nil)
+		}
+
+		b.assertWebhookSecrets(ctx, t, w.Name, map[string]string{
+			"workspace-0.mlflow-app-0": "secret-mlflow-app-0",
+			"workspace-0.mlflow-app-1": "secret-mlflow-app-1",
+		})
+
+		// the triggers are sorted by codeset
+		b.assertListenerTriggers(ctx, t, w.Name, []v1alpha1.EventListenerTrigger{
+			wantTrigger(w.Name, "workspace-0.mlflow-app-0", "body.repository.full_name == 'workspace-0/mlflow-app-0'"),
+			wantTrigger(w.Name, "workspace-0.mlflow-app-1", "body.repository.full_name == 'workspace-0/mlflow-app-1'"),
+		})
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		cs := createCodeset(t, 0, 0)
+		for _, secret := range []string{"old", "new"} {
+			err := b.SetWorkflowListenerTrigger(ctx, w.Name, cs, secret, nil)
+			assertError(t, err, nil)
+		}
+
+		b.assertWebhookSecrets(ctx, t, w.Name, map[string]string{"workspace-0.mlflow-app-0": "new"})
+		b.assertListenerTriggers(ctx, t, w.Name, []v1alpha1.EventListenerTrigger{
+			wantTrigger(w.Name, "workspace-0.mlflow-app-0", "body.repository.full_name == 'workspace-0/mlflow-app-0'"),
+		})
+	})
+
+	t.Run("ref filter", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		cs := createCodeset(t, 0, 0)
+		refs := &domain.CodesetRefFilter{Branches: []string{"main", "release/*"}, Tags: []string{"v?.*"}}
+		err := b.SetWorkflowListenerTrigger(ctx, w.Name, cs, "secret", refs)
+		assertError(t, err, nil)
+
+		b.assertListenerTriggers(ctx, t, w.Name, []v1alpha1.EventListenerTrigger{
+			wantTrigger(w.Name, "workspace-0.mlflow-app-0", "body.repository.full_name == 'workspace-0/mlflow-app-0' && "+
+				`body.ref.matches(r'^(refs/heads/(main|release/.*)|refs/tags/(v.\..*))$')`),
+		})
+	})
+
+	t.Run("remove unsigned triggers", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		// the listeners created before the webhook events were signed have a single unsigned trigger
+		el, err := b.tektonClients.EventListenerClient.Get(ctx, w.Name, metav1.GetOptions{})
+		assertError(t, err, nil)
+		elb := builder.EventListenerBuilder{EventListener: *el}
+		elb.TriggerBinding(w.Name, w.Name)
+		_, err = b.tektonClients.EventListenerClient.Update(ctx, &elb.EventListener, metav1.UpdateOptions{})
+		assertError(t, err, nil)
+
+		err = b.SetWorkflowListenerTrigger(ctx, w.Name, createCodeset(t, 0, 0), "secret", nil)
+		assertError(t, err, nil)
+
+		b.assertListenerTriggers(ctx, t, w.Name, []v1alpha1.EventListenerTrigger{
+			wantTrigger(w.Name, "workspace-0.mlflow-app-0", "body.repository.full_name == 'workspace-0/mlflow-app-0'"),
+		})
+	})
+
+	t.Run("listener not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		err := b.SetWorkflowListenerTrigger(ctx, "unknown", createCodeset(t, 0, 0), "secret", nil)
+		if err == nil {
+			t.Errorf("Expected an error setting the trigger of an unknown listener")
+		}
+	})
+}
+
+func TestDeleteWorkflowListenerTrigger(t *testing.T) {
+	t.Run("delete", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		codesets := []*domain.Codeset{createCodeset(t, 0, 0), createCodeset(t, 1, 0)}
+		for _, cs := range codesets {
+			err := b.SetWorkflowListenerTrigger(ctx, w.Name, cs, "secret", nil)
+			assertError(t, err, nil)
+		}
+
+		err := b.DeleteWorkflowListenerTrigger(ctx, w.Name, codesets[0])
+		assertError(t, err, nil)
+
+		b.assertWebhookSecrets(ctx, t, w.Name, map[string]string{"workspace-0.mlflow-app-1": "secret"})
+		b.assertListenerTriggers(ctx, t, w.Name, []v1alpha1.EventListenerTrigger{
+			wantTrigger(w.Name, "workspace-0.mlflow-app-1", "body.repository.full_name == 'workspace-0/mlflow-app-1'"),
+		})
+
+		// deleting the listener deletes the secret
+		err = b.DeleteWorkflowListener(ctx, w.Name)
+		assertError(t, err, nil)
+		_, err = b.tektonClients.SecretClient.Get(ctx, webhookSecretName(w.Name), metav1.GetOptions{})
+		if !k8serr.IsNotFound(err) {
+			t.Errorf("Expected the webhook secret to be deleted, got %v", err)
+		}
+	})
+
+	t.Run("skip not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflowListener(ctx, t)
+
+		err := b.DeleteWorkflowListenerTrigger(ctx, w.Name, createCodeset(t, 0, 0))
+		assertError(t, err, nil)
+	})
+}
+
+func wantTrigger(workflowName, key, filter string) v1alpha1.EventListenerTrigger {
+	elb := builder.EventListenerBuilder{}
+	elb.Trigger(key, workflowName, workflowName,
+		builder.CELInterceptor(filter),
+		builder.GitHubInterceptor(webhookSecretName(workflowName), key))
+	return elb.EventListener.Spec.Triggers[0]
+}
+
+func (b *WorkflowBackend) createTestWorkflowListener(ctx context.Context, t *testing.T) *domain.Workflow {
+	t.Helper()
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.CreateWorkflowListener(ctx, w.Name, 0)
+	if err != nil {
+		t.Fatalf("Failed to create listener for workflow %q: %s", w.Name, err)
+	}
+	return &w
+}
+
+func (b *WorkflowBackend) assertWebhookSecrets(ctx context.Context, t *testing.T, workflowName string, want map[string]string) {
+	t.Helper()
+
+	s, err := b.tektonClients.SecretClient.Get(ctx, webhookSecretName(workflowName), metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for key, value := range s.Data {
+		got[key] = string(value)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected webhook secrets: %s", diff.PrintWantGot(d))
+	}
+}
+
+func (b *WorkflowBackend) assertListenerTriggers(ctx context.Context, t *testing.T, workflowName string,
+	want []v1alpha1.EventListenerTrigger) {
+	t.Helper()
+
+	el, err := b.tektonClients.EventListenerClient.Get(ctx, workflowName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, el.Spec.Triggers); d != "" {
+		t.Errorf("Unexpected EventListener triggers: %s", diff.PrintWantGot(d))
+	}
+}
@@ -26,19 +26,31 @@ func (ws *WorkflowStore) GetWorkflow(ctx context.Context, name string) (*domain.
 	return nil, domain.ErrWorkflowNotFound
 }
 
-// GetWorkflows returns all workflows or the one that matches a given name.
-func (ws *WorkflowStore) GetWorkflows(ctx context.Context, name *string) (result []*domain.Workflow) {
-	result = []*domain.Workflow{}
+// GetWorkflows returns the page of workflows selected by the list options, or the one that matches a given name,
+// and the token used to retrieve the next page.
+func (ws *WorkflowStore) GetWorkflows(ctx context.Context, name *string, opts *domain.ListOptions) ([]*domain.Workflow, string, error) {
+	result := []*domain.Workflow{}
 	if name != nil {
 		if wf, ok := ws.items[*name]; ok {
 			result = append(result, wf)
 		}
-		return
+		return result, "", nil
 	}
 	for _, wf := range ws.items {
 		result = append(result, wf)
 	}
-	return
+	err := opts.SortItems(result, "name", domain.SortKeys{
+		"name":    func(i int) string { return result[i].Name },
+		"created": func(i int) string { return domain.TimeSortKey(result[i].Created) },
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	start, end, next, err := opts.Paginate(len(result))
+	if err != nil {
+		return nil, "", err
+	}
+	return result[start:end], next, nil
 }
 
 // AddWorkflow adds a new workflow based on the Workflow structure provided as argument
@@ -91,13 +103,13 @@ func (ws *WorkflowStore) GetAllCodesetAssignments(ctx context.Context, workflowN
 
 // AddCodesetAssignment adds a codeset assignment to the list of assigned codesets of a workflow
 func (ws *WorkflowStore) AddCodesetAssignment(ctx context.Context, workflowName string, codeset *domain.Codeset,
-	webhookID *int64) ([]*domain.CodesetAssignment, error) {
+	webhookID *int64, refs *domain.CodesetRefFilter) ([]*domain.CodesetAssignment, error) {
 	wf, ok := ws.items[workflowName]
 	if !ok {
 		return nil, domain.ErrWorkflowNotFound
 	}
 
-	err := wf.AssignToCodeset(ctx, codeset, webhookID)
+	err := wf.AssignToCodeset(ctx, codeset, webhookID, refs)
 	if err != nil {
 		return nil, err
 	}
@@ -2,14 +2,69 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+const (
+	// ApplicationReady indicates that all the application resources are ready and the application URL is reachable
+	ApplicationReady = "ready"
+	// ApplicationDegraded indicates that some of the application resources are missing or not ready, or that
+	// the application URL is not reachable
+	ApplicationDegraded = "degraded"
+	// ApplicationMissing indicates that none of the application resources can be found
+	ApplicationMissing = "missing"
+
+	// ErrApplicationExists describes the error message returned when trying to register an application
+	// with a name that is already in use.
+	ErrApplicationExists = ApplicationErr("application with the specified name already exists")
+	// ErrApplicationNotFound describes the error message returned when trying to update an application
+	// that does not exist.
+	ErrApplicationNotFound = ApplicationErr("could not find an application with the specified name")
+	// ErrApplicationVersionConflict describes the error message returned when trying to update an application
+	// using a version that does not match the version of the stored application.
+	ErrApplicationVersionConflict = ApplicationErr("application version does not match, it was modified in the meantime")
+	// ErrApplicationNamespace describes the error message returned when trying to register an application whose
+	// resources are not located in the FuseML workloads namespace.
+	ErrApplicationNamespace = ApplicationErr("the application resources must be located in the FuseML workloads namespace")
+)
+
+// ApplicationErr are expected errors returned when performing operations on applications
+type ApplicationErr string
+
+// Error returns the error message
+func (e ApplicationErr) Error() string {
+	return string(e)
+}
+
 // ApplicationStore is an inteface to application stores
 type ApplicationStore interface {
 	Find(context.Context, string) *Application
-	GetAll(context.Context, *string, *string) ([]*Application, error)
+	// GetAll returns a page of the applications matching the filter and the token used to retrieve the
+	// next page.
+	GetAll(context.Context, *ApplicationFilter, *ListOptions) ([]*Application, string, error)
+	// Add stores a new application, returning ErrApplicationExists if an application with the same name
+	// is already stored.
 	Add(context.Context, *Application) (*Application, error)
+	// Update replaces a stored application, returning ErrApplicationVersionConflict if the version of
+	// the provided application does not match the version of the stored one. The version is incremented
+	// with every update.
+	Update(context.Context, *Application) (*Application, error)
 	Delete(context.Context, string) error
+	UpdateStatus(context.Context, string, *ApplicationStatus) error
+}
+
+// KubernetesResourceInspector is an interface for objects able to inspect the Kubernetes resources
+// that form an application
+type KubernetesResourceInspector interface {
+	// InspectResource returns whether a Kubernetes resource exists and is ready, along with a message
+	// describing its state, if it is not ready.
+	InspectResource(ctx context.Context, namespace string, resource *KubernetesResource) (found bool, ready bool, message string, err error)
+}
+
+// KubernetesResourceRemover is an interface for deleting the Kubernetes resources of the applications
+type KubernetesResourceRemover interface {
+	// DeleteResource deletes a Kubernetes resource
+	DeleteResource(ctx context.Context, name, namespace, kind string) error
 }
 
 // Application holds the information about the application
@@ -28,6 +83,92 @@ type Application struct {
 	K8sResources []*KubernetesResource
 	// Kubernetes namespace where the resources are located
 	K8sNamespace string
+	// The status of the Application, nil if it was not yet checked
+	Status *ApplicationStatus
+	// Name of the Workflow run that created the Application
+	WorkflowRun string
+	// The codeset used by the Workflow run that created the Application
+	Codeset *ApplicationCodeset
+	// The codeset version (git revision) used by the Workflow run that created the Application
+	CodesetVersion string
+	// The time when the Application was registered
+	CreatedAt time.Time
+	// The version of the Application record, incremented with every update
+	Version uint64
+	// Information about the last time the Application record was replaced by a new registration
+	Replaced *ApplicationReplacement
+}
+
+// ApplicationReplacement records who replaced an application registered with the same name and what was replaced
+type ApplicationReplacement struct {
+	// The time when the Application was replaced
+	ReplacedAt time.Time
+	// Who replaced the Application (the workflow run that registered it or, if unknown, the workflow)
+	ReplacedBy string
+	// The version of the Application record that was replaced
+	PreviousVersion uint64
+	// Name of the Workflow used to create the replaced Application
+	PreviousWorkflow string
+	// Name of the Workflow run that created the replaced Application
+	PreviousWorkflowRun string
+	// The codeset version used to create the replaced Application
+	PreviousCodesetVersion string
+}
+
+// ApplicationCodeset references the codeset used to create an application
+type ApplicationCodeset struct {
+	// The codeset project
+	Project string
+	// The codeset name
+	Name string
+}
+
+// ApplicationFilter describes the criteria used to filter applications. Only applications matching
+// all the criteria that are set are returned.
+type ApplicationFilter struct {
+	// The type of the Application
+	Type *string
+	// Name of the Workflow used to create the Application
+	Workflow *string
+	// Project of the codeset used to create the Application
+	CodesetProject *string
+	// Name of the codeset used to create the Application
+	CodesetName *string
+	// Visible selects the projects whose Applications are returned, the Applications created without
+	// a codeset are always returned
+	Visible ProjectVisibility
+}
+
+// SortApplications orders applications by the field selected by the list options, by name by default.
+func SortApplications(apps []*Application, opts *ListOptions) error {
+	return opts.SortItems(apps, "name", SortKeys{
+		"name":     func(i int) string { return apps[i].Name },
+		"type":     func(i int) string { return apps[i].Type + "/" + apps[i].Name },
+		"workflow": func(i int) string { return apps[i].Workflow + "/" + apps[i].Name },
+	})
+}
+
+// Matches returns true if the application matches all the criteria set in the filter
+func (f *ApplicationFilter) Matches(app *Application) bool {
+	if f == nil {
+		return true
+	}
+	if f.Type != nil && app.Type != *f.Type {
+		return false
+	}
+	if f.Workflow != nil && app.Workflow != *f.Workflow {
+		return false
+	}
+	if f.CodesetProject != nil && (app.Codeset == nil || app.Codeset.Project != *f.CodesetProject) {
+		return false
+	}
+	if f.CodesetName != nil && (app.Codeset == nil || app.Codeset.Name != *f.CodesetName) {
+		return false
+	}
+	if app.Codeset != nil && !f.Visible.Includes(app.Codeset.Project) {
+		return false
+	}
+	return true
 }
 
 // KubernetesResource describes the Kubernetes resource that forms the application
@@ -37,3 +178,13 @@ type KubernetesResource struct {
 	// The kind of Kubernetes resource
 	Kind string
 }
+
+// ApplicationStatus describes the health of an application
+type ApplicationStatus struct {
+	// The state of the Application (ready, degraded or missing)
+	State string
+	// The time when the Application status was last checked
+	LastChecked time.Time
+	// Message describing the reason why the Application is not ready
+	Message string
+}
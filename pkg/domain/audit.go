@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// AuditResultSuccess is the result recorded for operations that succeeded
+	AuditResultSuccess = "success"
+	// AuditResultFailure is the result recorded for operations that failed
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent records an operation that changed the resources managed by FuseML
+type AuditEvent struct {
+	// The unique ID of the event
+	ID string
+	// The time when the operation was performed
+	Timestamp time.Time
+	// The name of the principal that performed the operation
+	Principal string
+	// The service and method called to perform the operation
+	Service string
+	Method  string
+	// The project owning the resource targeted by the operation, if any
+	Project string
+	// The name or ID of the resource targeted by the operation, if known
+	Resource string
+	// A summary of the operation payload, with the secrets redacted
+	Payload string
+	// The result of the operation (success or failure) and the error returned on failure
+	Result string
+	Error  string
+}
+
+// AuditFilter describes the filters that can be applied when listing audit events
+type AuditFilter struct {
+	// Only events recorded at or after this time
+	Since *time.Time
+	// Only events recorded before this time
+	Until *time.Time
+	// Only events recorded for operations performed through this service
+	Service *string
+	// Only events recorded for operations targeting this resource
+	Resource *string
+	// Only events recorded for operations targeting the resources of these projects
+	Projects []string
+}
+
+// Matches returns whether an audit event matches the filter.
+func (f *AuditFilter) Matches(e *AuditEvent) bool {
+	if f == nil {
+		return true
+	}
+	if f.Since != nil && e.Timestamp.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && !e.Timestamp.Before(*f.Until) {
+		return false
+	}
+	if f.Service != nil && e.Service != *f.Service {
+		return false
+	}
+	if f.Resource != nil && e.Resource != *f.Resource {
+		return false
+	}
+	if f.Projects != nil {
+		for _, p := range f.Projects {
+			if e.Project == p {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// AuditStore is an interface to the stores holding the audit events
+type AuditStore interface {
+	// Add records a new audit event
+	Add(ctx context.Context, e *AuditEvent) error
+	// GetAll returns the page of audit events matching the filter selected by the list options,
+	// ordered by time, and the token used to retrieve the next page
+	GetAll(ctx context.Context, filter *AuditFilter, opts *ListOptions) ([]*AuditEvent, string, error)
+	// DeleteBefore removes the audit events recorded before the given time, returning their number
+	DeleteBefore(ctx context.Context, t time.Time) (int, error)
+}
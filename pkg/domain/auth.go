@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// ErrInvalidCredentials describes the error message returned when the credentials used to authenticate
+	// a request are not valid.
+	ErrInvalidCredentials = AuthErr("invalid credentials")
+	// ErrTokenExpired describes the error message returned when the token used to authenticate a request
+	// has expired.
+	ErrTokenExpired = AuthErr("token expired")
+)
+
+// AuthErr are expected errors returned when authenticating requests
+type AuthErr string
+
+// Error returns the error message
+func (e AuthErr) Error() string {
+	return string(e)
+}
+
+// Principal describes an authenticated caller of the FuseML API
+type Principal struct {
+	// Name of the user or of the API key used to authenticate
+	Name string
+	// Admin is set for principals allowed to perform any operation
+	Admin bool
+	// Workflow is set for the principals authenticated with the API key issued for the steps of a workflow
+	Workflow string
+	// Projects holds the projects of the codesets the workflow of a workflow principal is assigned to, which
+	// are the only projects it may act on, with the editor role
+	Projects []string
+}
+
+// AuthToken describes a token issued for an authenticated user
+type AuthToken struct {
+	// The token value
+	Token string
+	// The principal the token was issued for
+	Principal *Principal
+	// The time when the token expires
+	ExpiresAt time.Time
+}
+
+// UserVerifier is an interface for objects able to verify the credentials of FuseML users
+type UserVerifier interface {
+	// VerifyUser checks the user name and password, returning the principal corresponding to the user
+	// or ErrInvalidCredentials if the credentials are not valid.
+	VerifyUser(ctx context.Context, username, password string) (*Principal, error)
+}
+
+// Authenticator describes the interface used to issue and validate the credentials of FuseML API callers
+type Authenticator interface {
+	// Login verifies the user credentials and issues a token for the user.
+	Login(ctx context.Context, username, password string) (*AuthToken, error)
+	// ValidateToken validates a token issued by Login and returns the principal it was issued for.
+	ValidateToken(ctx context.Context, token string) (*Principal, error)
+	// ValidateAPIKey validates a static API key and returns the principal corresponding to it.
+	ValidateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// WorkflowKeyIssuer is an interface for objects issuing the API keys used by the workflow steps
+type WorkflowKeyIssuer interface {
+	// WorkflowAPIKey returns the API key used by the steps of a workflow to call the FuseML API.
+	WorkflowAPIKey(workflow string) string
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of the context holding the authenticated principal
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in the context, or nil if the
+// context does not hold one
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
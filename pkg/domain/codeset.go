@@ -2,8 +2,65 @@ package domain
 
 import (
 	"context"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 )
 
+const (
+	// ErrCodesetVersionNotFound describes the error message returned when a codeset branch, tag or commit does not exist.
+	ErrCodesetVersionNotFound = CodesetErr("could not find the specified codeset version")
+	// ErrCodesetVersionExists describes the error message returned when trying to tag a codeset version with a tag
+	// that already exists.
+	ErrCodesetVersionExists = CodesetErr("codeset version with the specified tag already exists")
+	// ErrCodesetNotFound describes the error message returned when trying to get a codeset that does not exist.
+	ErrCodesetNotFound = CodesetErr("could not find a codeset with the specified name and project")
+	// ErrCodesetExists describes the error message returned when trying to register a codeset that already exists.
+	ErrCodesetExists = CodesetErr("codeset with the specified name and project already exists")
+	// ErrCodesetReadOnly describes the error message returned when trying to modify the code of a codeset
+	// registered from an external repository.
+	ErrCodesetReadOnly = CodesetErr("the codeset is registered from an external repository and cannot be modified")
+	// ErrCodesetFileNotFound describes the error message returned when a path does not exist in a codeset version.
+	ErrCodesetFileNotFound = CodesetErr("could not find the specified path in the codeset version")
+	// ErrCodesetNotAFile describes the error message returned when trying to read the contents of a codeset path
+	// that is not a file.
+	ErrCodesetNotAFile = CodesetErr("the specified codeset path is not a file")
+	// ErrCodesetArchived describes the error message returned when trying to modify an archived codeset.
+	ErrCodesetArchived = CodesetErr("the codeset is archived and cannot be modified, unarchive it first")
+	// ErrCodesetFileTooLarge describes the error message returned when trying to read the contents of a codeset
+	// file larger than MaxCodesetFileSize.
+	ErrCodesetFileTooLarge = CodesetErr("the specified codeset file is too large to be read")
+	// ErrInvalidCodesetPath describes the error message returned when a codeset path refers to a parent directory.
+	ErrInvalidCodesetPath = CodesetErr("invalid codeset path, parent directory references are not allowed")
+	// ErrInvalidCodesetSource describes the error message returned when the external repository of a codeset is not
+	// a valid HTTP(S) git URL, or its mode or interval are not valid.
+	ErrInvalidCodesetSource = CodesetErr("invalid codeset source, an HTTP(S) git URL, the mirror or track mode and an interval of at least one minute are required")
+)
+
+const (
+	// CodesetSourceMirror is the mode of the codesets whose external repository is mirrored into the FuseML git server
+	CodesetSourceMirror CodesetSourceMode = "mirror"
+	// CodesetSourceTrack is the mode of the codesets whose external repository is polled directly for new commits
+	CodesetSourceTrack CodesetSourceMode = "track"
+
+	// MaxCodesetFileSize is the maximum size, in bytes, of the codeset files whose contents can be read
+	MaxCodesetFileSize = 10 << 20
+
+	// DefaultCodesetSourceInterval is the default interval at which external codeset repositories are synchronized
+	DefaultCodesetSourceInterval = 10 * time.Minute
+	// minCodesetSourceInterval is the minimum interval at which external codeset repositories are synchronized
+	minCodesetSourceInterval = time.Minute
+)
+
+// CodesetErr are expected errors returned when performing operations on codesets
+type CodesetErr string
+
+// Error returns the error message
+func (e CodesetErr) Error() string {
+	return string(e)
+}
+
 // Codeset represents a codeset artifact
 type Codeset struct {
 	// The name of the Codeset
@@ -16,6 +73,134 @@ type Codeset struct {
 	Labels []string
 	// Full URL to the Codeset
 	URL string
+	// The external repository the Codeset is registered from, if any
+	Source *CodesetSource
+	// Whether the Codeset is archived, which makes it read-only and hides it from the Codeset list by default
+	Archived bool
+}
+
+// CodesetSourceMode describes how FuseML follows the external repository of a codeset
+type CodesetSourceMode string
+
+// CodesetSource describes the external git repository a codeset is registered from
+type CodesetSource struct {
+	// The URL of the git repository
+	URL string
+	// Whether the repository is mirrored into the FuseML git server or tracked directly
+	Mode CodesetSourceMode
+	// The credentials used to access the repository. They are only set when registering the codeset and
+	// are kept in a CodesetCredentialsStore afterwards.
+	Username string
+	Password string
+	// The interval at which the repository is mirrored or polled for new commits
+	Interval time.Duration
+	// The default branch of the repository, used by the workflow runs not triggered by a push
+	DefaultBranch string
+	// The commits the branches and tags of a tracked repository pointed to the last time it was polled,
+	// indexed by the full ref name (e.g. refs/heads/main)
+	Heads map[string]string
+	// The last time a tracked repository was polled
+	Polled time.Time
+}
+
+// Validate checks that the source URL is an HTTP(S) URL and that its mode and interval are valid. The default
+// interval is set if none is given.
+func (s *CodesetSource) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidCodesetSource
+	}
+	if s.Mode != CodesetSourceMirror && s.Mode != CodesetSourceTrack {
+		return ErrInvalidCodesetSource
+	}
+	if s.Interval == 0 {
+		s.Interval = DefaultCodesetSourceInterval
+	}
+	if s.Interval < minCodesetSourceInterval {
+		return ErrInvalidCodesetSource
+	}
+	return nil
+}
+
+// IsTracked returns true if the codeset is registered from an external repository that is tracked directly
+func (c *Codeset) IsTracked() bool {
+	return c.Source != nil && c.Source.Mode == CodesetSourceTrack
+}
+
+// RefHeads returns the commits the branches and tags point to, indexed by their full ref name
+func RefHeads(branches, tags []*CodesetRef) map[string]string {
+	heads := map[string]string{}
+	for _, b := range branches {
+		heads["refs/heads/"+b.Name] = b.Commit
+	}
+	for _, t := range tags {
+		heads["refs/tags/"+t.Name] = t.Commit
+	}
+	return heads
+}
+
+// CleanCodesetPath returns the shortest form of a path relative to the root of a codeset (empty for the root),
+// or ErrInvalidCodesetPath if the path refers to a parent directory
+func CleanCodesetPath(p string) (string, error) {
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return "", ErrInvalidCodesetPath
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/"), nil
+}
+
+// CodesetVersions describes the versions of a codeset: its branches, tags and most recent commits
+type CodesetVersions struct {
+	// The branches of the Codeset
+	Branches []*CodesetRef
+	// The tags of the Codeset
+	Tags []*CodesetRef
+	// The most recent commits of the Codeset, newest first
+	Commits []*CodesetCommit
+}
+
+// CodesetRef is a named version of a codeset (a branch or a tag) and the commit it points to
+type CodesetRef struct {
+	// The name of the branch or tag
+	Name string
+	// The ID of the commit the branch or tag points to
+	Commit string
+}
+
+// CodesetCommit describes a commit of a codeset
+type CodesetCommit struct {
+	// The commit ID (SHA)
+	ID string
+	// The name of the commit author
+	Author string
+	// The commit message
+	Message string
+	// The time the commit was authored
+	Date time.Time
+}
+
+const (
+	// CodesetFileTypeFile is the type of the regular files of a codeset
+	CodesetFileTypeFile = "file"
+	// CodesetFileTypeDir is the type of the directories of a codeset
+	CodesetFileTypeDir = "dir"
+	// CodesetFileTypeSymlink is the type of the symbolic links of a codeset
+	CodesetFileTypeSymlink = "symlink"
+	// CodesetFileTypeSubmodule is the type of the git submodules of a codeset
+	CodesetFileTypeSubmodule = "submodule"
+)
+
+// CodesetFile describes an entry of a codeset directory
+type CodesetFile struct {
+	// The name of the file
+	Name string
+	// The path of the file, relative to the root of the codeset
+	Path string
+	// The type of the file: file, dir, symlink or submodule
+	Type string
+	// The size of the file, in bytes
+	Size int64
 }
 
 // CodesetSubscriber is an interface for objects interested in operations performed on
@@ -24,28 +209,93 @@ type CodesetSubscriber interface {
 	OnDeletingCodeset(ctx context.Context, c *Codeset)
 }
 
+// ExternalCodesetStore is an interface to the stores of the codesets tracking an external repository, which are
+// not hosted by the FuseML git server
+type ExternalCodesetStore interface {
+	// AddCodeset adds a codeset to the store.
+	AddCodeset(ctx context.Context, c *Codeset) error
+	// GetCodeset returns a codeset, or ErrCodesetNotFound.
+	GetCodeset(ctx context.Context, project, name string) (*Codeset, error)
+	// GetCodesets returns all the codesets, or the codesets of a given project.
+	GetCodesets(ctx context.Context, project *string) ([]*Codeset, error)
+	// UpdateCodeset replaces a codeset in the store.
+	UpdateCodeset(ctx context.Context, c *Codeset) error
+	// UpdateCodesetHeads records the polled heads of a codeset, leaving the rest of it unchanged.
+	UpdateCodesetHeads(ctx context.Context, project, name string, heads map[string]string, polled time.Time) error
+	// DeleteCodeset deletes a codeset from the store.
+	DeleteCodeset(ctx context.Context, project, name string) error
+}
+
+// CodesetCredentialsStore is an interface to the stores of the credentials used to access the external
+// repositories of the codesets
+type CodesetCredentialsStore interface {
+	// SetCodesetCredentials stores the credentials used to access the external repository of a codeset.
+	SetCodesetCredentials(ctx context.Context, c *Codeset, username, password string) error
+	// GetCodesetCredentials returns the credentials used to access the external repository of a codeset, which
+	// are empty if none are stored.
+	GetCodesetCredentials(ctx context.Context, c *Codeset) (username, password string, err error)
+	// DeleteCodesetCredentials deletes the credentials used to access the external repository of a codeset.
+	DeleteCodesetCredentials(ctx context.Context, c *Codeset) error
+}
+
+// CodesetProvider is an interface to the git servers hosting the external repositories tracked by codesets
+type CodesetProvider interface {
+	// GetRefs returns the branches and tags of the repository.
+	GetRefs(ctx context.Context, source *CodesetSource) (branches, tags []*CodesetRef, err error)
+	// GetDefaultBranch returns the name of the branch the HEAD of the repository points to, which is
+	// empty for an empty repository.
+	GetDefaultBranch(ctx context.Context, source *CodesetSource) (string, error)
+	// GetCommits returns the most recent commits reachable from the given branch, tag or commit of the
+	// repository (the default branch when empty), newest first.
+	GetCommits(ctx context.Context, source *CodesetSource, ref string, limit int) ([]*CodesetCommit, error)
+	// GetFiles returns the entries of a directory (or the file itself) at the given path and branch, tag or
+	// commit of the repository (the default branch when empty).
+	GetFiles(ctx context.Context, source *CodesetSource, ref, path string) ([]*CodesetFile, error)
+	// GetFile returns the contents of the file at the given path and branch, tag or commit of the repository
+	// (the default branch when empty).
+	GetFile(ctx context.Context, source *CodesetSource, ref, path string) ([]byte, error)
+}
+
 // CodesetStore is an interface to codeset stores
 type CodesetStore interface {
 	Find(ctx context.Context, project, name string) (*Codeset, error)
-	GetAll(ctx context.Context, project, label *string) ([]*Codeset, error)
+	// GetAll returns the page of the codesets of the visible projects, matching the project and label,
+	// selected by the list options
+	GetAll(ctx context.Context, project, label *string, archived bool, visible ProjectVisibility, opts *ListOptions) (result []*Codeset, next string, err error)
 	Add(ctx context.Context, c *Codeset) (*Codeset, *string, *string, error)
-	CreateWebhook(context.Context, *Codeset, string) (*int64, error)
+	Update(ctx context.Context, c *Codeset) (*Codeset, error)
+	CreateWebhook(ctx context.Context, c *Codeset, listenerURL, secret string, refs *CodesetRefFilter) (*int64, error)
 	DeleteWebhook(context.Context, *Codeset, *int64) error
 	Delete(ctx context.Context, project, name string) error
+	GetVersions(ctx context.Context, project, name, ref string, commits int) (*CodesetVersions, error)
+	AddTag(ctx context.Context, project, name, tag, commit, message string) (*CodesetRef, error)
+	GetFiles(ctx context.Context, project, name, ref, path string) ([]*CodesetFile, error)
+	GetFile(ctx context.Context, project, name, ref, path string) ([]byte, error)
 	Subscribe(ctx context.Context, watcher CodesetSubscriber, codeset *Codeset) error
 	Unsubscribe(ctx context.Context, watcher CodesetSubscriber, codeset *Codeset) error
 }
 
 // GitAdminClient describes the interface of a Git admin client
 type GitAdminClient interface {
-	PrepareRepository(*Codeset, *string) (*string, *string, error)
-	CreateRepoWebhook(string, string, *string) (*int64, error)
-	DeleteRepoWebhook(string, string, *int64) error
-	GetRepositories(org, label *string) ([]*Codeset, error)
-	GetRepository(org, name string) (*Codeset, error)
-	DeleteRepository(org, name string) error
-	GetProjects() ([]*Project, error)
-	GetProject(org string) (*Project, error)
-	DeleteProject(org string) error
-	CreateProject(string, string, bool) (*Project, error)
+	PrepareRepository(context.Context, *Codeset, *string) (*string, *string, error)
+	MirrorRepository(ctx context.Context, c *Codeset) error
+	CreateRepoWebhook(ctx context.Context, org, name string, listenerURL *string, secret string, refs *CodesetRefFilter) (*int64, error)
+	DeleteRepoWebhook(context.Context, string, string, *int64) error
+	GetRepositories(ctx context.Context, org, label *string) ([]*Codeset, error)
+	GetRepository(ctx context.Context, org, name string) (*Codeset, error)
+	UpdateRepository(ctx context.Context, c *Codeset) error
+	DeleteRepository(ctx context.Context, org, name string) error
+	GetRepoBranches(ctx context.Context, org, name string) ([]*CodesetRef, error)
+	GetRepoTags(ctx context.Context, org, name string) ([]*CodesetRef, error)
+	GetRepoCommits(ctx context.Context, org, name, ref string, limit int) ([]*CodesetCommit, error)
+	CreateRepoTag(ctx context.Context, org, name, tag, commit, message string) (*CodesetRef, error)
+	GetRepoFiles(ctx context.Context, org, name, ref, path string) ([]*CodesetFile, error)
+	GetRepoFile(ctx context.Context, org, name, ref, path string) ([]byte, error)
+	GetProjects(context.Context) ([]*Project, error)
+	GetProject(ctx context.Context, org string) (*Project, error)
+	UpdateProject(ctx context.Context, org, desc string) error
+	DeleteProject(ctx context.Context, org string) error
+	// GetExclusiveProjectUsers returns the names of the users of a project that belong to no other project
+	GetExclusiveProjectUsers(ctx context.Context, org string) ([]string, error)
+	CreateProject(context.Context, string, string, bool) (*Project, error)
 }
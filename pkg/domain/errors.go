@@ -3,6 +3,17 @@ package domain
 const (
 	// ErrProjectExists is the error message returned when trying to create a project (org) that already exists.
 	ErrProjectExists = projectErr("Project with that name already exists")
+	// ErrProjectNotFound is the error message returned when a project does not exist.
+	ErrProjectNotFound = projectErr("Project not found")
+	// ErrProjectMemberNotFound is the error message returned when a user is not a member of a project.
+	ErrProjectMemberNotFound = projectErr("User is not a member of the project")
+	// ErrProjectQuotaExceeded is the error message returned when creating a resource would exceed a project quota.
+	ErrProjectQuotaExceeded = projectErr("Project quota exceeded")
+	// ErrInvalidProjectQuota is the error message returned when setting a project quota with invalid limits.
+	ErrInvalidProjectQuota = projectErr("Invalid project quota")
+	// ErrProjectAdminRequired is the error message returned when replacing the members of a project with members
+	// that include no admin.
+	ErrProjectAdminRequired = projectErr("The project must have at least one admin")
 )
 
 type projectErr string
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ErrInvalidContinueToken is the error returned when the continue token of a list operation was
+	// not issued by FuseML.
+	ErrInvalidContinueToken = ListErr("invalid continue token")
+)
+
+// ListErr are expected errors returned when listing items
+type ListErr string
+
+func (e ListErr) Error() string {
+	return string(e)
+}
+
+// ListOptions selects the page of items returned by a list operation and the order of the items.
+type ListOptions struct {
+	// Limit is the maximum number of items returned, all the items are returned when it is 0.
+	Limit int
+	// Continue is the token returned with the previous page, used to return the next page.
+	Continue string
+	// Sort is the field used to order the items, prefixed with "-" for descending order.
+	Sort string
+}
+
+// continueToken is the decoded form of the continue tokens returned with the pages of items
+type continueToken struct {
+	// Offset is the position of the first item of the next page, for the items listed in memory
+	Offset int `json:"offset,omitempty"`
+	// Backend is the continue token issued by the backend listing the items, e.g. Kubernetes
+	Backend string `json:"backend,omitempty"`
+}
+
+// encode returns the opaque form of the token
+func (t continueToken) encode() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// token decodes the continue token of the list options
+func (o *ListOptions) token() (t continueToken, err error) {
+	if o == nil || o.Continue == "" {
+		return t, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(o.Continue)
+	if err != nil {
+		return t, ErrInvalidContinueToken
+	}
+	if err = json.Unmarshal(b, &t); err != nil || t.Offset < 0 {
+		return t, ErrInvalidContinueToken
+	}
+	return t, nil
+}
+
+// Paged returns true when the items are listed in pages.
+func (o *ListOptions) Paged() bool {
+	return o != nil && o.Limit > 0
+}
+
+// SortField returns the field used to order the items, or defaultField if none is selected, and
+// whether the items are ordered in descending order.
+func (o *ListOptions) SortField(defaultField string) (field string, desc bool) {
+	if o == nil || o.Sort == "" {
+		return defaultField, false
+	}
+	if strings.HasPrefix(o.Sort, "-") {
+		return o.Sort[1:], true
+	}
+	return o.Sort, false
+}
+
+// Offset returns the position of the first item of the page selected by the continue token.
+func (o *ListOptions) Offset() (int, error) {
+	t, err := o.token()
+	if err != nil {
+		return 0, err
+	}
+	if t.Backend != "" {
+		return 0, ErrInvalidContinueToken
+	}
+	return t.Offset, nil
+}
+
+// BackendContinue returns the continue token issued by the backend listing the items, as
+// previously wrapped by NewBackendContinueToken.
+func (o *ListOptions) BackendContinue() (string, error) {
+	t, err := o.token()
+	if err != nil {
+		return "", err
+	}
+	if t.Offset != 0 {
+		return "", ErrInvalidContinueToken
+	}
+	return t.Backend, nil
+}
+
+// NewContinueToken returns the continue token of the page starting at the given offset.
+func NewContinueToken(offset int) string {
+	return continueToken{Offset: offset}.encode()
+}
+
+// NewBackendContinueToken wraps the continue token issued by the backend listing the items, or
+// returns an empty token when the backend returned the last page.
+func NewBackendContinueToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	return continueToken{Backend: token}.encode()
+}
+
+// Paginate returns the bounds of the page selected by the list options within n ordered items,
+// as well as the continue token of the next page, empty for the last page.
+func (o *ListOptions) Paginate(n int) (start, end int, next string, err error) {
+	start, err = o.Offset()
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if start > n {
+		start = n
+	}
+	end = n
+	if o.Paged() && start+o.Limit < n {
+		end = start + o.Limit
+		next = NewContinueToken(end)
+	}
+	return start, end, next, nil
+}
+
+// SortKeys maps the fields that the items of a list can be sorted by to functions returning the
+// sort key of the item at a given index.
+type SortKeys map[string]func(i int) string
+
+// SortItems orders items, a slice, by the field selected by the list options, or by defaultField
+// when none is selected. Items with equal keys keep their order.
+func (o *ListOptions) SortItems(items interface{}, defaultField string, keys SortKeys) error {
+	field, desc := o.SortField(defaultField)
+	key, ok := keys[field]
+	if !ok {
+		return fmt.Errorf("the items cannot be sorted by %q", field)
+	}
+	if reflect.ValueOf(items).Len() < 2 {
+		return nil
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return key(i) > key(j)
+		}
+		return key(i) < key(j)
+	})
+	return nil
+}
+
+// TimeSortKey returns a sort key ordering the times chronologically.
+func TimeSortKey(t time.Time) string {
+	return t.UTC().Format("20060102150405.000000000")
+}
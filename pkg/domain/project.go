@@ -12,6 +12,12 @@ type Project struct {
 	Description string
 	// Users assigned to the project
 	Users []*User
+	// Members holds the roles granted to the users in the project
+	Members []*ProjectMember
+	// Quota holds the limits on the resources consumed by the project
+	Quota *ProjectQuota
+	// Usage holds the resources currently consumed by the project
+	Usage *ProjectUsage
 }
 
 // User represents user assigned to the project
@@ -20,10 +26,165 @@ type User struct {
 	Email string
 }
 
+// ProjectRole is a role that can be granted to a user in a project
+type ProjectRole string
+
+const (
+	// ProjectRoleViewer allows viewing the resources of a project
+	ProjectRoleViewer ProjectRole = "viewer"
+	// ProjectRoleEditor allows viewing and changing the resources of a project
+	ProjectRoleEditor ProjectRole = "editor"
+	// ProjectRoleAdmin allows changing the resources of a project and managing its members
+	ProjectRoleAdmin ProjectRole = "admin"
+)
+
+// projectRoleLevels orders the project roles by the permissions they grant
+var projectRoleLevels = map[ProjectRole]int{
+	ProjectRoleViewer: 1,
+	ProjectRoleEditor: 2,
+	ProjectRoleAdmin:  3,
+}
+
+// Valid returns whether the role is one of the supported project roles
+func (r ProjectRole) Valid() bool {
+	_, ok := projectRoleLevels[r]
+	return ok
+}
+
+// Includes returns whether the role grants all the permissions of another role
+func (r ProjectRole) Includes(other ProjectRole) bool {
+	return r.Valid() && other.Valid() && projectRoleLevels[r] >= projectRoleLevels[other]
+}
+
+// ProjectMember binds a role to a user in a project
+type ProjectMember struct {
+	// The name of the project
+	Project string
+	// The name of the user
+	User string
+	// The role of the user in the project
+	Role ProjectRole
+}
+
+// ProjectMemberStore is an interface to the stores holding the members of the projects
+type ProjectMemberStore interface {
+	// GetMember returns the membership of a user in a project, or ErrProjectMemberNotFound
+	GetMember(ctx context.Context, project, user string) (*ProjectMember, error)
+	// GetMembers returns the members of a project
+	GetMembers(ctx context.Context, project string) ([]*ProjectMember, error)
+	// GetMemberships returns the memberships of a user in all projects
+	GetMemberships(ctx context.Context, user string) ([]*ProjectMember, error)
+	// SetMember adds a user to a project or changes the role of an existing member
+	SetMember(ctx context.Context, member *ProjectMember) (*ProjectMember, error)
+	// RemoveMember removes a user from a project, returning ErrProjectMemberNotFound if the user is not a member
+	RemoveMember(ctx context.Context, project, user string) error
+	// RemoveProject removes all the members of a project
+	RemoveProject(ctx context.Context, project string) error
+}
+
+// ProjectQuota holds the limits on the resources consumed by a project. A zero limit means that the resource
+// is not limited.
+type ProjectQuota struct {
+	// The name of the project
+	Project string
+	// MaxCodesets is the maximum number of codesets registered in the project
+	MaxCodesets int
+	// MaxConcurrentRuns is the maximum number of workflow runs in progress for the codesets of the project
+	MaxConcurrentRuns int
+	// MaxWorkspaceSize is the maximum total size of the workspace volumes used by the workflow runs in progress
+	// for the codesets of the project, as a Kubernetes quantity (e.g. 10Gi)
+	MaxWorkspaceSize string
+}
+
+// ProjectUsage holds the resources consumed by a project
+type ProjectUsage struct {
+	// Codesets is the number of codesets registered in the project
+	Codesets int
+	// ConcurrentRuns is the number of workflow runs in progress for the codesets of the project
+	ConcurrentRuns int
+	// WorkspaceSize is the total size of the workspace volumes used by the workflow runs in progress, as a
+	// Kubernetes quantity
+	WorkspaceSize string
+}
+
+// ProjectQuotaStore is an interface to the stores holding the quotas of the projects
+type ProjectQuotaStore interface {
+	// GetQuota returns the quota of a project, without any limit if it was not set
+	GetQuota(ctx context.Context, project string) (*ProjectQuota, error)
+	// SetQuota sets the quota of a project
+	SetQuota(ctx context.Context, quota *ProjectQuota) (*ProjectQuota, error)
+	// RemoveQuota removes the quota of a project
+	RemoveQuota(ctx context.Context, project string) error
+}
+
+// ProjectQuotaManager manages the quotas of the projects and enforces them when the project resources are created
+type ProjectQuotaManager interface {
+	// GetQuota returns the quota of a project
+	GetQuota(ctx context.Context, project string) (*ProjectQuota, error)
+	// SetQuota validates and sets the quota of a project, returning ErrInvalidProjectQuota for invalid limits
+	SetQuota(ctx context.Context, quota *ProjectQuota) (*ProjectQuota, error)
+	// RemoveQuota removes the quota of a project
+	RemoveQuota(ctx context.Context, project string) error
+	// GetUsage returns the resources currently consumed by a project
+	GetUsage(ctx context.Context, project string) (*ProjectUsage, error)
+	// CheckCodesetQuota returns ErrProjectQuotaExceeded if a new codeset cannot be registered in the project
+	CheckCodesetQuota(ctx context.Context, project string) error
+	// CheckRunQuota returns ErrProjectQuotaExceeded if a new workflow run cannot be started for a codeset of
+	// the project
+	CheckRunQuota(ctx context.Context, project string) error
+}
+
+// ProjectAssignment is the assignment of a workflow to a codeset of a project
+type ProjectAssignment struct {
+	// The name of the workflow
+	Workflow string
+	// The name of the codeset
+	Codeset string
+}
+
+// ProjectResources lists the resources that belong to a project, or that were created for its codesets
+type ProjectResources struct {
+	// Codesets holds the names of the codesets registered in the project
+	Codesets []string
+	// Assignments holds the assignments of workflows to the codesets of the project
+	Assignments []*ProjectAssignment
+	// WorkflowRuns holds the names of the workflow runs created for the codesets of the project
+	WorkflowRuns []string
+	// Applications holds the names of the applications created from the codesets of the project
+	Applications []string
+	// Members holds the names of the users that were granted a role in the project
+	Members []string
+	// Users holds the names of the git users of the project that belong to no other project, which are deleted
+	// along with the project
+	Users []string
+}
+
+// ProjectVisibility reports whether the resources of a project are visible to the caller of a list
+// operation. A nil ProjectVisibility makes the resources of all the projects visible.
+type ProjectVisibility func(project string) bool
+
+// Includes returns whether the resources of the project are visible.
+func (v ProjectVisibility) Includes(project string) bool {
+	return v == nil || v(project)
+}
+
+// ProjectManager describes the interface for a Project Manager
+type ProjectManager interface {
+	// DeleteProject deletes a project along with all its resources, which are returned. With dryRun, the
+	// resources are only listed.
+	DeleteProject(ctx context.Context, name string, dryRun bool) (*ProjectResources, error)
+}
+
 // ProjectStore is an interface to project stores
 type ProjectStore interface {
 	Find(ctx context.Context, name string) (*Project, error)
-	GetAll(ctx context.Context) ([]*Project, error)
+	// GetAll returns the page of the visible projects selected by the list options
+	GetAll(ctx context.Context, visible ProjectVisibility, opts *ListOptions) (result []*Project, next string, err error)
 	Delete(ctx context.Context, name string) error
 	Create(ctx context.Context, name, desc string) (*Project, error)
+	// Update changes the description of a project
+	Update(ctx context.Context, name, desc string) (*Project, error)
+	// ExclusiveUsers returns the names of the users of a project that belong to no other project, which are
+	// deleted along with the project
+	ExclusiveUsers(ctx context.Context, name string) ([]string, error)
 }
@@ -9,8 +9,31 @@ const (
 	// LocalRegistryHostname - Container image location values may use this identifier as a hostname to indicate
 	// that they are stored internally in the local OCI registry managed by fuseml
 	LocalRegistryHostname = "fuseml.local"
+
+	// ErrRunnableNotFound describes the error message returned when trying to get a runnable that does not exist.
+	ErrRunnableNotFound = RunnableErr("could not find a runnable with the specified ID")
+	// ErrRunnableBuildSourceMissing describes the error message returned when trying to build a runnable without
+	// specifying where the sources are located.
+	ErrRunnableBuildSourceMissing = RunnableErr("either a codeset or a git URL must be provided as the build source")
+	// ErrRunnableBuildSourceConflict describes the error message returned when trying to build a runnable from
+	// both a codeset and a git URL.
+	ErrRunnableBuildSourceConflict = RunnableErr("only one of a codeset or a git URL can be provided as the build source")
+	// ErrRunnableBuildDockerfileMissing describes the error message returned when trying to build a runnable from a
+	// codeset that does not contain the Dockerfile at the given revision.
+	ErrRunnableBuildDockerfileMissing = RunnableErr("the Dockerfile does not exist in the codeset at the given revision")
+	// ErrRunnableBuildReplace describes the error message returned when trying to build a runnable that already
+	// exists, without being allowed to replace it.
+	ErrRunnableBuildReplace = RunnableErr("the runnable already exists and the build is not allowed to replace it")
 )
 
+// RunnableErr are expected errors returned when performing operations on runnables
+type RunnableErr string
+
+// Error returns the error message
+func (e RunnableErr) Error() string {
+	return string(e)
+}
+
 // RunnableKind encodes valid values that can be assigned to the Runnable.Kind field
 type RunnableKind string
 
@@ -260,7 +283,51 @@ type RunnableOutputRunnable struct {
 
 // RunnableStore defines the public interface that needs to be implemented by all runnable stores
 type RunnableStore interface {
-	Find(ctx context.Context, id string, kind string, labels map[string]string) (res []*Runnable, err error)
+	Find(ctx context.Context, id string, kind string, labels map[string]string, opts *ListOptions) (res []*Runnable, next string, err error)
 	Register(ctx context.Context, r *Runnable) (res *Runnable, err error)
 	Get(ctx context.Context, name string) (res *Runnable, err error)
+	Update(ctx context.Context, r *Runnable) (res *Runnable, err error)
+}
+
+// RunnableBuild describes a build of a runnable container image from source
+type RunnableBuild struct {
+	// Name of the build, as assigned by the builder
+	Name string
+	// ID of the runnable that is registered or updated when the build succeeds
+	RunnableID string
+	// Codeset providing the sources, if the build is not done directly from a git URL
+	Codeset *Codeset
+	// URL of the git repository with the sources
+	SourceURL string
+	// Git revision (branch, tag or commit) of the sources
+	Revision string
+	// Path to the Dockerfile, relative to the root of the sources
+	Dockerfile string
+	// Location of the resulting image in the built-in container registry, without the registry hostname
+	Image string
+	// Replace allows the build to update an existing runnable, otherwise it may only register a new one
+	Replace bool
+	// Build status
+	Status string
+	// Time when the build started
+	StartTime time.Time
+	// Time when the build completed
+	CompletionTime time.Time
+	// URL to the build details in the builder dashboard
+	URL string
+}
+
+// RunnableBuilder is the interface for backends that are able to build runnable container images
+type RunnableBuilder interface {
+	// CreateRunnableBuild starts building a runnable container image.
+	CreateRunnableBuild(ctx context.Context, build *RunnableBuild) (*RunnableBuild, error)
+	// GetRunnableBuild returns a runnable build.
+	GetRunnableBuild(ctx context.Context, name string) (*RunnableBuild, error)
+}
+
+// RunnableManager describes the interface for a Runnable Manager
+type RunnableManager interface {
+	// BuildRunnable builds the container image for a runnable and, when the build succeeds, registers
+	// the runnable or updates its container image.
+	BuildRunnable(ctx context.Context, build *RunnableBuild, runnable *Runnable) (*RunnableBuild, error)
 }
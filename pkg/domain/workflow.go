@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -16,8 +18,17 @@ const (
 	ErrWorkflowNotAssignedToCodeset = WorkflowErr("workflow not assigned to codeset")
 	// ErrCannotDeleteAssignedWorkflow describes the error message returned when trying to delete a workflow that is assigned to a codeset.
 	ErrCannotDeleteAssignedWorkflow = WorkflowErr("cannot delete workflow, there are codesets assigned to it")
+	// ErrInvalidRefPattern describes the error message returned when a branch or tag filter of a codeset assignment
+	// is not a valid glob pattern.
+	ErrInvalidRefPattern = WorkflowErr("invalid branch or tag pattern, only letters, digits and the '.', '_', '-', '/', '*' and '?' characters are allowed")
+	// ErrWorkflowDockerfileNotFound describes the error message returned when trying to assign a workflow to a codeset
+	// that does not contain the Dockerfile used to build the image of a workflow step.
+	ErrWorkflowDockerfileNotFound = WorkflowErr("the Dockerfile used by a workflow step does not exist in the codeset")
 )
 
+// refPattern matches the glob patterns accepted as branch and tag filters
+var refPattern = regexp.MustCompile(`^[A-Za-z0-9._/*?-]+$`)
+
 const (
 	// WorkflowIOTypeString represents a workflow input that is of a string type.
 	WorkflowIOTypeString WorkflowIOType = "string"
@@ -169,8 +180,14 @@ type WorkflowRun struct {
 	Status string
 	// URL is the URL to the workflow run.
 	URL string
+	// Ref is the git ref (branch or tag) whose push triggered the workflow run.
+	Ref string
 }
 
+// WorkflowRunQueued is the status of the workflow runs triggered by a codeset webhook that are not started
+// until the quota of the codeset project allows it.
+const WorkflowRunQueued = "Queued"
+
 // WorkflowRunInput represents a input from a FuseML workflow run.
 type WorkflowRunInput struct {
 	// Input is the input from the workflow.
@@ -213,6 +230,14 @@ type WorkflowRunFilter struct {
 	Status []string
 }
 
+// SortWorkflowRuns orders workflow runs by the field selected by the list options, by name by default.
+func SortWorkflowRuns(runs []*WorkflowRun, opts *ListOptions) error {
+	return opts.SortItems(runs, "name", SortKeys{
+		"name":      func(i int) string { return runs[i].Name },
+		"startTime": func(i int) string { return TimeSortKey(runs[i].StartTime) },
+	})
+}
+
 // WorkflowListener defines a listener for a workflow
 type WorkflowListener struct {
 	// Name is the name of the listener.
@@ -231,6 +256,79 @@ type CodesetAssignment struct {
 	Codeset *Codeset
 	// WebhookID is the ID of the webhook that is used by the workflow assignment.
 	WebhookID *int64
+	// Refs selects the branches and tags whose pushes trigger the workflow, all of them when nil.
+	Refs *CodesetRefFilter
+}
+
+// CodesetRefFilter selects, with glob patterns, the branches and tags of a codeset whose pushes trigger
+// the assigned workflow. In the patterns, '*' matches any sequence of characters and '?' any single
+// character. Pushes to any branch or tag trigger the workflow when no pattern is set.
+type CodesetRefFilter struct {
+	// Branches are the patterns matching the names of the branches.
+	Branches []string
+	// Tags are the patterns matching the names of the tags.
+	Tags []string
+}
+
+// IsEmpty returns true when the filter has no pattern, i.e. when it lets through all the refs.
+func (f *CodesetRefFilter) IsEmpty() bool {
+	return f == nil || (len(f.Branches) == 0 && len(f.Tags) == 0)
+}
+
+// Validate checks that the branch and tag filters are valid glob patterns.
+func (f *CodesetRefFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for _, p := range append(append([]string{}, f.Branches...), f.Tags...) {
+		if !refPattern.MatchString(p) {
+			return fmt.Errorf("%w: %q", ErrInvalidRefPattern, p)
+		}
+	}
+	return nil
+}
+
+// Regexp returns a regular expression matching the full git refs (e.g. "refs/heads/main") selected by
+// the filter, or an empty string when the filter lets through all the refs.
+func (f *CodesetRefFilter) Regexp() string {
+	if f.IsEmpty() {
+		return ""
+	}
+	alternatives := []string{}
+	if len(f.Branches) > 0 {
+		alternatives = append(alternatives, "refs/heads/("+globsToRegexp(f.Branches)+")")
+	}
+	if len(f.Tags) > 0 {
+		alternatives = append(alternatives, "refs/tags/("+globsToRegexp(f.Tags)+")")
+	}
+	return "^(" + strings.Join(alternatives, "|") + ")$"
+}
+
+// Match returns true if the full git ref is selected by the filter.
+func (f *CodesetRefFilter) Match(ref string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	return regexp.MustCompile(f.Regexp()).MatchString(ref)
+}
+
+func globsToRegexp(globs []string) string {
+	res := make([]string, len(globs))
+	for i, glob := range globs {
+		var b strings.Builder
+		for _, c := range glob {
+			switch c {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		res[i] = b.String()
+	}
+	return strings.Join(res, "|")
 }
 
 // WorkflowErr are expected errors returned when performing operations on workflows,
@@ -242,20 +340,21 @@ type WorkflowManager interface {
 	CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workflow, error)
 	// GetWorkflow retrieves a workflow.
 	GetWorkflow(ctx context.Context, name string) (*Workflow, error)
-	// GetWorkflows returns a list of workflows.
-	GetWorkflows(ctx context.Context, name *string) []*Workflow
+	// GetWorkflows returns a page of workflows and the token used to retrieve the next page.
+	GetWorkflows(ctx context.Context, name *string, opts *ListOptions) (result []*Workflow, next string, err error)
 	// DeleteWorkflow deletes a workflow.
 	DeleteWorkflow(ctx context.Context, name string) error
 	// AssignToCodeset assigns a workflow to a codeset.
-	AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string) (*WorkflowListener, *int64, error)
+	AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string, refs *CodesetRefFilter) (*WorkflowListener, *int64, error)
 	// UnassignFromCodeset removes a workflow assignment from a codeset.
 	UnassignFromCodeset(ctx context.Context, name, codesetProject, codesetName string) error
 	// GetAllCodesetAssignments returns all the codeset assignments from all workflows, or a specific one.
 	GetAllCodesetAssignments(ctx context.Context, name *string) map[string][]*CodesetAssignment
 	// GetAssignmentStatus returns the status of a workflow assignment.
 	GetAssignmentStatus(ctx context.Context, name string) *WorkflowAssignmentStatus
-	// GetWorkflowRuns returns all the workflow runs for a workflow.
-	GetWorkflowRuns(ctx context.Context, filter *WorkflowRunFilter) ([]*WorkflowRun, error)
+	// GetWorkflowRuns returns a page of the workflow runs for a workflow, or for all workflows, and the token used
+	// to retrieve the next page.
+	GetWorkflowRuns(ctx context.Context, filter *WorkflowRunFilter, opts *ListOptions) (result []*WorkflowRun, next string, err error)
 }
 
 // WorkflowStore is an interface for workflow stores.
@@ -264,12 +363,12 @@ type WorkflowStore interface {
 	AddWorkflow(ctx context.Context, w *Workflow) (*Workflow, error)
 	// GetWorkflow returns a workflow.
 	GetWorkflow(ctx context.Context, name string) (*Workflow, error)
-	// ListWorkflows returns a list of workflows.
-	GetWorkflows(ctx context.Context, name *string) []*Workflow
+	// GetWorkflows returns a page of workflows and the token used to retrieve the next page.
+	GetWorkflows(ctx context.Context, name *string, opts *ListOptions) (result []*Workflow, next string, err error)
 	// DeleteWorkflow deletes a workflow from the store.
 	DeleteWorkflow(ctx context.Context, name string) error
 	// AddCodesetAssignment adds a codeset assignment to the store.
-	AddCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset, webhook *int64) ([]*CodesetAssignment, error)
+	AddCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset, webhook *int64, refs *CodesetRefFilter) ([]*CodesetAssignment, error)
 	// GetCodesetAssignment returns the assignment for a workflow and a codeset.
 	GetCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset) (*CodesetAssignment, error)
 	// GetCodesetAssignments returns the codeset assignments for a workflow.
@@ -286,20 +385,33 @@ type WorkflowBackend interface {
 	CreateWorkflow(ctx context.Context, workflow *Workflow) error
 	// DeleteWorkflow deletes a workflow.
 	DeleteWorkflow(ctx context.Context, workflowName string) error
-	// CreateWorkflowRun creates a new workflow run.
-	CreateWorkflowRun(ctx context.Context, workflowName string, codeset *Codeset) error
-	// GetWorkflowRuns returns a list of workflow runs.
-	GetWorkflowRuns(ctx context.Context, workflow *Workflow, filter *WorkflowRunFilter) ([]*WorkflowRun, error)
+	// CreateWorkflowRun creates a new workflow run for the codeset revision (commit, branch or tag) pushed to the
+	// given ref, or for the main branch of the codeset when they are empty.
+	CreateWorkflowRun(ctx context.Context, workflowName string, codeset *Codeset, ref, revision string) error
+	// QueueWorkflowRun creates a new workflow run like CreateWorkflowRun, which is queued until it is started
+	// with StartWorkflowRun.
+	QueueWorkflowRun(ctx context.Context, workflowName string, codeset *Codeset, ref, revision string) error
+	// GetWorkflowRuns returns a page of workflow runs and the token used to retrieve the next page.
+	GetWorkflowRuns(ctx context.Context, workflow *Workflow, filter *WorkflowRunFilter, opts *ListOptions) (result []*WorkflowRun, next string, err error)
+	// DeleteWorkflowRun deletes a workflow run.
+	DeleteWorkflowRun(ctx context.Context, name string) error
+	// StartWorkflowRun starts a queued workflow run.
+	StartWorkflowRun(ctx context.Context, name string) error
 	// CreateWorkflowListener creates a new workflow listener.
 	CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*WorkflowListener, error)
 	// DeleteWorkflowListener deletes a workflow listener.
 	DeleteWorkflowListener(ctx context.Context, workflowName string) error
 	// GetWorkflowListener returns a workflow listener for a workflow.
 	GetWorkflowListener(ctx context.Context, workflowName string) (*WorkflowListener, error)
+	// SetWorkflowListenerTrigger makes the workflow listener accept the events sent for a codeset that are
+	// signed with the secret and that were pushed to one of the refs selected by the filter.
+	SetWorkflowListenerTrigger(ctx context.Context, workflowName string, codeset *Codeset, secret string, refs *CodesetRefFilter) error
+	// DeleteWorkflowListenerTrigger stops the workflow listener from accepting the events sent for a codeset.
+	DeleteWorkflowListenerTrigger(ctx context.Context, workflowName string, codeset *Codeset) error
 }
 
 // AssignToCodeset assigns a workflow to a codeset.
-func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhookID *int64) error {
+func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhookID *int64, refs *CodesetRefFilter) error {
 	if codeset == nil {
 		return fmt.Errorf("codeset is nil")
 	}
@@ -309,18 +421,21 @@ func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhoo
 	}
 
 	if w.AssignedTo.Codesets == nil {
-		w.AssignedTo.Codesets = []*CodesetAssignment{{Codeset: codeset, WebhookID: webhookID}}
+		w.AssignedTo.Codesets = []*CodesetAssignment{{Codeset: codeset, WebhookID: webhookID, Refs: refs}}
 		return nil
 	}
 
 	codesetAssignments := w.AssignedTo.Codesets
 	for _, assignment := range codesetAssignments {
 		if assignment.Codeset.Name == codeset.Name && assignment.Codeset.Project == codeset.Project {
+			// the webhook is recreated when the workflow is reassigned
+			assignment.WebhookID = webhookID
+			assignment.Refs = refs
 			return nil
 		}
 	}
 
-	codesetAssignments = append(codesetAssignments, &CodesetAssignment{Codeset: codeset, WebhookID: webhookID})
+	codesetAssignments = append(codesetAssignments, &CodesetAssignment{Codeset: codeset, WebhookID: webhookID, Refs: refs})
 	w.AssignedTo.Codesets = codesetAssignments
 	return nil
 }
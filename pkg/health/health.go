@@ -0,0 +1,123 @@
+// Package health reports the liveness and readiness of the FuseML server, to be used by the
+// Kubernetes probes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK is reported when the server, or one of its dependencies, is available
+	StatusOK = "ok"
+	// StatusUnavailable is reported when the server, or one of its dependencies, is not available
+	StatusUnavailable = "unavailable"
+
+	// DefaultCheckTimeout is the maximum time spent checking the dependencies
+	DefaultCheckTimeout = 5 * time.Second
+)
+
+// Check verifies that a dependency is available.
+type Check func(ctx context.Context) error
+
+// Status describes the readiness of the server and of each of its dependencies.
+type Status struct {
+	// Status is ok when all the dependencies are available
+	Status string `json:"status"`
+	// Checks holds the status of each dependency
+	Checks map[string]*CheckStatus `json:"checks,omitempty"`
+}
+
+// Ready returns whether all the dependencies are available.
+func (s *Status) Ready() bool {
+	return s.Status == StatusOK
+}
+
+// CheckStatus describes the status of a dependency.
+type CheckStatus struct {
+	// Status is ok when the dependency is available
+	Status string `json:"status"`
+	// Error is the reason why the dependency is not available
+	Error string `json:"error,omitempty"`
+}
+
+// Checker checks the dependencies of the server.
+type Checker struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]Check
+}
+
+// NewChecker returns a new Checker, which gives up on the checks that take longer than the timeout.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Add registers the check of a dependency.
+func (c *Checker) Add(name string, check Check) {
+	if _, exists := c.checks[name]; !exists {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Check runs all the checks concurrently and returns the status of the dependencies.
+func (c *Checker) Check(ctx context.Context) *Status {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	status := &Status{Status: StatusOK, Checks: make(map[string]*CheckStatus, len(c.names))}
+	for _, name := range c.names {
+		status.Checks[name] = &CheckStatus{Status: StatusOK}
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(check Check, cs *CheckStatus) {
+			defer wg.Done()
+			errc := make(chan error, 1)
+			go func() { errc <- check(ctx) }()
+			var err error
+			select {
+			case err = <-errc:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+			if err != nil {
+				cs.Status = StatusUnavailable
+				cs.Error = err.Error()
+			}
+		}(c.checks[name], status.Checks[name])
+	}
+	wg.Wait()
+
+	for _, cs := range status.Checks {
+		if cs.Status != StatusOK {
+			status.Status = StatusUnavailable
+		}
+	}
+	return status
+}
+
+// HealthzHandler reports that the server process is alive.
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, &Status{Status: StatusOK})
+}
+
+// ReadyzHandler reports the status of the dependencies, with the 503 status code when any of
+// them is not available.
+func (c *Checker) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, c.Check(r.Context()))
+}
+
+func writeStatus(w http.ResponseWriter, status *Status) {
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failed := func(context.Context) error { return errors.New("connection refused") }
+	blocked := func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		checks map[string]Check
+		want   map[string]string
+		code   int
+	}{
+		{"ready", map[string]Check{"store": ok, "gitea": ok}, map[string]string{"store": StatusOK, "gitea": StatusOK}, http.StatusOK},
+		{"failed", map[string]Check{"store": ok, "gitea": failed}, map[string]string{"store": StatusOK, "gitea": StatusUnavailable}, http.StatusServiceUnavailable},
+		{"timeout", map[string]Check{"store": ok, "tekton": blocked}, map[string]string{"store": StatusOK, "tekton": StatusUnavailable}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				c.Add(name, check)
+			}
+			rec := httptest.NewRecorder()
+			c.ReadyzHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.code {
+				t.Errorf("unexpected status code: got %d, want %d", rec.Code, tt.code)
+			}
+			status := &Status{}
+			if err := json.NewDecoder(rec.Body).Decode(status); err != nil {
+				t.Fatal(err)
+			}
+			if status.Ready() != (tt.code == http.StatusOK) {
+				t.Errorf("unexpected readiness: %s", status.Status)
+			}
+			for name, want := range tt.want {
+				cs, ok := status.Checks[name]
+				if !ok {
+					t.Fatalf("missing status of %s", name)
+				}
+				if cs.Status != want {
+					t.Errorf("unexpected status of %s: got %s, want %s", name, cs.Status, want)
+				}
+				if (cs.Error != "") != (want != StatusOK) {
+					t.Errorf("unexpected error for %s: %q", name, cs.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthzHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HealthzHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("unexpected status code: got %d", rec.Code)
+	}
+}
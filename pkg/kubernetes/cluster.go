@@ -3,12 +3,14 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 
+	"go.uber.org/zap"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
@@ -16,12 +18,18 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
 )
 
 // Cluster holds the config information for Kubernetes cluster
 type Cluster struct {
 	restConfig *rest.Config
-	logger     *log.Logger
+	logger     *zap.SugaredLogger
+	// mapper resolves the resource kinds, caching the discovered API resources
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+	client dynamic.Interface
 }
 
 // GetClientConfig fetchs the kubernetes config of current cluster
@@ -39,49 +47,103 @@ func GetClientConfig() (*rest.Config, error) {
 }
 
 // NewCluster returns new cluster struct initialized with KUBECONFIG from environment
-func NewCluster(logger *log.Logger) (*Cluster, error) {
+func NewCluster(logger *zap.SugaredLogger) (*Cluster, error) {
 
 	config, err := GetClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error getting kubernetes client config: %w", err)
 	}
 
-	return &Cluster{config, logger}, nil
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes discovery client: %w", err)
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes dynamic client: %w", err)
+	}
+
+	return &Cluster{config, logger, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)), client}, nil
 }
 
 // DeleteResource deletes kuberneres resource from current cluster, identified by name, namespace and kind
 func (c *Cluster) DeleteResource(ctx context.Context, name, namespace, kind string) error {
-	c.logger.Printf("want to delete resource %s of kind %s in %s namespace", name, kind, namespace)
-	// Prepare a RESTMapper to find GVR
-	dc, err := discovery.NewDiscoveryClientForConfig(c.restConfig)
+	log := logging.FromContext(ctx, c.logger).With("resource", name, "kind", kind, "namespace", namespace)
+	log.Info("Deleting resource")
+	dr, err := c.resourceClient(namespace, kind)
 	if err != nil {
 		return err
 	}
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
 
-	gvk, err := mapper.KindFor(schema.GroupVersionResource{Resource: kind})
-	if err != nil {
+	err = dr.Delete(ctx, name, metav1.DeleteOptions{})
+	if !k8serr.IsNotFound(err) {
 		return err
 	}
+	log.Info("Resource not found, no need to delete")
+	return nil
+}
 
-	// Find GVR
-	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+// InspectResource checks whether a kubernetes resource exists in the current cluster and whether it
+// is ready, based on the conditions reported in its status
+func (c *Cluster) InspectResource(ctx context.Context, namespace string, resource *domain.KubernetesResource) (found bool, ready bool, message string, err error) {
+	dr, err := c.resourceClient(namespace, resource.Kind)
 	if err != nil {
-		return err
+		return false, false, "", err
 	}
 
-	// create the dynamic client
-	dynClient, err := dynamic.NewForConfig(c.restConfig)
+	obj, err := dr.Get(ctx, resource.Name, metav1.GetOptions{})
 	if err != nil {
-		return err
+		if k8serr.IsNotFound(err) {
+			return false, false, fmt.Sprintf("%s %s not found", resource.Kind, resource.Name), nil
+		}
+		return false, false, "", err
 	}
+
+	ready, message = resourceReady(obj)
+	return true, ready, message, nil
+}
+
+// resourceClient returns the dynamic client interface for resources of the given kind
+func (c *Cluster) resourceClient(namespace, kind string) (dynamic.ResourceInterface, error) {
+	gvk, err := c.mapper.KindFor(schema.GroupVersionResource{Resource: kind})
+	if meta.IsNoMatchError(err) {
+		// the kind may be provided by a CRD installed after the API resources were discovered
+		c.mapper.Reset()
+		gvk, err = c.mapper.KindFor(schema.GroupVersionResource{Resource: kind})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Find GVR
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
 	// get REST interface
-	dr := dynClient.Resource(mapping.Resource).Namespace(namespace)
+	return c.client.Resource(mapping.Resource).Namespace(namespace), nil
+}
 
-	err = dr.Delete(ctx, name, metav1.DeleteOptions{})
-	if !k8serr.IsNotFound(err) {
-		return err
+// resourceReady looks for the Ready or Available conditions in the status of a resource. Resources that
+// do not report any of these conditions are considered ready.
+func resourceReady(obj *unstructured.Unstructured) (bool, string) {
+	conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return true, ""
 	}
-	c.logger.Print("resource not found, no need to delete")
-	return nil
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condType, _ := condition["type"].(string); condType != "Ready" && condType != "Available" {
+			continue
+		}
+		if status, _ := condition["status"].(string); status != "True" {
+			message, _ := condition["message"].(string)
+			return false, fmt.Sprintf("%s %s is not ready: %s", obj.GetKind(), obj.GetName(), message)
+		}
+	}
+	return true, ""
 }
@@ -0,0 +1,112 @@
+// Package logging provides the leveled, structured logger used by the FuseML server, and
+// correlates the log lines with the API requests that produced them.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"goa.design/goa/v3/middleware"
+)
+
+const (
+	// FormatJSON writes each log line as a JSON object
+	FormatJSON = "json"
+	// FormatConsole writes human readable log lines
+	FormatConsole = "console"
+
+	// DefaultFormat is the default format of the log lines
+	DefaultFormat = FormatJSON
+	// DefaultLevel is the default minimum level of the logged messages
+	DefaultLevel = "info"
+)
+
+// Keys of the fields attached to the log lines.
+const (
+	// RequestIDKey identifies the API request that produced a log line
+	RequestIDKey = "requestID"
+	// TraceIDKey identifies the trace of the API request that produced a log line
+	TraceIDKey = "traceID"
+	// ProjectKey identifies the project targeted by an operation
+	ProjectKey = "project"
+	// CodesetKey identifies the codeset targeted by an operation
+	CodesetKey = "codeset"
+	// WorkflowKey identifies the workflow targeted by an operation
+	WorkflowKey = "workflow"
+	// RunKey identifies the workflow run, or the runnable build, targeted by an operation
+	RunKey = "run"
+	// ErrorKey holds the error reported by a log line
+	ErrorKey = "error"
+)
+
+// Validate checks that the log format and level are supported.
+func Validate(format, level string) error {
+	if format != FormatJSON && format != FormatConsole {
+		return fmt.Errorf("invalid log format %q (valid formats: %s|%s)", format, FormatJSON, FormatConsole)
+	}
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q (valid levels: debug|info|warn|error)", level)
+	}
+	return nil
+}
+
+// New returns a logger writing the messages with at least the given level to w, in the given format.
+func New(w io.Writer, format, level string) (*zap.SugaredLogger, error) {
+	if err := Validate(format, level); err != nil {
+		return nil, err
+	}
+	var l zapcore.Level
+	_ = l.UnmarshalText([]byte(level))
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	if format == FormatConsole {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+	return zap.New(zapcore.NewCore(encoder, zapcore.AddSync(w), l)).Sugar(), nil
+}
+
+// FromContext returns a logger attaching the ID of the API request being served, as set by the
+// Goa request ID middleware, and the ID of its trace, if it is traced, to the log lines.
+func FromContext(ctx context.Context, logger *zap.SugaredLogger) *zap.SugaredLogger {
+	if id, ok := ctx.Value(middleware.RequestIDKey).(string); ok && id != "" {
+		logger = logger.With(RequestIDKey, id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With(TraceIDKey, sc.TraceID().String())
+	}
+	return logger
+}
+
+// goaLogger adapts a structured logger to the Goa middleware logger interface.
+type goaLogger struct {
+	logger *zap.SugaredLogger
+}
+
+// NewGoaLogger returns a logger for the Goa logging middlewares. The request ID logged by the
+// middlewares uses the same key as the rest of the log lines.
+func NewGoaLogger(logger *zap.SugaredLogger) middleware.Logger {
+	return &goaLogger{logger}
+}
+
+// Log implements middleware.Logger.
+func (l *goaLogger) Log(keyvals ...interface{}) error {
+	fields := make([]interface{}, len(keyvals))
+	copy(fields, keyvals)
+	for i := 0; i < len(fields); i += 2 {
+		if fields[i] == "id" {
+			fields[i] = RequestIDKey
+		}
+	}
+	l.logger.Infow("request", fields...)
+	return nil
+}
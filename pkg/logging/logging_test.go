@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"goa.design/goa/v3/middleware"
+)
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		format, level string
+		valid         bool
+	}{
+		{FormatJSON, "info", true},
+		{FormatConsole, "debug", true},
+		{"text", "info", false},
+		{FormatJSON, "verbose", false},
+	} {
+		if _, err := New(&bytes.Buffer{}, tc.format, tc.level); (err == nil) != tc.valid {
+			t.Errorf("unexpected result for format %q and level %q: %v", tc.format, tc.level, err)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := New(buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "abc123")
+	FromContext(ctx, logger).Debugw("filtered out")
+	FromContext(ctx, logger).Infow("workflow created", WorkflowKey, "mlflow")
+	FromContext(context.Background(), logger).Info("no request")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("unexpected number of log lines: got %d, want 2", len(lines))
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"msg": "workflow created", "level": "info", RequestIDKey: "abc123", WorkflowKey: "mlflow"} {
+		if entry[key] != want {
+			t.Errorf("unexpected %s: got %v, want %q", key, entry[key], want)
+		}
+	}
+	if strings.Contains(lines[1], RequestIDKey) {
+		t.Errorf("unexpected request ID in %s", lines[1])
+	}
+}
+
+func TestFromContextTraceID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := New(buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	FromContext(trace.ContextWithSpanContext(context.Background(), sc), logger).Info("traced")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if want := "4bf92f3577b34da6a3ce929d0e0e4736"; entry[TraceIDKey] != want {
+		t.Errorf("unexpected trace ID: got %v, want %q", entry[TraceIDKey], want)
+	}
+}
+
+func TestGoaLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := New(buf, FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewGoaLogger(logger).Log("id", "abc123", "req", "GET /workflows")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry[RequestIDKey] != "abc123" || entry["req"] != "GET /workflows" {
+		t.Errorf("unexpected log line: %s", buf.String())
+	}
+}
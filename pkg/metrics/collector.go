@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/timshannon/badgerhold/v3"
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+// collectTimeout is the maximum time spent collecting the domain metrics on each scrape
+const collectTimeout = 10 * time.Second
+
+var (
+	codesetsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "codesets"),
+		"Number of registered codesets.", nil, nil)
+	workflowsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "workflows"),
+		"Number of registered workflows.", nil, nil)
+	workflowRunsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "workflow_runs"),
+		"Number of workflow runs, by workflow and status.", []string{"workflow", "status"}, nil)
+	extensionsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "extensions"),
+		"Number of extensions in the extension registry.", nil, nil)
+	storeSizeDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "store", "size_bytes"),
+		"Size of the badger store, by type of files (lsm, vlog).", []string{"type"}, nil)
+)
+
+// DomainCollector collects the metrics describing the resources managed by FuseML when scraped.
+type DomainCollector struct {
+	logger     *zap.SugaredLogger
+	codesets   domain.CodesetStore
+	workflows  domain.WorkflowManager
+	extensions domain.ExtensionRegistry
+	store      *badgerhold.Store
+}
+
+// NewDomainCollector returns a new DomainCollector.
+func NewDomainCollector(logger *zap.SugaredLogger, codesets domain.CodesetStore, workflows domain.WorkflowManager,
+	extensions domain.ExtensionRegistry, store *badgerhold.Store) *DomainCollector {
+	return &DomainCollector{logger, codesets, workflows, extensions, store}
+}
+
+// Describe implements prometheus.Collector.
+func (c *DomainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- codesetsDesc
+	ch <- workflowsDesc
+	ch <- workflowRunsDesc
+	ch <- extensionsDesc
+	ch <- storeSizeDesc
+}
+
+// Collect implements prometheus.Collector. A metric that cannot be collected is logged and
+// left out, so that the other metrics are still exposed.
+func (c *DomainCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	if codesets, _, err := c.codesets.GetAll(ctx, nil, nil, true, nil, nil); err != nil {
+		c.logger.Errorw("failed to collect the codeset metrics", logging.ErrorKey, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(codesetsDesc, prometheus.GaugeValue, float64(len(codesets)))
+	}
+
+	if workflows, _, err := c.workflows.GetWorkflows(ctx, nil, nil); err != nil {
+		c.logger.Errorw("failed to collect the workflow metrics", logging.ErrorKey, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(workflowsDesc, prometheus.GaugeValue, float64(len(workflows)))
+	}
+
+	if runs, _, err := c.workflows.GetWorkflowRuns(ctx, &domain.WorkflowRunFilter{}, nil); err != nil {
+		c.logger.Errorw("failed to collect the workflow run metrics", logging.ErrorKey, err)
+	} else {
+		counts := map[[2]string]int{}
+		for _, run := range runs {
+			counts[[2]string{run.WorkflowRef, run.Status}]++
+		}
+		for labels, count := range counts {
+			ch <- prometheus.MustNewConstMetric(workflowRunsDesc, prometheus.GaugeValue, float64(count), labels[0], labels[1])
+		}
+	}
+
+	if extensions, _, err := c.extensions.ListExtensions(ctx, &domain.ExtensionQuery{}, nil); err != nil {
+		c.logger.Errorw("failed to collect the extension metrics", logging.ErrorKey, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(extensionsDesc, prometheus.GaugeValue, float64(len(extensions)))
+	}
+
+	lsm, vlog := c.store.Badger().Size()
+	ch <- prometheus.MustNewConstMetric(storeSizeDesc, prometheus.GaugeValue, float64(lsm), "lsm")
+	ch <- prometheus.MustNewConstMetric(storeSizeDesc, prometheus.GaugeValue, float64(vlog), "vlog")
+}
@@ -0,0 +1,158 @@
+// Package metrics exposes the fuseml-core metrics in the Prometheus format.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	goa "goa.design/goa/v3/pkg"
+	"google.golang.org/grpc"
+)
+
+const (
+	namespace = "fuseml"
+
+	// TransportHTTP labels the requests received by the HTTP server
+	TransportHTTP = "http"
+	// TransportGRPC labels the requests received by the gRPC server
+	TransportGRPC = "grpc"
+
+	// resultSuccess labels the requests that did not return an error
+	resultSuccess = "success"
+	// resultError labels the requests that returned an error that is not a Goa service error
+	resultError = "error"
+)
+
+// transportKey is the context key under which the transport of a request is stored
+type transportKey struct{}
+
+// Metrics holds the collectors exposed on the /metrics endpoint.
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	backendDuration *prometheus.HistogramVec
+	backendErrors   *prometheus.CounterVec
+}
+
+// New creates the API and backend metrics, along with the Go runtime and process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "requests_total",
+			Help:      "Number of API requests, by transport, service, method and result.",
+		}, []string{"transport", "service", "method", "result"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "api",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the API requests, by transport, service and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"transport", "service", "method"}),
+		backendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "backend",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests sent to the backends (gitea, tekton), by backend, HTTP method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"backend", "method", "code"}),
+		backendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "backend",
+			Name:      "request_errors_total",
+			Help:      "Number of requests sent to the backends (gitea, tekton) that failed or returned a server error.",
+		}, []string{"backend", "method"}),
+	}
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.backendDuration,
+		m.backendErrors,
+	)
+	return m
+}
+
+// Register adds a collector to the exposed metrics.
+func (m *Metrics) Register(c prometheus.Collector) error {
+	return m.registry.Register(c)
+}
+
+// Handler returns the HTTP handler serving the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// EndpointMiddleware is a Goa endpoint middleware recording the number and the latency of the
+// requests for each service method.
+func (m *Metrics) EndpointMiddleware(e goa.Endpoint) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		service, _ := ctx.Value(goa.ServiceKey).(string)
+		method, _ := ctx.Value(goa.MethodKey).(string)
+		transport, _ := ctx.Value(transportKey{}).(string)
+
+		start := time.Now()
+		res, err := e(ctx, req)
+		m.requestDuration.WithLabelValues(transport, service, method).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(transport, service, method, result(err)).Inc()
+		return res, err
+	}
+}
+
+// result returns the label describing the outcome of a request: the name of the returned
+// service error, e.g. NotFound, or a generic label.
+func result(err error) string {
+	if err == nil {
+		return resultSuccess
+	}
+	if serr, ok := err.(*goa.ServiceError); ok && serr.Name != "" {
+		return serr.Name
+	}
+	return resultError
+}
+
+// HTTPMiddleware labels the requests received by the HTTP server with their transport.
+func (m *Metrics) HTTPMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), transportKey{}, TransportHTTP)))
+	})
+}
+
+// UnaryServerInterceptor labels the requests received by the gRPC server with their transport.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(context.WithValue(ctx, transportKey{}, TransportGRPC), req)
+	}
+}
+
+// InstrumentTransport returns a round tripper recording the latency and the errors of the requests
+// sent to a backend through the given round tripper.
+func (m *Metrics) InstrumentTransport(backend string, rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := rt.RoundTrip(r)
+		code := resultError
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		m.backendDuration.WithLabelValues(backend, r.Method, code).Observe(time.Since(start).Seconds())
+		if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+			m.backendErrors.WithLabelValues(backend, r.Method).Inc()
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
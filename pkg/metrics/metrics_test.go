@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestEndpointMiddleware(t *testing.T) {
+	m := New()
+	endpoint := m.EndpointMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		if req != nil {
+			return nil, goa.PermanentError("NotFound", "not found")
+		}
+		return "ok", nil
+	})
+
+	ctx := context.WithValue(context.Background(), goa.ServiceKey, "codeset")
+	ctx = context.WithValue(ctx, goa.MethodKey, "get")
+	ctx = context.WithValue(ctx, transportKey{}, TransportHTTP)
+	endpoint(ctx, nil)
+	endpoint(ctx, "missing")
+	endpoint(ctx, "missing")
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues(TransportHTTP, "codeset", "get", resultSuccess)); got != 1 {
+		t.Errorf("unexpected number of successful requests: got %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.requests.WithLabelValues(TransportHTTP, "codeset", "get", "NotFound")); got != 2 {
+		t.Errorf("unexpected number of failed requests: got %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(m.requestDuration); got != 1 {
+		t.Errorf("unexpected number of latency histograms: got %v, want 1", got)
+	}
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	m := New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: m.InstrumentTransport("gitea", http.DefaultTransport)}
+	for _, path := range []string{"/ok", "/fail"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	client.Transport = m.InstrumentTransport("gitea", roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}))
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := testutil.ToFloat64(m.backendErrors.WithLabelValues("gitea", http.MethodGet)); got != 2 {
+		t.Errorf("unexpected number of backend errors: got %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(m.backendDuration); got != 3 {
+		t.Errorf("unexpected number of latency histograms: got %v, want 3", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.EndpointMiddleware(func(context.Context, interface{}) (interface{}, error) { return nil, nil })(
+			context.WithValue(r.Context(), goa.ServiceKey, "version"), nil)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+	for _, want := range []string{`fuseml_api_requests_total{method="",result="success",service="version",transport="http"} 1`, "go_goroutines"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected the metrics to contain %q", want)
+		}
+	}
+}
@@ -2,12 +2,17 @@ package svc
 
 import (
 	"context"
+	"time"
+
 	"github.com/pkg/errors"
-	"log"
+	"go.uber.org/zap"
 
 	"github.com/fuseml/fuseml-core/gen/application"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 func appRestToDomain(ra *application.Application) (a *domain.Application, err error) {
@@ -21,6 +26,21 @@ func appRestToDomain(ra *application.Application) (a *domain.Application, err er
 	if ra.Description != nil {
 		a.Description = *ra.Description
 	}
+	if ra.WorkflowRun != nil {
+		a.WorkflowRun = *ra.WorkflowRun
+	}
+	if ra.Codeset != nil {
+		a.Codeset = &domain.ApplicationCodeset{
+			Project: ra.Codeset.Project,
+			Name:    ra.Codeset.Name,
+		}
+	}
+	if ra.CodesetVersion != nil {
+		a.CodesetVersion = *ra.CodesetVersion
+	}
+	if ra.Version != nil {
+		a.Version = *ra.Version
+	}
 	for _, res := range ra.K8sResources {
 		a.K8sResources = append(a.K8sResources,
 			&domain.KubernetesResource{
@@ -43,6 +63,39 @@ func appDomainToRest(a *domain.Application) (ret *application.Application) {
 	if a.Description != "" {
 		ret.Description = &a.Description
 	}
+	if a.WorkflowRun != "" {
+		ret.WorkflowRun = &a.WorkflowRun
+	}
+	if a.Codeset != nil {
+		ret.Codeset = &application.ApplicationCodeset{
+			Project: a.Codeset.Project,
+			Name:    a.Codeset.Name,
+		}
+	}
+	if a.CodesetVersion != "" {
+		ret.CodesetVersion = &a.CodesetVersion
+	}
+	if !a.CreatedAt.IsZero() {
+		createdAt := a.CreatedAt.Format(time.RFC3339)
+		ret.CreatedAt = &createdAt
+	}
+	if a.Version != 0 {
+		ret.Version = &a.Version
+	}
+	if a.Replaced != nil {
+		ret.Replaced = &application.ApplicationReplacement{
+			ReplacedAt:       a.Replaced.ReplacedAt.Format(time.RFC3339),
+			ReplacedBy:       a.Replaced.ReplacedBy,
+			PreviousVersion:  a.Replaced.PreviousVersion,
+			PreviousWorkflow: a.Replaced.PreviousWorkflow,
+		}
+		if a.Replaced.PreviousWorkflowRun != "" {
+			ret.Replaced.PreviousWorkflowRun = &a.Replaced.PreviousWorkflowRun
+		}
+		if a.Replaced.PreviousCodesetVersion != "" {
+			ret.Replaced.PreviousCodesetVersion = &a.Replaced.PreviousCodesetVersion
+		}
+	}
 	for _, res := range a.K8sResources {
 		ret.K8sResources = append(ret.K8sResources,
 			&application.KubernetesResource{
@@ -51,60 +104,226 @@ func appDomainToRest(a *domain.Application) (ret *application.Application) {
 			},
 		)
 	}
+	if a.Status != nil {
+		ret.Status = &application.ApplicationStatus{
+			State:       a.Status.State,
+			LastChecked: a.Status.LastChecked.Format(time.RFC3339),
+		}
+		if a.Status.Message != "" {
+			ret.Status.Message = &a.Status.Message
+		}
+	}
 	return ret
 }
 
 // application service implementation.
 type applicationsrvc struct {
-	logger *log.Logger
-	store  domain.ApplicationStore
+	*authorizer
+	logger    *zap.SugaredLogger
+	store     domain.ApplicationStore
+	namespace string
 }
 
 // NewApplicationService returns the application service implementation.
-func NewApplicationService(logger *log.Logger, store domain.ApplicationStore) application.Service {
-	return &applicationsrvc{logger, store}
+func NewApplicationService(logger *zap.SugaredLogger, store domain.ApplicationStore, cfg config.TektonConfig,
+	authenticator domain.Authenticator, members domain.ProjectMemberStore) application.Service {
+	return &applicationsrvc{&authorizer{authenticator, members}, logger, store, cfg.Namespace}
+}
+
+// authorizeApp checks the role of the caller in the project of the codeset an application was produced
+// from. Applications that are not linked to a codeset can be viewed by anyone, but only changed by admins.
+func (s *applicationsrvc) authorizeApp(ctx context.Context, app *domain.Application, role domain.ProjectRole) error {
+	if app.Codeset != nil {
+		return s.authorize(ctx, app.Codeset.Project, role)
+	}
+	if role == domain.ProjectRoleViewer {
+		return nil
+	}
+	return s.authorizeAdmin(ctx)
 }
 
 // Retrieve information about applications registered in FuseML.
-func (s *applicationsrvc) List(ctx context.Context, p *application.ListPayload) (res []*application.Application, err error) {
-	s.logger.Print("application.list")
-	items, err := s.store.GetAll(ctx, p.Type, p.Workflow)
-	res = make([]*application.Application, 0, len(items))
+func (s *applicationsrvc) List(ctx context.Context, p *application.ListPayload) (res *application.ApplicationPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("application.list")
+	visible, err := s.visibleProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.store.GetAll(ctx, &domain.ApplicationFilter{
+		Type:           p.Type,
+		Workflow:       p.Workflow,
+		CodesetProject: p.CodesetProject,
+		CodesetName:    p.CodesetName,
+		Visible:        visible,
+	}, listOptions(p.Limit, p.Continue, p.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, application.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &application.ApplicationPage{Items: make([]*application.Application, 0, len(items)), Continue: util.RefString(next)}
 	for _, a := range items {
-		res = append(res, appDomainToRest(a))
+		res.Items = append(res.Items, appDomainToRest(a))
 	}
-	return res, err
+	return res, nil
 }
 
 // Register a application with the FuseML application store.
-func (s *applicationsrvc) Register(ctx context.Context, a *application.Application) (res *application.Application, err error) {
-	s.logger.Print("application.register")
-	app, err := appRestToDomain(a)
+func (s *applicationsrvc) Register(ctx context.Context, p *application.RegisterPayload) (res *application.Application, err error) {
+	logging.FromContext(ctx, s.logger).Info("application.register")
+	app, err := appRestToDomain(p.Application)
+	if err != nil {
+		return nil, application.MakeBadRequest(err)
+	}
+	if err := s.authorizeApp(ctx, app, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	// the resources of the applications are deleted along with them, so they are restricted to the
+	// workloads namespace
+	if app.K8sNamespace != s.namespace {
+		return nil, application.MakeBadRequest(domain.ErrApplicationNamespace)
+	}
+	app.CreatedAt = time.Now()
+
+	existing := s.store.Find(ctx, app.Name)
+	if existing == nil {
+		added, err := s.store.Add(ctx, app)
+		if err == nil {
+			return appDomainToRest(added), nil
+		}
+		if err != domain.ErrApplicationExists {
+			return nil, err
+		}
+		// registered in the meantime
+		existing = s.store.Find(ctx, app.Name)
+		if existing == nil {
+			return nil, application.MakeConflict(err)
+		}
+	}
+	if !p.Replace {
+		return nil, application.MakeConflict(errors.Errorf("Application %q is already registered by workflow %q, "+
+			"set replace to replace it", app.Name, existing.Workflow))
+	}
+	if err := s.authorizeApp(ctx, existing, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+
+	app.Version = existing.Version
+	app.Replaced = &domain.ApplicationReplacement{
+		ReplacedAt:             app.CreatedAt,
+		ReplacedBy:             replacedBy(app),
+		PreviousVersion:        existing.Version,
+		PreviousWorkflow:       existing.Workflow,
+		PreviousWorkflowRun:    existing.WorkflowRun,
+		PreviousCodesetVersion: existing.CodesetVersion,
+	}
+	app, err = s.store.Update(ctx, app)
+	if err != nil {
+		if err == domain.ErrApplicationVersionConflict || err == domain.ErrApplicationNotFound {
+			return nil, application.MakeConflict(errors.Wrap(err, "Application was modified while being replaced"))
+		}
+		return nil, err
+	}
+	logging.FromContext(ctx, s.logger).Infow("application replaced", "application", app.Name, "version", existing.Version,
+		logging.WorkflowKey, existing.Workflow, logging.RunKey, existing.WorkflowRun, "replacedBy", app.Replaced.ReplacedBy)
+	return appDomainToRest(app), nil
+}
+
+// Update an Application registered by FuseML.
+func (s *applicationsrvc) Update(ctx context.Context, p *application.UpdatePayload) (res *application.Application, err error) {
+	logging.FromContext(ctx, s.logger).Info("application.update")
+	if p.Version == nil {
+		return nil, application.MakeBadRequest(errors.New("the version of the Application is required when updating it"))
+	}
+	app, err := appRestToDomain(&application.Application{
+		Name:           p.Name,
+		Type:           p.Type,
+		Description:    p.Description,
+		URL:            p.URL,
+		Workflow:       p.Workflow,
+		K8sResources:   p.K8sResources,
+		K8sNamespace:   p.K8sNamespace,
+		WorkflowRun:    p.WorkflowRun,
+		Codeset:        p.Codeset,
+		CodesetVersion: p.CodesetVersion,
+		Version:        p.Version,
+	})
 	if err != nil {
 		return nil, application.MakeBadRequest(err)
 	}
-	app, err = s.store.Add(ctx, app)
-	return appDomainToRest(app), err
+
+	existing := s.store.Find(ctx, app.Name)
+	if existing == nil {
+		return nil, application.MakeNotFound(errors.New("Application with the specified name not found"))
+	}
+	if err := s.authorizeApp(ctx, existing, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeApp(ctx, app, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	if app.K8sNamespace != s.namespace {
+		return nil, application.MakeBadRequest(domain.ErrApplicationNamespace)
+	}
+	// fields that are managed by FuseML are kept from the registered application
+	app.CreatedAt = existing.CreatedAt
+	app.Status = existing.Status
+	app.Replaced = existing.Replaced
+
+	app, err = s.store.Update(ctx, app)
+	if err != nil {
+		switch err {
+		case domain.ErrApplicationNotFound:
+			return nil, application.MakeNotFound(err)
+		case domain.ErrApplicationVersionConflict:
+			return nil, application.MakeConflict(err)
+		}
+		return nil, err
+	}
+	return appDomainToRest(app), nil
+}
+
+// replacedBy identifies who is replacing an application: the workflow run that registers it or, if not
+// known, the workflow.
+func replacedBy(app *domain.Application) string {
+	if app.WorkflowRun != "" {
+		return "workflow run " + app.WorkflowRun
+	}
+	return "workflow " + app.Workflow
 }
 
 // Retrieve an Application from FuseML.
 func (s *applicationsrvc) Get(ctx context.Context, p *application.GetPayload) (res *application.Application, err error) {
-	s.logger.Print("application.get")
+	logging.FromContext(ctx, s.logger).Info("application.get")
 
 	app := s.store.Find(ctx, p.Name)
 	if app == nil {
 		return nil, application.MakeNotFound(errors.New("Application with the specified name not found"))
 	}
+	if err := s.authorizeApp(ctx, app, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
 	return appDomainToRest(app), nil
 }
 
 // Delete an Application registered by FuseML.
 func (s *applicationsrvc) Delete(ctx context.Context, p *application.DeletePayload) error {
-	s.logger.Print("application.delete")
+	logging.FromContext(ctx, s.logger).Info("application.delete")
 	app := s.store.Find(ctx, p.Name)
 	if app == nil {
 		return application.MakeNotFound(errors.New("Application with the specified name not found"))
 	}
+	if err := s.authorizeApp(ctx, app, domain.ProjectRoleEditor); err != nil {
+		return err
+	}
+	if app.K8sNamespace != s.namespace {
+		// not deleting the resources of the applications registered before they were restricted to the
+		// workloads namespace
+		logging.FromContext(ctx, s.logger).Warnw("Not deleting the application resources outside of the workloads namespace",
+			"application", app.Name, "namespace", app.K8sNamespace)
+		return s.store.Delete(ctx, p.Name)
+	}
 	cluster, err := kubernetes.NewCluster(s.logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed initializing kubernetes cluster")
@@ -0,0 +1,168 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	goa "goa.design/goa/v3/pkg"
+
+	"github.com/fuseml/fuseml-core/gen/application"
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const testWorkloadsNamespace = "fuseml-workloads"
+
+// newTestApplicationService returns an application service with an application produced from a codeset of
+// each of the "prj" and "other" projects, an application that is not linked to a codeset and legacy
+// applications registered outside of the workloads namespace, which are deleted without their resources
+func newTestApplicationService() *applicationsrvc {
+	store := core.NewApplicationStore()
+	apps := []*domain.Application{
+		{Name: "app", Codeset: &domain.ApplicationCodeset{Project: "prj", Name: "cs"}, K8sNamespace: testWorkloadsNamespace},
+		{Name: "other", Codeset: &domain.ApplicationCodeset{Project: "other", Name: "cs"}, K8sNamespace: testWorkloadsNamespace},
+		{Name: "unlinked", K8sNamespace: testWorkloadsNamespace},
+		{Name: "legacy", Codeset: &domain.ApplicationCodeset{Project: "prj", Name: "cs"}, K8sNamespace: "kube-system"},
+		{Name: "unlinked-legacy", K8sNamespace: "kube-system"},
+	}
+	for _, app := range apps {
+		if _, err := store.Add(context.Background(), app); err != nil {
+			panic(err)
+		}
+	}
+	return NewApplicationService(zap.NewNop().Sugar(), store, config.TektonConfig{Namespace: testWorkloadsNamespace},
+		nil, newTestMembers()).(*applicationsrvc)
+}
+
+func testApplication(name, project string) *application.Application {
+	app := &application.Application{Name: name, K8sNamespace: testWorkloadsNamespace}
+	if project != "" {
+		app.Codeset = &application.ApplicationCodeset{Project: project, Name: "cs"}
+	}
+	return app
+}
+
+func TestApplicationAuthorization(t *testing.T) {
+	version := uint64(1)
+	tests := []struct {
+		name    string
+		allowed []string
+		call    func(ctx context.Context, s *applicationsrvc) error
+	}{
+		{
+			name:    "get",
+			allowed: prjViewers,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Get(ctx, &application.GetPayload{Name: "app"})
+				return err
+			},
+		},
+		{
+			name:    "get unlinked",
+			allowed: []string{"admin", "owner", "editor", "viewer", "non-member", "workflow", "anonymous"},
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Get(ctx, &application.GetPayload{Name: "unlinked"})
+				return err
+			},
+		},
+		{
+			name:    "register",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Register(ctx, &application.RegisterPayload{Application: testApplication("new", "prj")})
+				return err
+			},
+		},
+		{
+			name:    "register unlinked",
+			allowed: admins,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Register(ctx, &application.RegisterPayload{Application: testApplication("new", "")})
+				return err
+			},
+		},
+		{
+			name:    "replace",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Register(ctx, &application.RegisterPayload{Application: testApplication("app", "prj"), Replace: true})
+				return err
+			},
+		},
+		{
+			name:    "replace from another project",
+			allowed: admins,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Register(ctx, &application.RegisterPayload{Application: testApplication("other", "prj"), Replace: true})
+				return err
+			},
+		},
+		{
+			name:    "update",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				_, err := s.Update(ctx, &application.UpdatePayload{Name: "app", K8sNamespace: testWorkloadsNamespace,
+					Codeset: &application.ApplicationCodeset{Project: "prj", Name: "cs"}, Version: &version})
+				return err
+			},
+		},
+		{
+			name:    "delete",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				return s.Delete(ctx, &application.DeletePayload{Name: "legacy"})
+			},
+		},
+		{
+			name:    "delete unlinked",
+			allowed: admins,
+			call: func(ctx context.Context, s *applicationsrvc) error {
+				return s.Delete(ctx, &application.DeletePayload{Name: "unlinked-legacy"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		checkAuthorization(t, tt.name, tt.allowed, func(ctx context.Context) error {
+			return tt.call(ctx, newTestApplicationService())
+		})
+	}
+}
+
+func TestApplicationNamespace(t *testing.T) {
+	s := newTestApplicationService()
+	ctx := principalContext(&domain.Principal{Name: "admin", Admin: true})
+	app := testApplication("new", "prj")
+	app.K8sNamespace = "kube-system"
+	version := uint64(1)
+
+	_, err := s.Register(ctx, &application.RegisterPayload{Application: app})
+	assertBadRequest(t, "register", err)
+	_, err = s.Update(ctx, &application.UpdatePayload{Name: "app", K8sNamespace: "kube-system", Version: &version})
+	assertBadRequest(t, "update", err)
+	if s.store.Find(ctx, "new") != nil {
+		t.Errorf("application outside of the workloads namespace was registered")
+	}
+	if got := s.store.Find(ctx, "app").K8sNamespace; got != testWorkloadsNamespace {
+		t.Errorf("application was moved to namespace %q", got)
+	}
+
+	// the legacy applications are deleted without deleting their resources
+	if err := s.Delete(ctx, &application.DeletePayload{Name: "legacy"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.store.Find(ctx, "legacy") != nil {
+		t.Errorf("legacy application was not deleted")
+	}
+}
+
+func assertBadRequest(t *testing.T, name string, err error) {
+	t.Helper()
+
+	var serr *goa.ServiceError
+	if !errors.As(err, &serr) || serr.Name != "BadRequest" {
+		t.Errorf("%s: expected a BadRequest error, got: %v", name, err)
+	}
+}
@@ -0,0 +1,339 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	goa "goa.design/goa/v3/pkg"
+
+	"github.com/fuseml/fuseml-core/gen/audit"
+	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// maxAuditPayloadLength is the maximum length of the payload summary recorded for an operation
+	maxAuditPayloadLength = 1024
+	// redacted replaces the values of the secrets in the recorded payloads
+	redacted = "<redacted>"
+	// auditPruneInterval is the interval at which the audit events older than the retention period are removed
+	auditPruneInterval = 1 * time.Hour
+)
+
+var (
+	// unauditedServices are the services that do not change the resources managed by FuseML
+	unauditedServices = []string{"auth", "audit", "version"}
+	// readMethodPrefixes identify the methods that do not change the resources managed by FuseML
+	readMethodPrefixes = []string{"get", "list"}
+	// secretFields are the payload fields that hold secrets
+	secretFields = []string{"token", "key", "password", "secret"}
+	// resourceFields are the payload fields identifying the resource targeted by an operation, in order of priority
+	resourceFields = []string{"Name", "ID", "ExtensionID"}
+	// projectFields are the payload fields identifying the project targeted by an operation, in order of priority
+	projectFields = []string{"Project", "CodesetProject"}
+)
+
+// auditedCallKey is the context key under which the call being audited is stored
+type auditedCallKey struct{}
+
+// auditedCall holds the information about a call that is only available while it is performed
+type auditedCall struct {
+	principal string
+}
+
+// recordPrincipal stores the principal authenticated for a request in the call being audited, if any.
+func recordPrincipal(ctx context.Context, principal *domain.Principal) {
+	if call, ok := ctx.Value(auditedCallKey{}).(*auditedCall); ok {
+		call.principal = principal.Name
+	}
+}
+
+// Auditor records the create, update and delete operations performed through the FuseML API.
+type Auditor struct {
+	logger    *zap.SugaredLogger
+	store     domain.AuditStore
+	retention time.Duration
+}
+
+// NewAuditor returns a new Auditor, keeping the audit events for the retention period configured
+// for the store.
+func NewAuditor(logger *zap.SugaredLogger, store domain.AuditStore, cfg config.StoreConfig) *Auditor {
+	return &Auditor{logger, store, cfg.AuditRetentionPeriod()}
+}
+
+// Run periodically removes the audit events older than the retention period, until the context is
+// cancelled. It returns immediately when the audit events are kept forever.
+func (a *Auditor) Run(ctx context.Context) {
+	if a.retention == 0 {
+		return
+	}
+	ticker := time.NewTicker(auditPruneInterval)
+	defer ticker.Stop()
+	for {
+		a.Prune(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune removes the audit events older than the retention period once.
+func (a *Auditor) Prune(ctx context.Context) {
+	n, err := a.store.DeleteBefore(ctx, time.Now().UTC().Add(-a.retention))
+	if err != nil {
+		a.logger.Errorw("Failed to remove the expired audit events", logging.ErrorKey, err)
+		return
+	}
+	if n > 0 {
+		a.logger.Infof("Removed %d expired audit events", n)
+	}
+}
+
+// Middleware is a Goa endpoint middleware recording an audit event for each call of a method
+// that changes the resources managed by FuseML.
+func (a *Auditor) Middleware(e goa.Endpoint) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		service, _ := ctx.Value(goa.ServiceKey).(string)
+		method, _ := ctx.Value(goa.MethodKey).(string)
+		if !audited(service, method) {
+			return e(ctx, req)
+		}
+
+		call := &auditedCall{}
+		res, err := e(context.WithValue(ctx, auditedCallKey{}, call), req)
+
+		event := &domain.AuditEvent{
+			Timestamp: time.Now().UTC(),
+			Principal: call.principal,
+			Service:   service,
+			Method:    method,
+			Result:    domain.AuditResultSuccess,
+		}
+		event.Payload, event.Project, event.Resource = summarizePayload(req)
+		if err != nil {
+			event.Result = domain.AuditResultFailure
+			event.Error = err.Error()
+		}
+		if storeErr := a.store.Add(ctx, event); storeErr != nil {
+			logging.FromContext(ctx, a.logger).Errorw("failed to record audit event", "service", service, "method", method,
+				logging.ErrorKey, storeErr)
+		}
+		return res, err
+	}
+}
+
+// audited returns whether the calls of a service method are recorded.
+func audited(service, method string) bool {
+	if service == "" || util.StringInSlice(service, unauditedServices) {
+		return false
+	}
+	for _, prefix := range readMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// summarizePayload returns a JSON summary of a method payload, with the secrets redacted, along with
+// the project and resource targeted by the operation, when they can be identified.
+func summarizePayload(payload interface{}) (summary, project, resource string) {
+	if payload == nil {
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil {
+		return
+	}
+	project = firstField(fields, projectFields)
+	resource = firstField(fields, resourceFields)
+
+	redact(fields)
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return
+	}
+	summary = string(data)
+	if len(summary) > maxAuditPayloadLength {
+		summary = summary[:maxAuditPayloadLength] + "..."
+	}
+	return
+}
+
+// firstField returns the value of the first string field with one of the given names. When not found,
+// the nested objects are searched, e.g. the codeset described by the codeset.register payload.
+func firstField(fields map[string]interface{}, names []string) string {
+	for _, name := range names {
+		if v, ok := fields[name].(string); ok && v != "" {
+			return v
+		}
+	}
+	for _, field := range fields {
+		if nested, ok := field.(map[string]interface{}); ok {
+			if v := firstField(nested, names); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// redact replaces the secrets found in a decoded JSON value and returns the redacted value. The
+// configuration of the extension credentials is always considered secret, as well as the user
+// information of the URLs, e.g. the credentials of the external git repositories.
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			lowerName := strings.ToLower(name)
+			switch {
+			case field == nil:
+			case util.StringInSlice(lowerName, secretFields):
+				v[name] = redacted
+			case lowerName == "configuration" && isCredentials(v):
+				v[name] = redacted
+			default:
+				v[name] = redact(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	case string:
+		return redactURL(v)
+	}
+	return value
+}
+
+// redactURL replaces the user information of a URL, returning any other value unchanged.
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	u.User = nil
+	return u.Scheme + "://" + redacted + "@" + strings.TrimPrefix(u.String(), u.Scheme+"://")
+}
+
+// isCredentials returns whether a decoded JSON object describes extension credentials.
+func isCredentials(fields map[string]interface{}) bool {
+	_, hasScope := fields["Scope"]
+	_, hasUsers := fields["Users"]
+	return hasScope || hasUsers
+}
+
+// audit service implementation.
+type auditsrvc struct {
+	*authorizer
+	logger *zap.SugaredLogger
+	store  domain.AuditStore
+}
+
+// NewAuditService returns the audit service implementation.
+func NewAuditService(logger *zap.SugaredLogger, store domain.AuditStore, authenticator domain.Authenticator,
+	members domain.ProjectMemberStore) audit.Service {
+	return &auditsrvc{&authorizer{authenticator, members}, logger, store}
+}
+
+// List the audit events recorded for the create, update and delete operations. Admins can list all
+// the events, the other users only the events of the projects in which they have the admin role.
+func (s *auditsrvc) List(ctx context.Context, p *audit.ListPayload) (res *audit.AuditEventPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("audit.list")
+	filter := &domain.AuditFilter{
+		Service:  p.Service,
+		Resource: p.Resource,
+	}
+	if filter.Since, err = parseTimeFilter(p.Since); err != nil {
+		return nil, audit.MakeBadRequest(err)
+	}
+	if filter.Until, err = parseTimeFilter(p.Until); err != nil {
+		return nil, audit.MakeBadRequest(err)
+	}
+
+	if p.Project != nil {
+		if err := s.authorize(ctx, *p.Project, domain.ProjectRoleAdmin); err != nil {
+			return nil, err
+		}
+		filter.Projects = []string{*p.Project}
+	} else if s.authorizeAdmin(ctx) != nil {
+		filter.Projects, err = s.adminProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	events, next, err := s.store.GetAll(ctx, filter, listOptions(p.Limit, p.Continue, p.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, audit.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &audit.AuditEventPage{Items: make([]*audit.AuditEvent, 0, len(events)), Continue: util.RefString(next)}
+	for _, e := range events {
+		res.Items = append(res.Items, auditEventDomainToRest(e))
+	}
+	return res, nil
+}
+
+// adminProjects returns the projects in which the caller has the admin role.
+func (s *auditsrvc) adminProjects(ctx context.Context) ([]string, error) {
+	projects := []string{}
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return projects, nil
+	}
+	memberships, err := s.members.GetMemberships(ctx, principal.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memberships {
+		if m.Role.Includes(domain.ProjectRoleAdmin) {
+			projects = append(projects, m.Project)
+		}
+	}
+	return projects, nil
+}
+
+func parseTimeFilter(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func auditEventDomainToRest(e *domain.AuditEvent) *audit.AuditEvent {
+	return &audit.AuditEvent{
+		ID:        e.ID,
+		Timestamp: e.Timestamp.Format(time.RFC3339),
+		Principal: e.Principal,
+		Service:   e.Service,
+		Method:    e.Method,
+		Project:   util.RefString(e.Project),
+		Resource:  util.RefString(e.Resource),
+		Payload:   util.RefString(e.Payload),
+		Result:    e.Result,
+		Error:     util.RefString(e.Error),
+	}
+}
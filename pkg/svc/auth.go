@@ -0,0 +1,191 @@
+package svc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	goa "goa.design/goa/v3/pkg"
+	"goa.design/goa/v3/security"
+
+	"github.com/fuseml/fuseml-core/gen/auth"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// authorizer implements the authentication functions required by the secured services. On success, the
+// authenticated principal is stored in the request context. It also checks the roles granted to the
+// principal in the projects that own the resources targeted by a request.
+type authorizer struct {
+	authenticator domain.Authenticator
+	members       domain.ProjectMemberStore
+}
+
+// JWTAuth implements the authorization logic for the JWT security scheme.
+func (a *authorizer) JWTAuth(ctx context.Context, token string, scheme *security.JWTScheme) (context.Context, error) {
+	if token == "" {
+		return ctx, unauthorized(domain.ErrInvalidCredentials)
+	}
+	principal, err := a.authenticator.ValidateToken(ctx, token)
+	if err != nil {
+		return ctx, unauthorized(err)
+	}
+	recordPrincipal(ctx, principal)
+	return domain.ContextWithPrincipal(ctx, principal), nil
+}
+
+// APIKeyAuth implements the authorization logic for the API key security scheme.
+func (a *authorizer) APIKeyAuth(ctx context.Context, key string, scheme *security.APIKeyScheme) (context.Context, error) {
+	if key == "" {
+		return ctx, unauthorized(domain.ErrInvalidCredentials)
+	}
+	principal, err := a.authenticator.ValidateAPIKey(ctx, key)
+	if err != nil {
+		return ctx, unauthorized(err)
+	}
+	recordPrincipal(ctx, principal)
+	return domain.ContextWithPrincipal(ctx, principal), nil
+}
+
+// unauthorized returns the error mapped to the Unauthorized response of the secured services.
+func unauthorized(err error) error {
+	return goa.PermanentError("Unauthorized", err.Error())
+}
+
+// forbidden returns the error mapped to the Forbidden response of the secured services.
+func forbidden(format string, v ...interface{}) error {
+	return goa.PermanentError("Forbidden", format, v...)
+}
+
+// authorize checks that the principal authenticated for the request was granted at least the
+// given role in a project. Admin principals are allowed to perform any operation.
+func (a *authorizer) authorize(ctx context.Context, project string, role domain.ProjectRole) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return forbidden("request is not authenticated")
+	}
+	if principal.Admin {
+		return nil
+	}
+	if principal.Workflow != "" {
+		// the steps of a workflow act as editors of the projects of the codesets the workflow is assigned to
+		if !util.StringInSlice(project, principal.Projects) {
+			return forbidden("workflow %q is not assigned to a codeset of project %q", principal.Workflow, project)
+		}
+		if !domain.ProjectRoleEditor.Includes(role) {
+			return forbidden("workflow %q requires the %s role in project %q", principal.Workflow, role, project)
+		}
+		return nil
+	}
+	member, err := a.members.GetMember(ctx, project, principal.Name)
+	if err != nil {
+		if err == domain.ErrProjectMemberNotFound {
+			return forbidden("user %q is not a member of project %q", principal.Name, project)
+		}
+		return err
+	}
+	if !member.Role.Includes(role) {
+		return forbidden("user %q requires the %s role in project %q", principal.Name, role, project)
+	}
+	return nil
+}
+
+// authorizeAdmin checks that the principal authenticated for the request is an admin.
+func (a *authorizer) authorizeAdmin(ctx context.Context) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil || !principal.Admin {
+		return forbidden("operation is restricted to admins")
+	}
+	return nil
+}
+
+// visibleProjects returns a function reporting whether the principal authenticated for the request
+// may view the resources of a project, passed to the stores to filter the listings before they are
+// paged. It returns nil for the admins, who may view the resources of all the projects.
+func (a *authorizer) visibleProjects(ctx context.Context) (domain.ProjectVisibility, error) {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return func(string) bool { return false }, nil
+	}
+	if principal.Admin {
+		return nil, nil
+	}
+	if principal.Workflow != "" {
+		return func(project string) bool { return util.StringInSlice(project, principal.Projects) }, nil
+	}
+	memberships, err := a.members.GetMemberships(ctx, principal.Name)
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[string]bool, len(memberships))
+	for _, m := range memberships {
+		projects[m.Project] = true
+	}
+	return func(project string) bool { return projects[project] }, nil
+}
+
+// authorizeProjectCreation checks that the principal authenticated for the request may create a project,
+// e.g. implicitly when registering its first codeset. Any authenticated principal may create projects, except
+// the steps of the workflows.
+func (a *authorizer) authorizeProjectCreation(ctx context.Context) error {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return forbidden("request is not authenticated")
+	}
+	if principal.Workflow != "" {
+		return forbidden("workflow %q may not create projects", principal.Workflow)
+	}
+	return nil
+}
+
+// grantCreator grants the admin role to the principal authenticated for the request in a project it has
+// just created. It must not be used for existing projects, which would allow taking them over.
+func (a *authorizer) grantCreator(ctx context.Context, project string) (*domain.ProjectMember, error) {
+	principal := domain.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, nil
+	}
+	return a.members.SetMember(ctx, &domain.ProjectMember{
+		Project: project,
+		User:    principal.Name,
+		Role:    domain.ProjectRoleAdmin,
+	})
+}
+
+// auth service implementation.
+type authsrvc struct {
+	logger        *zap.SugaredLogger
+	authenticator domain.Authenticator
+}
+
+// NewAuthService returns the auth service implementation.
+func NewAuthService(logger *zap.SugaredLogger, authenticator domain.Authenticator) auth.Service {
+	return &authsrvc{logger, authenticator}
+}
+
+// BasicAuth implements the authorization logic for the basic security scheme. The credentials are
+// verified when issuing the token.
+func (s *authsrvc) BasicAuth(ctx context.Context, user, pass string, scheme *security.BasicScheme) (context.Context, error) {
+	if user == "" || pass == "" {
+		return ctx, auth.MakeUnauthorized(domain.ErrInvalidCredentials)
+	}
+	return ctx, nil
+}
+
+// Authenticate a FuseML user and issue a JWT token for it.
+func (s *authsrvc) Login(ctx context.Context, p *auth.LoginPayload) (res *auth.AuthToken, err error) {
+	logging.FromContext(ctx, s.logger).Info("auth.login")
+	token, err := s.authenticator.Login(ctx, p.Username, p.Password)
+	if err != nil {
+		if err == domain.ErrInvalidCredentials {
+			return nil, auth.MakeUnauthorized(err)
+		}
+		return nil, err
+	}
+	return &auth.AuthToken{
+		Token:     token.Token,
+		User:      token.Principal.Name,
+		ExpiresAt: token.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
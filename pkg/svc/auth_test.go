@@ -0,0 +1,210 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goa "goa.design/goa/v3/pkg"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// testPrincipal is a principal the authorization tests call the services as
+type testPrincipal struct {
+	name      string
+	principal *domain.Principal
+}
+
+// testPrincipals are an admin, the members of the "prj" project with each role, the admin of the "other"
+// project, a workflow assigned to a codeset of "prj" and an unauthenticated caller
+var testPrincipals = []testPrincipal{
+	{"admin", &domain.Principal{Name: "admin", Admin: true}},
+	{"owner", &domain.Principal{Name: "owner"}},
+	{"editor", &domain.Principal{Name: "editor"}},
+	{"viewer", &domain.Principal{Name: "viewer"}},
+	{"non-member", &domain.Principal{Name: "stranger"}},
+	{"workflow", &domain.Principal{Name: "workflow:wf", Workflow: "wf", Projects: []string{"prj"}}},
+	{"anonymous", nil},
+}
+
+// the principals allowed to perform the operations requiring each role in the "prj" project
+var (
+	prjViewers = []string{"admin", "owner", "editor", "viewer", "workflow"}
+	prjEditors = []string{"admin", "owner", "editor", "workflow"}
+	prjAdmins  = []string{"admin", "owner"}
+	admins     = []string{"admin"}
+)
+
+type fakeMemberStore map[string]map[string]domain.ProjectRole
+
+func newTestMembers() fakeMemberStore {
+	return fakeMemberStore{
+		"prj": {
+			"owner":  domain.ProjectRoleAdmin,
+			"editor": domain.ProjectRoleEditor,
+			"viewer": domain.ProjectRoleViewer,
+		},
+		"other": {
+			"stranger": domain.ProjectRoleAdmin,
+		},
+	}
+}
+
+func (s fakeMemberStore) GetMember(ctx context.Context, project, user string) (*domain.ProjectMember, error) {
+	role, ok := s[project][user]
+	if !ok {
+		return nil, domain.ErrProjectMemberNotFound
+	}
+	return &domain.ProjectMember{Project: project, User: user, Role: role}, nil
+}
+
+func (s fakeMemberStore) GetMembers(ctx context.Context, project string) ([]*domain.ProjectMember, error) {
+	var res []*domain.ProjectMember
+	for user, role := range s[project] {
+		res = append(res, &domain.ProjectMember{Project: project, User: user, Role: role})
+	}
+	return res, nil
+}
+
+func (s fakeMemberStore) GetMemberships(ctx context.Context, user string) ([]*domain.ProjectMember, error) {
+	var res []*domain.ProjectMember
+	for project, members := range s {
+		if role, ok := members[user]; ok {
+			res = append(res, &domain.ProjectMember{Project: project, User: user, Role: role})
+		}
+	}
+	return res, nil
+}
+
+func (s fakeMemberStore) SetMember(ctx context.Context, member *domain.ProjectMember) (*domain.ProjectMember, error) {
+	if s[member.Project] == nil {
+		s[member.Project] = map[string]domain.ProjectRole{}
+	}
+	s[member.Project][member.User] = member.Role
+	return member, nil
+}
+
+func (s fakeMemberStore) RemoveMember(ctx context.Context, project, user string) error {
+	if _, ok := s[project][user]; !ok {
+		return domain.ErrProjectMemberNotFound
+	}
+	delete(s[project], user)
+	return nil
+}
+
+func (s fakeMemberStore) RemoveProject(ctx context.Context, project string) error {
+	delete(s, project)
+	return nil
+}
+
+func principalContext(principal *domain.Principal) context.Context {
+	if principal == nil {
+		return context.Background()
+	}
+	return domain.ContextWithPrincipal(context.Background(), principal)
+}
+
+func isForbidden(err error) bool {
+	var serr *goa.ServiceError
+	return errors.As(err, &serr) && serr.Name == "Forbidden"
+}
+
+// checkAuthorization calls a service method as each of the test principals, expecting it to succeed for the
+// allowed principals and to be forbidden for the others
+func checkAuthorization(t *testing.T, name string, allowed []string, call func(ctx context.Context) error) {
+	t.Helper()
+
+	for _, p := range testPrincipals {
+		err := call(principalContext(p.principal))
+		if util.StringInSlice(p.name, allowed) {
+			if err != nil {
+				t.Errorf("%s as %s: unexpected error: %v", name, p.name, err)
+			}
+		} else if !isForbidden(err) {
+			t.Errorf("%s as %s: expected a Forbidden error, got: %v", name, p.name, err)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	a := &authorizer{members: newTestMembers()}
+	tests := []struct {
+		project string
+		role    domain.ProjectRole
+		allowed []string
+	}{
+		{"prj", domain.ProjectRoleViewer, prjViewers},
+		{"prj", domain.ProjectRoleEditor, prjEditors},
+		{"prj", domain.ProjectRoleAdmin, prjAdmins},
+		{"other", domain.ProjectRoleViewer, []string{"admin", "non-member"}},
+		{"missing", domain.ProjectRoleViewer, admins},
+	}
+	for _, tt := range tests {
+		checkAuthorization(t, tt.project+"/"+string(tt.role), tt.allowed, func(ctx context.Context) error {
+			return a.authorize(ctx, tt.project, tt.role)
+		})
+	}
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	a := &authorizer{members: newTestMembers()}
+	checkAuthorization(t, "authorizeAdmin", admins, a.authorizeAdmin)
+}
+
+func TestVisibleProjects(t *testing.T) {
+	a := &authorizer{members: newTestMembers()}
+	projects := []string{"prj", "other", "missing"}
+	want := map[string][]string{
+		"admin":      projects,
+		"owner":      {"prj"},
+		"editor":     {"prj"},
+		"viewer":     {"prj"},
+		"non-member": {"other"},
+		"workflow":   {"prj"},
+		"anonymous":  {},
+	}
+	for _, p := range testPrincipals {
+		visible, err := a.visibleProjects(principalContext(p.principal))
+		if err != nil {
+			t.Fatalf("visibleProjects as %s: unexpected error: %v", p.name, err)
+		}
+		for _, project := range projects {
+			if got, want := visible.Includes(project), util.StringInSlice(project, want[p.name]); got != want {
+				t.Errorf("visibleProjects as %s: got %v for project %q, want %v", p.name, got, project, want)
+			}
+		}
+	}
+}
+
+func TestAuthorizeProjectCreation(t *testing.T) {
+	a := &authorizer{members: newTestMembers()}
+	checkAuthorization(t, "authorizeProjectCreation", []string{"admin", "owner", "editor", "viewer", "non-member"},
+		a.authorizeProjectCreation)
+}
+
+func TestGrantCreator(t *testing.T) {
+	members := newTestMembers()
+	a := &authorizer{members: members}
+	ctx := principalContext(&domain.Principal{Name: "stranger"})
+
+	member, err := a.grantCreator(ctx, "new")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if member.User != "stranger" || member.Role != domain.ProjectRoleAdmin {
+		t.Errorf("unexpected member: %+v", member)
+	}
+	if err := a.authorize(ctx, "new", domain.ProjectRoleAdmin); err != nil {
+		t.Errorf("creator is not the admin of the project: %v", err)
+	}
+	if members["prj"]["stranger"] != "" {
+		t.Errorf("creator was granted a role in another project")
+	}
+
+	member, err = a.grantCreator(context.Background(), "anonymous")
+	if err != nil || member != nil {
+		t.Errorf("unexpected membership for an unauthenticated request: %+v, %v", member, err)
+	}
+}
@@ -2,21 +2,30 @@ package svc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/fuseml/fuseml-core/gen/codeset"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // codeset service implementation.
 type codesetsrvc struct {
-	logger *log.Logger
-	store  domain.CodesetStore
+	*authorizer
+	logger   *zap.SugaredLogger
+	store    domain.CodesetStore
+	quotas   domain.ProjectQuotaManager
+	projects domain.ProjectStore
 }
 
 // NewCodesetService returns the codeset service implementation.
-func NewCodesetService(logger *log.Logger, store domain.CodesetStore) codeset.Service {
-	return &codesetsrvc{logger, store}
+func NewCodesetService(logger *zap.SugaredLogger, store domain.CodesetStore, authenticator domain.Authenticator,
+	members domain.ProjectMemberStore, quotas domain.ProjectQuotaManager, projects domain.ProjectStore) codeset.Service {
+	return &codesetsrvc{&authorizer{authenticator, members}, logger, store, quotas, projects}
 }
 
 func codesetRestToDomain(restCodeset *codeset.Codeset) (res *domain.Codeset, err error) {
@@ -37,25 +46,106 @@ func codesetDomainToRest(c *domain.Codeset) (res *codeset.Codeset) {
 		Description: c.Description,
 		Labels:      c.Labels,
 		URL:         &c.URL,
+		Source:      codesetSourceDomainToRest(c.Source),
+		Archived:    c.Archived,
 	}
 
 	return
 }
 
+// codesetSourceRegistrationToDomain converts the external repository of a codeset being registered, whose
+// interval is a duration string (e.g. "10m")
+func codesetSourceRegistrationToDomain(r *codeset.CodesetSourceRegistration) (*domain.CodesetSource, error) {
+	if r == nil {
+		return nil, nil
+	}
+	interval, err := time.ParseDuration(r.Interval)
+	if err != nil {
+		return nil, domain.ErrInvalidCodesetSource
+	}
+	res := &domain.CodesetSource{URL: r.URL, Mode: domain.CodesetSourceMode(r.Mode), Interval: interval}
+	if r.Username != nil {
+		res.Username = *r.Username
+	}
+	if r.Password != nil {
+		res.Password = *r.Password
+	}
+	return res, nil
+}
+
+// codesetSourceDomainToRest converts the external repository of a codeset, leaving out its credentials
+func codesetSourceDomainToRest(s *domain.CodesetSource) *codeset.CodesetSource {
+	if s == nil {
+		return nil
+	}
+	res := &codeset.CodesetSource{URL: s.URL, Mode: string(s.Mode)}
+	if s.Interval != 0 {
+		res.Interval = util.RefString(s.Interval.String())
+	}
+	if s.DefaultBranch != "" {
+		res.DefaultBranch = util.RefString(s.DefaultBranch)
+	}
+	if !s.Polled.IsZero() {
+		res.Polled = util.RefString(s.Polled.UTC().Format(time.RFC3339))
+	}
+	return res
+}
+
+func codesetRefDomainToRest(r *domain.CodesetRef) *codeset.CodesetRef {
+	return &codeset.CodesetRef{Name: r.Name, Commit: r.Commit}
+}
+
+func codesetVersionsDomainToRest(v *domain.CodesetVersions) *codeset.CodesetVersions {
+	res := &codeset.CodesetVersions{
+		Branches: make([]*codeset.CodesetRef, 0, len(v.Branches)),
+		Tags:     make([]*codeset.CodesetRef, 0, len(v.Tags)),
+		Commits:  make([]*codeset.CodesetCommit, 0, len(v.Commits)),
+	}
+	for _, b := range v.Branches {
+		res.Branches = append(res.Branches, codesetRefDomainToRest(b))
+	}
+	for _, t := range v.Tags {
+		res.Tags = append(res.Tags, codesetRefDomainToRest(t))
+	}
+	for _, c := range v.Commits {
+		commit := &codeset.CodesetCommit{ID: c.ID, Author: c.Author, Message: c.Message}
+		if !c.Date.IsZero() {
+			commit.Date = util.RefString(c.Date.Format(time.RFC3339))
+		}
+		res.Commits = append(res.Commits, commit)
+	}
+	return res
+}
+
 // Retrieve information about codesets registered in FuseML.
-func (s *codesetsrvc) List(ctx context.Context, p *codeset.ListPayload) (res []*codeset.Codeset, err error) {
-	s.logger.Print("codeset.list")
-	items, err := s.store.GetAll(ctx, p.Project, p.Label)
-	res = make([]*codeset.Codeset, 0, len(items))
+func (s *codesetsrvc) List(ctx context.Context, p *codeset.ListPayload) (res *codeset.CodesetPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("codeset.list")
+	if p.Project != nil {
+		if err := s.authorize(ctx, *p.Project, domain.ProjectRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	visible, err := s.visibleProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.store.GetAll(ctx, p.Project, p.Label, p.Archived, visible, listOptions(p.Limit, p.Continue, p.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &codeset.CodesetPage{Items: make([]*codeset.Codeset, 0, len(items)), Continue: util.RefString(next)}
 	for _, c := range items {
-		res = append(res, codesetDomainToRest(c))
+		res.Items = append(res.Items, codesetDomainToRest(c))
 	}
-	return res, err
+	return res, nil
 }
 
 // Register a codeset with the FuseML codeset codesetStore.
 func (s *codesetsrvc) Register(ctx context.Context, p *codeset.RegisterPayload) (*codeset.RegisterResult, error) {
-	s.logger.Print("codeset.register")
+	logging.FromContext(ctx, s.logger).Infow("codeset.register", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
 	c, err := codesetRestToDomain(&codeset.Codeset{
 		Name:        p.Name,
 		Project:     p.Project,
@@ -65,10 +155,39 @@ func (s *codesetsrvc) Register(ctx context.Context, p *codeset.RegisterPayload)
 	if err != nil {
 		return nil, codeset.MakeBadRequest(err)
 	}
+	if c.Source, err = codesetSourceRegistrationToDomain(p.Source); err != nil {
+		return nil, codeset.MakeBadRequest(err)
+	}
+	// the project is created along with its first codeset, in which case the user registering the
+	// codeset becomes its admin
+	_, err = s.projects.Find(ctx, c.Project)
+	newProject := errors.Is(err, domain.ErrProjectNotFound)
+	if err != nil && !newProject {
+		return nil, err
+	}
+	if newProject {
+		err = s.authorizeProjectCreation(ctx)
+	} else {
+		err = s.authorize(ctx, c.Project, domain.ProjectRoleEditor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.quotas.CheckCodesetQuota(ctx, c.Project); err != nil {
+		if errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
 	c, username, password, err := s.store.Add(ctx, c)
 	if err != nil {
 		return nil, codeset.MakeBadRequest(err)
 	}
+	if newProject {
+		if _, err := s.grantCreator(ctx, c.Project); err != nil {
+			return nil, err
+		}
+	}
 	res := codeset.RegisterResult{
 		Codeset:  codesetDomainToRest(c),
 		Username: username,
@@ -79,7 +198,10 @@ func (s *codesetsrvc) Register(ctx context.Context, p *codeset.RegisterPayload)
 
 // Retrieve an Codeset from FuseML.
 func (s *codesetsrvc) Get(ctx context.Context, p *codeset.GetPayload) (res *codeset.Codeset, err error) {
-	s.logger.Print("codeset.get")
+	logging.FromContext(ctx, s.logger).Infow("codeset.get", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
 	c, err := s.store.Find(ctx, p.Project, p.Name)
 	if err != nil {
 		return nil, codeset.MakeBadRequest(err)
@@ -87,7 +209,165 @@ func (s *codesetsrvc) Get(ctx context.Context, p *codeset.GetPayload) (res *code
 	return codesetDomainToRest(c), nil
 }
 
+// Update the description and labels of a Codeset.
+func (s *codesetsrvc) Update(ctx context.Context, p *codeset.UpdatePayload) (*codeset.Codeset, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.update", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	c, err := s.store.Find(ctx, p.Project, p.Name)
+	if err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	if c.Archived {
+		return nil, codeset.MakeBadRequest(domain.ErrCodesetArchived)
+	}
+	if p.Description != nil {
+		c.Description = *p.Description
+	}
+	if p.Labels != nil {
+		c.Labels = p.Labels
+	}
+	if c, err = s.store.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return codesetDomainToRest(c), nil
+}
+
+// Archive a Codeset.
+func (s *codesetsrvc) Archive(ctx context.Context, p *codeset.ArchivePayload) (*codeset.Codeset, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.archive", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	return s.setArchived(ctx, p.Project, p.Name, true)
+}
+
+// Unarchive an archived Codeset.
+func (s *codesetsrvc) Unarchive(ctx context.Context, p *codeset.UnarchivePayload) (*codeset.Codeset, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.unarchive", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	return s.setArchived(ctx, p.Project, p.Name, false)
+}
+
+// setArchived archives or unarchives a codeset, if not already done
+func (s *codesetsrvc) setArchived(ctx context.Context, project, name string, archived bool) (*codeset.Codeset, error) {
+	if err := s.authorize(ctx, project, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	c, err := s.store.Find(ctx, project, name)
+	if err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	if c.Archived != archived {
+		c.Archived = archived
+		if c, err = s.store.Update(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	return codesetDomainToRest(c), nil
+}
+
 func (s *codesetsrvc) Delete(ctx context.Context, p *codeset.DeletePayload) error {
-	s.logger.Print("codeset.delete")
+	logging.FromContext(ctx, s.logger).Infow("codeset.delete", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleEditor); err != nil {
+		return err
+	}
 	return s.store.Delete(ctx, p.Project, p.Name)
 }
+
+// List the versions of a Codeset: its branches, tags and most recent commits.
+func (s *codesetsrvc) ListVersions(ctx context.Context, p *codeset.ListVersionsPayload) (*codeset.CodesetVersions, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.listVersions", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	ref := ""
+	if p.Ref != nil {
+		ref = *p.Ref
+	}
+	versions, err := s.store.GetVersions(ctx, p.Project, p.Name, ref, p.Commits)
+	if err != nil {
+		if errors.Is(err, domain.ErrCodesetVersionNotFound) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return codesetVersionsDomainToRest(versions), nil
+}
+
+// List the files of a Codeset directory, at a given Codeset version.
+func (s *codesetsrvc) ListFiles(ctx context.Context, p *codeset.ListFilesPayload) ([]*codeset.CodesetFile, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.listFiles", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name, "path", p.Path)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	files, err := s.store.GetFiles(ctx, p.Project, p.Name, util.DerefString(p.Ref), p.Path)
+	if err != nil {
+		return nil, codesetFileError(err)
+	}
+	res := make([]*codeset.CodesetFile, 0, len(files))
+	for _, f := range files {
+		file := &codeset.CodesetFile{Name: f.Name, Path: f.Path, Type: f.Type}
+		if f.Type == domain.CodesetFileTypeFile {
+			size := f.Size
+			file.Size = &size
+		}
+		res = append(res, file)
+	}
+	return res, nil
+}
+
+// Retrieve the contents of a Codeset file, at a given Codeset version.
+func (s *codesetsrvc) GetFile(ctx context.Context, p *codeset.GetFilePayload) (*codeset.CodesetFileContents, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.getFile", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name, "path", p.Path)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	content, err := s.store.GetFile(ctx, p.Project, p.Name, util.DerefString(p.Ref), p.Path)
+	if err != nil {
+		return nil, codesetFileError(err)
+	}
+	return &codeset.CodesetFileContents{Path: p.Path, Content: content}, nil
+}
+
+// codesetFileError maps the errors returned when reading the codeset files
+func codesetFileError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrCodesetFileNotFound):
+		return codeset.MakeNotFound(err)
+	case errors.Is(err, domain.ErrCodesetVersionNotFound), errors.Is(err, domain.ErrCodesetNotAFile),
+		errors.Is(err, domain.ErrInvalidCodesetPath), errors.Is(err, domain.ErrCodesetFileTooLarge):
+		return codeset.MakeBadRequest(err)
+	}
+	return err
+}
+
+// Tag a Codeset commit as a named Codeset version.
+func (s *codesetsrvc) Tag(ctx context.Context, p *codeset.TagPayload) (*codeset.CodesetRef, error) {
+	logging.FromContext(ctx, s.logger).Infow("codeset.tag", logging.ProjectKey, p.Project, logging.CodesetKey, p.Name, "tag", p.Tag)
+	if err := s.authorize(ctx, p.Project, domain.ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		return nil, codeset.MakeNotFound(err)
+	}
+	commit := ""
+	if p.Commit != nil {
+		commit = *p.Commit
+	}
+	ref, err := s.store.AddTag(ctx, p.Project, p.Name, p.Tag, commit, p.Message)
+	if err != nil {
+		if errors.Is(err, domain.ErrCodesetVersionNotFound) || errors.Is(err, domain.ErrCodesetVersionExists) ||
+			errors.Is(err, domain.ErrCodesetReadOnly) || errors.Is(err, domain.ErrCodesetArchived) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return codesetRefDomainToRest(ref), nil
+}
@@ -0,0 +1,204 @@
+package svc
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// fakeCodesetStore keeps the codesets in memory, by project and name. The methods not used by the
+// tests are left unimplemented.
+type fakeCodesetStore struct {
+	domain.CodesetStore
+	codesets map[string]*domain.Codeset
+}
+
+func (s *fakeCodesetStore) Find(ctx context.Context, project, name string) (*domain.Codeset, error) {
+	if c, ok := s.codesets[project+"/"+name]; ok {
+		return c, nil
+	}
+	return nil, domain.ErrCodesetNotFound
+}
+
+func (s *fakeCodesetStore) GetAll(ctx context.Context, project, label *string, archived bool, visible domain.ProjectVisibility,
+	opts *domain.ListOptions) ([]*domain.Codeset, string, error) {
+	var res []*domain.Codeset
+	for _, c := range s.codesets {
+		if (project == nil || c.Project == *project) && visible.Includes(c.Project) {
+			res = append(res, c)
+		}
+	}
+	return res, "", nil
+}
+
+func (s *fakeCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.Codeset, *string, *string, error) {
+	s.codesets[c.Project+"/"+c.Name] = c
+	return c, nil, nil, nil
+}
+
+func (s *fakeCodesetStore) Update(ctx context.Context, c *domain.Codeset) (*domain.Codeset, error) {
+	s.codesets[c.Project+"/"+c.Name] = c
+	return c, nil
+}
+
+func (s *fakeCodesetStore) Delete(ctx context.Context, project, name string) error {
+	delete(s.codesets, project+"/"+name)
+	return nil
+}
+
+// fakeProjectStore only finds the projects that have codesets
+type fakeProjectStore struct {
+	domain.ProjectStore
+	codesets *fakeCodesetStore
+}
+
+func (s *fakeProjectStore) Find(ctx context.Context, name string) (*domain.Project, error) {
+	for _, c := range s.codesets.codesets {
+		if c.Project == name {
+			return &domain.Project{Name: name}, nil
+		}
+	}
+	return nil, domain.ErrProjectNotFound
+}
+
+// fakeQuotaManager does not limit the projects
+type fakeQuotaManager struct {
+	domain.ProjectQuotaManager
+}
+
+func (fakeQuotaManager) CheckCodesetQuota(ctx context.Context, project string) error {
+	return nil
+}
+
+func newTestCodesetService(members domain.ProjectMemberStore) *codesetsrvc {
+	store := &fakeCodesetStore{codesets: map[string]*domain.Codeset{
+		"prj/cs":   {Project: "prj", Name: "cs"},
+		"other/cs": {Project: "other", Name: "cs"},
+	}}
+	return NewCodesetService(zap.NewNop().Sugar(), store, nil, members, fakeQuotaManager{},
+		&fakeProjectStore{codesets: store}).(*codesetsrvc)
+}
+
+func TestCodesetAuthorization(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		call    func(ctx context.Context, s *codesetsrvc) error
+	}{
+		{
+			name:    "list project",
+			allowed: prjViewers,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.List(ctx, &codeset.ListPayload{Project: util.RefString("prj")})
+				return err
+			},
+		},
+		{
+			name:    "get",
+			allowed: prjViewers,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.Get(ctx, &codeset.GetPayload{Project: "prj", Name: "cs"})
+				return err
+			},
+		},
+		{
+			name:    "register in existing project",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.Register(ctx, &codeset.RegisterPayload{Project: "prj", Name: "new"})
+				return err
+			},
+		},
+		{
+			name:    "register in new project",
+			allowed: []string{"admin", "owner", "editor", "viewer", "non-member"},
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.Register(ctx, &codeset.RegisterPayload{Project: "new", Name: "new"})
+				return err
+			},
+		},
+		{
+			name:    "update",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.Update(ctx, &codeset.UpdatePayload{Project: "prj", Name: "cs", Description: util.RefString("updated")})
+				return err
+			},
+		},
+		{
+			name:    "archive",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				_, err := s.Archive(ctx, &codeset.ArchivePayload{Project: "prj", Name: "cs"})
+				return err
+			},
+		},
+		{
+			name:    "delete",
+			allowed: prjEditors,
+			call: func(ctx context.Context, s *codesetsrvc) error {
+				return s.Delete(ctx, &codeset.DeletePayload{Project: "prj", Name: "cs"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		checkAuthorization(t, tt.name, tt.allowed, func(ctx context.Context) error {
+			return tt.call(ctx, newTestCodesetService(newTestMembers()))
+		})
+	}
+}
+
+func TestCodesetRegisterGrantsCreator(t *testing.T) {
+	members := newTestMembers()
+	s := newTestCodesetService(members)
+	ctx := principalContext(&domain.Principal{Name: "viewer"})
+
+	if _, err := s.Register(ctx, &codeset.RegisterPayload{Project: "new", Name: "new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role := members["new"]["viewer"]; role != domain.ProjectRoleAdmin {
+		t.Errorf("creator of the project was granted the %q role, want %q", role, domain.ProjectRoleAdmin)
+	}
+	// registering in an existing project does not change the role of the caller
+	_, err := s.Register(ctx, &codeset.RegisterPayload{Project: "prj", Name: "new"})
+	if !isForbidden(err) {
+		t.Errorf("expected a Forbidden error, got: %v", err)
+	}
+	if role := members["prj"]["viewer"]; role != domain.ProjectRoleViewer {
+		t.Errorf("viewer of an existing project was granted the %q role", role)
+	}
+}
+
+func TestCodesetListVisibility(t *testing.T) {
+	want := map[string][]string{
+		"admin":      {"other", "prj"},
+		"owner":      {"prj"},
+		"editor":     {"prj"},
+		"viewer":     {"prj"},
+		"non-member": {"other"},
+		"workflow":   {"prj"},
+		"anonymous":  {},
+	}
+	for _, p := range testPrincipals {
+		s := newTestCodesetService(newTestMembers())
+		res, err := s.List(principalContext(p.principal), &codeset.ListPayload{})
+		if err != nil {
+			t.Fatalf("list as %s: unexpected error: %v", p.name, err)
+		}
+		projects := []string{}
+		for _, c := range res.Items {
+			projects = append(projects, c.Project)
+		}
+		sort.Strings(projects)
+		if !reflect.DeepEqual(projects, want[p.name]) {
+			t.Errorf("list as %s: got the codesets of %v, want %v", p.name, projects, want[p.name])
+		}
+	}
+}
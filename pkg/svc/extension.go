@@ -2,24 +2,86 @@ package svc
 
 import (
 	"context"
-	"log"
 	"net/url"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/fuseml/fuseml-core/gen/extension"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // extension registry service implementation.
 type extensionRegistrySvc struct {
-	logger   *log.Logger
+	*authorizer
+	logger   *zap.SugaredLogger
 	registry domain.ExtensionRegistry
 }
 
 // NewExtensionRegistryService returns the extension registry service implementation.
-func NewExtensionRegistryService(logger *log.Logger, registry domain.ExtensionRegistry) extension.Service {
-	return &extensionRegistrySvc{logger, registry}
+func NewExtensionRegistryService(logger *zap.SugaredLogger, registry domain.ExtensionRegistry, authenticator domain.Authenticator,
+	members domain.ProjectMemberStore) extension.Service {
+	return &extensionRegistrySvc{&authorizer{authenticator, members}, logger, registry}
+}
+
+// authorizeCredentials checks that the caller may manage a set of credentials: project scoped credentials
+// may be managed by the admins of all the projects they are used for, all other credentials only by admins.
+func (s *extensionRegistrySvc) authorizeCredentials(ctx context.Context, credentials *domain.ExtensionCredentials) error {
+	if s.authorizeAdmin(ctx) == nil {
+		return nil
+	}
+	if credentials.Scope != domain.ECSProject || len(credentials.Projects) == 0 {
+		return forbidden("managing %s scoped credentials is restricted to admins", credentials.Scope)
+	}
+	for _, project := range credentials.Projects {
+		if err := s.authorize(ctx, project, domain.ProjectRoleAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizeServiceCredentials checks that the caller may manage all the credentials of a service record
+func (s *extensionRegistrySvc) authorizeServiceCredentials(ctx context.Context, svcRecord *domain.ExtensionServiceRecord) error {
+	for _, credentials := range svcRecord.Credentials {
+		if err := s.authorizeCredentials(ctx, credentials); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// credentialsVisibility returns a function reporting whether the caller may view a set of credentials:
+// global credentials are visible to everyone, project scoped credentials to the members of their projects
+// and user scoped credentials to their users.
+func (s *extensionRegistrySvc) credentialsVisibility(ctx context.Context) (func(*domain.ExtensionCredentials) bool, error) {
+	visible, err := s.visibleProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	principal := domain.PrincipalFromContext(ctx)
+	return func(credentials *domain.ExtensionCredentials) bool {
+		if principal == nil {
+			return false
+		}
+		if principal.Admin {
+			return true
+		}
+		switch credentials.Scope {
+		case domain.ECSProject:
+			for _, project := range credentials.Projects {
+				if visible.Includes(project) {
+					return true
+				}
+			}
+			return false
+		case domain.ECSUser:
+			return util.StringInSlice(principal.Name, credentials.Users)
+		}
+		return true
+	}, nil
 }
 
 func extensionToDomain(extension *extension.Extension) (result *domain.Extension) {
@@ -113,7 +175,7 @@ func extensionServiceRecordToDomain(service *extension.ExtensionService) (result
 	return result
 }
 
-func extensionQueryToDomain(query *extension.ExtensionQuery) (result *domain.ExtensionQuery) {
+func extensionQueryToDomain(query *extension.ListExtensionsPayload) (result *domain.ExtensionQuery) {
 	result = &domain.ExtensionQuery{
 		ExtensionID:        query.ExtensionID,
 		Product:            query.Product,
@@ -199,18 +261,20 @@ func extensionCredentialsToRest(credentials *domain.ExtensionCredentials) *exten
 	}
 }
 
-func extensionRecordToRest(extRecord *domain.ExtensionRecord) (result *extension.Extension) {
+// extensionRecordToRest converts an extension record, including only the credentials reported as visible
+func extensionRecordToRest(extRecord *domain.ExtensionRecord, visible func(*domain.ExtensionCredentials) bool) (result *extension.Extension) {
 
 	result = extensionToRest(&extRecord.Extension)
 
 	for _, svcRecord := range extRecord.Services {
-		result.Services = append(result.Services, extensionServiceRecordToRest(svcRecord))
+		result.Services = append(result.Services, extensionServiceRecordToRest(svcRecord, visible))
 	}
 
 	return result
 }
 
-func extensionServiceRecordToRest(svcRecord *domain.ExtensionServiceRecord) (result *extension.ExtensionService) {
+// extensionServiceRecordToRest converts a service record, including only the credentials reported as visible
+func extensionServiceRecordToRest(svcRecord *domain.ExtensionServiceRecord, visible func(*domain.ExtensionCredentials) bool) (result *extension.ExtensionService) {
 
 	result = extensionServiceToRest(&svcRecord.ExtensionService)
 
@@ -219,7 +283,9 @@ func extensionServiceRecordToRest(svcRecord *domain.ExtensionServiceRecord) (res
 	}
 
 	for _, credsRecord := range svcRecord.Credentials {
-		result.Credentials = append(result.Credentials, extensionCredentialsToRest(credsRecord))
+		if visible(credsRecord) {
+			result.Credentials = append(result.Credentials, extensionCredentialsToRest(credsRecord))
+		}
 	}
 
 	return result
@@ -249,52 +315,85 @@ func errToRest(err error) error {
 }
 
 // Register an extension with the FuseML extension registry.
-func (s *extensionRegistrySvc) RegisterExtension(ctx context.Context, req *extension.Extension) (*extension.Extension, error) {
-	s.logger.Print("extension.registerExtension")
-	extRecord, err := s.registry.RegisterExtension(ctx, extensionRecordToDomain(req))
+func (s *extensionRegistrySvc) RegisterExtension(ctx context.Context, req *extension.RegisterExtensionPayload) (*extension.Extension, error) {
+	logging.FromContext(ctx, s.logger).Info("extension.registerExtension")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	extRecord := extensionRecordToDomain(&extension.Extension{
+		ID:            req.ID,
+		Product:       req.Product,
+		Version:       req.Version,
+		Description:   req.Description,
+		Zone:          req.Zone,
+		Configuration: req.Configuration,
+		Services:      req.Services,
+	})
+	for _, svcRecord := range extRecord.Services {
+		if err := s.authorizeServiceCredentials(ctx, svcRecord); err != nil {
+			return nil, err
+		}
+	}
+	extRecord, err := s.registry.RegisterExtension(ctx, extRecord)
 	if err != nil {
 		return nil, errToRest(err)
 	}
-	return extensionRecordToRest(extRecord), nil
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extensionRecordToRest(extRecord, visible), nil
 }
 
 // Retrieve information about an extension.
 func (s *extensionRegistrySvc) GetExtension(ctx context.Context, req *extension.GetExtensionPayload) (res *extension.Extension, err error) {
-	s.logger.Print("extension.getExtension")
+	logging.FromContext(ctx, s.logger).Info("extension.getExtension")
 	extRecord, err := s.registry.GetExtension(ctx, req.ID, true)
 	if err != nil {
 		return nil, errToRest(err)
 	}
-	return extensionRecordToRest(extRecord), nil
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extensionRecordToRest(extRecord, visible), nil
 }
 
 // List extensions registered in FuseML
-func (s *extensionRegistrySvc) ListExtensions(ctx context.Context, query *extension.ExtensionQuery) (res []*extension.Extension, err error) {
-	s.logger.Print("extension.listExtensions")
-	extRecords, err := s.registry.ListExtensions(ctx, extensionQueryToDomain(query))
+func (s *extensionRegistrySvc) ListExtensions(ctx context.Context, query *extension.ListExtensionsPayload) (res *extension.ExtensionPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.listExtensions")
+	extRecords, next, err := s.registry.ListExtensions(ctx, extensionQueryToDomain(query),
+		listOptions(query.Limit, query.Continue, query.Sort))
 	if err != nil {
 		return nil, errToRest(err)
 	}
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	res = make([]*extension.Extension, len(extRecords))
+	res = &extension.ExtensionPage{Items: make([]*extension.Extension, len(extRecords)), Continue: util.RefString(next)}
 	for i, extRecord := range extRecords {
-		res[i] = extensionRecordToRest(extRecord)
+		res.Items[i] = extensionRecordToRest(extRecord, visible)
 	}
 
 	return res, nil
 }
 
 // Update an extension registered in FuseML
-func (s *extensionRegistrySvc) UpdateExtension(ctx context.Context, req *extension.Extension) (res *extension.Extension, err error) {
-	s.logger.Print("extension.updateExtension")
-	ext := extensionToDomain(req)
-	extRecord, err := s.registry.GetExtension(ctx, ext.ID, false)
+func (s *extensionRegistrySvc) UpdateExtension(ctx context.Context, req *extension.UpdateExtensionPayload) (res *extension.Extension, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.updateExtension")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	extID := util.DerefString(req.ID)
+	extRecord, err := s.registry.GetExtension(ctx, extID, false)
 	if err != nil {
 		return nil, errToRest(err)
 	}
 	// update only attributes present in the update request
 	extUpdate := domain.Extension{
-		ID:            ext.ID,
+		ID:            extID,
 		Product:       util.DerefString(req.Product, extRecord.Product),
 		Version:       util.DerefString(req.Version, extRecord.Version),
 		Description:   util.DerefString(req.Description, extRecord.Description),
@@ -314,7 +413,10 @@ func (s *extensionRegistrySvc) UpdateExtension(ctx context.Context, req *extensi
 
 // Delete an extension and its subtree of services, endpoints and credentials
 func (s *extensionRegistrySvc) DeleteExtension(ctx context.Context, req *extension.DeleteExtensionPayload) (err error) {
-	s.logger.Print("extension.deleteExtension")
+	logging.FromContext(ctx, s.logger).Info("extension.deleteExtension")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
 	err = s.registry.RemoveExtension(ctx, req.ID)
 	if err != nil {
 		return errToRest(err)
@@ -324,12 +426,34 @@ func (s *extensionRegistrySvc) DeleteExtension(ctx context.Context, req *extensi
 
 // Add a service to an existing extension registered with the FuseML extension
 // registry.
-func (s *extensionRegistrySvc) AddService(ctx context.Context, service *extension.ExtensionService) (res *extension.ExtensionService, err error) {
-	svcRecord, err := s.registry.AddService(ctx, extensionServiceRecordToDomain(service))
+func (s *extensionRegistrySvc) AddService(ctx context.Context, service *extension.AddServicePayload) (res *extension.ExtensionService, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.addService")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	svcRecord := extensionServiceRecordToDomain(&extension.ExtensionService{
+		ID:            service.ID,
+		ExtensionID:   service.ExtensionID,
+		Resource:      service.Resource,
+		Category:      service.Category,
+		AuthRequired:  service.AuthRequired,
+		Description:   service.Description,
+		Configuration: service.Configuration,
+		Endpoints:     service.Endpoints,
+		Credentials:   service.Credentials,
+	})
+	if err := s.authorizeServiceCredentials(ctx, svcRecord); err != nil {
+		return nil, err
+	}
+	svcRecord, err = s.registry.AddService(ctx, svcRecord)
 	if err != nil {
 		return nil, errToRest(err)
 	}
-	return extensionServiceRecordToRest(svcRecord), nil
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extensionServiceRecordToRest(svcRecord, visible), nil
 }
 
 // Retrieve information about a service belonging to an extension.
@@ -341,7 +465,11 @@ func (s *extensionRegistrySvc) GetService(ctx context.Context, req *extension.Ge
 	if err != nil {
 		return nil, errToRest(err)
 	}
-	return extensionServiceRecordToRest(svcRecord), nil
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return extensionServiceRecordToRest(svcRecord, visible), nil
 }
 
 // List all services associated with an extension registered in FuseML
@@ -350,20 +478,26 @@ func (s *extensionRegistrySvc) ListServices(ctx context.Context, req *extension.
 	if err != nil {
 		return nil, errToRest(err)
 	}
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
 	res = make([]*extension.ExtensionService, len(extRecord.Services))
 	for i, svcRecord := range extRecord.Services {
-		res[i] = extensionServiceRecordToRest(svcRecord)
+		res[i] = extensionServiceRecordToRest(svcRecord, visible)
 	}
 	return res, nil
 }
 
 // Update a service belonging to an extension registered in FuseML
-func (s *extensionRegistrySvc) UpdateService(ctx context.Context, req *extension.ExtensionService) (res *extension.ExtensionService, err error) {
-	s.logger.Print("extension.updateService")
-	service := extensionServiceToDomain(req)
+func (s *extensionRegistrySvc) UpdateService(ctx context.Context, req *extension.UpdateServicePayload) (res *extension.ExtensionService, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.updateService")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
 	svcRecord, err := s.registry.GetService(ctx, domain.ExtensionServiceID{
-		ID:          service.ID,
-		ExtensionID: service.ExtensionID,
+		ID:          util.DerefString(req.ID),
+		ExtensionID: util.DerefString(req.ExtensionID),
 	}, false)
 	if err != nil {
 		return nil, errToRest(err)
@@ -391,7 +525,10 @@ func (s *extensionRegistrySvc) UpdateService(ctx context.Context, req *extension
 
 // Delete an extension service and its subtree of endpoints and credentials
 func (s *extensionRegistrySvc) DeleteService(ctx context.Context, req *extension.DeleteServicePayload) (err error) {
-	s.logger.Print("extension.deleteService")
+	logging.FromContext(ctx, s.logger).Info("extension.deleteService")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
 	err = s.registry.RemoveService(ctx, domain.ExtensionServiceID{
 		ExtensionID: req.ExtensionID,
 		ID:          req.ID,
@@ -404,9 +541,18 @@ func (s *extensionRegistrySvc) DeleteService(ctx context.Context, req *extension
 
 // Add an endpoint to an existing extension service registered with the FuseML
 // extension registry.
-func (s *extensionRegistrySvc) AddEndpoint(ctx context.Context, req *extension.ExtensionEndpoint) (res *extension.ExtensionEndpoint, err error) {
-	s.logger.Print("extension.addEndpoint")
-	endpoint, err := s.registry.AddEndpoint(ctx, extensionEndpointToDomain(req))
+func (s *extensionRegistrySvc) AddEndpoint(ctx context.Context, req *extension.AddEndpointPayload) (res *extension.ExtensionEndpoint, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.addEndpoint")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	endpoint, err := s.registry.AddEndpoint(ctx, extensionEndpointToDomain(&extension.ExtensionEndpoint{
+		URL:           req.URL,
+		ExtensionID:   req.ExtensionID,
+		ServiceID:     req.ServiceID,
+		Type:          req.Type,
+		Configuration: req.Configuration,
+	}))
 	if err != nil {
 		return nil, errToRest(err)
 	}
@@ -415,7 +561,7 @@ func (s *extensionRegistrySvc) AddEndpoint(ctx context.Context, req *extension.E
 
 // Retrieve information about an endpoint belonging to an extension.
 func (s *extensionRegistrySvc) GetEndpoint(ctx context.Context, req *extension.GetEndpointPayload) (res *extension.ExtensionEndpoint, err error) {
-	s.logger.Print("extension.getEndpoint")
+	logging.FromContext(ctx, s.logger).Info("extension.getEndpoint")
 	endpoint, err := s.registry.GetEndpoint(ctx, domain.ExtensionEndpointID{
 		ExtensionID: req.ExtensionID,
 		ServiceID:   req.ServiceID,
@@ -429,7 +575,7 @@ func (s *extensionRegistrySvc) GetEndpoint(ctx context.Context, req *extension.G
 
 // List all endpoints associated with an extension service registered in FuseML
 func (s *extensionRegistrySvc) ListEndpoints(ctx context.Context, req *extension.ListEndpointsPayload) (res []*extension.ExtensionEndpoint, err error) {
-	s.logger.Print("extension.listEndpoints")
+	logging.FromContext(ctx, s.logger).Info("extension.listEndpoints")
 	svcRecord, err := s.registry.GetService(ctx, domain.ExtensionServiceID{
 		ExtensionID: req.ExtensionID,
 		ID:          req.ServiceID,
@@ -445,13 +591,15 @@ func (s *extensionRegistrySvc) ListEndpoints(ctx context.Context, req *extension
 }
 
 // Update an endpoint belonging to an extension service registered in FuseML
-func (s *extensionRegistrySvc) UpdateEndpoint(ctx context.Context, req *extension.ExtensionEndpoint) (res *extension.ExtensionEndpoint, err error) {
-	s.logger.Print("extension.updateEndpoint")
-	endpoint := extensionEndpointToDomain(req)
+func (s *extensionRegistrySvc) UpdateEndpoint(ctx context.Context, req *extension.UpdateEndpointPayload) (res *extension.ExtensionEndpoint, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.updateEndpoint")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
 	ep, err := s.registry.GetEndpoint(ctx, domain.ExtensionEndpointID{
-		URL:         extensionEndpointURLToDomain(&endpoint.URL),
-		ExtensionID: endpoint.ExtensionID,
-		ServiceID:   endpoint.ServiceID,
+		URL:         extensionEndpointURLToDomain(req.URL),
+		ExtensionID: util.DerefString(req.ExtensionID),
+		ServiceID:   util.DerefString(req.ServiceID),
 	})
 	if err != nil {
 		return nil, errToRest(err)
@@ -476,7 +624,10 @@ func (s *extensionRegistrySvc) UpdateEndpoint(ctx context.Context, req *extensio
 
 // Delete an extension endpoint
 func (s *extensionRegistrySvc) DeleteEndpoint(ctx context.Context, req *extension.DeleteEndpointPayload) (err error) {
-	s.logger.Print("extension.deleteEndpoint")
+	logging.FromContext(ctx, s.logger).Info("extension.deleteEndpoint")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
 	err = s.registry.RemoveEndpoint(ctx, domain.ExtensionEndpointID{
 		ExtensionID: req.ExtensionID,
 		ServiceID:   req.ServiceID,
@@ -490,8 +641,21 @@ func (s *extensionRegistrySvc) DeleteEndpoint(ctx context.Context, req *extensio
 
 // Add a set of credentials to an existing extension service registered with
 // the FuseML extension registry.
-func (s *extensionRegistrySvc) AddCredentials(ctx context.Context, req *extension.ExtensionCredentials) (res *extension.ExtensionCredentials, err error) {
-	credentials, err := s.registry.AddCredentials(ctx, extensionCredentialsToDomain(req))
+func (s *extensionRegistrySvc) AddCredentials(ctx context.Context, req *extension.AddCredentialsPayload) (res *extension.ExtensionCredentials, err error) {
+	credentials := extensionCredentialsToDomain(&extension.ExtensionCredentials{
+		ID:            req.ID,
+		ExtensionID:   req.ExtensionID,
+		ServiceID:     req.ServiceID,
+		Default:       req.Default,
+		Scope:         req.Scope,
+		Projects:      req.Projects,
+		Users:         req.Users,
+		Configuration: req.Configuration,
+	})
+	if err := s.authorizeCredentials(ctx, credentials); err != nil {
+		return nil, err
+	}
+	credentials, err = s.registry.AddCredentials(ctx, credentials)
 	if err != nil {
 		return nil, errToRest(err)
 	}
@@ -500,7 +664,7 @@ func (s *extensionRegistrySvc) AddCredentials(ctx context.Context, req *extensio
 
 // Retrieve information about a set of credentials belonging to an extension.
 func (s *extensionRegistrySvc) GetCredentials(ctx context.Context, req *extension.GetCredentialsPayload) (res *extension.ExtensionCredentials, err error) {
-	s.logger.Print("extension.getCredentials")
+	logging.FromContext(ctx, s.logger).Info("extension.getCredentials")
 	credentials, err := s.registry.GetCredentials(ctx, domain.ExtensionCredentialsID{
 		ExtensionID: req.ExtensionID,
 		ServiceID:   req.ServiceID,
@@ -509,13 +673,20 @@ func (s *extensionRegistrySvc) GetCredentials(ctx context.Context, req *extensio
 	if err != nil {
 		return nil, errToRest(err)
 	}
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !visible(credentials) {
+		return nil, forbidden("not allowed to view credentials %q", credentials.ID)
+	}
 	return extensionCredentialsToRest(credentials), nil
 }
 
 // List all credentials associated with an extension service registered in
 // FuseML
 func (s *extensionRegistrySvc) ListCredentials(ctx context.Context, req *extension.ListCredentialsPayload) (res []*extension.ExtensionCredentials, err error) {
-	s.logger.Print("extension.listCredentials")
+	logging.FromContext(ctx, s.logger).Info("extension.listCredentials")
 	svcRecord, err := s.registry.GetService(ctx, domain.ExtensionServiceID{
 		ExtensionID: req.ExtensionID,
 		ID:          req.ServiceID,
@@ -523,26 +694,34 @@ func (s *extensionRegistrySvc) ListCredentials(ctx context.Context, req *extensi
 	if err != nil {
 		return nil, errToRest(err)
 	}
-	res = make([]*extension.ExtensionCredentials, len(svcRecord.Credentials))
-	for i, credentials := range svcRecord.Credentials {
-		res[i] = extensionCredentialsToRest(credentials)
+	visible, err := s.credentialsVisibility(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res = make([]*extension.ExtensionCredentials, 0, len(svcRecord.Credentials))
+	for _, credentials := range svcRecord.Credentials {
+		if visible(credentials) {
+			res = append(res, extensionCredentialsToRest(credentials))
+		}
 	}
 	return res, nil
 }
 
 // Update a set of credentials belonging to an extension service registered in
 // FuseML
-func (s *extensionRegistrySvc) UpdateCredentials(ctx context.Context, req *extension.ExtensionCredentials) (res *extension.ExtensionCredentials, err error) {
-	s.logger.Print("extension.updateCredentials")
-	credentials := extensionCredentialsToDomain(req)
+func (s *extensionRegistrySvc) UpdateCredentials(ctx context.Context, req *extension.UpdateCredentialsPayload) (res *extension.ExtensionCredentials, err error) {
+	logging.FromContext(ctx, s.logger).Info("extension.updateCredentials")
 	cred, err := s.registry.GetCredentials(ctx, domain.ExtensionCredentialsID{
-		ID:          credentials.ID,
-		ExtensionID: credentials.ExtensionID,
-		ServiceID:   credentials.ServiceID,
+		ID:          util.DerefString(req.ID),
+		ExtensionID: util.DerefString(req.ExtensionID),
+		ServiceID:   util.DerefString(req.ServiceID),
 	})
 	if err != nil {
 		return nil, errToRest(err)
 	}
+	if err := s.authorizeCredentials(ctx, cred); err != nil {
+		return nil, err
+	}
 
 	// update only attributes present in the update request
 	credUpdate := domain.ExtensionCredentials{
@@ -562,6 +741,9 @@ func (s *extensionRegistrySvc) UpdateCredentials(ctx context.Context, req *exten
 	if req.Users != nil {
 		credUpdate.Users = req.Users
 	}
+	if err := s.authorizeCredentials(ctx, &credUpdate); err != nil {
+		return nil, err
+	}
 
 	err = s.registry.UpdateCredentials(ctx, &credUpdate)
 	if err != nil {
@@ -572,12 +754,20 @@ func (s *extensionRegistrySvc) UpdateCredentials(ctx context.Context, req *exten
 
 // Delete a set of extension credentials
 func (s *extensionRegistrySvc) DeleteCredentials(ctx context.Context, req *extension.DeleteCredentialsPayload) (err error) {
-	s.logger.Print("extension.deleteCredentials")
-	err = s.registry.RemoveCredentials(ctx, domain.ExtensionCredentialsID{
+	logging.FromContext(ctx, s.logger).Info("extension.deleteCredentials")
+	credentialsID := domain.ExtensionCredentialsID{
 		ExtensionID: req.ExtensionID,
 		ServiceID:   req.ServiceID,
 		ID:          req.ID,
-	})
+	}
+	credentials, err := s.registry.GetCredentials(ctx, credentialsID)
+	if err != nil {
+		return errToRest(err)
+	}
+	if err := s.authorizeCredentials(ctx, credentials); err != nil {
+		return err
+	}
+	err = s.registry.RemoveCredentials(ctx, credentialsID)
 	if err != nil {
 		return errToRest(err)
 	}
@@ -0,0 +1,190 @@
+package svc
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/gen/extension"
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/manager"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// newTestExtensionService returns an extension registry service with a service holding global credentials,
+// credentials scoped to each of the "prj" and "other" projects and credentials scoped to the editor of "prj"
+func newTestExtensionService() *extensionRegistrySvc {
+	registry := manager.NewExtensionRegistry(core.NewExtensionStore())
+	svcRecord := &domain.ExtensionServiceRecord{
+		ExtensionService: domain.ExtensionService{ExtensionServiceID: domain.ExtensionServiceID{ID: "svc"}},
+		Credentials: []*domain.ExtensionCredentials{
+			{ExtensionCredentialsID: domain.ExtensionCredentialsID{ID: "global"}, Scope: domain.ECSGlobal},
+			{ExtensionCredentialsID: domain.ExtensionCredentialsID{ID: "prj"}, Scope: domain.ECSProject, Projects: []string{"prj"}},
+			{ExtensionCredentialsID: domain.ExtensionCredentialsID{ID: "other"}, Scope: domain.ECSProject, Projects: []string{"other"}},
+			{ExtensionCredentialsID: domain.ExtensionCredentialsID{ID: "user"}, Scope: domain.ECSUser, Users: []string{"editor"}},
+		},
+	}
+	_, err := registry.RegisterExtension(context.Background(), &domain.ExtensionRecord{
+		Extension: domain.Extension{ID: "ext"},
+		Services:  []*domain.ExtensionServiceRecord{svcRecord},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return NewExtensionRegistryService(zap.NewNop().Sugar(), registry, nil, newTestMembers()).(*extensionRegistrySvc)
+}
+
+func TestExtensionAuthorization(t *testing.T) {
+	projectScope := string(domain.ECSProject)
+	tests := []struct {
+		name    string
+		allowed []string
+		call    func(ctx context.Context, s *extensionRegistrySvc) error
+	}{
+		{
+			name:    "register extension",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.RegisterExtension(ctx, &extension.RegisterExtensionPayload{ID: util.RefString("new")})
+				return err
+			},
+		},
+		{
+			name:    "add service",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.AddService(ctx, &extension.AddServicePayload{ExtensionID: util.RefString("ext"), ID: util.RefString("new")})
+				return err
+			},
+		},
+		{
+			name:    "delete extension",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				return s.DeleteExtension(ctx, &extension.DeleteExtensionPayload{ID: "ext"})
+			},
+		},
+		{
+			name:    "add project credentials",
+			allowed: prjAdmins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.AddCredentials(ctx, &extension.AddCredentialsPayload{ExtensionID: util.RefString("ext"),
+					ServiceID: util.RefString("svc"), ID: util.RefString("new"), Scope: &projectScope, Projects: []string{"prj"}})
+				return err
+			},
+		},
+		{
+			name:    "add global credentials",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.AddCredentials(ctx, &extension.AddCredentialsPayload{ExtensionID: util.RefString("ext"),
+					ServiceID: util.RefString("svc"), ID: util.RefString("new")})
+				return err
+			},
+		},
+		{
+			name:    "update project credentials",
+			allowed: prjAdmins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.UpdateCredentials(ctx, &extension.UpdateCredentialsPayload{ExtensionID: util.RefString("ext"),
+					ServiceID: util.RefString("svc"), ID: util.RefString("prj"), Default: util.RefBool(true)})
+				return err
+			},
+		},
+		{
+			name:    "share project credentials with another project",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.UpdateCredentials(ctx, &extension.UpdateCredentialsPayload{ExtensionID: util.RefString("ext"),
+					ServiceID: util.RefString("svc"), ID: util.RefString("prj"), Projects: []string{"prj", "other"}})
+				return err
+			},
+		},
+		{
+			name:    "delete project credentials",
+			allowed: prjAdmins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				return s.DeleteCredentials(ctx, &extension.DeleteCredentialsPayload{ExtensionID: "ext", ServiceID: "svc", ID: "prj"})
+			},
+		},
+		{
+			name:    "delete global credentials",
+			allowed: admins,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				return s.DeleteCredentials(ctx, &extension.DeleteCredentialsPayload{ExtensionID: "ext", ServiceID: "svc", ID: "global"})
+			},
+		},
+		{
+			name:    "get project credentials",
+			allowed: prjViewers,
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.GetCredentials(ctx, &extension.GetCredentialsPayload{ExtensionID: "ext", ServiceID: "svc", ID: "prj"})
+				return err
+			},
+		},
+		{
+			name:    "get user credentials",
+			allowed: []string{"admin", "editor"},
+			call: func(ctx context.Context, s *extensionRegistrySvc) error {
+				_, err := s.GetCredentials(ctx, &extension.GetCredentialsPayload{ExtensionID: "ext", ServiceID: "svc", ID: "user"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		checkAuthorization(t, tt.name, tt.allowed, func(ctx context.Context) error {
+			return tt.call(ctx, newTestExtensionService())
+		})
+	}
+}
+
+func TestExtensionCredentialsVisibility(t *testing.T) {
+	s := newTestExtensionService()
+	want := map[string][]string{
+		"admin":      {"global", "other", "prj", "user"},
+		"owner":      {"global", "prj"},
+		"editor":     {"global", "prj", "user"},
+		"viewer":     {"global", "prj"},
+		"non-member": {"global", "other"},
+		"workflow":   {"global", "prj"},
+		"anonymous":  {},
+	}
+	for _, p := range testPrincipals {
+		ctx := principalContext(p.principal)
+		credentialIDs := func(credentials []*extension.ExtensionCredentials) []string {
+			ids := []string{}
+			for _, c := range credentials {
+				ids = append(ids, util.DerefString(c.ID))
+			}
+			sort.Strings(ids)
+			return ids
+		}
+
+		list, err := s.ListCredentials(ctx, &extension.ListCredentialsPayload{ExtensionID: "ext", ServiceID: "svc"})
+		if err != nil {
+			t.Fatalf("list credentials as %s: unexpected error: %v", p.name, err)
+		}
+		if got := credentialIDs(list); !reflect.DeepEqual(got, want[p.name]) {
+			t.Errorf("list credentials as %s: got %v, want %v", p.name, got, want[p.name])
+		}
+		// the credentials nested in the services and extensions are filtered the same way
+		svc, err := s.GetService(ctx, &extension.GetServicePayload{ExtensionID: "ext", ID: "svc"})
+		if err != nil {
+			t.Fatalf("get service as %s: unexpected error: %v", p.name, err)
+		}
+		if got := credentialIDs(svc.Credentials); !reflect.DeepEqual(got, want[p.name]) {
+			t.Errorf("get service as %s: got credentials %v, want %v", p.name, got, want[p.name])
+		}
+		ext, err := s.GetExtension(ctx, &extension.GetExtensionPayload{ID: "ext"})
+		if err != nil {
+			t.Fatalf("get extension as %s: unexpected error: %v", p.name, err)
+		}
+		if got := credentialIDs(ext.Services[0].Credentials); !reflect.DeepEqual(got, want[p.name]) {
+			t.Errorf("get extension as %s: got credentials %v, want %v", p.name, got, want[p.name])
+		}
+	}
+}
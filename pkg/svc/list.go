@@ -0,0 +1,20 @@
+package svc
+
+import (
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// listOptions returns the options selecting the page of items requested by the payload of a list method.
+func listOptions(limit *int, cont, sort *string) *domain.ListOptions {
+	opts := &domain.ListOptions{}
+	if limit != nil {
+		opts.Limit = *limit
+	}
+	if cont != nil {
+		opts.Continue = *cont
+	}
+	if sort != nil {
+		opts.Sort = *sort
+	}
+	return opts
+}
@@ -1,7 +1,7 @@
 package svc
 
 import (
-	"log"
+	"go.uber.org/zap"
 
 	openapi "github.com/fuseml/fuseml-core/gen/openapi"
 )
@@ -9,10 +9,10 @@ import (
 // openapi service example implementation.
 // The example methods log the requests and return zero values.
 type openapisrvc struct {
-	logger *log.Logger
+	logger *zap.SugaredLogger
 }
 
 // NewOpenapi returns the openapi service implementation.
-func NewOpenapi(logger *log.Logger) openapi.Service {
+func NewOpenapi(logger *zap.SugaredLogger) openapi.Service {
 	return &openapisrvc{logger}
 }
@@ -2,21 +2,30 @@ package svc
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
 
 	"github.com/fuseml/fuseml-core/gen/project"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // project service implementation.
 type projectsrvc struct {
-	logger *log.Logger
+	*authorizer
+	logger *zap.SugaredLogger
 	store  domain.ProjectStore
+	quotas domain.ProjectQuotaManager
+	mgr    domain.ProjectManager
 }
 
 // NewProjectService returns the project service implementation.
-func NewProjectService(logger *log.Logger, store domain.ProjectStore) project.Service {
-	return &projectsrvc{logger, store}
+func NewProjectService(logger *zap.SugaredLogger, store domain.ProjectStore, authenticator domain.Authenticator,
+	members domain.ProjectMemberStore, quotas domain.ProjectQuotaManager, mgr domain.ProjectManager) project.Service {
+	return &projectsrvc{&authorizer{authenticator, members}, logger, store, quotas, mgr}
 }
 
 func projectDomainToRest(p *domain.Project) (res *project.Project) {
@@ -33,32 +42,77 @@ func projectDomainToRest(p *domain.Project) (res *project.Project) {
 			},
 		)
 	}
+	for _, m := range p.Members {
+		res.Members = append(res.Members,
+			&project.ProjectMember{
+				User: m.User,
+				Role: string(m.Role),
+			},
+		)
+	}
+	if p.Quota != nil {
+		res.Quota = &project.ProjectQuota{
+			MaxCodesets:       p.Quota.MaxCodesets,
+			MaxConcurrentRuns: p.Quota.MaxConcurrentRuns,
+			MaxWorkspaceSize:  p.Quota.MaxWorkspaceSize,
+		}
+	}
+	if p.Usage != nil {
+		res.Usage = &project.ProjectUsage{
+			Codesets:       p.Usage.Codesets,
+			ConcurrentRuns: p.Usage.ConcurrentRuns,
+			WorkspaceSize:  p.Usage.WorkspaceSize,
+		}
+	}
 	return
 }
 
 // Retrieve information about projects registered in FuseML.
-func (s *projectsrvc) List(ctx context.Context) (res []*project.Project, err error) {
-	s.logger.Print("project.list")
-	items, err := s.store.GetAll(ctx)
-	res = make([]*project.Project, 0, len(items))
+func (s *projectsrvc) List(ctx context.Context, p *project.ListPayload) (res *project.ProjectPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("project.list")
+	visible, err := s.visibleProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.store.GetAll(ctx, visible, listOptions(p.Limit, p.Continue, p.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, project.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &project.ProjectPage{Items: make([]*project.Project, 0, len(items)), Continue: util.RefString(next)}
 	for _, c := range items {
-		res = append(res, projectDomainToRest(c))
+		res.Items = append(res.Items, projectDomainToRest(c))
 	}
-	return res, err
+	return res, nil
 }
 
 // Retrieve an Project from FuseML.
 func (s *projectsrvc) Get(ctx context.Context, p *project.GetPayload) (res *project.Project, err error) {
-	s.logger.Print("project.get")
+	logging.FromContext(ctx, s.logger).Info("project.get")
+	if err := s.authorize(ctx, p.Name, domain.ProjectRoleViewer); err != nil {
+		return nil, err
+	}
 	c, err := s.store.Find(ctx, p.Name)
 	if err != nil {
 		return nil, project.MakeBadRequest(err)
 	}
+	c.Members, err = s.members.GetMembers(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	if c.Quota, err = s.quotas.GetQuota(ctx, p.Name); err != nil {
+		return nil, err
+	}
+	if c.Usage, err = s.quotas.GetUsage(ctx, p.Name); err != nil {
+		return nil, err
+	}
 	return projectDomainToRest(c), nil
 }
 
 func (s *projectsrvc) Create(ctx context.Context, p *project.CreatePayload) (res *project.Project, err error) {
-	s.logger.Print("project.create")
+	logging.FromContext(ctx, s.logger).Info("project.create")
 	c, err := s.store.Create(ctx, p.Name, p.Description)
 	if err != nil {
 		if err == domain.ErrProjectExists {
@@ -66,10 +120,171 @@ func (s *projectsrvc) Create(ctx context.Context, p *project.CreatePayload) (res
 		}
 		return nil, err
 	}
+	// the user creating the project becomes its admin
+	member, err := s.grantCreator(ctx, c.Name)
+	if err != nil {
+		return nil, err
+	}
+	if member != nil {
+		c.Members = []*domain.ProjectMember{member}
+	}
+	return projectDomainToRest(c), nil
+}
+
+// Add a user to a Project, or change the role of a Project member.
+func (s *projectsrvc) AddMember(ctx context.Context, p *project.AddMemberPayload) (res *project.Project, err error) {
+	logging.FromContext(ctx, s.logger).Info("project.addMember")
+	if err := s.authorize(ctx, p.Name, domain.ProjectRoleAdmin); err != nil {
+		return nil, err
+	}
+	role := domain.ProjectRole(p.Role)
+	if !role.Valid() {
+		return nil, project.MakeBadRequest(fmt.Errorf("invalid project role %q", p.Role))
+	}
+	c, err := s.store.Find(ctx, p.Name)
+	if err != nil {
+		return nil, project.MakeNotFound(err)
+	}
+	_, err = s.members.SetMember(ctx, &domain.ProjectMember{Project: p.Name, User: p.User, Role: role})
+	if err != nil {
+		return nil, err
+	}
+	c.Members, err = s.members.GetMembers(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
 	return projectDomainToRest(c), nil
 }
 
-func (s *projectsrvc) Delete(ctx context.Context, p *project.DeletePayload) error {
-	s.logger.Print("project.delete")
-	return s.store.Delete(ctx, p.Name)
+// Remove a user from a Project.
+func (s *projectsrvc) RemoveMember(ctx context.Context, p *project.RemoveMemberPayload) error {
+	logging.FromContext(ctx, s.logger).Info("project.removeMember")
+	if err := s.authorize(ctx, p.Name, domain.ProjectRoleAdmin); err != nil {
+		return err
+	}
+	err := s.members.RemoveMember(ctx, p.Name, p.User)
+	if err == domain.ErrProjectMemberNotFound {
+		return project.MakeNotFound(err)
+	}
+	return err
+}
+
+// Set the limits on the resources consumed by a Project.
+func (s *projectsrvc) SetQuota(ctx context.Context, p *project.SetQuotaPayload) (res *project.Project, err error) {
+	logging.FromContext(ctx, s.logger).Info("project.setQuota")
+	// the project admins are not allowed to change the limits of their projects
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	c, err := s.store.Find(ctx, p.Name)
+	if err != nil {
+		return nil, project.MakeNotFound(err)
+	}
+	c.Quota, err = s.quotas.SetQuota(ctx, &domain.ProjectQuota{
+		Project:           p.Name,
+		MaxCodesets:       p.MaxCodesets,
+		MaxConcurrentRuns: p.MaxConcurrentRuns,
+		MaxWorkspaceSize:  p.MaxWorkspaceSize,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidProjectQuota) {
+			return nil, project.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return projectDomainToRest(c), nil
+}
+
+// Update the description and members of a Project.
+func (s *projectsrvc) Update(ctx context.Context, p *project.UpdatePayload) (res *project.Project, err error) {
+	logging.FromContext(ctx, s.logger).Info("project.update")
+	if err := s.authorize(ctx, p.Name, domain.ProjectRoleAdmin); err != nil {
+		return nil, err
+	}
+	admins := 0
+	for _, m := range p.Members {
+		role := domain.ProjectRole(m.Role)
+		if !role.Valid() {
+			return nil, project.MakeBadRequest(fmt.Errorf("invalid project role %q for user %q", m.Role, m.User))
+		}
+		if role.Includes(domain.ProjectRoleAdmin) {
+			admins++
+		}
+	}
+	// the members replace the existing ones, which would leave the project without anyone to manage it
+	if p.Members != nil && admins == 0 {
+		return nil, project.MakeBadRequest(domain.ErrProjectAdminRequired)
+	}
+	c, err := s.store.Find(ctx, p.Name)
+	if err != nil {
+		return nil, project.MakeNotFound(err)
+	}
+	if p.Description != nil {
+		if c, err = s.store.Update(ctx, p.Name, *p.Description); err != nil {
+			return nil, err
+		}
+	}
+	if p.Members != nil {
+		if err := s.replaceMembers(ctx, p.Name, p.Members); err != nil {
+			return nil, err
+		}
+	}
+	c.Members, err = s.members.GetMembers(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	return projectDomainToRest(c), nil
+}
+
+// replaceMembers sets the roles of the given members, which include at least one admin, and removes the other
+// members of a project
+func (s *projectsrvc) replaceMembers(ctx context.Context, name string, members []*project.ProjectMember) error {
+	current, err := s.members.GetMembers(ctx, name)
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]bool, len(members))
+	for _, m := range members {
+		keep[m.User] = true
+		_, err := s.members.SetMember(ctx, &domain.ProjectMember{Project: name, User: m.User, Role: domain.ProjectRole(m.Role)})
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range current {
+		if keep[m.User] {
+			continue
+		}
+		if err := s.members.RemoveMember(ctx, name, m.User); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete a Project along with all its resources.
+func (s *projectsrvc) Delete(ctx context.Context, p *project.DeletePayload) (res *project.ProjectResources, err error) {
+	logging.FromContext(ctx, s.logger).Info("project.delete")
+	if err := s.authorize(ctx, p.Name, domain.ProjectRoleAdmin); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Find(ctx, p.Name); err != nil {
+		return nil, project.MakeNotFound(err)
+	}
+	r, err := s.mgr.DeleteProject(ctx, p.Name, p.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	res = &project.ProjectResources{
+		Codesets:     r.Codesets,
+		Assignments:  make([]*project.ProjectAssignment, 0, len(r.Assignments)),
+		WorkflowRuns: r.WorkflowRuns,
+		Applications: r.Applications,
+		Members:      r.Members,
+		Users:        r.Users,
+	}
+	for _, a := range r.Assignments {
+		res.Assignments = append(res.Assignments, &project.ProjectAssignment{Workflow: a.Workflow, Codeset: a.Codeset})
+	}
+	return res, nil
 }
@@ -4,19 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/fuseml/fuseml-core/gen/runnable"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // runnable service example implementation.
 // The example methods log the requests and return zero values.
 type runnablesrvc struct {
-	logger *log.Logger
+	*authorizer
+	logger *zap.SugaredLogger
 	store  domain.RunnableStore
+	mgr    domain.RunnableManager
 }
 
 const (
@@ -25,11 +30,19 @@ const (
 	errDuplicateArtifactKind = "at most one artifact kind may be supplied"
 	errMissingDefaultValue   = "default value missing for optional input"
 	errMaxOneArtifactKind    = "at most one kind of artifact can be configured"
+	errRunnableIDMismatch    = "the runnable descriptor ID does not match the ID of the runnable being built"
+	errRunnableAlreadyExists = "a runnable with that ID is already registered"
+
+	runnableImportRegistered = "registered"
+	runnableImportUpdated    = "updated"
+	runnableImportSkipped    = "skipped"
+	runnableImportFailed     = "failed"
 )
 
 // NewRunnableService returns the runnable service implementation.
-func NewRunnableService(logger *log.Logger, store domain.RunnableStore) runnable.Service {
-	return &runnablesrvc{logger, store}
+func NewRunnableService(logger *zap.SugaredLogger, store domain.RunnableStore, runnableManager domain.RunnableManager,
+	authenticator domain.Authenticator, members domain.ProjectMemberStore) runnable.Service {
+	return &runnablesrvc{&authorizer{authenticator, members}, logger, store, runnableManager}
 }
 
 // RunnableInputError defines an error type that applies to a runnable input
@@ -344,7 +357,7 @@ func runnableDomainToRest(r *domain.Runnable) (res *runnable.Runnable) {
 
 	getLocalContainerImage := func(image string, local bool) string {
 		if local {
-			return image + "/" + domain.LocalRegistryHostname
+			return domain.LocalRegistryHostname + "/" + image
 		}
 		return image
 	}
@@ -540,8 +553,8 @@ func runnableDomainToRest(r *domain.Runnable) (res *runnable.Runnable) {
 }
 
 // Retrieve information about runnables registered in FuseML.
-func (s *runnablesrvc) List(ctx context.Context, p *runnable.ListPayload) (res []*runnable.Runnable, err error) {
-	s.logger.Print("runnable.list")
+func (s *runnablesrvc) List(ctx context.Context, p *runnable.ListPayload) (res *runnable.RunnablePage, err error) {
+	logging.FromContext(ctx, s.logger).Info("runnable.list")
 	idQuery := ""
 	if p.ID != nil {
 		idQuery = *p.ID
@@ -550,20 +563,43 @@ func (s *runnablesrvc) List(ctx context.Context, p *runnable.ListPayload) (res [
 	if p.Kind != nil {
 		kindQuery = *p.Kind
 	}
-	items, err := s.store.Find(ctx, idQuery, kindQuery, p.Labels)
-	res = make([]*runnable.Runnable, 0, len(items))
+	items, next, err := s.store.Find(ctx, idQuery, kindQuery, p.Labels, listOptions(p.Limit, p.Continue, p.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, runnable.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &runnable.RunnablePage{Items: make([]*runnable.Runnable, 0, len(items)), Continue: util.RefString(next)}
 	for _, r := range items {
-		res = append(res, runnableDomainToRest(r))
+		res.Items = append(res.Items, runnableDomainToRest(r))
 	}
-	return res, err
+	return res, nil
 }
 
 // Register a runnable with the FuseML runnable runnableStore.
-func (s *runnablesrvc) Register(ctx context.Context, p *runnable.Runnable) (res *runnable.Runnable, err error) {
-	s.logger.Print("runnable.register")
-	r, err := runnableRestToDomain(p)
+func (s *runnablesrvc) Register(ctx context.Context, p *runnable.RegisterPayload) (res *runnable.Runnable, err error) {
+	logging.FromContext(ctx, s.logger).Info("runnable.register")
+	// the runnables are used by the workflows of all the projects, so only admins may change them
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	r, err := runnableRestToDomain(&runnable.Runnable{
+		ID:                p.ID,
+		Created:           p.Created,
+		Description:       p.Description,
+		Author:            p.Author,
+		Source:            p.Source,
+		Kind:              p.Kind,
+		Container:         p.Container,
+		Input:             p.Input,
+		Output:            p.Output,
+		DefaultInputPath:  p.DefaultInputPath,
+		DefaultOutputPath: p.DefaultOutputPath,
+		Labels:            p.Labels,
+	})
 	if err != nil {
-		return p, runnable.MakeBadRequest(err)
+		return nil, runnable.MakeBadRequest(err)
 	}
 	r, err = s.store.Register(ctx, r)
 	if err != nil {
@@ -574,10 +610,134 @@ func (s *runnablesrvc) Register(ctx context.Context, p *runnable.Runnable) (res
 
 // Retrieve a Runnable from FuseML.
 func (s *runnablesrvc) Get(ctx context.Context, p *runnable.GetPayload) (res *runnable.Runnable, err error) {
-	s.logger.Print("runnable.get")
+	logging.FromContext(ctx, s.logger).Info("runnable.get")
 	r, err := s.store.Get(ctx, p.ID)
 	if r == nil {
 		return nil, runnable.MakeNotFound(errors.New(err.Error()))
 	}
 	return runnableDomainToRest(r), nil
 }
+
+// Import a set of runnables into FuseML.
+func (s *runnablesrvc) ImportRunnables(ctx context.Context, p *runnable.ImportRunnablesPayload) (res []*runnable.RunnableImportResult, err error) {
+	logging.FromContext(ctx, s.logger).Info("runnable.importRunnables")
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	res = make([]*runnable.RunnableImportResult, 0, len(p.Runnables))
+	for _, item := range p.Runnables {
+		res = append(res, s.importRunnable(ctx, item, p.Replace))
+	}
+	return res, nil
+}
+
+func (s *runnablesrvc) importRunnable(ctx context.Context, item *runnable.Runnable, replace bool) *runnable.RunnableImportResult {
+	res := &runnable.RunnableImportResult{ID: item.ID}
+	failed := func(err error) *runnable.RunnableImportResult {
+		res.Status = runnableImportFailed
+		res.Error = util.RefString(err.Error())
+		return res
+	}
+
+	r, err := runnableRestToDomain(item)
+	if err != nil {
+		return failed(err)
+	}
+
+	existing, err := s.store.Get(ctx, r.ID)
+	if err != nil {
+		return failed(err)
+	}
+	switch {
+	case existing == nil:
+		_, err = s.store.Register(ctx, r)
+		res.Status = runnableImportRegistered
+	case replace:
+		_, err = s.store.Update(ctx, r)
+		res.Status = runnableImportUpdated
+	default:
+		res.Status = runnableImportSkipped
+		res.Error = util.RefString(errRunnableAlreadyExists)
+	}
+	if err != nil {
+		return failed(err)
+	}
+	return res
+}
+
+// Build the container image for a runnable from source and register or update the runnable.
+func (s *runnablesrvc) Build(ctx context.Context, p *runnable.BuildPayload) (res *runnable.RunnableBuild, err error) {
+	logging.FromContext(ctx, s.logger).Info("runnable.build")
+	build := &domain.RunnableBuild{
+		RunnableID: p.ID,
+		Revision:   p.Revision,
+		Dockerfile: p.Dockerfile,
+		Image:      fmt.Sprintf("runnables/%s:%s", p.ID, p.Tag),
+		// the runnables are used by the workflows of all the projects, so only admins may rebuild existing ones
+		Replace: s.authorizeAdmin(ctx) == nil,
+	}
+	switch {
+	case p.CodesetProject != nil && p.CodesetName != nil && p.GitURL != nil:
+		return nil, runnable.MakeBadRequest(domain.ErrRunnableBuildSourceConflict)
+	case p.CodesetProject != nil && p.CodesetName != nil:
+		if err := s.authorize(ctx, *p.CodesetProject, domain.ProjectRoleEditor); err != nil {
+			return nil, err
+		}
+		build.Codeset = &domain.Codeset{Project: *p.CodesetProject, Name: *p.CodesetName}
+	case p.GitURL != nil:
+		// the git repositories do not belong to a project
+		if err := s.authorizeAdmin(ctx); err != nil {
+			return nil, err
+		}
+		build.SourceURL = *p.GitURL
+	default:
+		return nil, runnable.MakeBadRequest(domain.ErrRunnableBuildSourceMissing)
+	}
+
+	var r *domain.Runnable
+	if p.Runnable != nil {
+		if p.Runnable.ID != p.ID {
+			return nil, runnable.MakeBadRequest(errors.New(errRunnableIDMismatch))
+		}
+		r, err = runnableRestToDomain(p.Runnable)
+		if err != nil {
+			return nil, runnable.MakeBadRequest(err)
+		}
+	}
+
+	b, err := s.mgr.BuildRunnable(ctx, build, r)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
+		if err == domain.ErrRunnableNotFound || strings.Contains(err.Error(), "Fetching Codeset failed") {
+			return nil, runnable.MakeNotFound(err)
+		}
+		if err == domain.ErrRunnableBuildSourceMissing || err == domain.ErrRunnableBuildDockerfileMissing {
+			return nil, runnable.MakeBadRequest(err)
+		}
+		if err == domain.ErrRunnableBuildReplace {
+			return nil, forbidden("rebuilding the existing runnable %q is restricted to admins", p.ID)
+		}
+		return nil, err
+	}
+	return runnableBuildDomainToRest(b), nil
+}
+
+func runnableBuildDomainToRest(b *domain.RunnableBuild) *runnable.RunnableBuild {
+	res := &runnable.RunnableBuild{
+		Name:       b.Name,
+		Runnable:   b.RunnableID,
+		Source:     util.RefString(b.SourceURL),
+		Revision:   util.RefString(b.Revision),
+		Dockerfile: util.RefString(b.Dockerfile),
+		Image:      domain.LocalRegistryHostname + "/" + b.Image,
+		Status:     b.Status,
+		URL:        util.RefString(b.URL),
+	}
+	if !b.StartTime.IsZero() {
+		res.StartTime = util.RefString(b.StartTime.Format(time.RFC3339))
+	}
+	if !b.CompletionTime.IsZero() {
+		res.CompletionTime = util.RefString(b.CompletionTime.Format(time.RFC3339))
+	}
+	return res
+}
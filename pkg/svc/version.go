@@ -2,25 +2,27 @@ package svc
 
 import (
 	"context"
-	"log"
+
+	"go.uber.org/zap"
 
 	gversion "github.com/fuseml/fuseml-core/gen/version"
+	"github.com/fuseml/fuseml-core/pkg/logging"
 	"github.com/fuseml/fuseml-core/pkg/version"
 )
 
 // version service implementation.
 type versionsrvc struct {
-	logger *log.Logger
+	logger *zap.SugaredLogger
 }
 
 // NewVersionService returns the version service implementation.
-func NewVersionService(logger *log.Logger) gversion.Service {
+func NewVersionService(logger *zap.SugaredLogger) gversion.Service {
 	return &versionsrvc{logger}
 }
 
 // Retrieve an Codeset from FuseML.
 func (s *versionsrvc) Get(ctx context.Context) (res *gversion.VersionInfo, err error) {
-	s.logger.Print("version.get")
+	logging.FromContext(ctx, s.logger).Info("version.get")
 
 	v := version.GetInfo()
 
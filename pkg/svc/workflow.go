@@ -2,43 +2,65 @@ package svc
 
 import (
 	"context"
-	"log"
+	"errors"
 	"strings"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/logging"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // workflow service example implementation.
 // The example methods log the requests and return zero values.
 type workflowsrvc struct {
-	logger *log.Logger
+	*authorizer
+	logger *zap.SugaredLogger
 	mgr    domain.WorkflowManager
 }
 
 // NewWorkflowService returns the workflow service implementation.
-func NewWorkflowService(logger *log.Logger, workflowManager domain.WorkflowManager) workflow.Service {
-	return &workflowsrvc{logger, workflowManager}
+func NewWorkflowService(logger *zap.SugaredLogger, workflowManager domain.WorkflowManager, authenticator domain.Authenticator,
+	members domain.ProjectMemberStore) workflow.Service {
+	return &workflowsrvc{&authorizer{authenticator, members}, logger, workflowManager}
 }
 
 // List Workflows.
-func (s *workflowsrvc) List(ctx context.Context, w *workflow.ListPayload) (res []*workflow.Workflow, err error) {
-	s.logger.Print("workflow.list")
-	workflows := s.mgr.GetWorkflows(ctx, w.Name)
+func (s *workflowsrvc) List(ctx context.Context, w *workflow.ListPayload) (res *workflow.WorkflowPage, err error) {
+	logging.FromContext(ctx, s.logger).Info("workflow.list")
+	workflows, next, err := s.mgr.GetWorkflows(ctx, w.Name, listOptions(w.Limit, w.Continue, w.Sort))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, workflow.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res = &workflow.WorkflowPage{Items: make([]*workflow.Workflow, 0, len(workflows)), Continue: util.RefString(next)}
 	for _, w := range workflows {
-		res = append(res, workflowDomainToRest(w))
+		res.Items = append(res.Items, workflowDomainToRest(w))
 	}
-	return
+	return res, nil
 }
 
 // Create a new Workflow.
-func (s *workflowsrvc) Create(ctx context.Context, w *workflow.Workflow) (res *workflow.Workflow, err error) {
-	s.logger.Print("workflow.create")
-	wf, err := s.mgr.CreateWorkflow(ctx, workflowRestToDomain(w))
+func (s *workflowsrvc) Create(ctx context.Context, w *workflow.CreatePayload) (res *workflow.Workflow, err error) {
+	logging.FromContext(ctx, s.logger).Infow("workflow.create", logging.WorkflowKey, w.Name)
+	// the workflow steps run arbitrary images in the FuseML workloads namespace, so only admins may define them
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	wf, err := s.mgr.CreateWorkflow(ctx, workflowRestToDomain(&workflow.Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+		Inputs:      w.Inputs,
+		Outputs:     w.Outputs,
+		Steps:       w.Steps,
+	}))
 	if err != nil {
-		s.logger.Print(err)
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
 		if err == domain.ErrWorkflowExists {
 			return nil, workflow.MakeConflict(err)
 		}
@@ -49,10 +71,10 @@ func (s *workflowsrvc) Create(ctx context.Context, w *workflow.Workflow) (res *w
 
 // Get a Workflow.
 func (s *workflowsrvc) Get(ctx context.Context, w *workflow.GetPayload) (res *workflow.Workflow, err error) {
-	s.logger.Print("workflow.get")
+	logging.FromContext(ctx, s.logger).Infow("workflow.get", logging.WorkflowKey, w.Name)
 	wf, err := s.mgr.GetWorkflow(ctx, w.Name)
 	if err != nil {
-		s.logger.Print(err)
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
 		if err == domain.ErrWorkflowNotFound {
 			return nil, workflow.MakeNotFound(err)
 		}
@@ -63,10 +85,13 @@ func (s *workflowsrvc) Get(ctx context.Context, w *workflow.GetPayload) (res *wo
 
 // Delete a Workflow and its assignments.
 func (s *workflowsrvc) Delete(ctx context.Context, d *workflow.DeletePayload) (err error) {
-	s.logger.Print("workflow.delete")
+	logging.FromContext(ctx, s.logger).Infow("workflow.delete", logging.WorkflowKey, d.Name)
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return err
+	}
 	err = s.mgr.DeleteWorkflow(ctx, d.Name)
 	if err != nil {
-		s.logger.Print(err)
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
 		return
 	}
 	return
@@ -74,10 +99,21 @@ func (s *workflowsrvc) Delete(ctx context.Context, d *workflow.DeletePayload) (e
 
 // Assign a Workflow to a Codeset.
 func (s *workflowsrvc) Assign(ctx context.Context, w *workflow.AssignPayload) (err error) {
-	s.logger.Print("workflow.assign")
-	_, _, err = s.mgr.AssignToCodeset(ctx, w.Name, w.CodesetProject, w.CodesetName)
+	logging.FromContext(ctx, s.logger).Infow("workflow.assign", logging.WorkflowKey, w.Name, logging.ProjectKey, w.CodesetProject, logging.CodesetKey, w.CodesetName)
+	if err := s.authorize(ctx, w.CodesetProject, domain.ProjectRoleEditor); err != nil {
+		return err
+	}
+	var refs *domain.CodesetRefFilter
+	if len(w.Branches) > 0 || len(w.Tags) > 0 {
+		refs = &domain.CodesetRefFilter{Branches: w.Branches, Tags: w.Tags}
+	}
+	_, _, err = s.mgr.AssignToCodeset(ctx, w.Name, w.CodesetProject, w.CodesetName, refs)
 	if err != nil {
-		s.logger.Print(err)
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
+		if errors.Is(err, domain.ErrInvalidRefPattern) || errors.Is(err, domain.ErrWorkflowDockerfileNotFound) ||
+			errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			return workflow.MakeBadRequest(err)
+		}
 		// FIXME: codeset needs to thrown a known error when trying to get a codeset that does not exist
 		// to properly compare the returned error.
 		if err == domain.ErrWorkflowNotFound || strings.Contains(err.Error(), "Fetching Codeset failed") {
@@ -89,10 +125,13 @@ func (s *workflowsrvc) Assign(ctx context.Context, w *workflow.AssignPayload) (e
 
 // Unassign a Workflow from a Codeset.
 func (s *workflowsrvc) Unassign(ctx context.Context, u *workflow.UnassignPayload) (err error) {
-	s.logger.Print("workflow.unassign")
+	logging.FromContext(ctx, s.logger).Infow("workflow.unassign", logging.WorkflowKey, u.Name, logging.ProjectKey, u.CodesetProject, logging.CodesetKey, u.CodesetName)
+	if err := s.authorize(ctx, u.CodesetProject, domain.ProjectRoleEditor); err != nil {
+		return err
+	}
 	err = s.mgr.UnassignFromCodeset(ctx, u.Name, u.CodesetProject, u.CodesetName)
 	if err != nil {
-		s.logger.Print(err)
+		logging.FromContext(ctx, s.logger).Errorw("request failed", logging.ErrorKey, err)
 		if err == domain.ErrWorkflowNotFound || strings.Contains(err.Error(), "Fetching Codeset failed") || err == domain.ErrWorkflowNotAssignedToCodeset {
 			return workflow.MakeNotFound(err)
 		}
@@ -102,14 +141,25 @@ func (s *workflowsrvc) Unassign(ctx context.Context, u *workflow.UnassignPayload
 
 // ListAssignments lists Workflow assignments.
 func (s *workflowsrvc) ListAssignments(ctx context.Context, w *workflow.ListAssignmentsPayload) (assignments []*workflow.WorkflowAssignment, err error) {
-	s.logger.Print("workflow.listAssignments")
-	domainAssignments := s.mgr.GetAllCodesetAssignments(ctx, w.Name)
+	logging.FromContext(ctx, s.logger).Info("workflow.listAssignments")
+	visible, err := s.visibleProjects(ctx)
 	if err != nil {
 		return nil, err
 	}
+	domainAssignments := s.mgr.GetAllCodesetAssignments(ctx, w.Name)
 
 	assignments = []*workflow.WorkflowAssignment{}
-	for wf, assignment := range domainAssignments {
+	for wf, codesets := range domainAssignments {
+		// only list the codesets from the projects the caller may view
+		assignment := make([]*domain.CodesetAssignment, 0, len(codesets))
+		for _, c := range codesets {
+			if visible.Includes(c.Codeset.Project) {
+				assignment = append(assignment, c)
+			}
+		}
+		if len(assignment) == 0 && len(codesets) > 0 {
+			continue
+		}
 		status := s.mgr.GetAssignmentStatus(ctx, wf)
 		assignments = append(assignments, workflowAssignmentDomainToRest(assignment, wf, status))
 	}
@@ -117,8 +167,8 @@ func (s *workflowsrvc) ListAssignments(ctx context.Context, w *workflow.ListAssi
 }
 
 // List Workflow runs.
-func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload) ([]*workflow.WorkflowRun, error) {
-	s.logger.Print("workflow.listRuns")
+func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload) (*workflow.WorkflowRunPage, error) {
+	logging.FromContext(ctx, s.logger).Info("workflow.listRuns")
 	filter := domain.WorkflowRunFilter{WorkflowName: w.Name}
 	if w.CodesetName != nil {
 		filter.CodesetName = *w.CodesetName
@@ -129,11 +179,56 @@ func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload
 	if w.Status != nil {
 		filter.Status = []string{*w.Status}
 	}
-	domainRuns, err := s.mgr.GetWorkflowRuns(ctx, &filter)
+	opts := listOptions(w.Limit, w.Continue, w.Sort)
+	if filter.CodesetProject != "" {
+		if err := s.authorize(ctx, filter.CodesetProject, domain.ProjectRoleViewer); err != nil {
+			return nil, err
+		}
+		return s.getWorkflowRuns(ctx, &filter, opts)
+	}
+
+	principal := domain.PrincipalFromContext(ctx)
+	if principal != nil && principal.Admin {
+		return s.getWorkflowRuns(ctx, &filter, opts)
+	}
+	// only list the runs for the codesets from the projects the caller is a member of
+	runs := []*domain.WorkflowRun{}
+	if principal == nil {
+		return &workflow.WorkflowRunPage{Items: []*workflow.WorkflowRun{}}, nil
+	}
+	memberships, err := s.members.GetMemberships(ctx, principal.Name)
 	if err != nil {
 		return nil, err
 	}
-	return workflowRunsDomainToRest(domainRuns), nil
+	for _, m := range memberships {
+		projectFilter := filter
+		projectFilter.CodesetProject = m.Project
+		projectRuns, _, err := s.mgr.GetWorkflowRuns(ctx, &projectFilter, nil)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, projectRuns...)
+	}
+	// the runs of all the projects are sorted and paged together
+	if err := domain.SortWorkflowRuns(runs, opts); err != nil {
+		return nil, err
+	}
+	start, end, next, err := opts.Paginate(len(runs))
+	if err != nil {
+		return nil, workflow.MakeBadRequest(err)
+	}
+	return &workflow.WorkflowRunPage{Items: workflowRunsDomainToRest(runs[start:end]), Continue: util.RefString(next)}, nil
+}
+
+func (s *workflowsrvc) getWorkflowRuns(ctx context.Context, filter *domain.WorkflowRunFilter, opts *domain.ListOptions) (*workflow.WorkflowRunPage, error) {
+	domainRuns, next, err := s.mgr.GetWorkflowRuns(ctx, filter, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidContinueToken) {
+			return nil, workflow.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return &workflow.WorkflowRunPage{Items: workflowRunsDomainToRest(domainRuns), Continue: util.RefString(next)}, nil
 }
 
 func workflowRestToDomain(restWf *workflow.Workflow) *domain.Workflow {
@@ -391,7 +486,15 @@ func workflowStepEnvsDomainToRest(domainStepEnvs []*domain.WorkflowStepEnv) []*w
 func workflowAssignmentDomainToRest(domainAssignment []*domain.CodesetAssignment, wfName string, wfAsgStatus *domain.WorkflowAssignmentStatus) *workflow.WorkflowAssignment {
 	restCodesets := make([]*workflow.Codeset, len(domainAssignment))
 	for i, domainCodeset := range domainAssignment {
-		restCodesets[i] = (*workflow.Codeset)(codesetDomainToRest(domainCodeset.Codeset))
+		c := codesetDomainToRest(domainCodeset.Codeset)
+		restCodesets[i] = &workflow.Codeset{
+			Name:        c.Name,
+			Project:     c.Project,
+			Description: c.Description,
+			Labels:      c.Labels,
+			URL:         c.URL,
+			Source:      (*workflow.CodesetSource)(c.Source),
+		}
 	}
 
 	restAssignment := workflow.WorkflowAssignment{
@@ -402,6 +505,16 @@ func workflowAssignmentDomainToRest(domainAssignment []*domain.CodesetAssignment
 			URL:       util.RefString(wfAsgStatus.URL),
 		},
 	}
+	for _, a := range domainAssignment {
+		if !a.Refs.IsEmpty() {
+			restAssignment.RefFilters = append(restAssignment.RefFilters, &workflow.CodesetRefFilter{
+				CodesetProject: a.Codeset.Project,
+				CodesetName:    a.Codeset.Name,
+				Branches:       a.Refs.Branches,
+				Tags:           a.Refs.Tags,
+			})
+		}
+	}
 	return &restAssignment
 }
 
@@ -423,6 +536,7 @@ func workflowRunDomainToRest(domainRun *domain.WorkflowRun) *workflow.WorkflowRu
 		CompletionTime: domainRun.CompletionTime.Format(time.RFC3339),
 		Status:         domainRun.Status,
 		URL:            util.RefString(domainRun.URL),
+		Ref:            util.RefString(domainRun.Ref),
 	}
 }
 
@@ -0,0 +1,138 @@
+package svc
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/gen/workflow"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// fakeWorkflowManager accepts all the changes and assigns the "wf" workflow to a codeset of each of the
+// "prj" and "other" projects. The methods not used by the tests are left unimplemented.
+type fakeWorkflowManager struct {
+	domain.WorkflowManager
+}
+
+func (fakeWorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workflow) (*domain.Workflow, error) {
+	return wf, nil
+}
+
+func (fakeWorkflowManager) DeleteWorkflow(ctx context.Context, name string) error {
+	return nil
+}
+
+func (fakeWorkflowManager) AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string,
+	refs *domain.CodesetRefFilter) (*domain.WorkflowListener, *int64, error) {
+	return nil, nil, nil
+}
+
+func (fakeWorkflowManager) UnassignFromCodeset(ctx context.Context, name, codesetProject, codesetName string) error {
+	return nil
+}
+
+func (fakeWorkflowManager) GetAllCodesetAssignments(ctx context.Context, name *string) map[string][]*domain.CodesetAssignment {
+	return map[string][]*domain.CodesetAssignment{
+		"wf": {
+			{Codeset: &domain.Codeset{Project: "prj", Name: "cs"}},
+			{Codeset: &domain.Codeset{Project: "other", Name: "cs"}},
+		},
+	}
+}
+
+func (fakeWorkflowManager) GetAssignmentStatus(ctx context.Context, name string) *domain.WorkflowAssignmentStatus {
+	return &domain.WorkflowAssignmentStatus{}
+}
+
+func (fakeWorkflowManager) GetWorkflowRuns(ctx context.Context, filter *domain.WorkflowRunFilter,
+	opts *domain.ListOptions) ([]*domain.WorkflowRun, string, error) {
+	return nil, "", nil
+}
+
+func newTestWorkflowService() *workflowsrvc {
+	return NewWorkflowService(zap.NewNop().Sugar(), fakeWorkflowManager{}, nil, newTestMembers()).(*workflowsrvc)
+}
+
+func TestWorkflowAuthorization(t *testing.T) {
+	s := newTestWorkflowService()
+	tests := []struct {
+		name    string
+		allowed []string
+		call    func(ctx context.Context) error
+	}{
+		{
+			name:    "create",
+			allowed: admins,
+			call: func(ctx context.Context) error {
+				_, err := s.Create(ctx, &workflow.CreatePayload{Name: "new"})
+				return err
+			},
+		},
+		{
+			name:    "delete",
+			allowed: admins,
+			call: func(ctx context.Context) error {
+				return s.Delete(ctx, &workflow.DeletePayload{Name: "wf"})
+			},
+		},
+		{
+			name:    "assign",
+			allowed: prjEditors,
+			call: func(ctx context.Context) error {
+				return s.Assign(ctx, &workflow.AssignPayload{Name: "wf", CodesetProject: "prj", CodesetName: "cs"})
+			},
+		},
+		{
+			name:    "unassign",
+			allowed: prjEditors,
+			call: func(ctx context.Context) error {
+				return s.Unassign(ctx, &workflow.UnassignPayload{Name: "wf", CodesetProject: "prj", CodesetName: "cs"})
+			},
+		},
+		{
+			name:    "list project runs",
+			allowed: prjViewers,
+			call: func(ctx context.Context) error {
+				_, err := s.ListRuns(ctx, &workflow.ListRunsPayload{CodesetProject: util.RefString("prj")})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		checkAuthorization(t, tt.name, tt.allowed, tt.call)
+	}
+}
+
+func TestWorkflowListAssignmentsVisibility(t *testing.T) {
+	s := newTestWorkflowService()
+	want := map[string][]string{
+		"admin":      {"other", "prj"},
+		"owner":      {"prj"},
+		"editor":     {"prj"},
+		"viewer":     {"prj"},
+		"non-member": {"other"},
+		"workflow":   {"prj"},
+		"anonymous":  {},
+	}
+	for _, p := range testPrincipals {
+		res, err := s.ListAssignments(principalContext(p.principal), &workflow.ListAssignmentsPayload{})
+		if err != nil {
+			t.Fatalf("list assignments as %s: unexpected error: %v", p.name, err)
+		}
+		projects := []string{}
+		for _, a := range res {
+			for _, c := range a.Codesets {
+				projects = append(projects, c.Project)
+			}
+		}
+		sort.Strings(projects)
+		if !reflect.DeepEqual(projects, want[p.name]) {
+			t.Errorf("list assignments as %s: got the codesets of %v, want %v", p.name, projects, want[p.name])
+		}
+	}
+}
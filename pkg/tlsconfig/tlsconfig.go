@@ -0,0 +1,268 @@
+// Package tlsconfig builds the TLS configurations used by the FuseML server and clients.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fuseml/fuseml-core/pkg/logging"
+)
+
+// DefaultReloadInterval is the default interval at which the certificate files are checked for changes
+const DefaultReloadInterval = 10 * time.Second
+
+// ServerOptions holds the files from which the server TLS configuration is loaded
+type ServerOptions struct {
+	// CertFile is the PEM encoded certificate presented by the server
+	CertFile string `json:"certFile"`
+	// KeyFile is the PEM encoded private key matching the server certificate
+	KeyFile string `json:"keyFile"`
+	// ClientCAFile is the PEM encoded bundle of CA certificates used to verify the client
+	// certificates. When set, the clients are required to present a valid certificate (mTLS).
+	ClientCAFile string `json:"clientCAFile"`
+}
+
+// Enabled returns whether TLS is configured.
+func (o ServerOptions) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != "" || o.ClientCAFile != ""
+}
+
+// Validate checks that the options describe a complete TLS configuration.
+func (o ServerOptions) Validate() error {
+	if o.CertFile == "" || o.KeyFile == "" {
+		return fmt.Errorf("both the TLS certificate and key files must be provided")
+	}
+	return nil
+}
+
+// Reloader holds the server TLS certificate and client CAs, reloading them when the files change,
+// so that the certificates can be renewed without restarting the server.
+type Reloader struct {
+	logger  *zap.SugaredLogger
+	options ServerOptions
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewReloader loads the server TLS certificate and client CAs and returns a new Reloader.
+func NewReloader(logger *zap.SugaredLogger, options ServerOptions) (*Reloader, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	r := &Reloader{logger: logger, options: options}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Config returns the server TLS configuration. The configuration always uses the last loaded
+// certificate and client CAs. When client CAs are configured, the clients are required to present
+// a valid certificate.
+func (r *Reloader) Config() *tls.Config {
+	return r.config(tls.RequireAndVerifyClientCert)
+}
+
+// HTTPConfig returns the TLS configuration of the HTTP server. Unlike Config, the client certificates
+// are verified when presented but not required during the handshake, so that the health checks and
+// metrics can be retrieved without one. RequireClientCert enforces them for the other requests.
+func (r *Reloader) HTTPConfig() *tls.Config {
+	return r.config(tls.VerifyClientCertIfGiven)
+}
+
+// config returns a server TLS configuration authenticating the clients as selected by clientAuth
+// when client CAs are configured.
+func (r *Reloader) config(clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if r.clientCA != nil {
+				config.ClientCAs = r.clientCA
+				config.ClientAuth = clientAuth
+			}
+			return config, nil
+		},
+	}
+}
+
+// RequireClientCert returns a handler rejecting the requests made without a verified client
+// certificate when client CAs are configured, except for the requests to the exempt paths. It is
+// used along with HTTPConfig.
+func (r *Reloader) RequireClientCert(next http.Handler, exempt ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.RLock()
+		required := r.clientCA != nil
+		r.mu.RUnlock()
+		if required && (req.TLS == nil || len(req.TLS.VerifiedChains) == 0) && !isExempt(req.URL.Path, exempt) {
+			http.Error(w, "a valid client certificate is required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// isExempt returns whether path is one of the exempt paths.
+func isExempt(path string, exempt []string) bool {
+	for _, e := range exempt {
+		if path == e {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCertificate returns the last loaded server certificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Run checks the certificate files for changes at the given interval and reloads them,
+// until the context is canceled. A failed reload is logged and the previous certificates
+// are kept in use.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.changed()
+			if err != nil {
+				r.logger.Errorw("failed to check the TLS certificate files", logging.ErrorKey, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if err := r.load(); err != nil {
+				r.logger.Errorw("failed to reload the TLS certificates", logging.ErrorKey, err)
+				continue
+			}
+			r.logger.Info("reloaded the TLS certificates")
+		}
+	}
+}
+
+// files returns the files from which the TLS configuration is loaded.
+func (r *Reloader) files() []string {
+	files := []string{r.options.CertFile, r.options.KeyFile}
+	if r.options.ClientCAFile != "" {
+		files = append(files, r.options.ClientCAFile)
+	}
+	return files
+}
+
+// changed returns whether any of the files was modified since it was last loaded.
+func (r *Reloader) changed() (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// load reads the certificate, the key and the client CAs from their files.
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the TLS certificate: %w", err)
+	}
+	var clientCA *x509.CertPool
+	if r.options.ClientCAFile != "" {
+		clientCA, err = LoadCertPool(r.options.ClientCAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCA = clientCA
+	r.modTimes = modTimes
+	return nil
+}
+
+// LoadCertPool returns a certificate pool holding the PEM encoded certificates read from a file.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// ClientConfig returns the TLS configuration for a client trusting the CA certificates read from
+// caFile, in addition to the system ones, and presenting the certificate read from certFile and
+// keyFile. All files are optional.
+func ClientConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("both the client certificate and key files must be provided")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,211 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeCertificate writes a new self-signed certificate and its key to the given files.
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlsconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	options := ServerOptions{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "tls.crt"),
+	}
+	logger := zap.NewNop().Sugar()
+
+	t.Run("incomplete", func(t *testing.T) {
+		if _, err := NewReloader(logger, ServerOptions{CertFile: options.CertFile}); err == nil {
+			t.Error("expected an error when the key file is missing")
+		}
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		if _, err := NewReloader(logger, options); err == nil {
+			t.Error("expected an error when the files do not exist")
+		}
+	})
+
+	writeCertificate(t, options.CertFile, options.KeyFile, "first")
+	r, err := NewReloader(logger, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("load", func(t *testing.T) {
+		if got := commonName(t, r); got != "first" {
+			t.Errorf("unexpected certificate: got %q, want %q", got, "first")
+		}
+		config, err := r.Config().GetConfigForClient(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+			t.Error("expected client certificates to be required")
+		}
+		config, err = r.HTTPConfig().GetConfigForClient(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.ClientAuth != tls.VerifyClientCertIfGiven || config.ClientCAs == nil {
+			t.Error("expected client certificates to be verified when given")
+		}
+	})
+
+	t.Run("require client cert", func(t *testing.T) {
+		handler := r.RequireClientCert(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), "/healthz")
+		tests := []struct {
+			name string
+			path string
+			tls  *tls.ConnectionState
+			want int
+		}{
+			{"no certificate", "/projects", &tls.ConnectionState{}, http.StatusUnauthorized},
+			{"verified certificate", "/projects", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{}}}, http.StatusOK},
+			{"exempt path", "/healthz", &tls.ConnectionState{}, http.StatusOK},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.TLS = tt.tls
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s: unexpected status: got %d, want %d", tt.name, rec.Code, tt.want)
+			}
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		writeCertificate(t, options.CertFile, options.KeyFile, "second")
+		// make sure the change is detected regardless of the file system timestamp resolution
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(options.CertFile, future, future); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			r.Run(ctx, 10*time.Millisecond)
+			close(done)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for commonName(t, r) != "second" {
+			if time.Now().After(deadline) {
+				t.Fatal("the certificate was not reloaded")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("invalid reload", func(t *testing.T) {
+		if err := ioutil.WriteFile(options.KeyFile, []byte("invalid"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.load(); err == nil {
+			t.Error("expected an error when loading an invalid key")
+		}
+		if got := commonName(t, r); got != "second" {
+			t.Errorf("expected the previous certificate to be kept, got %q", got)
+		}
+	})
+}
+
+func TestClientConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tlsconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, "client")
+
+	config, err := ClientConfig(certFile, certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RootCAs == nil {
+		t.Error("expected the custom CA to be trusted")
+	}
+	if len(config.Certificates) != 1 {
+		t.Error("expected the client certificate to be presented")
+	}
+
+	if _, err := ClientConfig("", certFile, ""); err == nil {
+		t.Error("expected an error when the client key file is missing")
+	}
+	if _, err := ClientConfig(keyFile, "", ""); err == nil {
+		t.Error("expected an error when the CA file holds no certificates")
+	}
+}
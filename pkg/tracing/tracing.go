@@ -0,0 +1,181 @@
+// Package tracing instruments the FuseML server with OpenTelemetry traces, exported to an OTLP
+// collector or written to the standard output.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	goamiddleware "goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ExporterNone disables the export of the traces
+	ExporterNone = "none"
+	// ExporterOTLP exports the traces to an OpenTelemetry collector, using the OTLP gRPC protocol
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the traces to the standard output
+	ExporterStdout = "stdout"
+
+	// serviceName identifies the FuseML server in the exported traces
+	serviceName = "fuseml-core"
+	// instrumentationName identifies the spans started by the FuseML code
+	instrumentationName = "github.com/fuseml/fuseml-core"
+)
+
+// Keys of the attributes attached to the spans.
+const (
+	// RequestIDKey identifies the API request that started a trace
+	RequestIDKey = attribute.Key("fuseml.request_id")
+	// ProjectKey identifies the project targeted by an operation
+	ProjectKey = attribute.Key("fuseml.project")
+	// CodesetKey identifies the codeset targeted by an operation
+	CodesetKey = attribute.Key("fuseml.codeset")
+	// WorkflowKey identifies the workflow targeted by an operation
+	WorkflowKey = attribute.Key("fuseml.workflow")
+	// RunnableKey identifies the runnable targeted by an operation
+	RunnableKey = attribute.Key("fuseml.runnable")
+	// RunKey identifies the workflow run, or the runnable build, targeted by an operation
+	RunKey = attribute.Key("fuseml.run")
+)
+
+// untracedPaths are the HTTP paths of the requests that are not traced, as they are sent
+// periodically by the monitoring systems
+var untracedPaths = map[string]bool{"/metrics": true, "/healthz": true, "/readyz": true}
+
+// Options configures the export of the traces.
+type Options struct {
+	// Exporter selects where the traces are exported (valid values: none, otlp, stdout)
+	Exporter string `json:"exporter"`
+	// Endpoint is the address (host:port) of the OTLP collector
+	Endpoint string `json:"endpoint"`
+	// Insecure disables TLS for the connection to the OTLP collector
+	Insecure bool `json:"insecure"`
+}
+
+// Validate checks that the exporter is supported and that the OTLP collector is provided when
+// exporting the traces with OTLP.
+func (o Options) Validate() error {
+	switch o.Exporter {
+	case ExporterNone, ExporterStdout:
+	case ExporterOTLP:
+		if o.Endpoint == "" {
+			return fmt.Errorf("the endpoint of the OTLP collector was not provided")
+		}
+	default:
+		return fmt.Errorf("invalid exporter %q (valid exporters: %s|%s|%s)", o.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+	return nil
+}
+
+// Setup installs the global tracer provider, exporting the traces as configured, and the W3C
+// trace context propagator. Traces are written to w when using the stdout exporter. The returned
+// function flushes the pending spans and stops the exporter.
+func Setup(ctx context.Context, o Options, version string, w io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch o.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(o.Endpoint)}
+		if o.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, o.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s trace exporter: %w", o.Exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the span in the context, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the error returned by the traced operation, if any, and ends the span. It is meant
+// to be deferred with a pointer to the error returned by the operation.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// HTTPMiddleware starts a span for each request received by the HTTP server, continuing the
+// trace propagated by the client, if any.
+func HTTPMiddleware(h http.Handler) http.Handler {
+	return otelhttp.NewHandler(h, "HTTP",
+		otelhttp.WithFilter(func(r *http.Request) bool { return !untracedPaths[r.URL.Path] }),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string { return "HTTP " + r.Method }),
+	)
+}
+
+// UnaryServerInterceptor starts a span for each request received by the gRPC server, continuing
+// the trace propagated by the client, if any.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor()
+}
+
+// EndpointMiddleware is a Goa endpoint middleware naming the span of each API request after the
+// service method, e.g. workflow.assign, and tagging it with the request ID, so that the requests
+// are named the same way for both transports and can be correlated with the logs.
+func EndpointMiddleware(e goa.Endpoint) goa.Endpoint {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		span := trace.SpanFromContext(ctx)
+		service, _ := ctx.Value(goa.ServiceKey).(string)
+		method, _ := ctx.Value(goa.MethodKey).(string)
+		span.SetName(service + "." + method)
+		if id, ok := ctx.Value(goamiddleware.RequestIDKey).(string); ok {
+			span.SetAttributes(RequestIDKey.String(id))
+		}
+
+		res, err := e(ctx, req)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return res, err
+	}
+}
+
+// Transport returns a round tripper starting a span for each request sent through rt, as a child
+// of the span in the request context.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt)
+}
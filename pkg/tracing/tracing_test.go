@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	goamiddleware "goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+// newRecorder installs a tracer provider recording the ended spans
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func TestOptionsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		options Options
+		wantErr string
+	}{
+		{name: "none", options: Options{Exporter: ExporterNone}},
+		{name: "stdout", options: Options{Exporter: ExporterStdout}},
+		{name: "otlp", options: Options{Exporter: ExporterOTLP, Endpoint: "collector:4317"}},
+		{name: "otlp without endpoint", options: Options{Exporter: ExporterOTLP}, wantErr: "OTLP collector"},
+		{name: "invalid exporter", options: Options{Exporter: "jaeger"}, wantErr: `invalid exporter "jaeger"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.options.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestStartEnd(t *testing.T) {
+	recorder := newRecorder(t)
+
+	traced := func(ctx context.Context, fail bool) (err error) {
+		_, span := Start(ctx, "traced", WorkflowKey.String("mlflow"))
+		defer End(span, &err)
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	ctx, parent := Start(context.Background(), "parent")
+	_ = traced(ctx, false)
+	_ = traced(ctx, true)
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("unexpected number of spans: got %d, want 3", len(spans))
+	}
+	for i, want := range []codes.Code{codes.Unset, codes.Error} {
+		span := spans[i]
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d is not a child of the parent span", i)
+		}
+		if span.Status().Code != want {
+			t.Errorf("unexpected status of span %d: got %v, want %v", i, span.Status().Code, want)
+		}
+		if attrs := span.Attributes(); len(attrs) != 1 || attrs[0] != WorkflowKey.String("mlflow") {
+			t.Errorf("unexpected attributes of span %d: %v", i, attrs)
+		}
+	}
+}
+
+func TestEndpointMiddleware(t *testing.T) {
+	recorder := newRecorder(t)
+
+	endpoint := EndpointMiddleware(func(context.Context, interface{}) (interface{}, error) {
+		return nil, errors.New("not found")
+	})
+	ctx := context.WithValue(context.Background(), goa.ServiceKey, "workflow")
+	ctx = context.WithValue(ctx, goa.MethodKey, "get")
+	ctx = context.WithValue(ctx, goamiddleware.RequestIDKey, "abc123")
+	ctx, span := Start(ctx, "HTTP GET")
+	_, _ = endpoint(ctx, nil)
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("unexpected number of spans: got %d, want 1", len(spans))
+	}
+	if name := spans[0].Name(); name != "workflow.get" {
+		t.Errorf("unexpected span name: got %q, want %q", name, "workflow.get")
+	}
+	if attrs := spans[0].Attributes(); len(attrs) != 1 || attrs[0] != RequestIDKey.String("abc123") {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+	if events := spans[0].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("the error was not recorded: %v", events)
+	}
+}